@@ -20,7 +20,9 @@ func apiDefiner(ctx context.Context, config cfg.Config, logger mon.Logger) (*api
 	definitions.GET("/json-from-map", apiserver.CreateHandler(&JsonResponseFromMapHandler{}))
 	definitions.GET("/json-from-struct", apiserver.CreateHandler(&JsonResponseFromStructHandler{}))
 
-	definitions.POST("/json-handler", apiserver.CreateJsonHandler(&JsonInputHandler{}))
+	jsonInputHandler := &JsonInputHandler{}
+	definitions.POST("/json-handler", apiserver.CreateJsonHandler(jsonInputHandler)).
+		WithInput(jsonInputHandler.GetInput())
 
 	group := definitions.Group("/admin")
 	group.Use(auth.NewChainHandler(map[string]auth.Authenticator{
@@ -38,7 +40,5 @@ func apiDefiner(ctx context.Context, config cfg.Config, logger mon.Logger) (*api
 }
 
 func main() {
-	app := application.New(application.WithConfigFile("config.dist.yml", "yml"))
-	app.Add("api", apiserver.New(apiDefiner))
-	app.Run()
+	application.RunApiServer(apiDefiner, application.WithConfigFile("config.dist.yml", "yml"))
 }
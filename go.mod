@@ -2,7 +2,7 @@ module github.com/applike/gosoline
 
 require (
 	github.com/DATA-DOG/go-sqlmock v1.4.1
-	github.com/DataDog/zstd v1.4.4 // indirect
+	github.com/DataDog/zstd v1.4.4
 	github.com/Masterminds/squirrel v1.2.0
 	github.com/Microsoft/go-winio v0.4.14 // indirect
 	github.com/VividCortex/mysqlerr v0.0.0-20170204212430-6c6b55f8796f
@@ -12,6 +12,7 @@ require (
 	github.com/aws/aws-lambda-go v1.13.2
 	github.com/aws/aws-sdk-go v1.34.19
 	github.com/aws/aws-xray-sdk-go v1.1.0
+	github.com/bkaradzic/go-lz4 v1.0.0
 	github.com/cenkalti/backoff v2.2.1+incompatible
 	github.com/containerd/continuity v0.0.0-20190426062206-aaeac12a7ffc // indirect
 	github.com/denisenkom/go-mssqldb v0.0.0-20190313032549-041949b8d268 // indirect
@@ -30,7 +31,8 @@ require (
 	github.com/go-sql-driver/mysql v1.5.0
 	github.com/gogo/protobuf v1.2.2-0.20190723190241-65acae22fc9d // indirect
 	github.com/golang-migrate/migrate/v4 v4.2.5
-	github.com/golang/protobuf v1.3.2 // indirect
+	github.com/golang/protobuf v1.3.2
+	github.com/golang/snappy v0.0.1
 	github.com/gomodule/redigo v2.0.0+incompatible // indirect
 	github.com/google/go-querystring v1.0.0
 	github.com/google/uuid v1.1.1
@@ -49,6 +51,7 @@ require (
 	github.com/karlseguin/expect v1.0.1 // indirect
 	github.com/leodido/go-urn v1.1.0 // indirect
 	github.com/lib/pq v1.3.0
+	github.com/linkedin/goavro/v2 v2.11.1
 	github.com/mitchellh/mapstructure v1.2.2
 	github.com/myesui/uuid v1.0.0 // indirect
 	github.com/onsi/ginkgo v1.12.0 // indirect
@@ -57,7 +60,9 @@ require (
 	github.com/ory/dockertest v3.3.5+incompatible
 	github.com/ory/ladon v1.0.1
 	github.com/oschwald/geoip2-golang v1.4.0
+	github.com/pierrec/xxHash v0.1.5
 	github.com/pkg/errors v0.9.1
+	github.com/segmentio/kafka-go v0.3.5
 	github.com/sha1sum/aws_signing_client v0.0.0-20170514202702-9088e4c7b34b
 	github.com/sirupsen/logrus v1.5.0 // indirect
 	github.com/spf13/cast v1.3.0
@@ -3,6 +3,7 @@ package auth
 import (
 	"encoding/base64"
 	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/gin-gonic/gin"
@@ -121,3 +122,10 @@ func (a *basicAuthAuthenticator) IsValid(ginCtx *gin.Context) (bool, error) {
 
 	return false, fmt.Errorf("invalid credentials provided")
 }
+
+func (a *basicAuthAuthenticator) SecurityScheme() apiserver.SecurityScheme {
+	return apiserver.SecurityScheme{
+		Type:   "http",
+		Scheme: "basic",
+	}
+}
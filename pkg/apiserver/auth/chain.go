@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
@@ -26,3 +27,23 @@ func NewChainHandler(authenticators map[string]Authenticator) gin.HandlerFunc {
 		ginCtx.Abort()
 	}
 }
+
+// SecuritySchemer is implemented by authenticators which can describe themselves in the OpenAPI document
+type SecuritySchemer interface {
+	SecurityScheme() apiserver.SecurityScheme
+}
+
+// UseChain adds a chain handler of the authenticators to the definitions and documents the security schemes
+// of all authenticators implementing SecuritySchemer under their name in the chain.
+func UseChain(d *apiserver.Definitions, authenticators map[string]Authenticator) {
+	schemes := make(map[string]apiserver.SecurityScheme)
+
+	for name, authenticator := range authenticators {
+		if schemer, ok := authenticator.(SecuritySchemer); ok {
+			schemes[name] = schemer.SecurityScheme()
+		}
+	}
+
+	d.Use(NewChainHandler(authenticators))
+	d.UseSecurity(schemes)
+}
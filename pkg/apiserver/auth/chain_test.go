@@ -0,0 +1,36 @@
+package auth_test
+
+import (
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/apiserver/auth"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestUseChain_Security(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+
+	d := &apiserver.Definitions{}
+	auth.UseChain(d, map[string]auth.Authenticator{
+		auth.ByApiKey:    auth.NewUncheckedKeyAuthenticatorWithInterfaces(logger),
+		auth.ByBasicAuth: auth.NewBasicAuthAuthenticatorWithInterfaces(logger, map[string]string{}),
+		"custom":         auth.NewConfigKeyAuthenticatorWithInterfaces(logger, []string{}, auth.ProvideValueFromHeader("X-KEY")),
+	})
+	d.GET("/", nil)
+
+	doc := apiserver.BuildOpenApiDocument(d, &apiserver.OpenApiSettings{})
+
+	assert.Equal(t, map[string]apiserver.SecurityScheme{
+		auth.ByApiKey: {
+			Type: "apiKey",
+			In:   "header",
+			Name: auth.HeaderApiKey,
+		},
+		auth.ByBasicAuth: {
+			Type:   "http",
+			Scheme: "basic",
+		},
+	}, doc.Components.SecuritySchemes)
+	assert.Len(t, doc.Paths["/"]["get"].Security, 2)
+}
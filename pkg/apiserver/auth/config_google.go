@@ -3,6 +3,7 @@ package auth
 import (
 	"context"
 	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/gin-gonic/gin"
@@ -181,3 +182,11 @@ func (a *configGoogleAuthenticator) getSubjectForToken(tokenInfo *oauth2.Tokenin
 		AuthenticatedBy: ByGoogle,
 	}
 }
+
+func (a *configGoogleAuthenticator) SecurityScheme() apiserver.SecurityScheme {
+	return apiserver.SecurityScheme{
+		Type: "apiKey",
+		In:   "header",
+		Name: "X-ID-TOKEN",
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/subtle"
 	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/ddb"
 	"github.com/applike/gosoline/pkg/mon"
@@ -159,3 +160,12 @@ func ProvideTokenBearerFromDdb(repo ddb.Repository, getModel ModelProvider) Toke
 		return nil, err
 	}
 }
+
+// SecurityScheme documents the token header, the header of the bearer id is required as well
+func (a *tokenBearerAuthenticator) SecurityScheme() apiserver.SecurityScheme {
+	return apiserver.SecurityScheme{
+		Type: "apiKey",
+		In:   "header",
+		Name: a.tokenHeader,
+	}
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/gin-gonic/gin"
@@ -61,3 +62,11 @@ func (a *uncheckedKeyAuthenticator) IsValid(ginCtx *gin.Context) (bool, error) {
 
 	return true, nil
 }
+
+func (a *uncheckedKeyAuthenticator) SecurityScheme() apiserver.SecurityScheme {
+	return apiserver.SecurityScheme{
+		Type: "apiKey",
+		In:   "header",
+		Name: HeaderApiKey,
+	}
+}
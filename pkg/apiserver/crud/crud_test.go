@@ -2,11 +2,13 @@ package crud_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/apiserver/crud"
 	"github.com/applike/gosoline/pkg/apiserver/crud/mocks"
 	"github.com/applike/gosoline/pkg/db-repo"
+	dbRepoMocks "github.com/applike/gosoline/pkg/db-repo/mocks"
 	"github.com/applike/gosoline/pkg/mdl"
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/applike/gosoline/pkg/validation"
@@ -38,7 +40,12 @@ type UpdateInput struct {
 }
 
 type Handler struct {
-	Repo *mocks.Repository
+	Repo        *mocks.Repository
+	HistoryRepo *dbRepoMocks.ChangeHistoryRepository
+}
+
+func (h Handler) GetHistoryRepository() db_repo.ChangeHistoryRepository {
+	return h.HistoryRepo
 }
 
 func (h Handler) GetRepository() crud.Repository {
@@ -111,9 +118,11 @@ func (h Handler) List(_ context.Context, _ *db_repo.QueryBuilder, _ string) (int
 
 func NewTransformer() Handler {
 	repo := new(mocks.Repository)
+	historyRepo := new(dbRepoMocks.ChangeHistoryRepository)
 
 	return Handler{
-		Repo: repo,
+		Repo:        repo,
+		HistoryRepo: historyRepo,
 	}
 }
 
@@ -270,6 +279,41 @@ func TestUpdateHandler_Handle_ValidationError(t *testing.T) {
 	transformer.Repo.AssertExpectations(t)
 }
 
+func TestUpdateHandler_Handle_StaleObject(t *testing.T) {
+	readModel := &Model{}
+	updateModel := &Model{
+		Model: db_repo.Model{
+			Id: mdl.Uint(1),
+			Timestamps: db_repo.Timestamps{
+				UpdatedAt: &time.Time{},
+				CreatedAt: &time.Time{},
+			},
+		},
+		Name: mdl.String("updated"),
+	}
+
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+
+	transformer.Repo.On("Update", mock.Anything, updateModel).Return(db_repo.NewStaleObjectError(1, "model", 3))
+	transformer.Repo.On("Read", mock.Anything, mdl.Uint(1), readModel).Run(func(args mock.Arguments) {
+		model := args.Get(2).(*Model)
+		model.Id = mdl.Uint(1)
+		model.Name = mdl.String("updated")
+		model.UpdatedAt = &time.Time{}
+		model.CreatedAt = &time.Time{}
+	}).Return(nil)
+
+	handler := crud.NewUpdateHandler(logger, transformer)
+
+	body := `{"name": "updated"}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusConflict, response.Code)
+
+	transformer.Repo.AssertExpectations(t)
+}
+
 func TestDeleteHandler_Handle(t *testing.T) {
 	model := &Model{}
 	deleteModel := &Model{
@@ -370,3 +414,119 @@ func TestListHandler_Handle(t *testing.T) {
 
 	transformer.Repo.AssertExpectations(t)
 }
+
+func TestListHandler_HandleCursor(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+	handler := crud.NewListHandler(logger, transformer)
+
+	transformer.Repo.On("GetMetadata").Return(db_repo.Metadata{
+		TableName:  "footable",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id":   db_repo.NewFieldMapping("id"),
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	})
+	transformer.Repo.On("Query", mock.Anything, mock.AnythingOfType("*db_repo.QueryBuilder"), mock.AnythingOfType("*[]*crud_test.Model")).Run(func(args mock.Arguments) {
+		models := args.Get(2).(*[]*Model)
+		*models = []*Model{
+			{Model: db_repo.Model{Id: mdl.Uint(1)}, Name: mdl.String("bar")},
+			{Model: db_repo.Model{Id: mdl.Uint(2)}, Name: mdl.String("foo")},
+		}
+	}).Return(nil)
+
+	body := `{"order":[{"field":"name","direction":"ASC"}],"cursor":{"limit":1,"skipTotal":true}}`
+	response := apiserver.HttpTest("PUT", "/:id", "/1", body, handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	out := struct {
+		Next    string          `json:"next"`
+		Results json.RawMessage `json:"results"`
+	}{}
+
+	if assert.NoError(t, json.Unmarshal(response.Body.Bytes(), &out)) {
+		assert.NotEmpty(t, out.Next, "the next cursor should be built from the model rows")
+		assert.JSONEq(t, `[{"id":1,"name":"bar","updatedAt":null,"createdAt":null}]`, string(out.Results))
+	}
+
+	transformer.Repo.AssertExpectations(t)
+	transformer.Repo.AssertNotCalled(t, "Count", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHistoryListHandler_Handle(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+	actionAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	transformer.HistoryRepo.On("ListRevisions", mock.Anything, mdl.Uint(1)).Return([]*db_repo.ChangeHistoryEntry{
+		{
+			Revision: 1,
+			Action:   "insert",
+			ActionAt: actionAt,
+			Model: &Model{
+				Model: db_repo.Model{Id: mdl.Uint(1)},
+				Name:  mdl.String("foo"),
+			},
+		},
+	}, nil)
+
+	handler := crud.NewHistoryListHandler(logger, transformer)
+	response := apiserver.HttpTest("GET", "/:id/history", "/1/history", "", handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `[{"revision":1,"action":"insert","actionAt":"2020-01-01T00:00:00Z","model":{"id":1,"name":"foo","updatedAt":null,"createdAt":null}}]`, response.Body.String())
+
+	transformer.HistoryRepo.AssertExpectations(t)
+}
+
+func TestHistoryListHandler_Handle_AsOfNotFound(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+	at := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	transformer.HistoryRepo.On("ReadAsOf", mock.Anything, mdl.Uint(1), at).Return(nil, db_repo.NewRecordNotFoundError(1, "model", fmt.Errorf("deleted")))
+
+	handler := crud.NewHistoryListHandler(logger, transformer)
+	response := apiserver.HttpTest("GET", "/:id/history", "/1/history?at=2020-01-01T00:00:00Z", "", handler)
+
+	assert.Equal(t, http.StatusNotFound, response.Code)
+
+	transformer.HistoryRepo.AssertExpectations(t)
+}
+
+func TestHistoryDiffHandler_Handle(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+
+	transformer.HistoryRepo.On("Diff", mock.Anything, mdl.Uint(1), 1, 2).Return([]db_repo.ChangeHistoryFieldChange{
+		{Field: "name", From: "foo", To: "bar"},
+	}, nil)
+
+	handler := crud.NewHistoryDiffHandler(logger, transformer)
+	response := apiserver.HttpTest("GET", "/:id/diff/:from/:to", "/1/diff/1/2", "", handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `[{"field":"name","from":"foo","to":"bar"}]`, response.Body.String())
+
+	transformer.HistoryRepo.AssertExpectations(t)
+}
+
+func TestHistoryRestoreHandler_Handle(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	transformer := NewTransformer()
+
+	transformer.HistoryRepo.On("Restore", mock.Anything, mdl.Uint(1), 2).Return(&Model{
+		Model: db_repo.Model{Id: mdl.Uint(1)},
+		Name:  mdl.String("foo"),
+	}, nil)
+
+	handler := crud.NewHistoryRestoreHandler(logger, transformer)
+	response := apiserver.HttpTest("POST", "/:id/history/:revision/restore", "/1/history/2/restore", "", handler)
+
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"id":1,"name":"foo","updatedAt":null,"createdAt":null}`, response.Body.String())
+
+	transformer.HistoryRepo.AssertExpectations(t)
+}
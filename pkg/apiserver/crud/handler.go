@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/apiserver/sql"
 	"github.com/applike/gosoline/pkg/db-repo"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/jinzhu/inflection"
@@ -84,31 +85,46 @@ func AddCrudHandlers(logger mon.Logger, d *apiserver.Definitions, version int, b
 func AddCreateHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler CreateHandler) {
 	path, _ := getHandlerPaths(version, basePath)
 
-	d.POST(path, NewCreateHandler(logger, handler))
+	d.POST(path, NewCreateHandler(logger, handler)).
+		WithSummary(fmt.Sprintf("create %s", basePath)).
+		WithInput(handler.GetCreateInput()).
+		WithOutput(describeOutput(logger, handler))
 }
 
 func AddReadHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler BaseHandler) {
 	_, idPath := getHandlerPaths(version, basePath)
 
-	d.GET(idPath, NewReadHandler(logger, handler))
+	d.GET(idPath, NewReadHandler(logger, handler)).
+		WithSummary(fmt.Sprintf("read %s", basePath)).
+		WithOutput(describeOutput(logger, handler))
 }
 
 func AddUpdateHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler UpdateHandler) {
 	_, idPath := getHandlerPaths(version, basePath)
 
-	d.PUT(idPath, NewUpdateHandler(logger, handler))
+	d.PUT(idPath, NewUpdateHandler(logger, handler)).
+		WithSummary(fmt.Sprintf("update %s", basePath)).
+		WithInput(handler.GetUpdateInput()).
+		WithOutput(describeOutput(logger, handler))
 }
 
 func AddDeleteHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler BaseHandler) {
 	_, idPath := getHandlerPaths(version, basePath)
 
-	d.DELETE(idPath, NewDeleteHandler(logger, handler))
+	d.DELETE(idPath, NewDeleteHandler(logger, handler)).
+		WithSummary(fmt.Sprintf("delete %s", basePath)).
+		WithOutput(describeOutput(logger, handler))
 }
 
 func AddListHandler(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler ListHandler) {
 	plural := inflection.Plural(basePath)
 	path := fmt.Sprintf("/v%d/%s", version, plural)
-	d.POST(path, NewListHandler(logger, handler))
+	d.POST(path, NewListHandler(logger, handler)).
+		WithSummary(fmt.Sprintf("list %s", plural)).
+		WithInput(sql.NewInput()).
+		WithOutput(&Output{
+			Results: []interface{}{describeOutput(logger, handler)},
+		})
 }
 
 func getHandlerPaths(version int, basePath string) (path string, idPath string) {
@@ -118,6 +134,30 @@ func getHandlerPaths(version int, basePath string) (path string, idPath string)
 	return
 }
 
+// describeOutput returns the output sample of the handler to describe its output in the OpenAPI document.
+// The output stays undocumented if the sample can't be created.
+func describeOutput(logger mon.Logger, handler BaseHandler) interface{} {
+	sample, err := getOutputSample(handler)
+
+	if err != nil {
+		logger.Warnf("can not document the output of the crud handler for %T: %s", handler.GetModel(), err.Error())
+		return nil
+	}
+
+	return sample
+}
+
+// getOutputSample transforms an empty model of the handler with the default api view
+func getOutputSample(handler BaseHandler) (interface{}, error) {
+	sample, err := handler.TransformOutput(handler.GetModel(), DefaultApiView)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not transform an empty model: %w", err)
+	}
+
+	return sample, nil
+}
+
 func GetApiViewFromHeader(reqHeaders http.Header) string {
 	if apiView := reqHeaders.Get(apiserver.ApiViewKey); apiView != "" {
 		return apiView
@@ -0,0 +1,289 @@
+package crud
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//go:generate mockery -name BaseHistoryHandler
+type BaseHistoryHandler interface {
+	GetHistoryRepository() db_repo.ChangeHistoryRepository
+}
+
+//go:generate mockery -name HistoryHandler
+type HistoryHandler interface {
+	BaseHandler
+	BaseHistoryHandler
+}
+
+type HistoryEntryOutput struct {
+	Revision int         `json:"revision"`
+	Action   string      `json:"action"`
+	ActionAt time.Time   `json:"actionAt"`
+	Model    interface{} `json:"model"`
+}
+
+// AddHistoryHandlers exposes the change history of the model. The revision which was current at a time is
+// returned by the list route if the time is given in RFC3339 format as query parameter "at".
+func AddHistoryHandlers(logger mon.Logger, d *apiserver.Definitions, version int, basePath string, handler HistoryHandler) {
+	_, idPath := getHandlerPaths(version, basePath)
+	sample := &HistoryEntryOutput{
+		Model: describeOutput(logger, handler),
+	}
+
+	d.GET(fmt.Sprintf("%s/history", idPath), NewHistoryListHandler(logger, handler)).
+		WithSummary(fmt.Sprintf("list the revisions of %s", basePath)).
+		WithOutput(&[]interface{}{sample})
+
+	d.GET(fmt.Sprintf("%s/history/:revision", idPath), NewHistoryReadHandler(logger, handler)).
+		WithSummary(fmt.Sprintf("read a revision of %s", basePath)).
+		WithOutput(sample)
+
+	d.GET(fmt.Sprintf("%s/diff/:from/:to", idPath), NewHistoryDiffHandler(logger, handler)).
+		WithSummary(fmt.Sprintf("diff two revisions of %s", basePath)).
+		WithOutput(&[]db_repo.ChangeHistoryFieldChange{})
+
+	d.POST(fmt.Sprintf("%s/history/:revision/restore", idPath), NewHistoryRestoreHandler(logger, handler)).
+		WithSummary(fmt.Sprintf("restore a revision of %s", basePath)).
+		WithOutput(describeOutput(logger, handler))
+}
+
+type historyListHandler struct {
+	transformer HistoryHandler
+	logger      mon.Logger
+}
+
+func NewHistoryListHandler(logger mon.Logger, transformer HistoryHandler) gin.HandlerFunc {
+	return apiserver.CreateHandler(historyListHandler{
+		transformer: transformer,
+		logger:      logger,
+	})
+}
+
+func (hh historyListHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
+	id, valid := apiserver.GetUintFromRequest(request, "id")
+
+	if !valid {
+		return nil, errors.New("no valid id provided")
+	}
+
+	repo := hh.transformer.GetHistoryRepository()
+	apiView := GetApiViewFromHeader(request.Header)
+
+	if at := request.Url.Query().Get("at"); at != "" {
+		asOf, err := time.Parse(time.RFC3339, at)
+
+		if err != nil {
+			return apiserver.GetErrorHandler()(http.StatusBadRequest, fmt.Errorf("invalid time %s: %w", at, err)), nil
+		}
+
+		entry, err := repo.ReadAsOf(ctx, id, asOf)
+
+		if db_repo.IsRecordNotFoundError(err) {
+			hh.logger.WithContext(ctx).Warnf("failed to read revision: %s", err)
+			return apiserver.NewStatusResponse(http.StatusNotFound), nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		out, err := transformHistoryEntry(hh.transformer, entry, apiView)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return apiserver.NewJsonResponse(out), nil
+	}
+
+	entries, err := repo.ListRevisions(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*HistoryEntryOutput, 0, len(entries))
+
+	for _, entry := range entries {
+		entryOut, err := transformHistoryEntry(hh.transformer, entry, apiView)
+
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, entryOut)
+	}
+
+	return apiserver.NewJsonResponse(out), nil
+}
+
+type historyReadHandler struct {
+	transformer HistoryHandler
+	logger      mon.Logger
+}
+
+func NewHistoryReadHandler(logger mon.Logger, transformer HistoryHandler) gin.HandlerFunc {
+	return apiserver.CreateHandler(historyReadHandler{
+		transformer: transformer,
+		logger:      logger,
+	})
+}
+
+func (hh historyReadHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
+	id, valid := apiserver.GetUintFromRequest(request, "id")
+
+	if !valid {
+		return nil, errors.New("no valid id provided")
+	}
+
+	revision, valid := getRevisionFromRequest(request, "revision")
+
+	if !valid {
+		return nil, errors.New("no valid revision provided")
+	}
+
+	entry, err := hh.transformer.GetHistoryRepository().ReadRevision(ctx, id, revision)
+
+	if db_repo.IsRecordNotFoundError(err) {
+		hh.logger.WithContext(ctx).Warnf("failed to read revision: %s", err)
+		return apiserver.NewStatusResponse(http.StatusNotFound), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := transformHistoryEntry(hh.transformer, entry, GetApiViewFromHeader(request.Header))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return apiserver.NewJsonResponse(out), nil
+}
+
+type historyDiffHandler struct {
+	transformer HistoryHandler
+	logger      mon.Logger
+}
+
+func NewHistoryDiffHandler(logger mon.Logger, transformer HistoryHandler) gin.HandlerFunc {
+	return apiserver.CreateHandler(historyDiffHandler{
+		transformer: transformer,
+		logger:      logger,
+	})
+}
+
+func (hh historyDiffHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
+	id, valid := apiserver.GetUintFromRequest(request, "id")
+
+	if !valid {
+		return nil, errors.New("no valid id provided")
+	}
+
+	from, validFrom := getRevisionFromRequest(request, "from")
+	to, validTo := getRevisionFromRequest(request, "to")
+
+	if !validFrom || !validTo {
+		return nil, errors.New("no valid revisions provided")
+	}
+
+	changes, err := hh.transformer.GetHistoryRepository().Diff(ctx, id, from, to)
+
+	if db_repo.IsRecordNotFoundError(err) {
+		hh.logger.WithContext(ctx).Warnf("failed to diff revisions: %s", err)
+		return apiserver.NewStatusResponse(http.StatusNotFound), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return apiserver.NewJsonResponse(changes), nil
+}
+
+type historyRestoreHandler struct {
+	transformer HistoryHandler
+	logger      mon.Logger
+}
+
+func NewHistoryRestoreHandler(logger mon.Logger, transformer HistoryHandler) gin.HandlerFunc {
+	return apiserver.CreateHandler(historyRestoreHandler{
+		transformer: transformer,
+		logger:      logger,
+	})
+}
+
+func (hh historyRestoreHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
+	id, valid := apiserver.GetUintFromRequest(request, "id")
+
+	if !valid {
+		return nil, errors.New("no valid id provided")
+	}
+
+	revision, valid := getRevisionFromRequest(request, "revision")
+
+	if !valid {
+		return nil, errors.New("no valid revision provided")
+	}
+
+	model, err := hh.transformer.GetHistoryRepository().Restore(ctx, id, revision)
+
+	if db_repo.IsRecordNotFoundError(err) {
+		hh.logger.WithContext(ctx).Warnf("failed to restore revision: %s", err)
+		return apiserver.NewStatusResponse(http.StatusNotFound), nil
+	}
+
+	if db_repo.IsStaleObjectError(err) {
+		hh.logger.WithContext(ctx).Warnf("failed to restore revision: %s", err)
+		return apiserver.NewStatusResponse(http.StatusConflict), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := hh.transformer.TransformOutput(model, GetApiViewFromHeader(request.Header))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return apiserver.NewJsonResponse(out), nil
+}
+
+func transformHistoryEntry(transformer BaseHandler, entry *db_repo.ChangeHistoryEntry, apiView string) (*HistoryEntryOutput, error) {
+	model, err := transformer.TransformOutput(entry.Model, apiView)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &HistoryEntryOutput{
+		Revision: entry.Revision,
+		Action:   entry.Action,
+		ActionAt: entry.ActionAt,
+		Model:    model,
+	}, nil
+}
+
+func getRevisionFromRequest(request *apiserver.Request, name string) (int, bool) {
+	param, found := request.Params.Get(name)
+
+	if !found {
+		return 0, false
+	}
+
+	revision, err := strconv.Atoi(param)
+
+	return revision, err == nil && revision > 0
+}
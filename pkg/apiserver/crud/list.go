@@ -4,8 +4,10 @@ import (
 	"context"
 	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/apiserver/sql"
+	"github.com/applike/gosoline/pkg/db-repo"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/gin-gonic/gin"
+	"reflect"
 )
 
 type Output struct {
@@ -13,6 +15,14 @@ type Output struct {
 	Results interface{} `json:"results"`
 }
 
+// CursorOutput is returned if the input contains a cursor. Total is omitted if the cursor skips it.
+type CursorOutput struct {
+	Total   *int        `json:"total,omitempty"`
+	Next    string      `json:"next,omitempty"`
+	Prev    string      `json:"prev,omitempty"`
+	Results interface{} `json:"results"`
+}
+
 type listHandler struct {
 	transformer ListHandler
 	logger      mon.Logger
@@ -34,6 +44,10 @@ func (lh listHandler) GetInput() interface{} {
 func (lh listHandler) Handle(ctx context.Context, request *apiserver.Request) (*apiserver.Response, error) {
 	inp := request.Body.(*sql.Input)
 
+	if inp.Cursor != nil {
+		return lh.handleCursor(ctx, request, inp)
+	}
+
 	repo := lh.transformer.GetRepository()
 	metadata := repo.GetMetadata()
 
@@ -68,3 +82,71 @@ func (lh listHandler) Handle(ctx context.Context, request *apiserver.Request) (*
 
 	return resp, nil
 }
+
+func (lh listHandler) handleCursor(ctx context.Context, request *apiserver.Request, inp *sql.Input) (*apiserver.Response, error) {
+	repo := lh.transformer.GetRepository()
+	metadata := repo.GetMetadata()
+
+	lqb := sql.NewOrmQueryBuilder(metadata)
+	qb, err := lqb.Build(inp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// the cursor is built from the columns of the models, so they are queried directly
+	// and transformed one by one after the page has been cut
+	model := lh.transformer.GetModel()
+	models := reflect.New(reflect.SliceOf(reflect.TypeOf(model)))
+
+	if err = repo.Query(ctx, qb, models.Interface()); err != nil {
+		return nil, err
+	}
+
+	page, err := lqb.Paginate(inp, models.Elem().Interface())
+
+	if err != nil {
+		return nil, err
+	}
+
+	apiView := GetApiViewFromHeader(request.Header)
+	rows := reflect.ValueOf(page.Results)
+	results := make([]interface{}, rows.Len())
+
+	for i := 0; i < rows.Len(); i++ {
+		if results[i], err = lh.transformer.TransformOutput(rows.Index(i).Interface().(db_repo.ModelBased), apiView); err != nil {
+			return nil, err
+		}
+	}
+
+	out := CursorOutput{
+		Next:    page.Next,
+		Prev:    page.Prev,
+		Results: results,
+	}
+
+	if !inp.Cursor.SkipTotal {
+		// the total has to be counted without the condition of the cursor
+		countInp := *inp
+		countInp.Cursor = nil
+
+		countQb, err := lqb.Build(&countInp)
+
+		if err != nil {
+			return nil, err
+		}
+
+		total, err := repo.Count(ctx, countQb, model)
+
+		if err != nil {
+			return nil, err
+		}
+
+		out.Total = &total
+	}
+
+	resp := apiserver.NewJsonResponse(out)
+	resp.AddHeader(apiserver.ApiViewKey, apiView)
+
+	return resp, nil
+}
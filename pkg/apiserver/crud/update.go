@@ -63,10 +63,20 @@ func (uh updateHandler) Handle(ctx context.Context, request *apiserver.Request)
 
 	err = repo.Update(ctx, model)
 
+	if errors.As(err, &notFound) {
+		uh.logger.WithContext(ctx).Warnf("failed to update model: %s", err)
+		return apiserver.NewStatusResponse(http.StatusNotFound), nil
+	}
+
 	if db.IsDuplicateEntryError(err) {
 		return apiserver.NewStatusResponse(http.StatusConflict), nil
 	}
 
+	if db_repo.IsStaleObjectError(err) {
+		uh.logger.WithContext(ctx).Warnf("failed to update model: %s", err)
+		return apiserver.NewStatusResponse(http.StatusConflict), nil
+	}
+
 	if errors.Is(err, &validation.Error{}) {
 		return apiserver.GetErrorHandler()(http.StatusBadRequest, err), nil
 	}
@@ -6,6 +6,7 @@ import (
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"strings"
 )
 
@@ -16,6 +17,43 @@ type Definition struct {
 	httpMethod   string
 	relativePath string
 	handlers     []gin.HandlerFunc
+
+	summary      string
+	input        interface{}
+	inputBinding string
+	output       interface{}
+}
+
+// WithSummary sets the summary of the route in the OpenAPI document
+func (d *Definition) WithSummary(summary string) *Definition {
+	d.summary = summary
+
+	return d
+}
+
+// WithInput documents the input of the route, which is usually the result of HandlerWithInput.GetInput. The input of
+// GET and DELETE routes is documented as query parameters, the input of all other routes as json body.
+func (d *Definition) WithInput(input interface{}) *Definition {
+	d.input = input
+	d.inputBinding = ""
+
+	return d
+}
+
+// WithInputBinding documents the input of the route like it is bound by the binding, e.g. binding.Query for the input
+// of a handler created by CreateQueryHandler or binding.FormMultipart for one created by CreateMultiPartFormHandler.
+func (d *Definition) WithInputBinding(input interface{}, binding binding.Binding) *Definition {
+	d.input = input
+	d.inputBinding = binding.Name()
+
+	return d
+}
+
+// WithOutput documents the body of a successful response with a sample of it
+func (d *Definition) WithOutput(output interface{}) *Definition {
+	d.output = output
+
+	return d
 }
 
 func (d *Definition) getAbsolutePath() string {
@@ -30,7 +68,8 @@ func (d *Definition) getAbsolutePath() string {
 type Definitions struct {
 	basePath   string
 	middleware []gin.HandlerFunc
-	routes     []Definition
+	routes     []*Definition
+	security   map[string]SecurityScheme
 
 	children []*Definitions
 	parent   *Definitions
@@ -64,31 +103,62 @@ func (d *Definitions) Use(middleware ...gin.HandlerFunc) {
 	d.middleware = append(d.middleware, middleware...)
 }
 
-func (d *Definitions) Handle(httpMethod, relativePath string, handlers ...gin.HandlerFunc) {
+// UseSecurity documents that the routes of the group and its children accept any of the given security schemes.
+// It doesn't add any middleware, see auth.UseChain for an authentication middleware calling this.
+func (d *Definitions) UseSecurity(schemes map[string]SecurityScheme) {
+	if d.security == nil {
+		d.security = make(map[string]SecurityScheme)
+	}
+
+	for name, scheme := range schemes {
+		d.security[name] = scheme
+	}
+}
+
+func (d *Definitions) Handle(httpMethod, relativePath string, handlers ...gin.HandlerFunc) *Definition {
 	relativePath = strings.TrimRight(relativePath, "/")
 
-	d.routes = append(d.routes, Definition{
+	definition := &Definition{
 		group:        d,
 		httpMethod:   httpMethod,
 		relativePath: relativePath,
 		handlers:     handlers,
-	})
+	}
+
+	d.routes = append(d.routes, definition)
+
+	return definition
+}
+
+func (d *Definitions) POST(relativePath string, handlers ...gin.HandlerFunc) *Definition {
+	return d.Handle("POST", relativePath, handlers...)
 }
 
-func (d *Definitions) POST(relativePath string, handlers ...gin.HandlerFunc) {
-	d.Handle("POST", relativePath, handlers...)
+func (d *Definitions) GET(relativePath string, handlers ...gin.HandlerFunc) *Definition {
+	return d.Handle("GET", relativePath, handlers...)
 }
 
-func (d *Definitions) GET(relativePath string, handlers ...gin.HandlerFunc) {
-	d.Handle("GET", relativePath, handlers...)
+func (d *Definitions) DELETE(relativePath string, handlers ...gin.HandlerFunc) *Definition {
+	return d.Handle("DELETE", relativePath, handlers...)
 }
 
-func (d *Definitions) DELETE(relativePath string, handlers ...gin.HandlerFunc) {
-	d.Handle("DELETE", relativePath, handlers...)
+func (d *Definitions) PUT(relativePath string, handlers ...gin.HandlerFunc) *Definition {
+	return d.Handle("PUT", relativePath, handlers...)
 }
 
-func (d *Definitions) PUT(relativePath string, handlers ...gin.HandlerFunc) {
-	d.Handle("PUT", relativePath, handlers...)
+// getSecurity returns the security schemes of the group, including the ones of its parents
+func (d *Definitions) getSecurity() map[string]SecurityScheme {
+	security := make(map[string]SecurityScheme)
+
+	if d.parent != nil {
+		security = d.parent.getSecurity()
+	}
+
+	for name, scheme := range d.security {
+		security[name] = scheme
+	}
+
+	return security
 }
 
 func buildRouter(definitions *Definitions, router gin.IRouter) {
@@ -103,7 +173,7 @@ func buildRouter(definitions *Definitions, router gin.IRouter) {
 	}
 
 	for _, d := range definitions.routes {
-		metricHandler := CreateMetricHandler(d)
+		metricHandler := CreateMetricHandler(*d)
 		handlers := make([]gin.HandlerFunc, 0, len(d.handlers)+1)
 		handlers = append(handlers, metricHandler)
 		handlers = append(handlers, d.handlers...)
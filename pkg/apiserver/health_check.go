@@ -33,6 +33,10 @@ func NewApiHealthCheck() kernel.ModuleFactory {
 
 		healthCheck := NewApiHealthCheckWithInterfaces(logger, router, settings)
 
+		if metricsSettings := ReadApiMetricsSettings(config); metricsSettings.Enabled && metricsSettings.UseHealthCheckPort {
+			router.GET(metricsSettings.Path, gin.WrapH(mon.ProvidePromRegistry()))
+		}
+
 		return healthCheck, nil
 	}
 }
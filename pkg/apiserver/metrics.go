@@ -0,0 +1,92 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+type ApiMetricsSettings struct {
+	Enabled bool   `cfg:"enabled" default:"false"`
+	Port    int    `cfg:"port" default:"8092"`
+	Path    string `cfg:"path" default:"/metrics"`
+	// UseHealthCheckPort serves the metrics with the ApiHealthCheck instead of an own server
+	UseHealthCheckPort bool `cfg:"use_health_check_port" default:"false"`
+}
+
+type ApiMetrics struct {
+	kernel.BackgroundModule
+	kernel.ServiceStage
+
+	logger mon.Logger
+	server *http.Server
+}
+
+func ReadApiMetricsSettings(config cfg.Config) *ApiMetricsSettings {
+	settings := &ApiMetricsSettings{}
+	config.UnmarshalKey("api.metrics", settings)
+
+	return settings
+}
+
+// NewApiMetrics serves the metrics of the prom metric writer, no module is added if they are disabled or served by the ApiHealthCheck
+func NewApiMetrics() kernel.MultiModuleFactory {
+	return func(config cfg.Config, logger mon.Logger) (map[string]kernel.ModuleFactory, error) {
+		settings := ReadApiMetricsSettings(config)
+
+		if !settings.Enabled || settings.UseHealthCheckPort {
+			return map[string]kernel.ModuleFactory{}, nil
+		}
+
+		return map[string]kernel.ModuleFactory{
+			"api-metrics": func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+				gin.SetMode(gin.ReleaseMode)
+				router := gin.New()
+				registry := mon.ProvidePromRegistry()
+
+				return NewApiMetricsWithInterfaces(logger, router, registry, settings), nil
+			},
+		}, nil
+	}
+}
+
+func NewApiMetricsWithInterfaces(logger mon.Logger, router *gin.Engine, registry *mon.PromRegistry, settings *ApiMetricsSettings) *ApiMetrics {
+	router.GET(settings.Path, gin.WrapH(registry))
+
+	addr := fmt.Sprintf(":%d", settings.Port)
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: router,
+	}
+
+	return &ApiMetrics{
+		logger: logger,
+		server: server,
+	}
+}
+
+func (a *ApiMetrics) Run(ctx context.Context) error {
+	go a.waitForStop(ctx)
+	err := a.server.ListenAndServe()
+
+	if err != http.ErrServerClosed {
+		a.logger.Error(err, "api metrics closed unexpected")
+		return err
+	}
+
+	return nil
+}
+
+func (a *ApiMetrics) waitForStop(ctx context.Context) {
+	<-ctx.Done()
+	err := a.server.Close()
+
+	if err != nil {
+		a.logger.Error(err, "api metrics close")
+	}
+}
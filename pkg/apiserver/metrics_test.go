@@ -0,0 +1,32 @@
+package apiserver_test
+
+import (
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewApiMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	logger := mocks.NewLoggerMockedAll()
+
+	registry := mon.NewPromRegistry()
+	err := registry.Add("app_requests_total", "requests", map[string]string{"path": "/"}, 3)
+	assert.NoError(t, err)
+
+	apiserver.NewApiMetricsWithInterfaces(logger, ginEngine, registry, &apiserver.ApiMetricsSettings{
+		Path: "/metrics",
+	})
+
+	httpRecorder := httptest.NewRecorder()
+	assertRouteReturnsResponse(t, ginEngine, httpRecorder, "/metrics", http.StatusOK)
+
+	assert.Equal(t, mon.PromContentType, httpRecorder.Header().Get("Content-Type"))
+	assert.Equal(t, "# HELP app_requests_total requests\n# TYPE app_requests_total counter\napp_requests_total{path=\"/\"} 3\n", httpRecorder.Body.String())
+}
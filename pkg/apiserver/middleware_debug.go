@@ -0,0 +1,43 @@
+package apiserver
+
+import (
+	"crypto/subtle"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+)
+
+// DebugSettings are read from api.debug. If enabled, a request with the token in the header logs its debug messages
+// regardless of the level of the logger and the channel.
+type DebugSettings struct {
+	Enabled bool   `cfg:"enabled" default:"false"`
+	Header  string `cfg:"header" default:"X-Debug-Token"`
+	Token   string `cfg:"token"`
+}
+
+func ReadDebugSettings(config cfg.Config) *DebugSettings {
+	settings := &DebugSettings{}
+	config.UnmarshalKey("api.debug", settings)
+
+	return settings
+}
+
+// DebugContext marks the context of a request with mon.WithDebugContext if the header of the request contains the
+// configured token. The flag is passed on to the messages published while handling the request.
+func DebugContext(settings *DebugSettings) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		if !settings.Enabled || settings.Token == "" {
+			ginCtx.Next()
+			return
+		}
+
+		token := ginCtx.GetHeader(settings.Header)
+
+		if subtle.ConstantTimeCompare([]byte(token), []byte(settings.Token)) == 1 {
+			ctx := mon.WithDebugContext(ginCtx.Request.Context())
+			ginCtx.Request = ginCtx.Request.WithContext(ctx)
+		}
+
+		ginCtx.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package apiserver_test
+
+import (
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDebugContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := map[string]struct {
+		settings apiserver.DebugSettings
+		header   string
+		debug    bool
+	}{
+		"token":       {apiserver.DebugSettings{Enabled: true, Header: "X-Debug-Token", Token: "secret"}, "secret", true},
+		"wrong token": {apiserver.DebugSettings{Enabled: true, Header: "X-Debug-Token", Token: "secret"}, "guess", false},
+		"no header":   {apiserver.DebugSettings{Enabled: true, Header: "X-Debug-Token", Token: "secret"}, "", false},
+		"no token":    {apiserver.DebugSettings{Enabled: true, Header: "X-Debug-Token"}, "", false},
+		"disabled":    {apiserver.DebugSettings{Enabled: false, Header: "X-Debug-Token", Token: "secret"}, "secret", false},
+	}
+
+	for name, test := range tests {
+		settings := test.settings
+		expected := test.debug
+		header := test.header
+
+		t.Run(name, func(t *testing.T) {
+			debug := false
+
+			r := gin.New()
+			r.Use(apiserver.DebugContext(&settings))
+			r.GET("/some/route", func(ginCtx *gin.Context) {
+				debug = mon.IsDebugContext(ginCtx.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/some/route", nil)
+
+			if header != "" {
+				req.Header.Set("X-Debug-Token", header)
+			}
+
+			r.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.Equal(t, expected, debug)
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package apiserver
+
+import (
+	"github.com/applike/gosoline/pkg/conc"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+type RateLimitKeyFunc func(ginCtx *gin.Context) string
+
+// RateLimitKeyClientIp limits the requests of every client ip separately
+func RateLimitKeyClientIp(ginCtx *gin.Context) string {
+	return ginCtx.ClientIP()
+}
+
+// RateLimit aborts requests with 429 Too Many Requests if the rate limiter denies the key of the request.
+// Requests are let through if the rate limiter fails.
+func RateLimit(logger mon.Logger, limiter conc.RateLimiter, keyFunc RateLimitKeyFunc) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		key := keyFunc(ginCtx)
+		result, err := limiter.Allow(ginCtx.Request.Context(), key)
+
+		if err != nil {
+			logger.WithContext(ginCtx.Request.Context()).Warnf("can not check the rate limit of %s: %s", key, err)
+			ginCtx.Next()
+
+			return
+		}
+
+		if !result.Allowed {
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			ginCtx.Header("Retry-After", strconv.Itoa(retryAfter))
+			ginCtx.AbortWithStatus(http.StatusTooManyRequests)
+
+			return
+		}
+
+		ginCtx.Next()
+	}
+}
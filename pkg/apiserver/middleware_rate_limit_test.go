@@ -0,0 +1,44 @@
+package apiserver_test
+
+import (
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/conc"
+	concMocks "github.com/applike/gosoline/pkg/conc/mocks"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	logger := monMocks.NewLoggerMockedAll()
+	limiter := new(concMocks.RateLimiter)
+	limiter.On("Allow", mock.Anything, "192.0.2.1").Return(&conc.RateLimitResult{Allowed: true}, nil).Once()
+	limiter.On("Allow", mock.Anything, "192.0.2.1").Return(&conc.RateLimitResult{RetryAfter: 1500 * time.Millisecond}, nil).Once()
+
+	r := gin.New()
+	r.Use(apiserver.RateLimit(logger, limiter, apiserver.RateLimitKeyClientIp))
+	r.GET("/", func(ginCtx *gin.Context) {
+		ginCtx.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+
+	allowed := httptest.NewRecorder()
+	r.ServeHTTP(allowed, req)
+
+	denied := httptest.NewRecorder()
+	r.ServeHTTP(denied, req)
+
+	assert.Equal(t, http.StatusOK, allowed.Code)
+	assert.Equal(t, http.StatusTooManyRequests, denied.Code)
+	assert.Equal(t, "2", denied.Header().Get("Retry-After"))
+	limiter.AssertExpectations(t)
+}
@@ -0,0 +1,338 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/gin-gonic/gin/binding"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const OpenApiVersion = "3.0.3"
+
+var openApiPathParameter = regexp.MustCompile(`[:*]([^/]+)`)
+
+// OpenApiSettings are read from api.openapi. If enabled, the document is served by the api server at the path.
+type OpenApiSettings struct {
+	Enabled bool   `cfg:"enabled" default:"false"`
+	Path    string `cfg:"path" default:"/openapi.json"`
+	File    string `cfg:"file" default:"openapi.json"`
+	Title   string `cfg:"title" default:"{app_name}"`
+	Version string `cfg:"version" default:"1.0.0"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+type OpenApiDocument struct {
+	OpenApi    string                     `json:"openapi"`
+	Info       OpenApiInfo                `json:"info"`
+	Paths      map[string]OpenApiPathItem `json:"paths"`
+	Components OpenApiComponents          `json:"components"`
+}
+
+type OpenApiInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenApiComponents struct {
+	Schemas         map[string]*OpenApiSchema `json:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// OpenApiPathItem maps the lower case http methods onto the operations of a path
+type OpenApiPathItem map[string]*OpenApiOperation
+
+type OpenApiOperation struct {
+	Summary     string                     `json:"summary,omitempty"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []OpenApiParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenApiRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenApiResponse `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+type OpenApiParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   *OpenApiSchema `json:"schema"`
+}
+
+type OpenApiRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenApiMediaType `json:"content"`
+}
+
+type OpenApiResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenApiMediaType `json:"content,omitempty"`
+}
+
+type OpenApiMediaType struct {
+	Schema *OpenApiSchema `json:"schema"`
+}
+
+// BuildOpenApiDocument describes all routes of the definitions. Inputs with the query binding and inputs of GET and
+// DELETE routes without binding are documented as query parameters, all other inputs as request body.
+func BuildOpenApiDocument(definitions *Definitions, settings *OpenApiSettings) *OpenApiDocument {
+	builder := newOpenApiSchemaBuilder()
+	doc := &OpenApiDocument{
+		OpenApi: OpenApiVersion,
+		Info: OpenApiInfo{
+			Title:   settings.Title,
+			Version: settings.Version,
+		},
+		Paths: make(map[string]OpenApiPathItem),
+	}
+
+	securitySchemes := make(map[string]SecurityScheme)
+	addOpenApiPaths(doc, builder, definitions, securitySchemes)
+
+	if len(builder.components) > 0 {
+		doc.Components.Schemas = builder.components
+	}
+
+	if len(securitySchemes) > 0 {
+		doc.Components.SecuritySchemes = securitySchemes
+	}
+
+	return doc
+}
+
+func addOpenApiPaths(doc *OpenApiDocument, builder *openApiSchemaBuilder, definitions *Definitions, securitySchemes map[string]SecurityScheme) {
+	security := definitions.getSecurity()
+
+	for _, route := range definitions.routes {
+		path := openApiPathParameter.ReplaceAllString(route.getAbsolutePath(), "{$1}")
+
+		if path == "" {
+			path = "/"
+		}
+
+		if _, ok := doc.Paths[path]; !ok {
+			doc.Paths[path] = make(OpenApiPathItem)
+		}
+
+		operation := buildOpenApiOperation(builder, route)
+
+		for name, scheme := range security {
+			securitySchemes[name] = scheme
+			operation.Security = append(operation.Security, map[string][]string{name: {}})
+		}
+
+		sort.Slice(operation.Security, func(i, j int) bool {
+			return openApiRequirementName(operation.Security[i]) < openApiRequirementName(operation.Security[j])
+		})
+
+		doc.Paths[path][strings.ToLower(route.httpMethod)] = operation
+	}
+
+	for _, child := range definitions.children {
+		addOpenApiPaths(doc, builder, child, securitySchemes)
+	}
+}
+
+func buildOpenApiOperation(builder *openApiSchemaBuilder, route *Definition) *OpenApiOperation {
+	operation := &OpenApiOperation{
+		Summary:    route.summary,
+		Parameters: make([]OpenApiParameter, 0),
+		Responses:  make(map[string]OpenApiResponse),
+	}
+
+	for _, match := range openApiPathParameter.FindAllStringSubmatch(route.getAbsolutePath(), -1) {
+		operation.Parameters = append(operation.Parameters, OpenApiParameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &OpenApiSchema{Type: "string"},
+		})
+	}
+
+	input, bindingName := route.input, route.inputBinding
+
+	if input != nil {
+		if bindingName == "" && (route.httpMethod == http.MethodGet || route.httpMethod == http.MethodDelete) {
+			bindingName = binding.Query.Name()
+		}
+
+		switch bindingName {
+		case binding.Query.Name():
+			operation.Parameters = append(operation.Parameters, buildOpenApiQueryParameters(builder, input)...)
+
+		case binding.FormMultipart.Name():
+			operation.RequestBody = &OpenApiRequestBody{
+				Required: true,
+				Content: map[string]OpenApiMediaType{
+					binding.MIMEMultipartPOSTForm: {Schema: builder.valueSchema(input)},
+				},
+			}
+
+		default:
+			operation.RequestBody = &OpenApiRequestBody{
+				Required: true,
+				Content: map[string]OpenApiMediaType{
+					binding.MIMEJSON: {Schema: builder.valueSchema(input)},
+				},
+			}
+		}
+	}
+
+	response := OpenApiResponse{
+		Description: "successful operation",
+	}
+
+	if route.output != nil {
+		response.Content = map[string]OpenApiMediaType{
+			"application/json": {Schema: builder.valueSchema(route.output)},
+		}
+	}
+
+	operation.Responses["200"] = response
+
+	return operation
+}
+
+// buildOpenApiQueryParameters uses the form tags of the input like the query binding does
+func buildOpenApiQueryParameters(builder *openApiSchemaBuilder, input interface{}) []OpenApiParameter {
+	parameters := make([]OpenApiParameter, 0)
+	t := reflect.TypeOf(input)
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return parameters
+	}
+
+	schema := builder.structSchema(t)
+	required := make(map[string]bool)
+
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonName, _, ok := openApiFieldName(field)
+
+		if !ok {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("form"), ",")[0]
+
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema, ok := schema.Properties[jsonName]
+
+		if !ok {
+			continue
+		}
+
+		parameters = append(parameters, OpenApiParameter{
+			Name:     name,
+			In:       "query",
+			Required: required[jsonName],
+			Schema:   fieldSchema,
+		})
+	}
+
+	return parameters
+}
+
+func openApiRequirementName(requirement map[string][]string) string {
+	for name := range requirement {
+		return name
+	}
+
+	return ""
+}
+
+// NewOpenApiHandler serves the document of the definitions
+func NewOpenApiHandler(definitions *Definitions, settings *OpenApiSettings) HandlerWithoutInput {
+	return &openApiHandler{
+		document: BuildOpenApiDocument(definitions, settings),
+	}
+}
+
+type openApiHandler struct {
+	document *OpenApiDocument
+}
+
+func (h *openApiHandler) Handle(_ context.Context, _ *Request) (*Response, error) {
+	return NewJsonResponse(h.document), nil
+}
+
+type openApiGenerator struct {
+	kernel.EssentialModule
+
+	logger      mon.Logger
+	definitions *Definitions
+	settings    *OpenApiSettings
+}
+
+// NewOpenApiGenerator creates a module writing the document of the definitions to the file configured at
+// api.openapi.file. application.RunApiServer runs it for the openapi command instead of the api server.
+func NewOpenApiGenerator(definer Definer) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		definitions, err := definer(ctx, config, logger)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not define routes: %w", err)
+		}
+
+		settings := ReadOpenApiSettings(config)
+
+		return &openApiGenerator{
+			logger:      logger,
+			definitions: definitions,
+			settings:    settings,
+		}, nil
+	}
+}
+
+func (g *openApiGenerator) Run(_ context.Context) error {
+	doc := BuildOpenApiDocument(g.definitions, g.settings)
+	bytes, err := json.Marshal(doc)
+
+	if err != nil {
+		return fmt.Errorf("can not marshal openapi document: %w", err)
+	}
+
+	if err = ioutil.WriteFile(g.settings.File, bytes, 0644); err != nil {
+		return fmt.Errorf("can not write openapi document to %s: %w", g.settings.File, err)
+	}
+
+	g.logger.Infof("wrote openapi document with %d paths to %s", len(doc.Paths), g.settings.File)
+
+	return nil
+}
+
+func ReadOpenApiSettings(config cfg.Config) *OpenApiSettings {
+	settings := &OpenApiSettings{}
+	config.UnmarshalKey("api.openapi", settings)
+
+	return settings
+}
@@ -0,0 +1,364 @@
+package apiserver
+
+import (
+	"encoding"
+	"fmt"
+	"github.com/spf13/cast"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	typeTime            = reflect.TypeOf(time.Time{})
+	typeTextMarshaler   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	typeOpenApiByteList = reflect.TypeOf([]byte{})
+)
+
+type OpenApiSchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Nullable             bool                      `json:"nullable,omitempty"`
+	Properties           map[string]*OpenApiSchema `json:"properties,omitempty"`
+	AdditionalProperties *OpenApiSchema            `json:"additionalProperties,omitempty"`
+	Items                *OpenApiSchema            `json:"items,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	Enum                 []interface{}             `json:"enum,omitempty"`
+	Minimum              *float64                  `json:"minimum,omitempty"`
+	Maximum              *float64                  `json:"maximum,omitempty"`
+	MinLength            *int                      `json:"minLength,omitempty"`
+	MaxLength            *int                      `json:"maxLength,omitempty"`
+	MinItems             *int                      `json:"minItems,omitempty"`
+	MaxItems             *int                      `json:"maxItems,omitempty"`
+}
+
+// openApiSchemaBuilder creates schemas from go types. Named structs are added to the components and referenced.
+type openApiSchemaBuilder struct {
+	components map[string]*OpenApiSchema
+	names      map[reflect.Type]string
+}
+
+func newOpenApiSchemaBuilder() *openApiSchemaBuilder {
+	return &openApiSchemaBuilder{
+		components: make(map[string]*OpenApiSchema),
+		names:      make(map[reflect.Type]string),
+	}
+}
+
+// valueSchema uses the dynamic type of the value, so samples returned as interface{} are described correctly.
+// Slices of interfaces are described by their first element.
+func (b *openApiSchemaBuilder) valueSchema(value interface{}) *OpenApiSchema {
+	rv := reflect.ValueOf(value)
+
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return &OpenApiSchema{}
+		}
+
+		rv = rv.Elem()
+	}
+
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() == reflect.Interface {
+		items := &OpenApiSchema{}
+
+		if rv.Len() > 0 {
+			items = b.valueSchema(rv.Index(0).Interface())
+		}
+
+		return &OpenApiSchema{
+			Type:  "array",
+			Items: items,
+		}
+	}
+
+	if rv.Kind() == reflect.Struct && (rv.Type().Name() == "" || hasOpenApiInterfaceField(rv.Type())) {
+		return b.structValueSchema(rv)
+	}
+
+	return b.typeSchema(rv.Type())
+}
+
+// structValueSchema describes the struct inline using the values of its interface{} fields
+func (b *openApiSchemaBuilder) structValueSchema(rv reflect.Value) *OpenApiSchema {
+	schema := b.structSchema(rv.Type())
+
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+		name, _, ok := openApiFieldName(field)
+
+		if !ok || field.Type.Kind() != reflect.Interface {
+			continue
+		}
+
+		schema.Properties[name] = b.valueSchema(rv.Field(i).Interface())
+	}
+
+	return schema
+}
+
+func hasOpenApiInterfaceField(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Type.Kind() == reflect.Interface {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *openApiSchemaBuilder) typeSchema(t reflect.Type) *OpenApiSchema {
+	if t.Kind() == reflect.Ptr {
+		schema := b.typeSchema(t.Elem())
+
+		if schema.Ref == "" {
+			schema.Nullable = true
+		}
+
+		return schema
+	}
+
+	switch {
+	case t == typeTime:
+		return &OpenApiSchema{Type: "string", Format: "date-time"}
+	case t == typeOpenApiByteList:
+		return &OpenApiSchema{Type: "string", Format: "byte"}
+	case t.Implements(typeTextMarshaler) || reflect.PtrTo(t).Implements(typeTextMarshaler):
+		return &OpenApiSchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &OpenApiSchema{Type: "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &OpenApiSchema{Type: "integer", Format: "int32"}
+
+	case reflect.Int64:
+		return &OpenApiSchema{Type: "integer", Format: "int64"}
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &OpenApiSchema{Type: "integer", Format: "int64", Minimum: openApiFloat(0)}
+
+	case reflect.Float32:
+		return &OpenApiSchema{Type: "number", Format: "float"}
+
+	case reflect.Float64:
+		return &OpenApiSchema{Type: "number", Format: "double"}
+
+	case reflect.String:
+		return &OpenApiSchema{Type: "string"}
+
+	case reflect.Slice, reflect.Array:
+		return &OpenApiSchema{Type: "array", Items: b.typeSchema(t.Elem())}
+
+	case reflect.Map:
+		return &OpenApiSchema{Type: "object", AdditionalProperties: b.typeSchema(t.Elem())}
+
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+
+		return b.refSchema(t)
+	}
+
+	return &OpenApiSchema{}
+}
+
+func (b *openApiSchemaBuilder) refSchema(t reflect.Type) *OpenApiSchema {
+	name, ok := b.names[t]
+
+	if !ok {
+		name = b.componentName(t)
+		b.names[t] = name
+
+		// register the name first, so recursive types reference themselves instead of looping
+		b.components[name] = &OpenApiSchema{}
+		*b.components[name] = *b.structSchema(t)
+	}
+
+	return &OpenApiSchema{
+		Ref: fmt.Sprintf("#/components/schemas/%s", name),
+	}
+}
+
+func (b *openApiSchemaBuilder) componentName(t reflect.Type) string {
+	name := t.Name()
+
+	if _, taken := b.components[name]; !taken {
+		return name
+	}
+
+	pkg := t.PkgPath()
+
+	if idx := strings.LastIndex(pkg, "/"); idx != -1 {
+		pkg = pkg[idx+1:]
+	}
+
+	name = fmt.Sprintf("%s.%s", pkg, t.Name())
+
+	for i := 2; ; i++ {
+		if _, taken := b.components[name]; !taken {
+			return name
+		}
+
+		name = fmt.Sprintf("%s.%s%d", pkg, t.Name(), i)
+	}
+}
+
+func (b *openApiSchemaBuilder) structSchema(t reflect.Type) *OpenApiSchema {
+	schema := &OpenApiSchema{
+		Type:       "object",
+		Properties: make(map[string]*OpenApiSchema),
+	}
+
+	b.addStructFields(schema, t)
+
+	return schema
+}
+
+func (b *openApiSchemaBuilder) addStructFields(schema *OpenApiSchema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, tagged, ok := openApiFieldName(field)
+
+		if !ok {
+			continue
+		}
+
+		fieldType := field.Type
+
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		// embedded structs without a json name are flattened like encoding/json does
+		if field.Anonymous && !tagged && fieldType.Kind() == reflect.Struct {
+			b.addStructFields(schema, fieldType)
+			continue
+		}
+
+		fieldSchema := b.typeSchema(field.Type)
+		required := applyOpenApiValidation(fieldSchema, field)
+
+		schema.Properties[name] = fieldSchema
+
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// openApiFieldName returns the json name of the field, if it was set by a tag and if the field is serialized at all
+func openApiFieldName(field reflect.StructField) (string, bool, bool) {
+	if field.PkgPath != "" && !field.Anonymous {
+		return "", false, false
+	}
+
+	tag := field.Tag.Get("json")
+
+	if tag == "-" {
+		return "", false, false
+	}
+
+	name := strings.Split(tag, ",")[0]
+
+	if name == "" {
+		return field.Name, false, true
+	}
+
+	return name, true, true
+}
+
+// applyOpenApiValidation maps the binding and validate tags onto the schema and returns true if the field is required
+func applyOpenApiValidation(schema *OpenApiSchema, field reflect.StructField) bool {
+	required := false
+	rules := make([]string, 0)
+
+	for _, tag := range []string{"binding", "validate"} {
+		if value := field.Tag.Get(tag); value != "" {
+			rules = append(rules, strings.Split(value, ",")...)
+		}
+	}
+
+	for _, rule := range rules {
+		parts := strings.SplitN(rule, "=", 2)
+		name := parts[0]
+		param := ""
+
+		if len(parts) == 2 {
+			param = parts[1]
+		}
+
+		switch name {
+		case "required":
+			required = true
+
+		case "min", "gte":
+			setOpenApiBound(schema, param, true)
+
+		case "max", "lte":
+			setOpenApiBound(schema, param, false)
+
+		case "len":
+			setOpenApiBound(schema, param, true)
+			setOpenApiBound(schema, param, false)
+
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, value)
+			}
+
+		case "email":
+			schema.Format = "email"
+
+		case "url", "uri":
+			schema.Format = "uri"
+
+		case "uuid", "uuid4":
+			schema.Format = "uuid"
+		}
+	}
+
+	return required
+}
+
+func setOpenApiBound(schema *OpenApiSchema, param string, lower bool) {
+	switch schema.Type {
+	case "integer", "number":
+		value, err := cast.ToFloat64E(param)
+
+		if err != nil {
+			return
+		}
+
+		if lower {
+			schema.Minimum = &value
+		} else {
+			schema.Maximum = &value
+		}
+
+	case "string", "array":
+		value, err := cast.ToIntE(param)
+
+		if err != nil {
+			return
+		}
+
+		switch {
+		case schema.Type == "string" && lower:
+			schema.MinLength = &value
+		case schema.Type == "string":
+			schema.MaxLength = &value
+		case lower:
+			schema.MinItems = &value
+		default:
+			schema.MaxItems = &value
+		}
+	}
+}
+
+func openApiFloat(value float64) *float64 {
+	return &value
+}
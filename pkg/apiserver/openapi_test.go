@@ -0,0 +1,187 @@
+package apiserver_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type openApiAddress struct {
+	City string `json:"city" binding:"required"`
+}
+
+type openApiCreateInput struct {
+	Name    string          `json:"name" binding:"required,min=3,max=20"`
+	Status  string          `json:"status" binding:"oneof=active inactive"`
+	Age     *int            `json:"age" binding:"gte=0"`
+	Address *openApiAddress `json:"address"`
+	Secret  string          `json:"-"`
+}
+
+type openApiSearchInput struct {
+	Query string `form:"q" json:"query" binding:"required"`
+	Limit int    `form:"limit" json:"limit"`
+}
+
+type openApiOutput struct {
+	Id        uint      `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func TestBuildOpenApiDocument(t *testing.T) {
+	d := &apiserver.Definitions{}
+	d.UseSecurity(map[string]apiserver.SecurityScheme{
+		"apiKey": {
+			Type: "apiKey",
+			In:   "header",
+			Name: "X-API-KEY",
+		},
+	})
+
+	v1 := d.Group("/v1")
+	v1.POST("/item", nil).
+		WithSummary("create item").
+		WithInput(&openApiCreateInput{}).
+		WithOutput(&openApiOutput{})
+	v1.GET("/item/:id", nil).
+		WithOutput(&openApiOutput{})
+	v1.GET("/search", nil).
+		WithInput(&openApiSearchInput{})
+
+	doc := apiserver.BuildOpenApiDocument(d, &apiserver.OpenApiSettings{
+		Title:   "test",
+		Version: "1.0.0",
+	})
+
+	bytes, err := json.Marshal(doc)
+	assert.NoError(t, err)
+
+	expected := `{
+		"openapi": "3.0.3",
+		"info": {"title": "test", "version": "1.0.0"},
+		"paths": {
+			"/v1/item": {
+				"post": {
+					"summary": "create item",
+					"requestBody": {
+						"required": true,
+						"content": {"application/json": {"schema": {"$ref": "#/components/schemas/openApiCreateInput"}}}
+					},
+					"responses": {
+						"200": {
+							"description": "successful operation",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/openApiOutput"}}}
+						}
+					},
+					"security": [{"apiKey": []}]
+				}
+			},
+			"/v1/item/{id}": {
+				"get": {
+					"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}],
+					"responses": {
+						"200": {
+							"description": "successful operation",
+							"content": {"application/json": {"schema": {"$ref": "#/components/schemas/openApiOutput"}}}
+						}
+					},
+					"security": [{"apiKey": []}]
+				}
+			},
+			"/v1/search": {
+				"get": {
+					"parameters": [
+						{"name": "q", "in": "query", "required": true, "schema": {"type": "string"}},
+						{"name": "limit", "in": "query", "required": false, "schema": {"type": "integer", "format": "int32"}}
+					],
+					"responses": {"200": {"description": "successful operation"}},
+					"security": [{"apiKey": []}]
+				}
+			}
+		},
+		"components": {
+			"schemas": {
+				"openApiAddress": {
+					"type": "object",
+					"properties": {"city": {"type": "string"}},
+					"required": ["city"]
+				},
+				"openApiCreateInput": {
+					"type": "object",
+					"properties": {
+						"name": {"type": "string", "minLength": 3, "maxLength": 20},
+						"status": {"type": "string", "enum": ["active", "inactive"]},
+						"age": {"type": "integer", "format": "int32", "nullable": true, "minimum": 0},
+						"address": {"$ref": "#/components/schemas/openApiAddress"}
+					},
+					"required": ["name"]
+				},
+				"openApiOutput": {
+					"type": "object",
+					"properties": {
+						"id": {"type": "integer", "format": "int64", "minimum": 0},
+						"createdAt": {"type": "string", "format": "date-time"}
+					}
+				}
+			},
+			"securitySchemes": {
+				"apiKey": {"type": "apiKey", "in": "header", "name": "X-API-KEY"}
+			}
+		}
+	}`
+
+	assert.JSONEq(t, expected, string(bytes))
+}
+
+func TestBuildOpenApiDocument_InterfaceOutput(t *testing.T) {
+	d := &apiserver.Definitions{}
+	d.POST("/list", nil).WithOutput(&struct {
+		Total   int         `json:"total"`
+		Results interface{} `json:"results"`
+	}{
+		Results: []interface{}{&openApiOutput{}},
+	})
+
+	doc := apiserver.BuildOpenApiDocument(d, &apiserver.OpenApiSettings{})
+	schema := doc.Paths["/list"]["post"].Responses["200"].Content["application/json"].Schema
+
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, "array", schema.Properties["results"].Type)
+	assert.Equal(t, "#/components/schemas/openApiOutput", schema.Properties["results"].Items.Ref)
+}
+
+type openApiInputHandler struct {
+	input interface{}
+}
+
+func (h openApiInputHandler) GetInput() interface{} {
+	return h.input
+}
+
+func (h openApiInputHandler) Handle(_ context.Context, _ *apiserver.Request) (*apiserver.Response, error) {
+	return apiserver.NewStatusResponse(http.StatusOK), nil
+}
+
+func TestBuildOpenApiDocument_InputBinding(t *testing.T) {
+	d := &apiserver.Definitions{}
+	d.POST("/item", apiserver.CreateJsonHandler(openApiInputHandler{input: &openApiCreateInput{}})).
+		WithInput(&openApiCreateInput{})
+	d.POST("/search", apiserver.CreateQueryHandler(openApiInputHandler{input: &openApiSearchInput{}})).
+		WithInputBinding(&openApiSearchInput{}, binding.Query)
+	d.POST("/upload", apiserver.CreateMultiPartFormHandler(openApiInputHandler{input: &openApiAddress{}})).
+		WithInputBinding(&openApiAddress{}, binding.FormMultipart)
+	d.POST("/undocumented", apiserver.CreateJsonHandler(openApiInputHandler{input: &openApiCreateInput{}}))
+
+	doc := apiserver.BuildOpenApiDocument(d, &apiserver.OpenApiSettings{})
+
+	assert.Equal(t, "#/components/schemas/openApiCreateInput", doc.Paths["/item"]["post"].RequestBody.Content["application/json"].Schema.Ref)
+	assert.Nil(t, doc.Paths["/search"]["post"].RequestBody)
+	assert.Len(t, doc.Paths["/search"]["post"].Parameters, 2)
+	assert.Equal(t, "#/components/schemas/openApiAddress", doc.Paths["/upload"]["post"].RequestBody.Content["multipart/form-data"].Schema.Ref)
+	assert.Nil(t, doc.Paths["/undocumented"]["post"].RequestBody)
+}
@@ -59,8 +59,15 @@ func New(definer Definer) kernel.ModuleFactory {
 		}
 
 		router.Use(RecoveryWithSentry(logger))
+		router.Use(DebugContext(ReadDebugSettings(config)))
 		router.Use(LoggingMiddleware(logger))
 
+		openApiSettings := ReadOpenApiSettings(config)
+
+		if openApiSettings.Enabled {
+			router.GET(openApiSettings.Path, CreateHandler(NewOpenApiHandler(definitions, openApiSettings)))
+		}
+
 		buildRouter(definitions, router)
 
 		return NewWithInterfaces(logger, router, tracer, settings)
@@ -0,0 +1,437 @@
+package sql
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/encoding/base64"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"github.com/jinzhu/gorm"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	cursorDirectionNext = "next"
+	cursorDirectionPrev = "prev"
+	cursorTypeTime      = "time"
+)
+
+// Cursor selects a page by the opaque next or prev value of the previous response instead of an offset.
+// The first page is requested without a value. The rows are ordered by the order fields of the input and
+// the primary key, so the columns used for ordering should not contain null values.
+type Cursor struct {
+	Value     string `json:"value"`
+	Limit     int    `json:"limit"`
+	SkipTotal bool   `json:"skipTotal"`
+}
+
+// CursorPage contains the results of a query built with a cursor and the cursors of the surrounding pages.
+// Next and Prev are empty if there is no such page.
+type CursorPage struct {
+	Results interface{}
+	Next    string
+	Prev    string
+}
+
+type cursorColumn struct {
+	name      string
+	direction string
+}
+
+type cursorValue struct {
+	Type  string      `json:"t,omitempty"`
+	Value interface{} `json:"v"`
+}
+
+type cursorData struct {
+	Direction string        `json:"d"`
+	Columns   []string      `json:"c"`
+	Values    []cursorValue `json:"v"`
+}
+
+func (qb baseQueryBuilder) buildCursor(inp *Input, dbQb db.QueryBuilder) error {
+	if inp.Page != nil {
+		return fmt.Errorf("page and cursor can not be used together")
+	}
+
+	if inp.Cursor.Limit <= 0 {
+		return fmt.Errorf("the limit of the cursor has to be greater than 0")
+	}
+
+	columns, err := qb.getCursorColumns(inp)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := decodeCursor(inp.Cursor.Value, columns)
+
+	if err != nil {
+		return err
+	}
+
+	reverse := data != nil && data.Direction == cursorDirectionPrev
+
+	if data != nil {
+		query, args := buildCursorCondition(columns, data.Values, reverse)
+		dbQb.Where(query, args...)
+	}
+
+	for _, column := range columns {
+		direction := column.direction
+
+		if reverse {
+			direction = invertDirection(direction)
+		}
+
+		dbQb.OrderBy(column.name, direction)
+	}
+
+	// one additional row tells us if there is another page in the direction of the cursor
+	dbQb.Page(0, inp.Cursor.Limit+1)
+
+	return nil
+}
+
+// Paginate cuts the results of a query built with a cursor to the requested page and creates the cursors
+// to the next and previous page from the first and last row. The results have to be a slice of structs
+// containing the ordered columns as fields.
+func (qb baseQueryBuilder) Paginate(inp *Input, results interface{}) (*CursorPage, error) {
+	if inp.Cursor == nil {
+		return nil, fmt.Errorf("the input has no cursor")
+	}
+
+	columns, err := qb.getCursorColumns(inp)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := decodeCursor(inp.Cursor.Value, columns)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows := reflect.ValueOf(results)
+
+	if rows.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("the results have to be a slice but are of type %T", results)
+	}
+
+	hasMore := rows.Len() > inp.Cursor.Limit
+
+	if hasMore {
+		rows = rows.Slice(0, inp.Cursor.Limit)
+	}
+
+	page := &CursorPage{}
+
+	if data != nil && data.Direction == cursorDirectionPrev {
+		rows = reverseRows(rows)
+	}
+
+	page.Results = rows.Interface()
+
+	if rows.Len() == 0 {
+		return page, nil
+	}
+
+	hasNext := hasMore
+	hasPrev := data != nil
+
+	if data != nil && data.Direction == cursorDirectionPrev {
+		hasNext = true
+		hasPrev = hasMore
+	}
+
+	if hasNext {
+		if page.Next, err = encodeCursor(cursorDirectionNext, columns, rows.Index(rows.Len()-1)); err != nil {
+			return nil, err
+		}
+	}
+
+	if hasPrev {
+		if page.Prev, err = encodeCursor(cursorDirectionPrev, columns, rows.Index(0)); err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// getCursorColumns returns the columns of the order fields with the primary key as tie breaker
+func (qb baseQueryBuilder) getCursorColumns(inp *Input) ([]cursorColumn, error) {
+	columns := make([]cursorColumn, 0, len(inp.Order)+1)
+	hasPrimaryKey := false
+
+	for _, o := range inp.Order {
+		if _, ok := qb.mapping[o.Field]; !ok {
+			return nil, fmt.Errorf("no list mapping found for order field %s", o.Field)
+		}
+
+		direction := strings.ToUpper(o.Direction)
+
+		if direction == "" {
+			direction = "ASC"
+		}
+
+		if direction != "ASC" && direction != "DESC" {
+			return nil, fmt.Errorf("the direction %s of order field %s is not supported with a cursor", o.Direction, o.Field)
+		}
+
+		for _, name := range qb.mapping[o.Field].ColumnNames() {
+			hasPrimaryKey = hasPrimaryKey || name == qb.metadata.PrimaryKey
+
+			columns = append(columns, cursorColumn{
+				name:      name,
+				direction: direction,
+			})
+		}
+	}
+
+	if !hasPrimaryKey {
+		columns = append(columns, cursorColumn{
+			name:      qb.metadata.PrimaryKey,
+			direction: "ASC",
+		})
+	}
+
+	return columns, nil
+}
+
+// buildCursorCondition selects the rows after the values of the cursor: (c1 > ?) OR (c1 = ? AND c2 > ?) ...
+func buildCursorCondition(columns []cursorColumn, values []cursorValue, reverse bool) (string, []interface{}) {
+	stmts := make([]string, 0, len(columns))
+	args := make([]interface{}, 0)
+
+	for i, column := range columns {
+		parts := make([]string, 0, i+1)
+
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%s = ?", columns[j].name))
+			args = append(args, values[j].native())
+		}
+
+		operator := ">"
+
+		if (column.direction == "DESC") != reverse {
+			operator = "<"
+		}
+
+		parts = append(parts, fmt.Sprintf("%s %s ?", column.name, operator))
+		args = append(args, values[i].native())
+
+		stmts = append(stmts, fmt.Sprintf("(%s)", strings.Join(parts, " AND ")))
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(stmts, " OR ")), args
+}
+
+func encodeCursor(direction string, columns []cursorColumn, row reflect.Value) (string, error) {
+	data := cursorData{
+		Direction: direction,
+		Columns:   make([]string, 0, len(columns)),
+		Values:    make([]cursorValue, 0, len(columns)),
+	}
+
+	for _, column := range columns {
+		value, err := readColumnValue(row, column.name)
+
+		if err != nil {
+			return "", err
+		}
+
+		if value == nil {
+			return "", fmt.Errorf("the order column %s has a null value which can not be used in a cursor", column.name)
+		}
+
+		cv := cursorValue{
+			Value: value,
+		}
+
+		if t, ok := value.(time.Time); ok {
+			cv.Type = cursorTypeTime
+			cv.Value = t.Format(time.RFC3339Nano)
+		}
+
+		data.Columns = append(data.Columns, column.name)
+		data.Values = append(data.Values, cv)
+	}
+
+	bytes, err := json.Marshal(data)
+
+	if err != nil {
+		return "", fmt.Errorf("can not marshal cursor: %w", err)
+	}
+
+	return base64.EncodeToString(bytes), nil
+}
+
+// decodeCursor returns nil if the cursor has no value, which is the case for the first page
+func decodeCursor(value string, columns []cursorColumn) (*cursorData, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	bytes, err := base64.DecodeString(value)
+
+	if err != nil {
+		return nil, fmt.Errorf("the cursor is invalid: %w", err)
+	}
+
+	data := &cursorData{}
+
+	if err = json.Unmarshal(bytes, data); err != nil {
+		return nil, fmt.Errorf("the cursor is invalid: %w", err)
+	}
+
+	if data.Direction != cursorDirectionNext && data.Direction != cursorDirectionPrev {
+		return nil, fmt.Errorf("the cursor has the invalid direction %s", data.Direction)
+	}
+
+	if len(data.Columns) != len(columns) || len(data.Values) != len(columns) {
+		return nil, fmt.Errorf("the cursor does not match the order of the input")
+	}
+
+	for i, column := range columns {
+		if data.Columns[i] != column.name {
+			return nil, fmt.Errorf("the cursor does not match the order of the input")
+		}
+	}
+
+	return data, nil
+}
+
+func (v cursorValue) native() interface{} {
+	switch value := v.Value.(type) {
+	case string:
+		if v.Type != cursorTypeTime {
+			return value
+		}
+
+		if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			return t
+		}
+
+		return value
+
+	case float64:
+		// json numbers are decoded as floats, but most cursors are based on integer ids
+		if value == math.Trunc(value) && math.Abs(value) < 1<<53 {
+			return int64(value)
+		}
+
+		return value
+	}
+
+	return v.Value
+}
+
+// readColumnValue finds the field of the row which is stored in the given column, following gorm's naming
+func readColumnValue(row reflect.Value, column string) (interface{}, error) {
+	if idx := strings.LastIndex(column, "."); idx != -1 {
+		column = column[idx+1:]
+	}
+
+	column = strings.Trim(column, "`\"")
+
+	for row.Kind() == reflect.Ptr || row.Kind() == reflect.Interface {
+		if row.IsNil() {
+			return nil, fmt.Errorf("the results contain a nil row")
+		}
+
+		row = row.Elem()
+	}
+
+	if row.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("the results have to contain structs to build a cursor but contain %s", row.Type())
+	}
+
+	field, ok := findColumnField(row, column)
+
+	if !ok {
+		return nil, fmt.Errorf("there is no field for the order column %s in %s", column, row.Type())
+	}
+
+	for field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil, nil
+		}
+
+		field = field.Elem()
+	}
+
+	return field.Interface(), nil
+}
+
+func findColumnField(row reflect.Value, column string) (reflect.Value, bool) {
+	typ := row.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		structField := typ.Field(i)
+
+		if columnName(structField) == column {
+			return row.Field(i), true
+		}
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		structField := typ.Field(i)
+		field := row.Field(i)
+
+		if !structField.Anonymous {
+			continue
+		}
+
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+
+			field = field.Elem()
+		}
+
+		if field.Kind() != reflect.Struct {
+			continue
+		}
+
+		if value, ok := findColumnField(field, column); ok {
+			return value, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+func columnName(field reflect.StructField) string {
+	for _, setting := range strings.Split(field.Tag.Get("gorm"), ";") {
+		parts := strings.SplitN(setting, ":", 2)
+
+		if len(parts) == 2 && strings.EqualFold(strings.TrimSpace(parts[0]), "column") {
+			return strings.TrimSpace(parts[1])
+		}
+	}
+
+	return gorm.ToColumnName(field.Name)
+}
+
+func reverseRows(rows reflect.Value) reflect.Value {
+	reversed := reflect.MakeSlice(rows.Type(), rows.Len(), rows.Len())
+
+	for i := 0; i < rows.Len(); i++ {
+		reversed.Index(rows.Len() - 1 - i).Set(rows.Index(i))
+	}
+
+	return reversed
+}
+
+func invertDirection(direction string) string {
+	if direction == "DESC" {
+		return "ASC"
+	}
+
+	return "DESC"
+}
@@ -0,0 +1,170 @@
+package sql_test
+
+import (
+	"github.com/applike/gosoline/pkg/apiserver/sql"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type cursorModel struct {
+	db_repo.Model
+	Name *string
+}
+
+func getCursorMetadata() db_repo.Metadata {
+	return db_repo.Metadata{
+		TableName:  "tablename",
+		PrimaryKey: "id",
+		Mappings: db_repo.FieldMappings{
+			"id":   db_repo.NewFieldMapping("id"),
+			"name": db_repo.NewFieldMapping("name"),
+		},
+	}
+}
+
+func getCursorInput(value string) *sql.Input {
+	return &sql.Input{
+		Filter: sql.Filter{
+			Matches: []sql.FilterMatch{
+				{
+					Dimension: "name",
+					Operator:  "!=",
+					Values:    []interface{}{"foo"},
+				},
+			},
+			Bool: "and",
+		},
+		Order: []sql.Order{
+			{
+				Field:     "name",
+				Direction: "DESC",
+			},
+		},
+		Cursor: &sql.Cursor{
+			Value: value,
+			Limit: 2,
+		},
+	}
+}
+
+func TestListQueryBuilder_Build_CursorFirstPage(t *testing.T) {
+	lqb := sql.NewOrmQueryBuilder(getCursorMetadata())
+	qb, err := lqb.Build(getCursorInput(""))
+
+	assert.NoError(t, err)
+
+	expected := db_repo.NewQueryBuilder()
+	expected.Table("tablename")
+	expected.Where("(((name != ?)))", "foo")
+	expected.GroupBy("id")
+	expected.OrderBy("name", "DESC")
+	expected.OrderBy("id", "ASC")
+	expected.Page(0, 3)
+
+	assert.Equal(t, expected, qb)
+}
+
+func TestListQueryBuilder_Build_CursorWithPage(t *testing.T) {
+	inp := getCursorInput("")
+	inp.Page = &sql.Page{
+		Limit: 2,
+	}
+
+	lqb := sql.NewOrmQueryBuilder(getCursorMetadata())
+	_, err := lqb.Build(inp)
+
+	assert.EqualError(t, err, "page and cursor can not be used together")
+}
+
+func TestListQueryBuilder_Paginate(t *testing.T) {
+	lqb := sql.NewOrmQueryBuilder(getCursorMetadata())
+	results := []*cursorModel{
+		{Model: db_repo.Model{Id: mdl.Uint(3)}, Name: mdl.String("c")},
+		{Model: db_repo.Model{Id: mdl.Uint(2)}, Name: mdl.String("b")},
+		{Model: db_repo.Model{Id: mdl.Uint(1)}, Name: mdl.String("a")},
+	}
+
+	page, err := lqb.Paginate(getCursorInput(""), results)
+
+	assert.NoError(t, err)
+	assert.Equal(t, results[:2], page.Results)
+	assert.Empty(t, page.Prev)
+	assert.NotEmpty(t, page.Next)
+
+	qb, err := lqb.Build(getCursorInput(page.Next))
+
+	assert.NoError(t, err)
+
+	expected := db_repo.NewQueryBuilder()
+	expected.Table("tablename")
+	expected.Where("(((name != ?)))", "foo")
+	expected.GroupBy("id")
+	expected.Where("((name < ?) OR (name = ? AND id > ?))", "b", "b", int64(2))
+	expected.OrderBy("name", "DESC")
+	expected.OrderBy("id", "ASC")
+	expected.Page(0, 3)
+
+	assert.Equal(t, expected, qb)
+
+	page, err = lqb.Paginate(getCursorInput(page.Next), results[2:])
+
+	assert.NoError(t, err)
+	assert.Equal(t, results[2:], page.Results)
+	assert.NotEmpty(t, page.Prev)
+	assert.Empty(t, page.Next)
+}
+
+func TestListQueryBuilder_PaginatePrev(t *testing.T) {
+	lqb := sql.NewOrmQueryBuilder(getCursorMetadata())
+	results := []*cursorModel{
+		{Model: db_repo.Model{Id: mdl.Uint(3)}, Name: mdl.String("c")},
+		{Model: db_repo.Model{Id: mdl.Uint(2)}, Name: mdl.String("b")},
+		{Model: db_repo.Model{Id: mdl.Uint(1)}, Name: mdl.String("a")},
+	}
+
+	page, err := lqb.Paginate(getCursorInput(""), results)
+	assert.NoError(t, err)
+
+	page, err = lqb.Paginate(getCursorInput(page.Next), results[2:])
+	assert.NoError(t, err)
+
+	rqb := sql.NewRawQueryBuilder(getCursorMetadata())
+	qb, err := rqb.Build(getCursorInput(page.Prev))
+
+	assert.NoError(t, err)
+
+	query, args, err := qb.Builder.Columns("*").ToSql()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM tablename WHERE (((name != ?))) AND ((name > ?) OR (name = ? AND id < ?)) GROUP BY id ORDER BY name ASC, id DESC LIMIT 3 OFFSET 0", query)
+	assert.Equal(t, []interface{}{"foo", "a", "a", int64(1)}, args)
+
+	// the rows are queried in reverse order and restored by the pagination
+	page, err = rqb.Paginate(getCursorInput(page.Prev), []*cursorModel{results[1], results[0]})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []*cursorModel{results[0], results[1]}, page.Results)
+	assert.NotEmpty(t, page.Next)
+	assert.Empty(t, page.Prev)
+}
+
+func TestListQueryBuilder_Build_CursorOrderMismatch(t *testing.T) {
+	lqb := sql.NewOrmQueryBuilder(getCursorMetadata())
+	results := []*cursorModel{
+		{Model: db_repo.Model{Id: mdl.Uint(1)}, Name: mdl.String("a")},
+		{Model: db_repo.Model{Id: mdl.Uint(2)}, Name: mdl.String("b")},
+		{Model: db_repo.Model{Id: mdl.Uint(3)}, Name: mdl.String("c")},
+	}
+
+	page, err := lqb.Paginate(getCursorInput(""), results)
+	assert.NoError(t, err)
+
+	inp := getCursorInput(page.Next)
+	inp.Order = []sql.Order{}
+
+	_, err = lqb.Build(inp)
+
+	assert.EqualError(t, err, "the cursor does not match the order of the input")
+}
@@ -42,6 +42,7 @@ type Input struct {
 	Order   []Order  `json:"order"`
 	GroupBy []string `json:"groupBy"`
 	Page    *Page    `json:"page"`
+	Cursor  *Cursor  `json:"cursor"`
 }
 
 func NewInput() *Input {
@@ -134,6 +135,10 @@ func (qb baseQueryBuilder) build(inp *Input, dbQb db.QueryBuilder) error {
 	dbQb.Where(query, args...)
 	dbQb.GroupBy(groupBy...)
 
+	if inp.Cursor != nil {
+		return qb.buildCursor(inp, dbQb)
+	}
+
 	for _, o := range inp.Order {
 		if _, ok := qb.mapping[o.Field]; !ok {
 			return fmt.Errorf("no list mapping found for order field %s", o.Field)
@@ -33,6 +33,7 @@ func (a *App) addSetupOption(opt SetupOption) {
 func Default(options ...Option) kernel.Kernel {
 	defaults := []Option{
 		WithApiHealthCheck,
+		WithApiMetrics,
 		WithConfigErrorHandlers(defaultErrorHandler),
 		WithConfigFile("./config.dist.yml", "yml"),
 		WithConfigFileFlag,
@@ -40,17 +41,22 @@ func Default(options ...Option) kernel.Kernel {
 		WithConfigSanitizers(cfg.TimeSanitizer),
 		WithConfigServer,
 		WithConsumerMessagesPerRunnerMetrics,
+		WithDbReplicaSets,
 		WithKernelSettingsFromConfig,
 		WithLoggerFormat(mon.FormatGelfFields),
 		WithLoggerApplicationTag,
 		WithLoggerTagsFromConfig,
 		WithLoggerSettingsFromConfig,
+		WithLoggerOutputsFromConfig,
+		WithLoggerChannelLevels,
 		WithLoggerContextFieldsMessageEncoder(),
 		WithLoggerContextFieldsResolver(mon.ContextLoggerFieldsResolver),
 		WithLoggerMetricHook,
 		WithLoggerSentryHook(mon.SentryExtraConfigProvider, mon.SentryExtraEcsMetadataProvider),
 		WithMetricDaemon,
+		WithOutputCloser,
 		WithProducerDaemon,
+		WithTracerShutdown,
 		WithTracing,
 		WithUTCClock(true),
 	}
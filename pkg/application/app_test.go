@@ -48,6 +48,20 @@ func TestDefaultConfigParser(t *testing.T) {
 	})
 }
 
+func TestLoggerChannelLevels(t *testing.T) {
+	runTestApp(t, func() {
+		application.New(
+			application.WithConfigFile("./config.dist.yml", "yml"),
+			application.WithLoggerChannelLevels,
+		)
+	})
+
+	levels := mon.ProvideChannelLevelRegistry().All()
+	assert.Equal(t, map[string]mon.ChannelLevel{
+		"consumerCallback": {Level: mon.Debug},
+	}, levels)
+}
+
 func runTestApp(t *testing.T, f func()) {
 	oldDir, err := os.Getwd()
 	assert.NoError(t, err)
@@ -14,6 +14,8 @@ import (
 
 type ConfigServerSettings struct {
 	Port int `cfg:"port" default:"8070"`
+	// LogLevelsWritable allows to change the channel levels with PUT and DELETE on /log-levels, which is not protected
+	LogLevelsWritable bool `cfg:"log_levels_writable" default:"false"`
 }
 
 type ConfigServer struct {
@@ -22,6 +24,7 @@ type ConfigServer struct {
 
 	config   cfg.Config
 	logger   mon.Logger
+	levels   *mon.ChannelLevelRegistry
 	server   *http.Server
 	settings *ConfigServerSettings
 }
@@ -34,6 +37,7 @@ func NewConfigServer() kernel.ModuleFactory {
 		server := &ConfigServer{
 			config:   config,
 			logger:   logger.WithChannel("config-server"),
+			levels:   mon.ProvideChannelLevelRegistry(),
 			server:   &http.Server{},
 			settings: settings,
 		}
@@ -55,6 +59,11 @@ func (s *ConfigServer) Run(ctx context.Context) error {
 
 	handler := http.NewServeMux()
 	handler.HandleFunc("/", s.handleRead)
+	if s.settings.LogLevelsWritable {
+		handler.Handle("/log-levels", s.levels)
+	} else {
+		handler.Handle("/log-levels", s.levels.ReadOnlyHandler())
+	}
 
 	s.server.Handler = handler
 	go s.waitForStop(ctx)
@@ -6,6 +6,7 @@ import (
 	"github.com/applike/gosoline/pkg/apiserver"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/db"
 	"github.com/applike/gosoline/pkg/fixtures"
 	kernelPkg "github.com/applike/gosoline/pkg/kernel"
 	"github.com/applike/gosoline/pkg/mon"
@@ -33,6 +34,14 @@ type loggerSettings struct {
 	Format          string                 `cfg:"format" default:"console" validate:"required"`
 	TimestampFormat string                 `cfg:"timestamp_format" default:"15:04:05.000" validate:"required"`
 	Tags            map[string]interface{} `cfg:"tags"`
+	Outputs         []string               `cfg:"outputs"`
+	ChannelLevels   []channelLevelSettings `cfg:"channel_levels"`
+}
+
+// channelLevelSettings are a list instead of a map as the keys of maps in the config are lower cased
+type channelLevelSettings struct {
+	Channel string `cfg:"channel" validate:"required"`
+	Level   string `cfg:"level" validate:"required"`
 }
 
 func WithApiHealthCheck(app *App) {
@@ -42,6 +51,13 @@ func WithApiHealthCheck(app *App) {
 	})
 }
 
+func WithApiMetrics(app *App) {
+	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
+		kernel.AddFactory(apiserver.NewApiMetrics())
+		return nil
+	})
+}
+
 func WithConfigEnvKeyPrefix(prefix string) Option {
 	return func(app *App) {
 		app.addConfigOption(func(config cfg.GosoConf) error {
@@ -169,6 +185,23 @@ func WithLoggerApplicationTag(app *App) {
 	})
 }
 
+func WithLoggerChannelLevels(app *App) {
+	app.addLoggerOption(func(config cfg.GosoConf, logger mon.GosoLog) error {
+		settings := &loggerSettings{}
+		config.UnmarshalKey("mon.logger", settings)
+
+		registry := mon.ProvideChannelLevelRegistry()
+
+		for _, channelLevel := range settings.ChannelLevels {
+			if err := registry.Set(channelLevel.Channel, channelLevel.Level, 0); err != nil {
+				return errors.Wrap(err, "can not set the level of a channel from config")
+			}
+		}
+
+		return logger.Option(mon.WithChannelLevels(registry))
+	})
+}
+
 func WithLoggerContextFieldsMessageEncoder() Option {
 	return func(app *App) {
 		app.addLoggerOption(func(config cfg.GosoConf, logger mon.GosoLog) error {
@@ -246,6 +279,44 @@ func WithLoggerSentryHook(extraProvider ...mon.SentryExtraProvider) Option {
 	}
 }
 
+func WithLoggerOutputsFromConfig(app *App) {
+	outputs := make([]*mon.AsyncLogOutput, 0)
+
+	app.addLoggerOption(func(config cfg.GosoConf, logger mon.GosoLog) error {
+		settings := &loggerSettings{}
+		config.UnmarshalKey("mon.logger", settings)
+
+		for _, name := range settings.Outputs {
+			outputSettings := mon.ReadLogOutputSettings(config, name)
+			output, err := mon.NewLogOutput(config, logger, name, outputSettings)
+
+			if err != nil {
+				return errors.Wrapf(err, "can not create log output %s", name)
+			}
+
+			if err := logger.Option(mon.WithLogOutput(output, outputSettings.Level)); err != nil {
+				return errors.Wrapf(err, "can not add log output %s", name)
+			}
+
+			outputs = append(outputs, output)
+		}
+
+		return nil
+	})
+
+	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
+		if len(outputs) == 0 {
+			return nil
+		}
+
+		kernel.Add("log-outputs", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernelPkg.Module, error) {
+			return mon.NewLogOutputCloser(outputs...), nil
+		})
+
+		return nil
+	})
+}
+
 func WithLoggerSettingsFromConfig(app *App) {
 	app.addLoggerOption(func(config cfg.GosoConf, logger mon.GosoLog) error {
 		settings := &loggerSettings{}
@@ -280,6 +351,30 @@ func WithMetricDaemon(app *App) {
 	})
 }
 
+func WithTracerShutdown(app *App) {
+	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
+		kernel.Add("tracer-shutdown", func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernelPkg.Module, error) {
+			return tracing.NewTracerShutdown(config, logger)
+		})
+
+		return nil
+	})
+}
+
+func WithDbReplicaSets(app *App) {
+	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
+		kernel.AddFactory(db.ReplicaSetsModuleFactory)
+		return nil
+	})
+}
+
+func WithOutputCloser(app *App) {
+	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
+		kernel.AddFactory(stream.OutputCloserFactory)
+		return nil
+	})
+}
+
 func WithProducerDaemon(app *App) {
 	app.addKernelOption(func(config cfg.GosoConf, kernel kernelPkg.GosoKernel) error {
 		kernel.AddFactory(stream.ProducerDaemonFactory)
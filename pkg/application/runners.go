@@ -1,10 +1,44 @@
 package application
 
 import (
+	"flag"
+	"github.com/applike/gosoline/pkg/apiserver"
+	"github.com/applike/gosoline/pkg/cli"
 	"github.com/applike/gosoline/pkg/mdlsub"
 	"github.com/applike/gosoline/pkg/stream"
+	"io/ioutil"
+	"os"
 )
 
+const CommandOpenApi = "openapi"
+
+// RunApiServer runs the api server with the routes of the definer. Started with the openapi command, like
+// "./app openapi" or "./app -config config.yml openapi", it writes the OpenAPI document of the routes to the
+// file configured at api.openapi.file instead.
+func RunApiServer(definer apiserver.Definer, options ...Option) {
+	if getCommand() == CommandOpenApi {
+		cli.Run(apiserver.NewOpenApiGenerator(definer))
+		return
+	}
+
+	app := Default(options...)
+	app.Add("api", apiserver.New(definer))
+	app.Run()
+}
+
+// getCommand returns the first argument after the flags of the config
+func getCommand() string {
+	flags := flag.NewFlagSet("command", flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	flags.String("config", "", "path to a config file")
+
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		return ""
+	}
+
+	return flags.Arg(0)
+}
+
 func RunConsumer(callback stream.ConsumerCallbackFactory, options ...Option) {
 	consumers := stream.NewConsumerFactory(map[string]stream.ConsumerCallbackFactory{
 		"default": callback,
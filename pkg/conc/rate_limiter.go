@@ -0,0 +1,180 @@
+package conc
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/mon"
+	"time"
+)
+
+const (
+	RateLimiterTypeDdb    = "ddb"
+	RateLimiterTypeMemory = "memory"
+	RateLimiterTypeRedis  = "redis"
+
+	RateLimiterAlgorithmSlidingWindow = "sliding_window"
+	RateLimiterAlgorithmTokenBucket   = "token_bucket"
+
+	metricNameRateLimiterAllowed  = "RateLimiterAllowed"
+	metricNameRateLimiterDenied   = "RateLimiterDenied"
+	metricNameRateLimiterError    = "RateLimiterError"
+	metricNameRateLimiterWaitTime = "RateLimiterWaitTime"
+)
+
+//go:generate mockery -name RateLimiter
+type RateLimiter interface {
+	// Allow takes a token for the key if one is available without waiting for it
+	Allow(ctx context.Context, key string) (*RateLimitResult, error)
+	// Wait blocks until a token for the key was taken or the context is canceled
+	Wait(ctx context.Context, key string) error
+}
+
+//go:generate mockery -name RateLimiterBackend
+type RateLimiterBackend interface {
+	Take(ctx context.Context, key string) (*RateLimitResult, error)
+}
+
+type RateLimitResult struct {
+	Allowed bool
+	// RetryAfter is the time until a token might be available again if the call was not allowed
+	RetryAfter time.Duration
+}
+
+type RateLimiterSettings struct {
+	Type      string `cfg:"type" default:"redis"`
+	Algorithm string `cfg:"algorithm" default:"token_bucket"`
+	// Limit is the number of calls allowed per Interval
+	Limit    int           `cfg:"limit" default:"10"`
+	Interval time.Duration `cfg:"interval" default:"1s"`
+	// Burst is the capacity of the token bucket, the Limit is used if it is 0
+	Burst     int    `cfg:"burst" default:"0"`
+	Redis     string `cfg:"redis" default:"default"`
+	TableName string `cfg:"table_name" default:"{app_project}-{env}-{app_family}-rate-limits"`
+}
+
+type RateLimiterFactory func(config cfg.Config, logger mon.Logger, name string, settings *RateLimiterSettings) (RateLimiterBackend, error)
+
+var rateLimiterFactories = map[string]RateLimiterFactory{
+	RateLimiterTypeDdb:    NewDdbRateLimiterBackend,
+	RateLimiterTypeMemory: NewMemoryRateLimiterBackend,
+	RateLimiterTypeRedis:  NewRedisRateLimiterBackend,
+}
+
+type rateLimiter struct {
+	logger       mon.Logger
+	clock        clock.Clock
+	metricWriter mon.MetricWriter
+	backend      RateLimiterBackend
+	name         string
+}
+
+func NewRateLimiter(config cfg.Config, logger mon.Logger, name string) (RateLimiter, error) {
+	settings := ReadRateLimiterSettings(config, name)
+
+	if _, ok := rateLimiterFactories[settings.Type]; !ok {
+		return nil, fmt.Errorf("rate limiter with name %s has an unknown type %s", name, settings.Type)
+	}
+
+	backend, err := rateLimiterFactories[settings.Type](config, logger, name, settings)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create backend of rate limiter %s: %w", name, err)
+	}
+
+	metricWriter := mon.NewMetricDaemonWriter(getRateLimiterDefaultMetrics(name)...)
+
+	return NewRateLimiterWithInterfaces(logger, clock.Provider, metricWriter, backend, name), nil
+}
+
+func NewRateLimiterWithInterfaces(logger mon.Logger, clock clock.Clock, metricWriter mon.MetricWriter, backend RateLimiterBackend, name string) RateLimiter {
+	return &rateLimiter{
+		logger:       logger.WithChannel("rateLimiter"),
+		clock:        clock,
+		metricWriter: metricWriter,
+		backend:      backend,
+		name:         name,
+	}
+}
+
+func ReadRateLimiterSettings(config cfg.Config, name string) *RateLimiterSettings {
+	settings := &RateLimiterSettings{}
+	config.UnmarshalKey(GetRateLimiterConfigKey(name), settings)
+
+	return settings
+}
+
+func GetRateLimiterConfigKey(name string) string {
+	return fmt.Sprintf("conc.rate_limiter.%s", name)
+}
+
+func (l *rateLimiter) Allow(ctx context.Context, key string) (*RateLimitResult, error) {
+	result, err := l.backend.Take(ctx, key)
+
+	if err != nil {
+		l.writeMetric(metricNameRateLimiterError, mon.UnitCount, 1)
+		return nil, fmt.Errorf("can not take a token of rate limiter %s for key %s: %w", l.name, key, err)
+	}
+
+	if result.Allowed {
+		l.writeMetric(metricNameRateLimiterAllowed, mon.UnitCount, 1)
+	} else {
+		l.writeMetric(metricNameRateLimiterDenied, mon.UnitCount, 1)
+	}
+
+	return result, nil
+}
+
+func (l *rateLimiter) Wait(ctx context.Context, key string) error {
+	start := l.clock.Now()
+
+	for {
+		result, err := l.Allow(ctx, key)
+
+		if err != nil {
+			return err
+		}
+
+		if result.Allowed {
+			l.writeMetric(metricNameRateLimiterWaitTime, mon.UnitMillisecondsAverage, float64(l.clock.Now().Sub(start)/time.Millisecond))
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for rate limiter %s for key %s: %w", l.name, key, ctx.Err())
+		case <-l.clock.After(result.RetryAfter):
+		}
+	}
+}
+
+func (l *rateLimiter) writeMetric(metricName string, unit string, value float64) {
+	l.metricWriter.WriteOne(&mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		MetricName: metricName,
+		Dimensions: map[string]string{
+			"RateLimiter": l.name,
+		},
+		Unit:  unit,
+		Value: value,
+	})
+}
+
+func getRateLimiterDefaultMetrics(name string) mon.MetricData {
+	defaults := make(mon.MetricData, 0, 3)
+
+	for _, metricName := range []string{metricNameRateLimiterAllowed, metricNameRateLimiterDenied, metricNameRateLimiterError} {
+		defaults = append(defaults, &mon.MetricDatum{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricName,
+			Dimensions: map[string]string{
+				"RateLimiter": name,
+			},
+			Unit:  mon.UnitCount,
+			Value: 0.0,
+		})
+	}
+
+	return defaults
+}
@@ -0,0 +1,209 @@
+package conc
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// rateLimitState is the state of a single key. The token bucket stores the remaining tokens as value and the time of
+// the last refill as timestamp, the sliding window stores the calls of the current and previous window and the start
+// of the current window.
+type rateLimitState struct {
+	Value     float64
+	Previous  float64
+	Timestamp int64
+}
+
+type rateLimitAlgorithm interface {
+	// take updates the state at the given time in unix milliseconds
+	take(state *rateLimitState, now int64) *RateLimitResult
+	// luaScript takes a token atomically on a redis hash with the fields of the state
+	luaScript() (string, []interface{})
+	// ttl is the time after which an unused state is the same as a new one
+	ttl() time.Duration
+}
+
+func newRateLimitAlgorithm(settings *RateLimiterSettings) (rateLimitAlgorithm, error) {
+	if settings.Limit <= 0 || settings.Interval <= 0 {
+		return nil, fmt.Errorf("the limit and interval of a rate limiter have to be positive")
+	}
+
+	switch settings.Algorithm {
+	case RateLimiterAlgorithmTokenBucket:
+		burst := settings.Burst
+
+		if burst == 0 {
+			burst = settings.Limit
+		}
+
+		return &tokenBucket{
+			burst: float64(burst),
+			rate:  float64(settings.Limit) / float64(settings.Interval/time.Millisecond),
+		}, nil
+	case RateLimiterAlgorithmSlidingWindow:
+		return &slidingWindow{
+			limit:  float64(settings.Limit),
+			window: int64(settings.Interval / time.Millisecond),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown rate limiter algorithm %s", settings.Algorithm)
+}
+
+func retryAfter(millis float64) *RateLimitResult {
+	return &RateLimitResult{
+		Allowed:    false,
+		RetryAfter: time.Duration(math.Max(1, math.Ceil(millis))) * time.Millisecond,
+	}
+}
+
+type tokenBucket struct {
+	burst float64
+	// rate is the number of tokens added per millisecond
+	rate float64
+}
+
+func (a *tokenBucket) take(state *rateLimitState, now int64) *RateLimitResult {
+	if state.Timestamp == 0 {
+		state.Value = a.burst
+		state.Timestamp = now
+	}
+
+	elapsed := math.Max(0, float64(now-state.Timestamp))
+	state.Value = math.Min(a.burst, state.Value+elapsed*a.rate)
+	state.Timestamp = now
+
+	if state.Value < 1 {
+		return retryAfter((1 - state.Value) / a.rate)
+	}
+
+	state.Value--
+
+	return &RateLimitResult{
+		Allowed: true,
+	}
+}
+
+func (a *tokenBucket) luaScript() (string, []interface{}) {
+	return luaTokenBucket, []interface{}{a.burst, a.rate, int64(a.ttl() / time.Millisecond)}
+}
+
+func (a *tokenBucket) ttl() time.Duration {
+	return time.Duration(math.Ceil(a.burst/a.rate)) * time.Millisecond
+}
+
+type slidingWindow struct {
+	limit float64
+	// window is the length of a window in milliseconds
+	window int64
+}
+
+func (a *slidingWindow) take(state *rateLimitState, now int64) *RateLimitResult {
+	windowStart := now - now%a.window
+
+	if state.Timestamp != windowStart {
+		if state.Timestamp == windowStart-a.window {
+			state.Previous = state.Value
+		} else {
+			state.Previous = 0
+		}
+
+		state.Value = 0
+		state.Timestamp = windowStart
+	}
+
+	elapsed := float64(now - windowStart)
+	weight := 1 - elapsed/float64(a.window)
+
+	if state.Previous*weight+state.Value < a.limit {
+		state.Value++
+
+		return &RateLimitResult{
+			Allowed: true,
+		}
+	}
+
+	if state.Value >= a.limit {
+		return retryAfter(float64(a.window) - elapsed)
+	}
+
+	// the weight of the previous window has to drop until the calls of the current window fit in
+	return retryAfter((1-(a.limit-state.Value)/state.Previous)*float64(a.window) - elapsed)
+}
+
+func (a *slidingWindow) luaScript() (string, []interface{}) {
+	return luaSlidingWindow, []interface{}{a.limit, a.window, int64(a.ttl() / time.Millisecond)}
+}
+
+func (a *slidingWindow) ttl() time.Duration {
+	return 2 * time.Duration(a.window) * time.Millisecond
+}
+
+// KEYS[1] is the hash of the state, ARGV are the current time, burst, rate and ttl in milliseconds
+const luaTokenBucket = `
+local now = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local rate = tonumber(ARGV[3])
+local state = redis.call("HMGET", KEYS[1], "value", "timestamp")
+local tokens = tonumber(state[1]) or burst
+local timestamp = tonumber(state[2]) or now
+
+tokens = math.min(burst, tokens + math.max(0, now - timestamp) * rate)
+
+local allowed = 0
+local retryAfter = 0
+
+if tokens < 1 then
+	retryAfter = math.max(1, math.ceil((1 - tokens) / rate))
+else
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call("HMSET", KEYS[1], "value", tostring(tokens), "timestamp", now)
+redis.call("PEXPIRE", KEYS[1], ARGV[4])
+
+return {allowed, retryAfter}
+`
+
+// KEYS[1] is the hash of the state, ARGV are the current time, limit, window and ttl in milliseconds
+const luaSlidingWindow = `
+local now = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local window = tonumber(ARGV[3])
+local windowStart = now - now % window
+local state = redis.call("HMGET", KEYS[1], "value", "previous", "timestamp")
+local current = tonumber(state[1]) or 0
+local previous = tonumber(state[2]) or 0
+local timestamp = tonumber(state[3]) or windowStart
+
+if timestamp ~= windowStart then
+	if timestamp == windowStart - window then
+		previous = current
+	else
+		previous = 0
+	end
+
+	current = 0
+end
+
+local elapsed = now - windowStart
+local weight = 1 - elapsed / window
+local allowed = 0
+local retryAfter = 0
+
+if previous * weight + current < limit then
+	current = current + 1
+	allowed = 1
+elseif current >= limit then
+	retryAfter = math.max(1, math.ceil(window - elapsed))
+else
+	retryAfter = math.max(1, math.ceil((1 - (limit - current) / previous) * window - elapsed))
+end
+
+redis.call("HMSET", KEYS[1], "value", current, "previous", previous, "timestamp", windowStart)
+redis.call("PEXPIRE", KEYS[1], ARGV[4])
+
+return {allowed, retryAfter}
+`
@@ -0,0 +1,135 @@
+package conc
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/ddb"
+	"github.com/applike/gosoline/pkg/exec"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/mon"
+	"time"
+)
+
+const ddbRateLimiterMaxAttempts = 10
+
+type DdbRateLimitItem struct {
+	Key       string  `json:"key" ddb:"key=hash"`
+	Value     float64 `json:"value"`
+	Previous  float64 `json:"previous"`
+	Timestamp int64   `json:"timestamp"`
+	Version   int64   `json:"version" ddb:"version=enabled"`
+	Ttl       int64   `json:"ttl" ddb:"ttl=enabled"`
+}
+
+// ddbRateLimiterBackend updates the state of a key with a conditional write on its version and retries on conflicts
+type ddbRateLimiterBackend struct {
+	repository ddb.Repository
+	clock      clock.Clock
+	algorithm  rateLimitAlgorithm
+	prefix     string
+}
+
+func NewDdbRateLimiterBackend(config cfg.Config, logger mon.Logger, name string, settings *RateLimiterSettings) (RateLimiterBackend, error) {
+	namingFactory := func(_ mdl.ModelId) string {
+		return settings.TableName
+	}
+
+	repository, err := ddb.NewRepository(config, logger, &ddb.Settings{
+		ModelId:        mdl.ModelId{},
+		NamingStrategy: namingFactory,
+		Backoff: exec.BackoffSettings{
+			Enabled:             true,
+			Blocking:            false,
+			InitialInterval:     100 * time.Millisecond,
+			RandomizationFactor: 0.5,
+			Multiplier:          1.5,
+			MaxInterval:         time.Second,
+			MaxElapsedTime:      10 * time.Second,
+		},
+		DisableTracing: true,
+		Main: ddb.MainSettings{
+			Model:       DdbRateLimitItem{},
+			BillingMode: ddb.BillingModePayPerRequest,
+		},
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create ddb repository: %w", err)
+	}
+
+	return NewDdbRateLimiterBackendWithInterfaces(repository, clock.Provider, name, settings)
+}
+
+func NewDdbRateLimiterBackendWithInterfaces(repository ddb.Repository, clock clock.Clock, name string, settings *RateLimiterSettings) (RateLimiterBackend, error) {
+	algorithm, err := newRateLimitAlgorithm(settings)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &ddbRateLimiterBackend{
+		repository: repository,
+		clock:      clock,
+		algorithm:  algorithm,
+		prefix:     fmt.Sprintf("%s-", name),
+	}, nil
+}
+
+func (b *ddbRateLimiterBackend) Take(ctx context.Context, key string) (*RateLimitResult, error) {
+	for i := 0; i < ddbRateLimiterMaxAttempts; i++ {
+		result, err := b.tryTake(ctx, b.prefix+key)
+
+		if ddb.IsVersionConflictError(err) {
+			continue
+		}
+
+		return result, err
+	}
+
+	return nil, fmt.Errorf("the state of key %s was changed concurrently %d times", key, ddbRateLimiterMaxAttempts)
+}
+
+func (b *ddbRateLimiterBackend) tryTake(ctx context.Context, key string) (*RateLimitResult, error) {
+	item := &DdbRateLimitItem{
+		Key: key,
+	}
+
+	qb := b.repository.GetItemBuilder().WithHash(key).WithConsistentRead(true)
+	res, err := b.repository.GetItem(ctx, qb, item)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not read the state of key %s: %w", key, err)
+	}
+
+	now := b.clock.Now()
+
+	if res.IsFound && item.Ttl < now.Unix() {
+		// expired items are deleted with a delay, so they might still be returned
+		item.Value, item.Previous, item.Timestamp = 0, 0, 0
+	}
+
+	state := &rateLimitState{
+		Value:     item.Value,
+		Previous:  item.Previous,
+		Timestamp: item.Timestamp,
+	}
+
+	result := b.algorithm.take(state, now.UnixNano()/int64(time.Millisecond))
+
+	if !result.Allowed {
+		return result, nil
+	}
+
+	item.Value = state.Value
+	item.Previous = state.Previous
+	item.Timestamp = state.Timestamp
+	item.Ttl = now.Add(b.algorithm.ttl()).Unix() + 1
+
+	if _, err = b.repository.PutItem(ctx, nil, item); err != nil {
+		return nil, fmt.Errorf("can not write the state of key %s: %w", key, err)
+	}
+
+	return result, nil
+}
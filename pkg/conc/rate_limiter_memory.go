@@ -0,0 +1,49 @@
+package conc
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/mon"
+	"sync"
+	"time"
+)
+
+// memoryRateLimiterBackend keeps the state in the process, so every instance of an application has its own limit
+type memoryRateLimiterBackend struct {
+	lck       sync.Mutex
+	clock     clock.Clock
+	algorithm rateLimitAlgorithm
+	states    map[string]*rateLimitState
+}
+
+func NewMemoryRateLimiterBackend(_ cfg.Config, _ mon.Logger, _ string, settings *RateLimiterSettings) (RateLimiterBackend, error) {
+	return NewMemoryRateLimiterBackendWithInterfaces(clock.Provider, settings)
+}
+
+func NewMemoryRateLimiterBackendWithInterfaces(clock clock.Clock, settings *RateLimiterSettings) (RateLimiterBackend, error) {
+	algorithm, err := newRateLimitAlgorithm(settings)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &memoryRateLimiterBackend{
+		clock:     clock,
+		algorithm: algorithm,
+		states:    make(map[string]*rateLimitState),
+	}, nil
+}
+
+func (b *memoryRateLimiterBackend) Take(_ context.Context, key string) (*RateLimitResult, error) {
+	b.lck.Lock()
+	defer b.lck.Unlock()
+
+	if _, ok := b.states[key]; !ok {
+		b.states[key] = &rateLimitState{}
+	}
+
+	now := b.clock.Now().UnixNano() / int64(time.Millisecond)
+
+	return b.algorithm.take(b.states[key], now), nil
+}
@@ -0,0 +1,70 @@
+package conc
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/redis"
+	"time"
+)
+
+type redisRateLimiterBackend struct {
+	client    redis.Client
+	clock     clock.Clock
+	algorithm rateLimitAlgorithm
+	prefix    string
+}
+
+func NewRedisRateLimiterBackend(config cfg.Config, logger mon.Logger, name string, settings *RateLimiterSettings) (RateLimiterBackend, error) {
+	client := redis.NewClient(config, logger, settings.Redis)
+
+	return NewRedisRateLimiterBackendWithInterfaces(client, clock.Provider, name, settings)
+}
+
+func NewRedisRateLimiterBackendWithInterfaces(client redis.Client, clock clock.Clock, name string, settings *RateLimiterSettings) (RateLimiterBackend, error) {
+	algorithm, err := newRateLimitAlgorithm(settings)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &redisRateLimiterBackend{
+		client:    client,
+		clock:     clock,
+		algorithm: algorithm,
+		prefix:    fmt.Sprintf("rate-limiter-%s-", name),
+	}, nil
+}
+
+func (b *redisRateLimiterBackend) Take(_ context.Context, key string) (*RateLimitResult, error) {
+	script, args := b.algorithm.luaScript()
+	now := b.clock.Now().UnixNano() / int64(time.Millisecond)
+
+	reply, err := b.client.Eval(script, []string{b.prefix + key}, append([]interface{}{now}, args...)...)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not execute rate limiter script: %w", err)
+	}
+
+	values, ok := reply.([]interface{})
+
+	if !ok || len(values) != 2 {
+		return nil, fmt.Errorf("the rate limiter script returned an unexpected reply %v", reply)
+	}
+
+	allowed, okAllowed := values[0].(int64)
+	retryAfter, okRetryAfter := values[1].(int64)
+
+	if !okAllowed || !okRetryAfter {
+		return nil, fmt.Errorf("the rate limiter script returned an unexpected reply %v", reply)
+	}
+
+	result := &RateLimitResult{
+		Allowed:    allowed == 1,
+		RetryAfter: time.Duration(retryAfter) * time.Millisecond,
+	}
+
+	return result, nil
+}
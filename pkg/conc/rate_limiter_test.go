@@ -0,0 +1,184 @@
+package conc_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/alicebob/miniredis"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/conc"
+	concMocks "github.com/applike/gosoline/pkg/conc/mocks"
+	"github.com/applike/gosoline/pkg/ddb"
+	ddbMocks "github.com/applike/gosoline/pkg/ddb/mocks"
+	"github.com/applike/gosoline/pkg/exec"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/redis"
+	baseRedis "github.com/go-redis/redis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+type rateLimiterStep struct {
+	advance time.Duration
+	allowed bool
+	retry   time.Duration
+}
+
+var rateLimiterScenarios = map[string]struct {
+	settings *conc.RateLimiterSettings
+	steps    []rateLimiterStep
+}{
+	conc.RateLimiterAlgorithmTokenBucket: {
+		settings: &conc.RateLimiterSettings{
+			Algorithm: conc.RateLimiterAlgorithmTokenBucket,
+			Limit:     2,
+			Interval:  time.Second,
+		},
+		steps: []rateLimiterStep{
+			{allowed: true},
+			{allowed: true},
+			{allowed: false, retry: 500 * time.Millisecond},
+			{advance: 250 * time.Millisecond, allowed: false, retry: 250 * time.Millisecond},
+			{advance: 250 * time.Millisecond, allowed: true},
+			{advance: 2 * time.Second, allowed: true},
+			{allowed: true},
+			{allowed: false, retry: 500 * time.Millisecond},
+		},
+	},
+	conc.RateLimiterAlgorithmSlidingWindow: {
+		settings: &conc.RateLimiterSettings{
+			Algorithm: conc.RateLimiterAlgorithmSlidingWindow,
+			Limit:     2,
+			Interval:  time.Second,
+		},
+		steps: []rateLimiterStep{
+			{allowed: true},
+			{allowed: true},
+			{allowed: false, retry: time.Second},
+			{advance: time.Second, allowed: false, retry: time.Millisecond},
+			{advance: 500 * time.Millisecond, allowed: true},
+			{allowed: false, retry: time.Millisecond},
+			{allowed: true, advance: 250 * time.Millisecond},
+			{allowed: false, retry: 250 * time.Millisecond},
+			{advance: 2 * time.Second, allowed: true},
+		},
+	},
+}
+
+func runRateLimiterScenarios(t *testing.T, newBackend func(clock clock.Clock, settings *conc.RateLimiterSettings) conc.RateLimiterBackend) {
+	for name, scenario := range rateLimiterScenarios {
+		t.Run(name, func(t *testing.T) {
+			fakeClock := clock.NewFakeClockAt(time.Unix(1600000000, 0))
+			backend := newBackend(fakeClock, scenario.settings)
+
+			for i, step := range scenario.steps {
+				fakeClock.Advance(step.advance)
+
+				result, err := backend.Take(context.Background(), "key")
+
+				assert.NoError(t, err)
+				assert.Equal(t, &conc.RateLimitResult{Allowed: step.allowed, RetryAfter: step.retry}, result, fmt.Sprintf("step %d", i))
+			}
+		})
+	}
+}
+
+func TestMemoryRateLimiterBackend(t *testing.T) {
+	runRateLimiterScenarios(t, func(clock clock.Clock, settings *conc.RateLimiterSettings) conc.RateLimiterBackend {
+		backend, err := conc.NewMemoryRateLimiterBackendWithInterfaces(clock, settings)
+		assert.NoError(t, err)
+
+		return backend
+	})
+}
+
+func TestRedisRateLimiterBackend(t *testing.T) {
+	server, err := miniredis.Run()
+	assert.NoError(t, err)
+	defer server.Close()
+
+	runRateLimiterScenarios(t, func(clock clock.Clock, settings *conc.RateLimiterSettings) conc.RateLimiterBackend {
+		server.FlushAll()
+
+		baseClient := baseRedis.NewClient(&baseRedis.Options{
+			Addr: server.Addr(),
+		})
+		client := redis.NewClientWithInterfaces(monMocks.NewLoggerMockedAll(), baseClient, exec.NewDefaultExecutor(), &redis.Settings{})
+
+		backend, err := conc.NewRedisRateLimiterBackendWithInterfaces(client, clock, "test", settings)
+		assert.NoError(t, err)
+
+		return backend
+	})
+}
+
+func TestDdbRateLimiterBackend_VersionConflict(t *testing.T) {
+	fakeClock := clock.NewFakeClockAt(time.Unix(1600000000, 0))
+	repo := new(ddbMocks.Repository)
+
+	qb := new(ddbMocks.GetItemBuilder)
+	qb.On("WithHash", "test-key").Return(qb)
+	qb.On("WithConsistentRead", true).Return(qb)
+	repo.On("GetItemBuilder").Return(qb)
+	repo.On("GetItem", mock.Anything, qb, &conc.DdbRateLimitItem{Key: "test-key"}).Return(&ddb.GetItemResult{}, nil).Twice()
+
+	written := &conc.DdbRateLimitItem{
+		Key:       "test-key",
+		Value:     1,
+		Timestamp: 1600000000000,
+		Ttl:       1600000002,
+	}
+	conflict := ddb.NewVersionConflictError("rate-limits", 0, ddb.ErrorConditionalCheckFailed)
+	repo.On("PutItem", mock.Anything, nil, written).Return(&ddb.PutItemResult{ConditionalCheckFailed: true}, conflict).Once()
+	repo.On("PutItem", mock.Anything, nil, written).Return(&ddb.PutItemResult{}, nil).Once()
+
+	backend, err := conc.NewDdbRateLimiterBackendWithInterfaces(repo, fakeClock, "test", &conc.RateLimiterSettings{
+		Algorithm: conc.RateLimiterAlgorithmTokenBucket,
+		Limit:     2,
+		Interval:  time.Second,
+	})
+	assert.NoError(t, err)
+
+	result, err := backend.Take(context.Background(), "key")
+
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	repo.AssertExpectations(t)
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	fakeClock := clock.NewFakeClockAt(time.Unix(1600000000, 0))
+	metricWriter := monMocks.NewMetricWriterMockedAll()
+	backend := new(concMocks.RateLimiterBackend)
+	backend.On("Take", mock.Anything, "key").Return(&conc.RateLimitResult{RetryAfter: time.Second}, nil).Once()
+	backend.On("Take", mock.Anything, "key").Return(&conc.RateLimitResult{Allowed: true}, nil).Once()
+
+	limiter := conc.NewRateLimiterWithInterfaces(monMocks.NewLoggerMockedAll(), fakeClock, metricWriter, backend, "test")
+	done := make(chan error)
+
+	go func() {
+		done <- limiter.Wait(context.Background(), "key")
+	}()
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(time.Second)
+
+	assert.NoError(t, <-done)
+	backend.AssertExpectations(t)
+}
+
+func TestRateLimiter_WaitCanceled(t *testing.T) {
+	backend := new(concMocks.RateLimiterBackend)
+	backend.On("Take", mock.Anything, "key").Return(&conc.RateLimitResult{RetryAfter: time.Hour}, nil).Once()
+
+	limiter := conc.NewRateLimiterWithInterfaces(monMocks.NewLoggerMockedAll(), clock.NewRealClock(), monMocks.NewMetricWriterMockedAll(), backend, "test")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := limiter.Wait(ctx, "key")
+
+	assert.EqualError(t, err, "stopped waiting for rate limiter test for key key: context canceled")
+	backend.AssertExpectations(t)
+}
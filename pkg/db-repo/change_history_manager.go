@@ -9,7 +9,8 @@ import (
 	"strings"
 )
 
-type changeHistoryManagerSettings struct {
+// ChangeHistorySettings are read from change_history
+type ChangeHistorySettings struct {
 	ChangeAuthorField string `cfg:"change_author_column"`
 	TableSuffix       string `cfg:"table_suffix" default:"history"`
 }
@@ -17,7 +18,7 @@ type changeHistoryManagerSettings struct {
 type changeHistoryManager struct {
 	orm           *gorm.DB
 	logger        mon.Logger
-	settings      *changeHistoryManagerSettings
+	settings      *ChangeHistorySettings
 	model         ModelBased
 	originalTable *tableMetadata
 	historyTable  *tableMetadata
@@ -30,13 +31,12 @@ func newChangeHistoryManager(config cfg.Config, logger mon.Logger, model ModelBa
 		return nil, fmt.Errorf("can not create orm: %w", err)
 	}
 
-	settings := &changeHistoryManagerSettings{}
-	config.UnmarshalKey("change_history", settings)
+	settings := ReadChangeHistorySettings(config)
 
 	return newChangeHistoryManagerWithInterfaces(logger, orm, model, settings), nil
 }
 
-func newChangeHistoryManagerWithInterfaces(logger mon.Logger, orm *gorm.DB, model ModelBased, settings *changeHistoryManagerSettings) *changeHistoryManager {
+func newChangeHistoryManagerWithInterfaces(logger mon.Logger, orm *gorm.DB, model ModelBased, settings *ChangeHistorySettings) *changeHistoryManager {
 	statements := make([]string, 0)
 
 	logger = logger.WithChannel("change_history_manager").WithFields(mon.Fields{
@@ -107,6 +107,11 @@ func (c *changeHistoryManager) appendStatement(statement string) {
 }
 
 func (c *changeHistoryManager) dropHistoryTriggers() {
+	if c.isPostgres() {
+		c.dropPostgresHistoryTriggers()
+		return
+	}
+
 	triggers := []string{
 		c.originalTable.tableName + "_ai",
 		c.originalTable.tableName + "_au",
@@ -120,6 +125,11 @@ func (c *changeHistoryManager) dropHistoryTriggers() {
 }
 
 func (c *changeHistoryManager) createHistoryTriggers() {
+	if c.isPostgres() {
+		c.createPostgresHistoryTriggers()
+		return
+	}
+
 	const NewRecord = "NEW"
 	const OldRecord = "OLD"
 
@@ -196,6 +206,11 @@ func (c *changeHistoryManager) rowUpdatedCondition() string {
 	var conditions []string
 	for _, columnName := range columnNames {
 		condition := fmt.Sprintf("NOT (OLD.%s <=> NEW.%s)", columnName, columnName)
+
+		if c.isPostgres() {
+			condition = fmt.Sprintf("OLD.%s IS DISTINCT FROM NEW.%s", columnName, columnName)
+		}
+
 		conditions = append(conditions, condition)
 	}
 	return strings.Join(conditions, " OR ")
@@ -233,3 +248,10 @@ func (c *changeHistoryManager) execute() error {
 
 	return nil
 }
+
+func ReadChangeHistorySettings(config cfg.Config) *ChangeHistorySettings {
+	settings := &ChangeHistorySettings{}
+	config.UnmarshalKey("change_history", settings)
+
+	return settings
+}
@@ -0,0 +1,70 @@
+package db_repo
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/db"
+)
+
+// postgres triggers execute functions, so every trigger of the mysql setup gets a function of the same name
+
+func (c *changeHistoryManager) isPostgres() bool {
+	return c.orm.Dialect().GetName() == db.DriverPostgres
+}
+
+func (c *changeHistoryManager) postgresTriggers() map[string]string {
+	return map[string]string{
+		c.originalTable.tableName + "_ai":   c.originalTable.tableNameQuoted,
+		c.originalTable.tableName + "_au":   c.originalTable.tableNameQuoted,
+		c.originalTable.tableName + "_bd":   c.originalTable.tableNameQuoted,
+		c.historyTable.tableName + "_revai": c.historyTable.tableNameQuoted,
+	}
+}
+
+func (c *changeHistoryManager) dropPostgresHistoryTriggers() {
+	for trigger, table := range c.postgresTriggers() {
+		c.appendStatement(fmt.Sprintf(`DROP TRIGGER IF EXISTS %s ON %s`, trigger, table))
+		c.appendStatement(fmt.Sprintf(`DROP FUNCTION IF EXISTS %s()`, trigger))
+	}
+}
+
+func (c *changeHistoryManager) createPostgresHistoryTriggers() {
+	const NewRecord = "NEW"
+	const OldRecord = "OLD"
+
+	c.createPostgresTrigger(c.originalTable.tableName+"_ai", "AFTER INSERT", c.originalTable.tableNameQuoted, fmt.Sprintf(`%s WHERE %s; RETURN NULL;`,
+		c.insertHistoryEntry("insert", true),
+		c.primaryKeysMatchCondition(NewRecord),
+	))
+
+	c.createPostgresTrigger(c.originalTable.tableName+"_au", "AFTER UPDATE", c.originalTable.tableNameQuoted, fmt.Sprintf(`%s WHERE %s AND (%s); RETURN NULL;`,
+		c.insertHistoryEntry("update", true),
+		c.primaryKeysMatchCondition(NewRecord),
+		c.rowUpdatedCondition(),
+	))
+
+	c.createPostgresTrigger(c.originalTable.tableName+"_bd", "BEFORE DELETE", c.originalTable.tableNameQuoted, fmt.Sprintf(`%s WHERE %s; RETURN OLD;`,
+		c.insertHistoryEntry("delete", false),
+		c.primaryKeysMatchCondition(OldRecord),
+	))
+
+	c.createPostgresTrigger(c.historyTable.tableName+"_revai", "BEFORE INSERT", c.historyTable.tableNameQuoted, fmt.Sprintf(`
+			NEW.change_history_revision := (SELECT COALESCE(MAX(d.change_history_revision), 0) + 1 FROM %s AS d WHERE %s);
+			RETURN NEW;`,
+		c.historyTable.tableNameQuoted,
+		c.primaryKeysMatchCondition(NewRecord),
+	))
+}
+
+func (c *changeHistoryManager) createPostgresTrigger(name string, event string, table string, body string) {
+	c.appendStatement(fmt.Sprintf(`
+		CREATE FUNCTION %s() RETURNS trigger AS $$
+		BEGIN
+			%s
+		END;
+		$$ LANGUAGE plpgsql`,
+		name,
+		body,
+	))
+
+	c.appendStatement(fmt.Sprintf(`CREATE TRIGGER %s %s ON %s FOR EACH ROW EXECUTE PROCEDURE %s()`, name, event, table, name))
+}
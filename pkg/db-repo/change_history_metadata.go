@@ -72,6 +72,14 @@ func (m *tableMetadataBuilder) dataTypeOfField(field *gorm.StructField) string {
 	tag = strings.Replace(tag, "AUTO_INCREMENT", "", -1)
 	tag = strings.Replace(tag, "UNIQUE", "", -1)
 
+	// the history table contains the ids of the original table, so postgres must not generate them
+	switch tag {
+	case "serial":
+		tag = "integer"
+	case "bigserial":
+		tag = "bigint"
+	}
+
 	return tag
 }
 
@@ -0,0 +1,333 @@
+package db_repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/jinzhu/gorm"
+	"reflect"
+	"time"
+)
+
+const (
+	columnChangeHistoryRevision = "change_history_revision"
+	columnChangeHistoryActionAt = "change_history_action_at"
+)
+
+// ChangeHistoryEntryFactory creates an empty history entry. The entry is a struct embedding the ChangeHistoryModel
+// and the model of the repository:
+//
+//	type ItemHistoryEntry struct {
+//		db_repo.ChangeHistoryModel
+//		Item
+//	}
+type ChangeHistoryEntryFactory func() ChangeHistoryModelBased
+
+type ChangeHistoryEntry struct {
+	Revision int
+	Action   string
+	ActionAt time.Time
+	Model    ModelBased
+}
+
+type ChangeHistoryFieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+//go:generate mockery -name ChangeHistoryRepository
+type ChangeHistoryRepository interface {
+	// ListRevisions returns all revisions of the model ordered by revision
+	ListRevisions(ctx context.Context, id *uint) ([]*ChangeHistoryEntry, error)
+	ReadRevision(ctx context.Context, id *uint, revision int) (*ChangeHistoryEntry, error)
+	// ReadAsOf returns the revision which was current at the time. It fails with a RecordNotFoundError if the
+	// model didn't exist at that time.
+	ReadAsOf(ctx context.Context, id *uint, at time.Time) (*ChangeHistoryEntry, error)
+	// Diff returns the fields which changed between the revisions
+	Diff(ctx context.Context, id *uint, from int, to int) ([]ChangeHistoryFieldChange, error)
+	// Restore writes the revision back through the update of the repository, so the restore is a new revision
+	// itself. Soft deleted models are undeleted by the update and hard deleted models are created again.
+	Restore(ctx context.Context, id *uint, revision int) (ModelBased, error)
+}
+
+type changeHistoryRepository struct {
+	logger   mon.Logger
+	orm      *gorm.DB
+	conn     *sql.DB
+	repo     Repository
+	newEntry ChangeHistoryEntryFactory
+	settings *ChangeHistorySettings
+
+	modelType    reflect.Type
+	tableName    string
+	historyTable string
+	primaryKey   string
+}
+
+func NewChangeHistoryRepository(config cfg.Config, logger mon.Logger, repo Repository, newEntry ChangeHistoryEntryFactory) (ChangeHistoryRepository, error) {
+	orm, err := NewOrm(config, logger)
+	if err != nil {
+		return nil, fmt.Errorf("can not create orm: %w", err)
+	}
+
+	settings := ReadChangeHistorySettings(config)
+
+	return NewChangeHistoryRepositoryWithInterfaces(logger, orm, repo, newEntry, settings)
+}
+
+func NewChangeHistoryRepositoryWithInterfaces(logger mon.Logger, orm *gorm.DB, repo Repository, newEntry ChangeHistoryEntryFactory, settings *ChangeHistorySettings) (ChangeHistoryRepository, error) {
+	model, err := changeHistoryEntryModel(newEntry())
+
+	if err != nil {
+		return nil, err
+	}
+
+	scope := orm.NewScope(model)
+	conn, _ := orm.CommonDB().(*sql.DB)
+
+	return &changeHistoryRepository{
+		logger:       logger,
+		orm:          orm,
+		conn:         conn,
+		repo:         repo,
+		newEntry:     newEntry,
+		settings:     settings,
+		modelType:    reflect.TypeOf(model).Elem(),
+		tableName:    scope.TableName(),
+		historyTable: fmt.Sprintf("%s_%s", scope.TableName(), settings.TableSuffix),
+		primaryKey:   scope.PrimaryKey(),
+	}, nil
+}
+
+func (r *changeHistoryRepository) ListRevisions(ctx context.Context, id *uint) ([]*ChangeHistoryEntry, error) {
+	return r.find(ctx, func(qry *gorm.DB) *gorm.DB {
+		return qry.Where(fmt.Sprintf("%s = ?", r.primaryKey), *id).Order(fmt.Sprintf("%s ASC", columnChangeHistoryRevision))
+	})
+}
+
+func (r *changeHistoryRepository) ReadRevision(ctx context.Context, id *uint, revision int) (*ChangeHistoryEntry, error) {
+	entries, err := r.find(ctx, func(qry *gorm.DB) *gorm.DB {
+		return qry.Where(fmt.Sprintf("%s = ? AND %s = ?", r.primaryKey, columnChangeHistoryRevision), *id, revision)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, NewRecordNotFoundError(*id, r.historyTable, fmt.Errorf("there is no revision %d", revision))
+	}
+
+	return entries[0], nil
+}
+
+func (r *changeHistoryRepository) ReadAsOf(ctx context.Context, id *uint, at time.Time) (*ChangeHistoryEntry, error) {
+	entries, err := r.find(ctx, func(qry *gorm.DB) *gorm.DB {
+		return qry.
+			Where(fmt.Sprintf("%s = ? AND %s <= ?", r.primaryKey, columnChangeHistoryActionAt), *id, at).
+			Order(fmt.Sprintf("%s DESC", columnChangeHistoryRevision)).
+			Limit(1)
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 || entries[0].Action == Delete {
+		return nil, NewRecordNotFoundError(*id, r.historyTable, fmt.Errorf("the model didn't exist at %s", at.Format(time.RFC3339)))
+	}
+
+	return entries[0], nil
+}
+
+func (r *changeHistoryRepository) Diff(ctx context.Context, id *uint, from int, to int) ([]ChangeHistoryFieldChange, error) {
+	fromEntry, err := r.ReadRevision(ctx, id, from)
+
+	if err != nil {
+		return nil, err
+	}
+
+	toEntry, err := r.ReadRevision(ctx, id, to)
+
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]ChangeHistoryFieldChange, 0)
+	toFields := r.orm.NewScope(toEntry.Model).Fields()
+
+	for i, field := range r.orm.NewScope(fromEntry.Model).Fields() {
+		if !field.IsNormal || field.IsIgnored {
+			continue
+		}
+
+		fromValue := changeHistoryFieldValue(field.Field)
+		toValue := changeHistoryFieldValue(toFields[i].Field)
+
+		if reflect.DeepEqual(fromValue, toValue) {
+			continue
+		}
+
+		changes = append(changes, ChangeHistoryFieldChange{
+			Field: field.DBName,
+			From:  fromValue,
+			To:    toValue,
+		})
+	}
+
+	return changes, nil
+}
+
+func (r *changeHistoryRepository) Restore(ctx context.Context, id *uint, revision int) (ModelBased, error) {
+	entry, err := r.ReadRevision(ctx, id, revision)
+
+	if err != nil {
+		return nil, err
+	}
+
+	model := entry.Model
+
+	err = r.repo.WithTx(ctx, func(ctx context.Context) error {
+		current, exists, err := r.readCurrent(ctx, id)
+
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			return r.repo.Create(ctx, model)
+		}
+
+		if versioned, ok := model.(Versionable); ok {
+			versioned.SetVersion(current.(Versionable).GetVersion())
+		}
+
+		if deletable, ok := current.(SoftDeletable); ok && deletable.GetDeletedAt() != nil {
+			ctx = withUndelete(ctx)
+		}
+
+		return r.repo.Update(ctx, model)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("can not restore revision %d of model %d: %w", revision, *id, err)
+	}
+
+	r.logger.WithContext(ctx).Infof("restored revision %d of model %d from %s", revision, *id, r.historyTable)
+
+	return model, nil
+}
+
+// readCurrent reads the stored model including soft deleted ones
+func (r *changeHistoryRepository) readCurrent(ctx context.Context, id *uint) (ModelBased, bool, error) {
+	orm, err := r.ormFor(ctx)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	current := reflect.New(r.modelType).Interface().(ModelBased)
+	err = orm.New().Unscoped().Set("gorm:auto_preload", false).Where(fmt.Sprintf("%s = ?", r.primaryKey), *id).First(current).Error
+
+	if gorm.IsRecordNotFoundError(err) {
+		return nil, false, nil
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("can not read the current model: %w", err)
+	}
+
+	return current, true, nil
+}
+
+func (r *changeHistoryRepository) find(ctx context.Context, where func(qry *gorm.DB) *gorm.DB) ([]*ChangeHistoryEntry, error) {
+	orm, err := r.ormFor(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	entryType := reflect.TypeOf(r.newEntry())
+	result := reflect.New(reflect.SliceOf(entryType))
+
+	// the history keeps deleted models and their associations aren't versioned
+	qry := orm.New().Unscoped().Set("gorm:auto_preload", false).Table(r.historyTable)
+
+	if err = where(qry).Find(result.Interface()).Error; err != nil {
+		return nil, fmt.Errorf("can not read the history of %s: %w", r.tableName, err)
+	}
+
+	entries := make([]*ChangeHistoryEntry, 0, result.Elem().Len())
+
+	for i := 0; i < result.Elem().Len(); i++ {
+		history := result.Elem().Index(i).Interface().(ChangeHistoryModelBased)
+		model, err := changeHistoryEntryModel(history)
+
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &ChangeHistoryEntry{
+			Revision: history.GetHistoryRevision(),
+			Action:   history.GetHistoryAction(),
+			ActionAt: history.GetHistoryActionAt(),
+			Model:    model,
+		})
+	}
+
+	return entries, nil
+}
+
+func (r *changeHistoryRepository) ormFor(ctx context.Context) (*gorm.DB, error) {
+	if r.conn == nil {
+		return r.orm, nil
+	}
+
+	orm, ok, err := txOrm(ctx, r.conn, r.orm)
+
+	if !ok {
+		return r.orm, nil
+	}
+
+	return orm, err
+}
+
+func changeHistoryFieldValue(rv reflect.Value) interface{} {
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+
+		rv = rv.Elem()
+	}
+
+	return rv.Interface()
+}
+
+// changeHistoryEntryModel returns the model embedded into the history entry
+func changeHistoryEntryModel(entry ChangeHistoryModelBased) (ModelBased, error) {
+	rv := reflect.ValueOf(entry)
+
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("the history entry has to be a pointer to a struct but is %T", entry)
+	}
+
+	rv = rv.Elem()
+
+	for i := 0; i < rv.NumField(); i++ {
+		field := rv.Type().Field(i)
+
+		if !field.Anonymous || field.Type == reflect.TypeOf(ChangeHistoryModel{}) {
+			continue
+		}
+
+		if model, ok := rv.Field(i).Addr().Interface().(ModelBased); ok {
+			return model, nil
+		}
+	}
+
+	return nil, fmt.Errorf("the history entry %T doesn't embed a model", entry)
+}
@@ -0,0 +1,198 @@
+package db_repo_test
+
+import (
+	"context"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/db-repo/mocks"
+	"github.com/applike/gosoline/pkg/exec"
+	"github.com/applike/gosoline/pkg/mdl"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+type HistoryTestModel struct {
+	db_repo.Model
+	Name *string
+}
+
+type HistoryTestModelEntry struct {
+	db_repo.ChangeHistoryModel
+	HistoryTestModel
+}
+
+var historyColumns = []string{"change_history_action", "change_history_action_at", "change_history_revision", "id", "updated_at", "created_at", "name"}
+
+func getHistoryMocks(t *testing.T) (goSqlMock.Sqlmock, *mocks.Repository, db_repo.ChangeHistoryRepository) {
+	logger := monMocks.NewLoggerMockedAll()
+	dbMock, sqlMock, _ := goSqlMock.New()
+
+	orm, err := db_repo.NewOrmWithInterfaces(logger, dbMock, db_repo.OrmSettings{
+		Driver: "mysql",
+	})
+	assert.NoError(t, err)
+
+	repo := new(mocks.Repository)
+	historyRepo, err := db_repo.NewChangeHistoryRepositoryWithInterfaces(logger, orm, repo, func() db_repo.ChangeHistoryModelBased {
+		return &HistoryTestModelEntry{}
+	}, &db_repo.ChangeHistorySettings{
+		TableSuffix: "history",
+	})
+	assert.NoError(t, err)
+
+	return sqlMock, repo, historyRepo
+}
+
+func TestChangeHistoryRepository_ListRevisions(t *testing.T) {
+	sqlMock, _, historyRepo := getHistoryMocks(t)
+	now := time.Unix(1549964818, 0)
+
+	rows := goSqlMock.NewRows(historyColumns).
+		AddRow("insert", now, 1, 1, now, now, "foo").
+		AddRow("update", now, 2, 1, now, now, "bar")
+	sqlMock.ExpectQuery("SELECT \\* FROM `history_test_models_history` WHERE \\(id = \\?\\) ORDER BY change_history_revision ASC").
+		WithArgs(1).
+		WillReturnRows(rows)
+
+	entries, err := historyRepo.ListRevisions(context.Background(), mdl.Uint(1))
+
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, 2, entries[1].Revision)
+	assert.Equal(t, "update", entries[1].Action)
+	assert.Equal(t, "bar", *entries[1].Model.(*HistoryTestModel).Name)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestChangeHistoryRepository_ReadAsOf_Deleted(t *testing.T) {
+	sqlMock, _, historyRepo := getHistoryMocks(t)
+	now := time.Unix(1549964818, 0)
+
+	rows := goSqlMock.NewRows(historyColumns).AddRow("delete", now, 3, 1, now, now, "bar")
+	sqlMock.ExpectQuery("SELECT \\* FROM `history_test_models_history` WHERE \\(id = \\? AND change_history_action_at <= \\?\\) ORDER BY change_history_revision DESC LIMIT 1").
+		WithArgs(1, now).
+		WillReturnRows(rows)
+
+	_, err := historyRepo.ReadAsOf(context.Background(), mdl.Uint(1), now)
+
+	assert.True(t, db_repo.IsRecordNotFoundError(err))
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestChangeHistoryRepository_Diff(t *testing.T) {
+	sqlMock, _, historyRepo := getHistoryMocks(t)
+	now := time.Unix(1549964818, 0)
+
+	sqlMock.ExpectQuery("SELECT \\* FROM `history_test_models_history` WHERE \\(id = \\? AND change_history_revision = \\?\\)").
+		WithArgs(1, 1).
+		WillReturnRows(goSqlMock.NewRows(historyColumns).AddRow("insert", now, 1, 1, now, now, "foo"))
+	sqlMock.ExpectQuery("SELECT \\* FROM `history_test_models_history` WHERE \\(id = \\? AND change_history_revision = \\?\\)").
+		WithArgs(1, 2).
+		WillReturnRows(goSqlMock.NewRows(historyColumns).AddRow("update", now, 2, 1, now, now, "bar"))
+
+	changes, err := historyRepo.Diff(context.Background(), mdl.Uint(1), 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []db_repo.ChangeHistoryFieldChange{
+		{
+			Field: "name",
+			From:  "foo",
+			To:    "bar",
+		},
+	}, changes)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestChangeHistoryRepository_Restore(t *testing.T) {
+	sqlMock, repo, historyRepo := getHistoryMocks(t)
+	now := time.Unix(1549964818, 0)
+
+	sqlMock.ExpectQuery("SELECT \\* FROM `history_test_models_history`").
+		WithArgs(1, 1).
+		WillReturnRows(goSqlMock.NewRows(historyColumns).AddRow("insert", now, 1, 1, now, now, "foo"))
+	sqlMock.ExpectQuery("SELECT \\* FROM `history_test_models` WHERE \\(id = \\?\\)").
+		WithArgs(1).
+		WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at", "name"}).AddRow(1, now, now, "bar"))
+
+	repo.On("WithTx", mock.Anything, mock.Anything).Return(func(ctx context.Context, f db.TxFunc, _ ...db.TxOption) error {
+		return f(ctx)
+	})
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(model *HistoryTestModel) bool {
+		return *model.Id == 1 && *model.Name == "foo"
+	})).Return(nil)
+
+	model, err := historyRepo.Restore(context.Background(), mdl.Uint(1), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", *model.(*HistoryTestModel).Name)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+	repo.AssertExpectations(t)
+}
+
+type HistoryDeletedModel struct {
+	db_repo.Model
+	db_repo.Versioned
+	db_repo.SoftDeletion
+	Name *string
+}
+
+type HistoryDeletedModelEntry struct {
+	db_repo.ChangeHistoryModel
+	HistoryDeletedModel
+}
+
+func TestChangeHistoryRepository_Restore_Deleted(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	dbMock, sqlMock, _ := goSqlMock.New()
+	now := time.Unix(1549964818, 0)
+
+	orm, err := db_repo.NewOrmWithInterfaces(logger, dbMock, db_repo.OrmSettings{
+		Driver: "mysql",
+	})
+	assert.NoError(t, err)
+
+	transactor, _ := db.NewTransactorWithInterfaces(logger, dbMock, exec.NewDefaultExecutor(), &db.TransactionSettings{
+		Isolation: "default",
+	})
+	repo := db_repo.NewWithInterfaces(logger, tracing.NewNoopTracer(), orm, transactor, nil, clockwork.NewFakeClockAt(now), db_repo.Settings{})
+
+	historyRepo, err := db_repo.NewChangeHistoryRepositoryWithInterfaces(logger, orm, repo, func() db_repo.ChangeHistoryModelBased {
+		return &HistoryDeletedModelEntry{}
+	}, &db_repo.ChangeHistorySettings{
+		TableSuffix: "history",
+	})
+	assert.NoError(t, err)
+
+	columns := []string{"id", "updated_at", "created_at", "version", "deleted_at", "name"}
+	historyColumns := append([]string{"change_history_action", "change_history_action_at", "change_history_revision"}, columns...)
+
+	sqlMock.ExpectQuery("SELECT \\* FROM `history_deleted_models_history`").
+		WithArgs(1, 1).
+		WillReturnRows(goSqlMock.NewRows(historyColumns).AddRow("insert", now, 1, 1, now, now, 1, nil, "foo"))
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery("SELECT \\* FROM `history_deleted_models` WHERE \\(id = \\?\\)").
+		WithArgs(1).
+		WillReturnRows(goSqlMock.NewRows(columns).AddRow(1, now, now, 3, now, "bar"))
+	sqlMock.ExpectQuery("SELECT version, deleted_at FROM `history_deleted_models` WHERE \\(id = \\?\\) FOR UPDATE").
+		WithArgs(1).
+		WillReturnRows(goSqlMock.NewRows([]string{"version", "deleted_at"}).AddRow(3, now))
+	sqlMock.ExpectExec("UPDATE `history_deleted_models` SET .*`version` = \\?, `deleted_at` = \\?, `name` = \\?  WHERE `history_deleted_models`\\.`id` = \\?").
+		WithArgs(goSqlMock.AnyArg(), goSqlMock.AnyArg(), 4, nil, "foo", 1).
+		WillReturnResult(goSqlMock.NewResult(0, 1))
+	sqlMock.ExpectQuery("SELECT \\* FROM `history_deleted_models` WHERE `history_deleted_models`\\.`deleted_at` IS NULL").
+		WillReturnRows(goSqlMock.NewRows(columns).AddRow(1, now, now, 4, nil, "foo"))
+	sqlMock.ExpectCommit()
+
+	model, err := historyRepo.Restore(context.Background(), mdl.Uint(1), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), model.(*HistoryDeletedModel).Version)
+	assert.Nil(t, model.(*HistoryDeletedModel).DeletedAt)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
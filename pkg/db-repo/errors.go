@@ -54,3 +54,25 @@ func (e NoQueryResultsError) Unwrap() error {
 func IsNoQueryResultsError(err error) bool {
 	return errors.As(err, &NoQueryResultsError{})
 }
+
+type StaleObjectError struct {
+	id      uint
+	modelId string
+	version uint
+}
+
+func NewStaleObjectError(id uint, modelId string, version uint) StaleObjectError {
+	return StaleObjectError{
+		id:      id,
+		modelId: modelId,
+		version: version,
+	}
+}
+
+func (e StaleObjectError) Error() string {
+	return fmt.Sprintf("could not update model of type %s with id %d: version %d is stale", e.modelId, e.id, e.version)
+}
+
+func IsStaleObjectError(err error) bool {
+	return errors.As(err, &StaleObjectError{})
+}
@@ -3,6 +3,7 @@
 package mocks
 
 import context "context"
+import db "github.com/applike/gosoline/pkg/db"
 import db_repo "github.com/applike/gosoline/pkg/db-repo"
 import mock "github.com/stretchr/testify/mock"
 
@@ -143,3 +144,24 @@ func (_m *Repository) Update(ctx context.Context, value db_repo.ModelBased) erro
 
 	return r0
 }
+
+// WithTx provides a mock function with given fields: ctx, f, options
+func (_m *Repository) WithTx(ctx context.Context, f db.TxFunc, options ...db.TxOption) error {
+	_va := make([]interface{}, len(options))
+	for _i := range options {
+		_va[_i] = options[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, f)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.TxFunc, ...db.TxOption) error); ok {
+		r0 = rf(ctx, f, options...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
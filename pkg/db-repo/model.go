@@ -5,7 +5,11 @@ import (
 	"time"
 )
 
-const ColumnUpdatedAt = "updated_at"
+const (
+	ColumnUpdatedAt = "updated_at"
+	ColumnVersion   = "version"
+	ColumnDeletedAt = "deleted_at"
+)
 
 type ModelBased interface {
 	mdl.Identifiable
@@ -58,3 +62,42 @@ func EmptyTimestamps() Timestamps {
 		CreatedAt: &time.Time{},
 	}
 }
+
+// Versionable models are updated only if their version is still the one stored in the database
+type Versionable interface {
+	GetVersion() uint
+	SetVersion(version uint)
+}
+
+// Versioned adds optimistic locking to a model. Every update increments the version and fails with a
+// StaleObjectError if the row was updated in the meantime or with a RecordNotFoundError if it was deleted.
+type Versioned struct {
+	Version uint `gorm:"not null"`
+}
+
+func (m *Versioned) GetVersion() uint {
+	return m.Version
+}
+
+func (m *Versioned) SetVersion(version uint) {
+	m.Version = version
+}
+
+type SoftDeletable interface {
+	GetDeletedAt() *time.Time
+	SetDeletedAt(deletedAt *time.Time)
+}
+
+// SoftDeletion turns the deletion of a model into setting deleted_at. Deleted models are filtered out of
+// Read, Query and Count.
+type SoftDeletion struct {
+	DeletedAt *time.Time `sql:"index"`
+}
+
+func (m *SoftDeletion) GetDeletedAt() *time.Time {
+	return m.DeletedAt
+}
+
+func (m *SoftDeletion) SetDeletedAt(deletedAt *time.Time) {
+	m.DeletedAt = deletedAt
+}
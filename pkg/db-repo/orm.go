@@ -1,6 +1,8 @@
 package db_repo
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/db"
@@ -20,7 +22,11 @@ type OrmSettings struct {
 }
 
 func NewOrm(config cfg.Config, logger mon.Logger) (*gorm.DB, error) {
-	dbClient := db.NewClient(config, logger, "default")
+	connection, err := db.ProvideConnection(config, logger, "default")
+
+	if err != nil {
+		return nil, fmt.Errorf("can not connect to sql database: %w", err)
+	}
 
 	settings := OrmSettings{}
 	config.UnmarshalKey("db.default", &settings)
@@ -32,7 +38,7 @@ func NewOrm(config cfg.Config, logger mon.Logger) (*gorm.DB, error) {
 
 	settings.Application = application
 
-	return NewOrmWithInterfaces(logger, dbClient, settings)
+	return NewOrmWithInterfaces(logger, connection.DB, settings)
 }
 
 func NewOrmWithInterfaces(logger mon.Logger, dbClient gorm.SQLCommon, settings OrmSettings) (*gorm.DB, error) {
@@ -42,9 +48,7 @@ func NewOrmWithInterfaces(logger mon.Logger, dbClient gorm.SQLCommon, settings O
 		return nil, fmt.Errorf("could not create gorm: %w", err)
 	}
 
-	orm.LogMode(false)
-	orm = orm.Set("gorm:auto_preload", true)
-	orm = orm.Set("gorm:save_associations", false)
+	orm = configureOrm(orm)
 
 	if !settings.Migrations.TablePrefixed {
 		return orm, nil
@@ -56,3 +60,44 @@ func NewOrmWithInterfaces(logger mon.Logger, dbClient gorm.SQLCommon, settings O
 
 	return orm, nil
 }
+
+type txOrmKey struct {
+	dialect string
+}
+
+// txOrm returns an orm running its statements in the transaction of the context, which is created once per
+// transaction. The callbacks are registered already, as gorm shares them between all orms.
+func txOrm(ctx context.Context, conn *sql.DB, orm *gorm.DB) (*gorm.DB, bool, error) {
+	dialect := orm.Dialect().GetName()
+
+	value, ok, err := db.TxValue(ctx, conn, txOrmKey{dialect: dialect}, func(tx *sql.Tx) (interface{}, error) {
+		txOrm, err := gorm.Open(dialect, tx)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not create gorm for transaction: %w", err)
+		}
+
+		return configureOrm(txOrm), nil
+	})
+
+	if !ok || err != nil {
+		return nil, ok, err
+	}
+
+	return value.(*gorm.DB), true, nil
+}
+
+func configureOrm(orm *gorm.DB) *gorm.DB {
+	orm.LogMode(false)
+	orm = orm.Set("gorm:auto_preload", true)
+	orm = orm.Set("gorm:save_associations", false)
+
+	return orm
+}
+
+func registerCallbacks(orm *gorm.DB) {
+	orm.Callback().
+		Update().
+		After("gorm:update_time_stamp").
+		Register("gosoline:ignore_created_at_if_needed", ignoreCreatedAtIfNeeded)
+}
@@ -0,0 +1,112 @@
+package db_repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/stream"
+	"github.com/jinzhu/gorm"
+	"github.com/jonboulle/clockwork"
+	"time"
+)
+
+// OutboxSettings enable the outbox mode of a repository. Every create, update and delete writes a notification
+// into the outbox table in the same transaction as the change of the model. The notifications are published
+// afterwards by an outbox relay module.
+type OutboxSettings struct {
+	Enabled     bool
+	Version     int
+	Transformer mdl.TransformerResolver
+}
+
+// OutboxMessage is a notification waiting in the outbox table to be relayed. The table has to be created by
+// a migration of your application, an index on (model_id, sent_at, id) keeps the relays fast.
+type OutboxMessage struct {
+	Id           *uint      `gorm:"primary_key;AUTO_INCREMENT"`
+	ModelId      string     `gorm:"type:varchar(255);not null"`
+	Type         string     `gorm:"type:varchar(32);not null"`
+	Version      int        `gorm:"not null"`
+	Attributes   string     `gorm:"type:text;not null"`
+	Body         string     `gorm:"type:longtext;not null"`
+	Attempts     int        `gorm:"not null"`
+	CreatedAt    *time.Time `gorm:"not null"`
+	ClaimedUntil *time.Time
+	SentAt       *time.Time
+}
+
+// StreamMessage converts the outbox message back into the stream message it was encoded to
+func (m *OutboxMessage) StreamMessage() (*stream.Message, error) {
+	attributes := make(map[string]interface{})
+
+	if err := json.Unmarshal([]byte(m.Attributes), &attributes); err != nil {
+		return nil, fmt.Errorf("can not unmarshal attributes of outbox message %d: %w", mdl.EmptyUintIfNil(m.Id), err)
+	}
+
+	return &stream.Message{
+		Attributes: attributes,
+		Body:       m.Body,
+	}, nil
+}
+
+type outbox struct {
+	encoder  stream.MessageEncoder
+	clock    clockwork.Clock
+	modelId  mdl.ModelId
+	settings OutboxSettings
+}
+
+func newOutbox(clock clockwork.Clock, modelId mdl.ModelId, settings OutboxSettings) *outbox {
+	encoder := stream.NewMessageEncoder(&stream.MessageEncoderSettings{
+		Encoding: stream.EncodingJson,
+	})
+
+	return &outbox{
+		encoder:  encoder,
+		clock:    clock,
+		modelId:  modelId,
+		settings: settings,
+	}
+}
+
+// write encodes the notification like the notifiers do and stores it with the given transaction
+func (o *outbox) write(ctx context.Context, tx *gorm.DB, notificationType string, value ModelBased) error {
+	var out interface{} = value
+	modelId := o.modelId.String()
+
+	if o.settings.Transformer != nil {
+		out = o.settings.Transformer("api", o.settings.Version, value)
+	}
+
+	msg, err := o.encoder.Encode(ctx, out, map[string]interface{}{
+		"type":    notificationType,
+		"version": o.settings.Version,
+		"modelId": modelId,
+	})
+
+	if err != nil {
+		return fmt.Errorf("can not encode outbox message: %w", err)
+	}
+
+	attributes, err := json.Marshal(msg.Attributes)
+
+	if err != nil {
+		return fmt.Errorf("can not marshal attributes of outbox message: %w", err)
+	}
+
+	now := o.clock.Now()
+	outboxMessage := &OutboxMessage{
+		ModelId:    modelId,
+		Type:       notificationType,
+		Version:    o.settings.Version,
+		Attributes: string(attributes),
+		Body:       msg.Body,
+		CreatedAt:  &now,
+	}
+
+	if err = tx.Create(outboxMessage).Error; err != nil {
+		return fmt.Errorf("can not write %s of model %s with id %d to the outbox: %w", notificationType, modelId, mdl.EmptyUintIfNil(value.GetId()), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,387 @@
+package db_repo
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/stream"
+	"github.com/jinzhu/gorm"
+	"github.com/jonboulle/clockwork"
+	"time"
+)
+
+const (
+	metricNameOutboxRelaySuccess = "OutboxRelaySuccess"
+	metricNameOutboxRelayFailure = "OutboxRelayFailure"
+	metricNameOutboxRelayGivenUp = "OutboxRelayGivenUp"
+)
+
+// OutboxRelaySettings are read from outbox.<name>. The messages are either written to the stream output
+// with the configured name or published with the configured mdlsub publisher (see mdlsub.NewOutboxRelay).
+type OutboxRelaySettings struct {
+	Name      string `cfg:"name"`
+	Output    string `cfg:"output"`
+	Publisher string `cfg:"publisher"`
+	// ModelId restricts the relay to the messages of one model, mdlsub.NewOutboxRelay defaults it to the model of the publisher
+	ModelId         string        `cfg:"model_id"`
+	Interval        time.Duration `cfg:"interval" default:"1s"`
+	BatchSize       int           `cfg:"batch_size" default:"50" validate:"min=1"`
+	MaxAttempts     int           `cfg:"max_attempts" default:"10" validate:"min=1"`
+	ClaimTimeout    time.Duration `cfg:"claim_timeout" default:"1m"`
+	Retention       time.Duration `cfg:"retention" default:"24h"`
+	FailedRetention time.Duration `cfg:"failed_retention" default:"168h"`
+}
+
+//go:generate mockery -name OutboxSender
+type OutboxSender interface {
+	Send(ctx context.Context, msg *OutboxMessage) error
+}
+
+type outboxOutputSender struct {
+	output stream.Output
+}
+
+func NewOutboxOutputSender(output stream.Output) OutboxSender {
+	return &outboxOutputSender{
+		output: output,
+	}
+}
+
+func (s *outboxOutputSender) Send(ctx context.Context, msg *OutboxMessage) error {
+	streamMessage, err := msg.StreamMessage()
+
+	if err != nil {
+		return err
+	}
+
+	return s.output.WriteOne(ctx, streamMessage)
+}
+
+type outboxRelay struct {
+	kernel.BackgroundModule
+	kernel.ApplicationStage
+
+	logger   mon.Logger
+	metric   mon.MetricWriter
+	orm      *gorm.DB
+	clock    clockwork.Clock
+	sender   OutboxSender
+	settings *OutboxRelaySettings
+}
+
+// NewOutboxRelay creates a module relaying the messages of the outbox to the stream output configured at outbox.<name>
+func NewOutboxRelay(name string) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		settings := ReadOutboxRelaySettings(config, name)
+
+		if settings.Output == "" {
+			return nil, fmt.Errorf("there is no output configured for the outbox relay %s", name)
+		}
+
+		output, err := stream.NewConfigurableOutput(config, logger, settings.Output)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not create output %s for the outbox relay %s: %w", settings.Output, name, err)
+		}
+
+		return NewOutboxRelayWithSender(config, logger, NewOutboxOutputSender(output), settings)
+	}
+}
+
+func NewOutboxRelayWithSender(config cfg.Config, logger mon.Logger, sender OutboxSender, settings *OutboxRelaySettings) (*outboxRelay, error) {
+	orm, err := NewOrm(config, logger)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create orm: %w", err)
+	}
+
+	defaults := getOutboxRelayDefaultMetrics(settings.Name)
+	metric := mon.NewMetricDaemonWriter(defaults...)
+	clock := clockwork.NewRealClock()
+
+	return NewOutboxRelayWithInterfaces(logger, metric, orm, clock, sender, settings), nil
+}
+
+func NewOutboxRelayWithInterfaces(logger mon.Logger, metric mon.MetricWriter, orm *gorm.DB, clock clockwork.Clock, sender OutboxSender, settings *OutboxRelaySettings) *outboxRelay {
+	return &outboxRelay{
+		logger:   logger.WithChannel("outbox_relay"),
+		metric:   metric,
+		orm:      orm,
+		clock:    clock,
+		sender:   sender,
+		settings: settings,
+	}
+}
+
+func ReadOutboxRelaySettings(config cfg.Config, name string) *OutboxRelaySettings {
+	settings := &OutboxRelaySettings{}
+	config.UnmarshalKey(fmt.Sprintf("outbox.%s", name), settings)
+
+	settings.Name = name
+
+	return settings
+}
+
+func (r *outboxRelay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.settings.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-ticker.C:
+			r.relay(ctx)
+			r.cleanup(ctx)
+		}
+	}
+}
+
+// relay sends pending messages until the outbox is drained or a message could not be sent
+func (r *outboxRelay) relay(ctx context.Context) {
+	for {
+		count, err := r.relayBatch(ctx)
+
+		if err != nil {
+			r.logger.WithContext(ctx).Error(err, "can not relay outbox messages")
+			return
+		}
+
+		if count < r.settings.BatchSize {
+			return
+		}
+	}
+}
+
+// relayBatch claims the next pending messages and sends them in the order they were written. On the first
+// failure the batch is aborted, so the following messages aren't sent before the failed one. A message is
+// given up after it failed max_attempts times.
+func (r *outboxRelay) relayBatch(ctx context.Context) (int, error) {
+	messages, err := r.claim()
+
+	if err != nil {
+		return 0, err
+	}
+
+	sent := make([]uint, 0, len(messages))
+	failed := make([]uint, 0)
+	released := make([]uint, 0)
+
+	var sendErr error
+
+	for i, msg := range messages {
+		id := mdl.EmptyUintIfNil(msg.Id)
+		err := r.sender.Send(ctx, msg)
+		r.writeMetric(err)
+
+		if err == nil {
+			sent = append(sent, id)
+			continue
+		}
+
+		failed = append(failed, id)
+
+		if attempts := msg.Attempts + 1; attempts >= r.settings.MaxAttempts {
+			r.logger.WithContext(ctx).Errorf(err, "giving up on outbox message %d for %s of model %s after %d attempts", id, msg.Type, msg.ModelId, attempts)
+			r.writeGivenUpMetric()
+			continue
+		}
+
+		sendErr = fmt.Errorf("can not send outbox message %d: %w", id, err)
+
+		for _, next := range messages[i+1:] {
+			released = append(released, mdl.EmptyUintIfNil(next.Id))
+		}
+
+		break
+	}
+
+	if err := r.finish(sent, failed, released); err != nil {
+		return 0, err
+	}
+
+	return len(messages), sendErr
+}
+
+// claim locks the next pending messages only for the short transaction which marks them as claimed until the
+// claim timeout is over. The messages are sent after the commit, so no lock is held during the network calls.
+// To keep the order, no message behind one claimed by another relay is claimed.
+func (r *outboxRelay) claim() ([]*OutboxMessage, error) {
+	messages := make([]*OutboxMessage, 0, r.settings.BatchSize)
+	now := r.clock.Now()
+	tx := r.orm.Begin()
+
+	if tx.Error != nil {
+		return nil, fmt.Errorf("can not begin transaction: %w", tx.Error)
+	}
+
+	query := tx.Set("gorm:query_option", "FOR UPDATE").
+		Where("sent_at IS NULL AND attempts < ?", r.settings.MaxAttempts)
+
+	if r.settings.ModelId != "" {
+		query = query.Where("model_id = ?", r.settings.ModelId)
+	}
+
+	err := query.Order("id ASC").
+		Limit(r.settings.BatchSize).
+		Find(&messages).Error
+
+	if err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("can not read pending outbox messages: %w", err)
+	}
+
+	ids := make([]uint, 0, len(messages))
+
+	for i, msg := range messages {
+		if msg.ClaimedUntil != nil && msg.ClaimedUntil.After(now) {
+			messages = messages[:i]
+			break
+		}
+
+		ids = append(ids, mdl.EmptyUintIfNil(msg.Id))
+	}
+
+	if len(ids) > 0 {
+		claimedUntil := now.Add(r.settings.ClaimTimeout)
+		err = tx.Model(&OutboxMessage{}).Where("id IN (?)", ids).UpdateColumn("claimed_until", &claimedUntil).Error
+
+		if err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("can not claim %d outbox messages: %w", len(ids), err)
+		}
+	}
+
+	if err = tx.Commit().Error; err != nil {
+		return nil, fmt.Errorf("can not commit the claim of the outbox messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// finish stores the result of the sent messages and releases the claim of the remaining ones. If this fails, the
+// messages are sent again after their claim timed out.
+func (r *outboxRelay) finish(sent []uint, failed []uint, released []uint) error {
+	if len(sent)+len(failed)+len(released) == 0 {
+		return nil
+	}
+
+	now := r.clock.Now()
+	tx := r.orm.Begin()
+
+	if tx.Error != nil {
+		return fmt.Errorf("can not begin transaction: %w", tx.Error)
+	}
+
+	updates := []struct {
+		ids     []uint
+		columns map[string]interface{}
+	}{
+		{ids: sent, columns: map[string]interface{}{"sent_at": &now, "claimed_until": nil}},
+		{ids: failed, columns: map[string]interface{}{"attempts": gorm.Expr("attempts + ?", 1), "claimed_until": nil}},
+		{ids: released, columns: map[string]interface{}{"claimed_until": nil}},
+	}
+
+	for _, update := range updates {
+		if len(update.ids) == 0 {
+			continue
+		}
+
+		if err := tx.Model(&OutboxMessage{}).Where("id IN (?)", update.ids).UpdateColumns(update.columns).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("can not update the state of %d outbox messages: %w", len(update.ids), err)
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("can not commit the state of the outbox messages: %w", err)
+	}
+
+	return nil
+}
+
+// cleanup removes sent messages which are older than the retention and given up messages which are older than
+// the failed retention
+func (r *outboxRelay) cleanup(ctx context.Context) {
+	now := r.clock.Now()
+
+	queries := []*gorm.DB{
+		r.orm.Where("sent_at IS NOT NULL AND sent_at < ?", now.Add(-r.settings.Retention)),
+		r.orm.Where("sent_at IS NULL AND attempts >= ? AND created_at < ?", r.settings.MaxAttempts, now.Add(-r.settings.FailedRetention)),
+	}
+
+	for _, query := range queries {
+		if r.settings.ModelId != "" {
+			query = query.Where("model_id = ?", r.settings.ModelId)
+		}
+
+		if err := query.Delete(&OutboxMessage{}).Error; err != nil {
+			r.logger.WithContext(ctx).Error(err, "can not remove outbox messages")
+		}
+	}
+}
+
+func (r *outboxRelay) writeMetric(err error) {
+	metricName := metricNameOutboxRelaySuccess
+
+	if err != nil {
+		metricName = metricNameOutboxRelayFailure
+	}
+
+	r.metric.WriteOne(&mon.MetricDatum{
+		MetricName: metricName,
+		Dimensions: map[string]string{
+			"OutboxRelay": r.settings.Name,
+		},
+		Unit:  mon.UnitCount,
+		Value: 1.0,
+	})
+}
+
+func (r *outboxRelay) writeGivenUpMetric() {
+	r.metric.WriteOne(&mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		MetricName: metricNameOutboxRelayGivenUp,
+		Dimensions: map[string]string{
+			"OutboxRelay": r.settings.Name,
+		},
+		Unit:  mon.UnitCount,
+		Value: 1.0,
+	})
+}
+
+func getOutboxRelayDefaultMetrics(name string) []*mon.MetricDatum {
+	return []*mon.MetricDatum{
+		{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameOutboxRelaySuccess,
+			Dimensions: map[string]string{
+				"OutboxRelay": name,
+			},
+			Unit:  mon.UnitCount,
+			Value: 0.0,
+		},
+		{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameOutboxRelayFailure,
+			Dimensions: map[string]string{
+				"OutboxRelay": name,
+			},
+			Unit:  mon.UnitCount,
+			Value: 0.0,
+		},
+		{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameOutboxRelayGivenUp,
+			Dimensions: map[string]string{
+				"OutboxRelay": name,
+			},
+			Unit:  mon.UnitCount,
+			Value: 0.0,
+		},
+	}
+}
@@ -0,0 +1,179 @@
+package db_repo_test
+
+import (
+	"context"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/db-repo/mocks"
+	"github.com/applike/gosoline/pkg/exec"
+	"github.com/applike/gosoline/pkg/mdl"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestRepository_CreateWithOutbox(t *testing.T) {
+	now := time.Unix(1549964818, 0).UTC()
+	dbc, repo := getOutboxMocks(t, now)
+
+	dbc.ExpectBegin()
+	dbc.ExpectExec("INSERT INTO `my_test_models` \\(`id`,`updated_at`,`created_at`\\) VALUES \\(\\?,\\?,\\?\\)").WithArgs(id1, &now, &now).WillReturnResult(goSqlMock.NewResult(0, 1))
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now)
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models` WHERE `my_test_models`\\.`id` = \\? AND \\(\\(`my_test_models`\\.`id` = 1\\)\\) ORDER BY `my_test_models`\\.`id` ASC LIMIT 1").WillReturnRows(rows)
+
+	attributes := `{"encoding":"application/json","modelId":"gosoline.test.repo.myTestModel","type":"create","version":1}`
+	body := `{"Id":1,"UpdatedAt":"2019-02-12T09:46:58Z","CreatedAt":"2019-02-12T09:46:58Z"}`
+	dbc.ExpectExec("INSERT INTO `outbox_messages` \\(`model_id`,`type`,`version`,`attributes`,`body`,`attempts`,`created_at`,`claimed_until`,`sent_at`\\) VALUES \\(\\?,\\?,\\?,\\?,\\?,\\?,\\?,\\?,\\?\\)").
+		WithArgs("gosoline.test.repo.myTestModel", "create", 1, attributes, body, 0, &now, nil, nil).
+		WillReturnResult(goSqlMock.NewResult(1, 1))
+	dbc.ExpectCommit()
+
+	rows = goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now)
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models` WHERE `my_test_models`\\.`id` = \\? AND \\(\\(`my_test_models`\\.`id` = 1\\)\\) ORDER BY `my_test_models`\\.`id` ASC LIMIT 1").WillReturnRows(rows)
+
+	model := MyTestModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+	}
+
+	err := repo.Create(context.Background(), &model)
+
+	assert.NoError(t, err, "there should not be an error")
+	assert.NoError(t, dbc.ExpectationsWereMet(), "there should be no unfulfilled expectations")
+}
+
+func TestRepository_CreateWithOutboxRollback(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getOutboxMocks(t, now)
+
+	dbc.ExpectBegin()
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WillReturnResult(goSqlMock.NewResult(0, 1))
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now)
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(rows)
+	dbc.ExpectExec("INSERT INTO `outbox_messages`").WillReturnError(assert.AnError)
+	dbc.ExpectRollback()
+
+	model := MyTestModel{
+		Model: db_repo.Model{
+			Id: id1,
+		},
+	}
+
+	err := repo.Create(context.Background(), &model)
+
+	assert.Error(t, err, "there should be an error")
+	assert.NoError(t, dbc.ExpectationsWereMet(), "there should be no unfulfilled expectations")
+}
+
+func TestOutboxRelay_Run(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	logger := monMocks.NewLoggerMockedAll()
+	metric := monMocks.NewMetricWriterMockedAll()
+
+	dbMock, dbc, _ := goSqlMock.New()
+	orm, err := db_repo.NewOrmWithInterfaces(logger, dbMock, db_repo.OrmSettings{
+		Driver: "mysql",
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	modelId := "gosoline.test.repo.myTestModel"
+	claimedUntil := now.Add(time.Second)
+	rows := goSqlMock.NewRows([]string{"id", "model_id", "type", "version", "attributes", "body", "attempts", "created_at", "claimed_until", "sent_at"}).
+		AddRow(1, modelId, "create", 1, `{"type":"create"}`, `{"id":1}`, 0, &now, nil, nil).
+		AddRow(2, modelId, "update", 1, `{"type":"update"}`, `{"id":1}`, 2, &now, nil, nil).
+		AddRow(3, modelId, "update", 1, `{"type":"update"}`, `{"id":1}`, 0, &now, nil, nil).
+		AddRow(4, modelId, "delete", 1, `{"type":"delete"}`, `{"id":1}`, 0, &now, nil, nil).
+		AddRow(5, modelId, "create", 1, `{"type":"create"}`, `{"id":2}`, 0, &now, &claimedUntil, nil)
+
+	dbc.ExpectBegin()
+	dbc.ExpectQuery("SELECT \\* FROM `outbox_messages` WHERE \\(sent_at IS NULL AND attempts < \\?\\) AND \\(model_id = \\?\\) ORDER BY id ASC LIMIT 5 FOR UPDATE").WithArgs(3, modelId).WillReturnRows(rows)
+	dbc.ExpectExec("UPDATE `outbox_messages` SET `claimed_until` = \\? WHERE \\(id IN \\(\\?,\\?,\\?,\\?\\)\\)").WithArgs(now.Add(time.Minute), 1, 2, 3, 4).WillReturnResult(goSqlMock.NewResult(0, 4))
+	dbc.ExpectCommit()
+
+	dbc.ExpectBegin()
+	dbc.ExpectExec("UPDATE `outbox_messages` SET `claimed_until` = \\?, `sent_at` = \\? WHERE \\(id IN \\(\\?\\)\\)").WithArgs(nil, &now, 1).WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectExec("UPDATE `outbox_messages` SET `attempts` = attempts \\+ \\?, `claimed_until` = \\? WHERE \\(id IN \\(\\?,\\?\\)\\)").WithArgs(1, nil, 2, 3).WillReturnResult(goSqlMock.NewResult(0, 2))
+	dbc.ExpectExec("UPDATE `outbox_messages` SET `claimed_until` = \\? WHERE \\(id IN \\(\\?\\)\\)").WithArgs(nil, 4).WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectCommit()
+
+	dbc.ExpectExec("DELETE FROM `outbox_messages` WHERE \\(sent_at IS NOT NULL AND sent_at < \\?\\) AND \\(model_id = \\?\\)").WithArgs(now.Add(-time.Hour), modelId).WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectExec("DELETE FROM `outbox_messages` WHERE \\(sent_at IS NULL AND attempts >= \\? AND created_at < \\?\\) AND \\(model_id = \\?\\)").WithArgs(3, now.Add(-2*time.Hour), modelId).WillReturnResult(goSqlMock.NewResult(0, 1))
+
+	sender := new(mocks.OutboxSender)
+	sender.On("Send", ctx, mock.MatchedBy(func(msg *db_repo.OutboxMessage) bool {
+		return *msg.Id == 1
+	})).Return(nil).Once()
+	sender.On("Send", ctx, mock.MatchedBy(func(msg *db_repo.OutboxMessage) bool {
+		return *msg.Id == 2
+	})).Return(assert.AnError).Once()
+	sender.On("Send", ctx, mock.MatchedBy(func(msg *db_repo.OutboxMessage) bool {
+		return *msg.Id == 3
+	})).Return(assert.AnError).Run(func(args mock.Arguments) {
+		cancel()
+	}).Once()
+
+	relay := db_repo.NewOutboxRelayWithInterfaces(logger, metric, orm, clockwork.NewFakeClockAt(now), sender, &db_repo.OutboxRelaySettings{
+		Name:            "test",
+		ModelId:         modelId,
+		Interval:        10 * time.Millisecond,
+		BatchSize:       5,
+		MaxAttempts:     3,
+		ClaimTimeout:    time.Minute,
+		Retention:       time.Hour,
+		FailedRetention: 2 * time.Hour,
+	})
+
+	err = relay.Run(ctx)
+
+	assert.NoError(t, err)
+	assert.NoError(t, dbc.ExpectationsWereMet(), "there should be no unfulfilled expectations")
+	sender.AssertExpectations(t)
+}
+
+func getOutboxMocks(t *testing.T, time time.Time) (goSqlMock.Sqlmock, db_repo.Repository) {
+	logger := monMocks.NewLoggerMockedAll()
+	tracer := tracing.NewNoopTracer()
+
+	dbMock, clientMock, _ := goSqlMock.New()
+
+	orm, err := db_repo.NewOrmWithInterfaces(logger, dbMock, db_repo.OrmSettings{
+		Driver: "mysql",
+	})
+	if err != nil {
+		assert.FailNow(t, err.Error())
+	}
+
+	clock := clockwork.NewFakeClockAt(time)
+
+	transactor, _ := db.NewTransactorWithInterfaces(logger, dbMock, exec.NewDefaultExecutor(), &db.TransactionSettings{
+		Isolation: "default",
+	})
+
+	repo := db_repo.NewWithInterfaces(logger, tracer, orm, transactor, nil, clock, db_repo.Settings{
+		Metadata: db_repo.Metadata{
+			ModelId: mdl.ModelId{
+				Project:     "gosoline",
+				Family:      "test",
+				Application: "repo",
+				Name:        "myTestModel",
+			},
+		},
+		Outbox: db_repo.OutboxSettings{
+			Enabled: true,
+			Version: 1,
+		},
+	})
+
+	return clientMock, repo
+}
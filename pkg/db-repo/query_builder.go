@@ -23,6 +23,8 @@ type QueryBuilder struct {
 	groupBy []string
 	orderBy []order
 	page    *page
+
+	withDeleted bool
 }
 
 func NewQueryBuilder() *QueryBuilder {
@@ -77,3 +79,10 @@ func (qb *QueryBuilder) Page(offset int, size int) db.QueryBuilder {
 
 	return qb
 }
+
+// WithDeleted includes soft deleted models in the results of Query and Count
+func (qb *QueryBuilder) WithDeleted() *QueryBuilder {
+	qb.withDeleted = true
+
+	return qb
+}
@@ -2,6 +2,7 @@ package db_repo
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/db"
@@ -13,6 +14,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -29,6 +31,7 @@ var operations = []string{Create, Read, Update, Delete, Query}
 type Settings struct {
 	cfg.AppId
 	Metadata Metadata
+	Outbox   OutboxSettings
 }
 
 //go:generate mockery -name Repository
@@ -39,6 +42,7 @@ type Repository interface {
 	Delete(ctx context.Context, value ModelBased) error
 	Query(ctx context.Context, qb *QueryBuilder, result interface{}) error
 	Count(ctx context.Context, qb *QueryBuilder, model ModelBased) (int, error)
+	WithTx(ctx context.Context, f db.TxFunc, options ...db.TxOption) error
 
 	GetModelId() string
 	GetModelName() string
@@ -46,11 +50,18 @@ type Repository interface {
 }
 
 type repository struct {
-	logger   mon.Logger
-	tracer   tracing.Tracer
-	orm      *gorm.DB
-	clock    clockwork.Clock
-	settings Settings
+	logger     mon.Logger
+	tracer     tracing.Tracer
+	orm        *gorm.DB
+	conn       *sql.DB
+	transactor db.Transactor
+	replicas   db.ReplicaSet
+	clock      clockwork.Clock
+	outbox     *outbox
+	settings   Settings
+
+	replicaLck  sync.Mutex
+	replicaOrms map[*sql.DB]*gorm.DB
 }
 
 func New(config cfg.Config, logger mon.Logger, s Settings) (*repository, error) {
@@ -61,24 +72,47 @@ func New(config cfg.Config, logger mon.Logger, s Settings) (*repository, error)
 		return nil, fmt.Errorf("can not create orm: %w", err)
 	}
 
-	orm.Callback().
-		Update().
-		After("gorm:update_time_stamp").
-		Register("gosoline:ignore_created_at_if_needed", ignoreCreatedAtIfNeeded)
+	registerCallbacks(orm)
+
+	transactor, err := db.NewTransactor(config, logger, "default")
+	if err != nil {
+		return nil, fmt.Errorf("can not create transactor: %w", err)
+	}
+
+	replicas, err := db.ProvideReplicaSet(config, logger, "default")
+	if err != nil {
+		return nil, fmt.Errorf("can not create replica set: %w", err)
+	}
+
 	clock := clockwork.NewRealClock()
 
 	s.PadFromConfig(config)
 
-	return NewWithInterfaces(logger, tracer, orm, clock, s), nil
+	return NewWithInterfaces(logger, tracer, orm, transactor, replicas, clock, s), nil
 }
 
-func NewWithInterfaces(logger mon.Logger, tracer tracing.Tracer, orm *gorm.DB, clock clockwork.Clock, settings Settings) *repository {
+// NewWithInterfaces creates a repository sending Read, Query and Count to the replicas, which can be nil to read from the primary
+func NewWithInterfaces(logger mon.Logger, tracer tracing.Tracer, orm *gorm.DB, transactor db.Transactor, replicas db.ReplicaSet, clock clockwork.Clock, settings Settings) *repository {
+	var ob *outbox
+
+	if settings.Outbox.Enabled {
+		ob = newOutbox(clock, settings.Metadata.ModelId, settings.Outbox)
+	}
+
+	// the connection is only known if the orm isn't bound to a transaction itself
+	conn, _ := orm.CommonDB().(*sql.DB)
+
 	return &repository{
-		logger:   logger,
-		tracer:   tracer,
-		orm:      orm,
-		clock:    clock,
-		settings: settings,
+		logger:      logger,
+		tracer:      tracer,
+		orm:         orm,
+		conn:        conn,
+		transactor:  transactor,
+		replicas:    replicas,
+		clock:       clock,
+		outbox:      ob,
+		settings:    settings,
+		replicaOrms: make(map[*sql.DB]*gorm.DB),
 	}
 }
 
@@ -93,30 +127,42 @@ func (r *repository) Create(ctx context.Context, value ModelBased) error {
 	value.SetUpdatedAt(&now)
 	value.SetCreatedAt(&now)
 
-	err := r.orm.Create(value).Error
+	if versioned, ok := value.(Versionable); ok {
+		versioned.SetVersion(1)
+	}
+
+	err := r.transaction(ctx, false, func(tx *gorm.DB) error {
+		err := tx.Create(value).Error
 
-	if db.IsDuplicateEntryError(err) {
-		logger.Warnf("could not create model of type %s due to duplicate entry error: %s", modelId, err.Error())
-		return &db.DuplicateEntryError{
-			Err: err,
+		if db.IsDuplicateEntryError(err) {
+			logger.Warnf("could not create model of type %s due to duplicate entry error: %s", modelId, err.Error())
+			return &db.DuplicateEntryError{
+				Err: err,
+			}
 		}
-	}
 
-	if err != nil {
-		logger.Errorf(err, "could not create model of type %v", modelId)
-		return err
-	}
+		if err != nil {
+			logger.Errorf(err, "could not create model of type %v", modelId)
+			return err
+		}
+
+		err = r.refreshAssociations(tx, value, Create)
+
+		if err != nil {
+			logger.Errorf(err, "could not update associations of model type %v", modelId)
+			return err
+		}
 
-	err = r.refreshAssociations(value, Create)
+		return r.writeOutbox(ctx, tx, Create, value)
+	})
 
 	if err != nil {
-		logger.Errorf(err, "could not update associations of model type %v", modelId)
 		return err
 	}
 
 	logger.Infof("created model of type %s with id %d", modelId, *value.GetId())
 
-	return r.Read(ctx, value.GetId(), value)
+	return r.Read(db.WithPrimary(ctx), value.GetId(), value)
 }
 
 func (r *repository) Read(ctx context.Context, id *uint, out ModelBased) error {
@@ -124,7 +170,13 @@ func (r *repository) Read(ctx context.Context, id *uint, out ModelBased) error {
 	_, span := r.startSubSpan(ctx, "Get")
 	defer span.Finish()
 
-	err := r.orm.First(out, *id).Error
+	orm, err := r.readOrmFor(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	err = orm.First(out, *id).Error
 
 	if gorm.IsRecordNotFoundError(err) {
 		return NewRecordNotFoundError(*id, modelId, err)
@@ -143,30 +195,56 @@ func (r *repository) Update(ctx context.Context, value ModelBased) error {
 	now := r.clock.Now()
 	value.SetUpdatedAt(&now)
 
-	err := r.orm.Save(value).Error
+	_, versioned := value.(Versionable)
 
-	if db.IsDuplicateEntryError(err) {
-		logger.Warnf("could not update model of type %s with id %d due to duplicate entry error: %s", modelId, mdl.EmptyUintIfNil(value.GetId()), err.Error())
-		return &db.DuplicateEntryError{
-			Err: err,
-		}
-	}
+	undelete := isUndelete(ctx)
 
-	if err != nil {
-		logger.Errorf(err, "could not update model of type %s with id %d", modelId, mdl.EmptyUintIfNil(value.GetId()))
-		return err
+	if deletable, ok := value.(SoftDeletable); ok && undelete {
+		deletable.SetDeletedAt(nil)
 	}
 
-	err = r.refreshAssociations(value, Update)
+	err := r.transaction(ctx, versioned, func(tx *gorm.DB) error {
+		err := r.save(tx, value, undelete)
+
+		if IsRecordNotFoundError(err) {
+			logger.Warnf("could not update model of type %s with id %d: %s", modelId, mdl.EmptyUintIfNil(value.GetId()), err.Error())
+			return err
+		}
+
+		if IsStaleObjectError(err) {
+			logger.Warnf("could not update model of type %s with id %d: %s", modelId, mdl.EmptyUintIfNil(value.GetId()), err.Error())
+			return err
+		}
+
+		if db.IsDuplicateEntryError(err) {
+			logger.Warnf("could not update model of type %s with id %d due to duplicate entry error: %s", modelId, mdl.EmptyUintIfNil(value.GetId()), err.Error())
+			return &db.DuplicateEntryError{
+				Err: err,
+			}
+		}
+
+		if err != nil {
+			logger.Errorf(err, "could not update model of type %s with id %d", modelId, mdl.EmptyUintIfNil(value.GetId()))
+			return err
+		}
+
+		err = r.refreshAssociations(tx, value, Update)
+
+		if err != nil {
+			logger.Errorf(err, "could not update associations of model type %s with id %d", modelId, *value.GetId())
+			return err
+		}
+
+		return r.writeOutbox(ctx, tx, Update, value)
+	})
 
 	if err != nil {
-		logger.Errorf(err, "could not update associations of model type %s with id %d", modelId, *value.GetId())
 		return err
 	}
 
 	logger.Infof("updated model of type %s with id %d", modelId, *value.GetId())
 
-	return r.Read(ctx, value.GetId(), value)
+	return r.Read(db.WithPrimary(ctx), value.GetId(), value)
 }
 
 func (r *repository) Delete(ctx context.Context, value ModelBased) error {
@@ -176,29 +254,52 @@ func (r *repository) Delete(ctx context.Context, value ModelBased) error {
 	_, span := r.startSubSpan(ctx, "Delete")
 	defer span.Finish()
 
-	err := r.refreshAssociations(value, Delete)
+	deletable, softDeletable := value.(SoftDeletable)
+	_, versioned := value.(Versionable)
 
-	if err != nil {
-		logger.Errorf(err, "could not delete associations of model type %s with id %d", modelId, *value.GetId())
-		return err
-	}
+	// versioned models are locked before they are soft deleted, which requires a transaction
+	err := r.transaction(ctx, softDeletable && versioned, func(tx *gorm.DB) error {
+		if softDeletable {
+			return r.softDelete(ctx, tx, deletable, value)
+		}
+
+		err := r.refreshAssociations(tx, value, Delete)
+
+		if err != nil {
+			logger.Errorf(err, "could not delete associations of model type %s with id %d", modelId, *value.GetId())
+			return err
+		}
+
+		err = tx.Delete(value).Error
+
+		if err != nil {
+			logger.Errorf(err, "could not delete model of type %s with id %d", modelId, *value.GetId())
+			return err
+		}
 
-	err = r.orm.Delete(value).Error
+		return r.writeOutbox(ctx, tx, Delete, value)
+	})
 
 	if err != nil {
-		logger.Errorf(err, "could not delete model of type %s with id %d", modelId, *value.GetId())
+		return err
 	}
 
 	logger.Infof("deleted model of type %s with id %d", modelId, *value.GetId())
 
-	return err
+	return nil
 }
 
 func (r *repository) Query(ctx context.Context, qb *QueryBuilder, result interface{}) error {
 	_, span := r.startSubSpan(ctx, "Query")
 	defer span.Finish()
 
-	db := r.orm.New()
+	orm, err := r.readOrmFor(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	db := orm.New()
 
 	for _, j := range qb.joins {
 		db = db.Joins(j)
@@ -216,12 +317,16 @@ func (r *repository) Query(ctx context.Context, qb *QueryBuilder, result interfa
 		db = db.Order(fmt.Sprintf("%s %s", o.field, o.direction))
 	}
 
+	if qb.withDeleted {
+		db = db.Unscoped()
+	}
+
 	if qb.page != nil {
 		db = db.Offset(qb.page.offset)
 		db = db.Limit(qb.page.limit)
 	}
 
-	err := db.Find(result).Error
+	err = db.Find(result).Error
 
 	if gorm.IsRecordNotFoundError(err) {
 		return NewNoQueryResultsError(r.GetModelId(), err)
@@ -238,7 +343,13 @@ func (r *repository) Count(ctx context.Context, qb *QueryBuilder, model ModelBas
 		Count int
 	}{}
 
-	db := r.orm.New()
+	orm, err := r.readOrmFor(ctx)
+
+	if err != nil {
+		return 0, err
+	}
+
+	db := orm.New()
 
 	for _, j := range qb.joins {
 		db = db.Joins(j)
@@ -248,17 +359,273 @@ func (r *repository) Count(ctx context.Context, qb *QueryBuilder, model ModelBas
 		db = db.Where(qb.where[i], qb.args[i]...)
 	}
 
-	scope := r.orm.NewScope(model)
+	scope := orm.NewScope(model)
 	tableName := scope.TableName()
 	key := scope.PrimaryKey()
+
+	// gorm only filters soft deleted rows itself if the scanned value is the model
+	if _, ok := model.(SoftDeletable); ok && !qb.withDeleted {
+		db = db.Where(fmt.Sprintf("%s.%s IS NULL", tableName, ColumnDeletedAt))
+	}
 	sel := fmt.Sprintf("COUNT(DISTINCT %s.%s) AS count", tableName, key)
 
-	err := db.Table(tableName).Select(sel).Scan(&result).Error
+	err = db.Table(tableName).Select(sel).Scan(&result).Error
 
 	return result.Count, err
 }
 
-func (r *repository) refreshAssociations(model interface{}, op string) error {
+// WithTx runs the function in a transaction which every repository and db.Client call with the context joins.
+// Notifiers send their notifications right away even if the transaction is rolled back later on, use the
+// outbox to send them only after the commit.
+func (r *repository) WithTx(ctx context.Context, f db.TxFunc, options ...db.TxOption) error {
+	return r.transactor.WithTx(ctx, f, options...)
+}
+
+// save updates all columns of the model. Versioned models lock their row first and are only updated if it still
+// has the version of the model, so save has to run in a transaction for them. Soft deleted rows are only
+// updated to undelete them.
+func (r *repository) save(tx *gorm.DB, value ModelBased, undelete bool) error {
+	if undelete {
+		tx = tx.Unscoped()
+	}
+
+	versioned, ok := value.(Versionable)
+
+	if !ok {
+		return tx.Save(value).Error
+	}
+
+	version := versioned.GetVersion()
+
+	if err := r.lockVersion(tx, value, version, undelete); err != nil {
+		return err
+	}
+
+	versioned.SetVersion(version + 1)
+
+	if err := tx.Save(value).Error; err != nil {
+		versioned.SetVersion(version)
+		return err
+	}
+
+	return nil
+}
+
+// lockVersion locks the row of the model and checks it wasn't deleted or updated since the model was read
+func (r *repository) lockVersion(tx *gorm.DB, value ModelBased, version uint, undelete bool) error {
+	id := mdl.EmptyUintIfNil(value.GetId())
+	scope := tx.NewScope(value)
+	columns := []string{ColumnVersion}
+
+	if _, ok := value.(SoftDeletable); ok {
+		columns = append(columns, ColumnDeletedAt)
+	}
+
+	current := &struct {
+		Version   uint
+		DeletedAt *time.Time
+	}{}
+
+	err := tx.New().Unscoped().
+		Set("gorm:query_option", "FOR UPDATE").
+		Table(scope.TableName()).
+		Select(columns).
+		Where(fmt.Sprintf("%s = ?", scope.PrimaryKey()), id).
+		Scan(current).Error
+
+	if gorm.IsRecordNotFoundError(err) {
+		return NewRecordNotFoundError(id, r.GetModelId(), err)
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not lock model of type %s with id %d: %w", r.GetModelId(), id, err)
+	}
+
+	if current.DeletedAt != nil && !undelete {
+		return NewRecordNotFoundError(id, r.GetModelId(), fmt.Errorf("the model was deleted at %s", current.DeletedAt.Format(time.RFC3339)))
+	}
+
+	if current.Version != version {
+		return NewStaleObjectError(id, r.GetModelId(), version)
+	}
+
+	return nil
+}
+
+// softDelete keeps the associations, so the model is complete again if deleted_at is reset. Only rows which aren't
+// deleted yet are updated. Versioned models are locked like in save and their version is incremented.
+func (r *repository) softDelete(ctx context.Context, tx *gorm.DB, deletable SoftDeletable, value ModelBased) error {
+	logger := r.logger.WithContext(ctx)
+	id := mdl.EmptyUintIfNil(value.GetId())
+	now := r.clock.Now()
+	columns := map[string]interface{}{
+		ColumnDeletedAt: &now,
+	}
+
+	versioned, isVersioned := value.(Versionable)
+	version := uint(0)
+
+	if isVersioned {
+		version = versioned.GetVersion()
+
+		if err := r.lockVersion(tx, value, version, false); err != nil {
+			logger.Warnf("could not soft delete model of type %s with id %d: %s", r.GetModelId(), id, err.Error())
+			return err
+		}
+
+		columns[ColumnVersion] = version + 1
+	}
+
+	reset := func() {
+		deletable.SetDeletedAt(nil)
+
+		if isVersioned {
+			versioned.SetVersion(version)
+		}
+	}
+
+	result := tx.Unscoped().
+		Model(value).
+		Where(fmt.Sprintf("%s IS NULL", ColumnDeletedAt)).
+		UpdateColumns(columns)
+
+	if result.Error != nil {
+		reset()
+		logger.Errorf(result.Error, "could not soft delete model of type %s with id %d", r.GetModelId(), id)
+
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		reset()
+		err := NewRecordNotFoundError(id, r.GetModelId(), fmt.Errorf("the model does not exist or was deleted already"))
+		logger.Warnf("could not soft delete model of type %s with id %d: %s", r.GetModelId(), id, err.Error())
+
+		return err
+	}
+
+	deletable.SetDeletedAt(&now)
+
+	if isVersioned {
+		versioned.SetVersion(version + 1)
+	}
+
+	return r.writeOutbox(ctx, tx, Delete, value)
+}
+
+type undeleteContextKey struct{}
+
+// withUndelete lets Update write soft deleted models and reset their deletion, which restores them
+func withUndelete(ctx context.Context) context.Context {
+	return context.WithValue(ctx, undeleteContextKey{}, true)
+}
+
+func isUndelete(ctx context.Context) bool {
+	undelete, ok := ctx.Value(undeleteContextKey{}).(bool)
+
+	return ok && undelete
+}
+
+// transaction runs the write in the transaction of the context or in a new one if the notifications have
+// to be written to the outbox or the write requires a transaction itself
+func (r *repository) transaction(ctx context.Context, requireTx bool, write func(tx *gorm.DB) error) error {
+	if _, ok := r.txFromContext(ctx); ok || (r.outbox == nil && !requireTx) {
+		orm, err := r.ormFor(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		return write(orm)
+	}
+
+	return r.transactor.WithTx(ctx, func(ctx context.Context) error {
+		orm, err := r.ormFor(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		return write(orm)
+	})
+}
+
+// ormFor returns an orm bound to the transaction of the context or the orm of the repository
+func (r *repository) ormFor(ctx context.Context) (*gorm.DB, error) {
+	if r.conn == nil {
+		return r.orm, nil
+	}
+
+	orm, ok, err := txOrm(ctx, r.conn, r.orm)
+
+	if !ok {
+		return r.orm, nil
+	}
+
+	return orm, err
+}
+
+// readOrmFor returns the orm of the transaction or of a healthy replica and falls back to the orm of the repository
+func (r *repository) readOrmFor(ctx context.Context) (*gorm.DB, error) {
+	if _, ok := r.txFromContext(ctx); ok {
+		return r.ormFor(ctx)
+	}
+
+	replica, ok := db.SelectReplica(ctx, r.replicas)
+
+	if !ok {
+		return r.orm, nil
+	}
+
+	r.replicaLck.Lock()
+	defer r.replicaLck.Unlock()
+
+	if orm, ok := r.replicaOrms[replica.DB]; ok {
+		return orm, nil
+	}
+
+	orm, err := gorm.Open(r.orm.Dialect().GetName(), replica.DB)
+
+	if err != nil {
+		return nil, fmt.Errorf("could not create gorm for replica: %w", err)
+	}
+
+	orm = configureOrm(orm)
+	r.replicaOrms[replica.DB] = orm
+
+	return orm, nil
+}
+
+func (r *repository) txFromContext(ctx context.Context) (*sql.Tx, bool) {
+	if r.conn == nil {
+		return nil, false
+	}
+
+	return db.TxFromContext(ctx, r.conn)
+}
+
+// writeOutbox stores the notification for the change in the outbox. Created and updated models are read
+// again first, so the notification contains the same data as a notifier would send.
+func (r *repository) writeOutbox(ctx context.Context, tx *gorm.DB, notificationType string, value ModelBased) error {
+	if r.outbox == nil {
+		return nil
+	}
+
+	if notificationType != Delete {
+		if err := tx.First(value, *value.GetId()).Error; err != nil {
+			return fmt.Errorf("can not read model of type %s with id %d for the outbox: %w", r.GetModelId(), *value.GetId(), err)
+		}
+	}
+
+	if err := r.outbox.write(ctx, tx, notificationType, value); err != nil {
+		r.logger.WithContext(ctx).Error(err, "can not write notification to the outbox")
+		return err
+	}
+
+	return nil
+}
+
+func (r *repository) refreshAssociations(orm *gorm.DB, model interface{}, op string) error {
 	typeReflection := reflect.TypeOf(model).Elem()
 	valueReflection := reflect.ValueOf(model).Elem()
 
@@ -289,7 +656,7 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 		var err error
 
 		values := valueReflection.Field(i)
-		scope := r.orm.NewScope(model)
+		scope := orm.NewScope(model)
 		scopeField, _ := scope.FieldByName(field.Name)
 
 		switch op {
@@ -299,7 +666,7 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 		case Update:
 			switch scopeField.Relationship.Kind {
 			case "many_to_many":
-				err = r.orm.Model(model).Association(scopeField.Name).Replace(values.Interface()).Error
+				err = orm.Model(model).Association(scopeField.Name).Replace(values.Interface()).Error
 
 			default:
 				assocIds := readIdsFromReflectValue(values)
@@ -316,7 +683,7 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 					qry = qry + fmt.Sprintf(" AND %s NOT IN (%s)", "id", strings.Join(assocIds, ","))
 				}
 
-				err = r.orm.Exec(qry).Error
+				err = orm.Exec(qry).Error
 			}
 
 		case Delete:
@@ -330,10 +697,10 @@ func (r *repository) refreshAssociations(model interface{}, op string) error {
 				}
 
 				qry := fmt.Sprintf("DELETE FROM %s WHERE %s = %d", tableName, scopeField.Relationship.ForeignDBNames[0], id)
-				err = r.orm.Exec(qry).Error
+				err = orm.Exec(qry).Error
 
 			default:
-				err = r.orm.Model(model).Association(field.Name).Clear().Error
+				err = orm.Model(model).Association(field.Name).Clear().Error
 			}
 
 		default:
@@ -4,10 +4,14 @@ import (
 	"context"
 	"database/sql/driver"
 	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db"
 	"github.com/applike/gosoline/pkg/db-repo"
+	dbMocks "github.com/applike/gosoline/pkg/db/mocks"
+	"github.com/applike/gosoline/pkg/exec"
 	"github.com/applike/gosoline/pkg/mdl"
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/jmoiron/sqlx"
 	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -77,6 +81,89 @@ func TestRepository_Create(t *testing.T) {
 	assert.Equal(t, &now, model.CreatedAt, "CreatedAt should match")
 }
 
+func TestRepository_WithTx(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now)
+
+	dbc.ExpectBegin()
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id1, &now, &now).WillReturnResult(goSqlMock.NewResult(0, 1))
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now)
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(rows)
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id42, &now, &now).WillReturnResult(goSqlMock.NewResult(0, 1))
+	rows = goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id42, &now, &now)
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(rows)
+	dbc.ExpectCommit()
+
+	err := repo.WithTx(context.Background(), func(ctx context.Context) error {
+		if err := repo.Create(ctx, &MyTestModel{Model: db_repo.Model{Id: id1}}); err != nil {
+			return err
+		}
+
+		return repo.Create(ctx, &MyTestModel{Model: db_repo.Model{Id: id42}})
+	})
+
+	assert.NoError(t, err, "there should not be an error")
+	assert.NoError(t, dbc.ExpectationsWereMet(), "there should be no unfulfilled expectations")
+}
+
+func TestRepository_ReadReplica(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	logger := monMocks.NewLoggerMockedAll()
+
+	primaryMock, primary, _ := goSqlMock.New()
+	replicaMock, replica, _ := goSqlMock.New()
+
+	orm, err := db_repo.NewOrmWithInterfaces(logger, primaryMock, db_repo.OrmSettings{
+		Driver: "mysql",
+	})
+	assert.NoError(t, err)
+
+	replicas := new(dbMocks.ReplicaSet)
+	replicas.On("Replica").Return(sqlx.NewDb(replicaMock, "mysql"), true)
+
+	transactor, _ := db.NewTransactorWithInterfaces(logger, primaryMock, exec.NewDefaultExecutor(), &db.TransactionSettings{
+		Isolation: "default",
+	})
+
+	repo := db_repo.NewWithInterfaces(logger, tracing.NewNoopTracer(), orm, transactor, replicas, clockwork.NewFakeClockAt(now), db_repo.Settings{})
+
+	primary.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id1, &now, &now).WillReturnResult(goSqlMock.NewResult(0, 1))
+	primary.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now))
+	replica.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now))
+
+	err = repo.Create(context.Background(), &MyTestModel{Model: db_repo.Model{Id: id1}})
+	assert.NoError(t, err)
+
+	err = repo.Read(context.Background(), id1, &MyTestModel{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
+
+func TestRepository_WithTxRollback(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now)
+
+	dbc.ExpectBegin()
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id1, &now, &now).WillReturnResult(goSqlMock.NewResult(0, 1))
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at"}).AddRow(id1, &now, &now)
+	dbc.ExpectQuery("SELECT \\* FROM `my_test_models`").WillReturnRows(rows)
+	dbc.ExpectExec("INSERT INTO `my_test_models`").WithArgs(id42, &now, &now).WillReturnError(assert.AnError)
+	dbc.ExpectRollback()
+
+	err := repo.WithTx(context.Background(), func(ctx context.Context) error {
+		if err := repo.Create(ctx, &MyTestModel{Model: db_repo.Model{Id: id1}}); err != nil {
+			return err
+		}
+
+		return repo.Create(ctx, &MyTestModel{Model: db_repo.Model{Id: id42}})
+	})
+
+	assert.Equal(t, assert.AnError, err, "the error of the second create should be returned")
+	assert.NoError(t, dbc.ExpectationsWereMet(), "there should be no unfulfilled expectations")
+}
+
 func TestRepository_CreateManyToManyNoRelation(t *testing.T) {
 	now := time.Unix(1549964818, 0)
 	dbc, repo := getTimedMocks(t, now)
@@ -679,8 +766,8 @@ func getMocks(t *testing.T) (goSqlMock.Sqlmock, db_repo.Repository) {
 	logger := monMocks.NewLoggerMockedAll()
 	tracer := tracing.NewNoopTracer()
 
-	db, clientMock, _ := goSqlMock.New()
-	orm, err := db_repo.NewOrmWithInterfaces(logger, db, db_repo.OrmSettings{
+	dbMock, clientMock, _ := goSqlMock.New()
+	orm, err := db_repo.NewOrmWithInterfaces(logger, dbMock, db_repo.OrmSettings{
 		Driver: "mysql",
 	})
 	if err != nil {
@@ -689,7 +776,11 @@ func getMocks(t *testing.T) (goSqlMock.Sqlmock, db_repo.Repository) {
 
 	clock := clockwork.NewFakeClock()
 
-	repo := db_repo.NewWithInterfaces(logger, tracer, orm, clock, db_repo.Settings{})
+	transactor, _ := db.NewTransactorWithInterfaces(logger, dbMock, exec.NewDefaultExecutor(), &db.TransactionSettings{
+		Isolation: "default",
+	})
+
+	repo := db_repo.NewWithInterfaces(logger, tracer, orm, transactor, nil, clock, db_repo.Settings{})
 
 	return clientMock, repo
 }
@@ -698,9 +789,9 @@ func getTimedMocks(t *testing.T, time time.Time) (goSqlMock.Sqlmock, db_repo.Rep
 	logger := monMocks.NewLoggerMockedAll()
 	tracer := tracing.NewNoopTracer()
 
-	db, clientMock, _ := goSqlMock.New()
+	dbMock, clientMock, _ := goSqlMock.New()
 
-	orm, err := db_repo.NewOrmWithInterfaces(logger, db, db_repo.OrmSettings{
+	orm, err := db_repo.NewOrmWithInterfaces(logger, dbMock, db_repo.OrmSettings{
 		Driver: "mysql",
 	})
 	if err != nil {
@@ -709,7 +800,216 @@ func getTimedMocks(t *testing.T, time time.Time) (goSqlMock.Sqlmock, db_repo.Rep
 
 	clock := clockwork.NewFakeClockAt(time)
 
-	repo := db_repo.NewWithInterfaces(logger, tracer, orm, clock, db_repo.Settings{})
+	transactor, _ := db.NewTransactorWithInterfaces(logger, dbMock, exec.NewDefaultExecutor(), &db.TransactionSettings{
+		Isolation: "default",
+	})
+
+	repo := db_repo.NewWithInterfaces(logger, tracer, orm, transactor, nil, clock, db_repo.Settings{})
 
 	return clientMock, repo
 }
+
+type VersionedModel struct {
+	db_repo.Model
+	db_repo.Versioned
+	Name string
+}
+
+type SoftDeletedModel struct {
+	db_repo.Model
+	db_repo.SoftDeletion
+}
+
+func TestRepository_UpdateVersioned(t *testing.T) {
+	dbc, repo := getMocks(t)
+	now := time.Unix(1549964818, 0)
+
+	dbc.ExpectBegin()
+	dbc.ExpectQuery("SELECT version FROM `versioned_models` WHERE \\(id = \\?\\) FOR UPDATE").
+		WithArgs(*id1).
+		WillReturnRows(goSqlMock.NewRows([]string{"version"}).AddRow(3))
+	dbc.ExpectExec("UPDATE `versioned_models` SET `updated_at` = \\?, `created_at` = \\?, `version` = \\?, `name` = \\?  WHERE `versioned_models`\\.`id` = \\?").
+		WithArgs(goSqlMock.AnyArg(), goSqlMock.AnyArg(), 4, "new", id1).
+		WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectCommit()
+
+	rows := goSqlMock.NewRows([]string{"id", "updated_at", "created_at", "version", "name"}).AddRow(id1, &now, &now, 4, "new")
+	dbc.ExpectQuery("SELECT \\* FROM `versioned_models`").WillReturnRows(rows)
+
+	model := &VersionedModel{
+		Model:     db_repo.Model{Id: id1},
+		Versioned: db_repo.Versioned{Version: 3},
+		Name:      "new",
+	}
+
+	err := repo.Update(context.Background(), model)
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint(4), model.Version)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_UpdateVersioned_Stale(t *testing.T) {
+	dbc, repo := getMocks(t)
+
+	dbc.ExpectBegin()
+	dbc.ExpectQuery("SELECT version FROM `versioned_models` WHERE \\(id = \\?\\) FOR UPDATE").
+		WithArgs(*id1).
+		WillReturnRows(goSqlMock.NewRows([]string{"version"}).AddRow(4))
+	dbc.ExpectRollback()
+
+	model := &VersionedModel{
+		Model:     db_repo.Model{Id: id1},
+		Versioned: db_repo.Versioned{Version: 3},
+		Name:      "new",
+	}
+
+	err := repo.Update(context.Background(), model)
+
+	assert.True(t, db_repo.IsStaleObjectError(err))
+	assert.Equal(t, uint(3), model.Version)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+type VersionedSoftDeletedModel struct {
+	db_repo.Model
+	db_repo.Versioned
+	db_repo.SoftDeletion
+}
+
+func TestRepository_UpdateVersioned_Deleted(t *testing.T) {
+	dbc, repo := getMocks(t)
+	now := time.Unix(1549964818, 0)
+
+	dbc.ExpectBegin()
+	dbc.ExpectQuery("SELECT version, deleted_at FROM `versioned_soft_deleted_models` WHERE \\(id = \\?\\) FOR UPDATE").
+		WithArgs(*id1).
+		WillReturnRows(goSqlMock.NewRows([]string{"version", "deleted_at"}).AddRow(3, &now))
+	dbc.ExpectRollback()
+
+	model := &VersionedSoftDeletedModel{
+		Model:     db_repo.Model{Id: id1},
+		Versioned: db_repo.Versioned{Version: 3},
+	}
+
+	err := repo.Update(context.Background(), model)
+
+	assert.True(t, db_repo.IsRecordNotFoundError(err))
+	assert.False(t, db_repo.IsStaleObjectError(err))
+	assert.Equal(t, uint(3), model.Version)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_SoftDelete(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now)
+
+	dbc.ExpectExec("UPDATE `soft_deleted_models` SET `deleted_at` = \\?  WHERE `soft_deleted_models`\\.`id` = \\? AND \\(\\(deleted_at IS NULL\\)\\)").
+		WithArgs(&now, id1).
+		WillReturnResult(goSqlMock.NewResult(0, 1))
+
+	model := &SoftDeletedModel{
+		Model: db_repo.Model{Id: id1},
+	}
+
+	err := repo.Delete(context.Background(), model)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &now, model.DeletedAt)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_SoftDelete_Deleted(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now)
+
+	dbc.ExpectExec("UPDATE `soft_deleted_models` SET `deleted_at` = \\?  WHERE `soft_deleted_models`\\.`id` = \\? AND \\(\\(deleted_at IS NULL\\)\\)").
+		WithArgs(&now, id1).
+		WillReturnResult(goSqlMock.NewResult(0, 0))
+
+	model := &SoftDeletedModel{
+		Model: db_repo.Model{Id: id1},
+	}
+
+	err := repo.Delete(context.Background(), model)
+
+	assert.True(t, db_repo.IsRecordNotFoundError(err))
+	assert.Nil(t, model.DeletedAt)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_SoftDeleteVersioned(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now)
+
+	dbc.ExpectBegin()
+	dbc.ExpectQuery("SELECT version, deleted_at FROM `versioned_soft_deleted_models` WHERE \\(id = \\?\\) FOR UPDATE").
+		WithArgs(*id1).
+		WillReturnRows(goSqlMock.NewRows([]string{"version", "deleted_at"}).AddRow(3, nil))
+	dbc.ExpectExec("UPDATE `versioned_soft_deleted_models` SET `deleted_at` = \\?, `version` = \\?  WHERE `versioned_soft_deleted_models`\\.`id` = \\? AND \\(\\(deleted_at IS NULL\\)\\)").
+		WithArgs(&now, 4, id1).
+		WillReturnResult(goSqlMock.NewResult(0, 1))
+	dbc.ExpectCommit()
+
+	model := &VersionedSoftDeletedModel{
+		Model:     db_repo.Model{Id: id1},
+		Versioned: db_repo.Versioned{Version: 3},
+	}
+
+	err := repo.Delete(context.Background(), model)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &now, model.DeletedAt)
+	assert.Equal(t, uint(4), model.Version)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_SoftDeleteVersioned_Stale(t *testing.T) {
+	now := time.Unix(1549964818, 0)
+	dbc, repo := getTimedMocks(t, now)
+
+	dbc.ExpectBegin()
+	dbc.ExpectQuery("SELECT version, deleted_at FROM `versioned_soft_deleted_models` WHERE \\(id = \\?\\) FOR UPDATE").
+		WithArgs(*id1).
+		WillReturnRows(goSqlMock.NewRows([]string{"version", "deleted_at"}).AddRow(4, nil))
+	dbc.ExpectRollback()
+
+	model := &VersionedSoftDeletedModel{
+		Model:     db_repo.Model{Id: id1},
+		Versioned: db_repo.Versioned{Version: 3},
+	}
+
+	err := repo.Delete(context.Background(), model)
+
+	assert.True(t, db_repo.IsStaleObjectError(err))
+	assert.Nil(t, model.DeletedAt)
+	assert.Equal(t, uint(3), model.Version)
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
+
+func TestRepository_SoftDeleteFilter(t *testing.T) {
+	dbc, repo := getMocks(t)
+
+	rows := goSqlMock.NewRows([]string{"id"}).AddRow(id1)
+	dbc.ExpectQuery("SELECT \\* FROM `soft_deleted_models` WHERE `soft_deleted_models`\\.`deleted_at` IS NULL AND").WillReturnRows(rows)
+
+	rows = goSqlMock.NewRows([]string{"count"}).AddRow(1)
+	dbc.ExpectQuery("SELECT COUNT\\(DISTINCT soft_deleted_models.id\\) AS count FROM `soft_deleted_models` WHERE \\(soft_deleted_models.deleted_at IS NULL\\)").WillReturnRows(rows)
+
+	rows = goSqlMock.NewRows([]string{"id"}).AddRow(id1)
+	dbc.ExpectQuery("SELECT \\* FROM `soft_deleted_models`$").WillReturnRows(rows)
+
+	err := repo.Read(context.Background(), id1, &SoftDeletedModel{})
+	assert.NoError(t, err)
+
+	count, err := repo.Count(context.Background(), db_repo.NewQueryBuilder(), &SoftDeletedModel{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	result := make([]SoftDeletedModel, 0)
+	err = repo.Query(context.Background(), db_repo.NewQueryBuilder().WithDeleted(), &result)
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+
+	assert.NoError(t, dbc.ExpectationsWereMet())
+}
@@ -27,6 +27,8 @@ type Result []ResultRow
 
 //go:generate mockery -name Client
 type Client interface {
+	Transactor
+
 	GetSingleScalarValue(query string, args ...interface{}) (int, error)
 	GetResult(query string, args ...interface{}) (*Result, error)
 	Exec(query string, args ...interface{}) (sql.Result, error)
@@ -36,11 +38,36 @@ type Client interface {
 	QueryRow(query string, args ...interface{}) *sql.Row
 	Select(dest interface{}, query string, args ...interface{}) error
 	Get(dest interface{}, query string, args ...interface{}) error
+
+	// the context variants join the transaction of a surrounding WithTx call
+	GetSingleScalarValueContext(ctx context.Context, query string, args ...interface{}) (int, error)
+	GetResultContext(ctx context.Context, query string, args ...interface{}) (*Result, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
+// sqlxConn is implemented by the connection and by transactions
+type sqlxConn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 }
 
 type ClientSqlx struct {
-	logger mon.Logger
-	db     *sqlx.DB
+	Transactor
+
+	logger   mon.Logger
+	db       *sqlx.DB
+	replicas ReplicaSet
 }
 
 func NewClient(config cfg.Config, logger mon.Logger, name string) Client {
@@ -50,23 +77,101 @@ func NewClient(config cfg.Config, logger mon.Logger, name string) Client {
 		logger.Fatal(err, "can not connect to sql database")
 	}
 
-	return NewClientWithInterfaces(logger, db)
+	settings := ReadTransactionSettings(config, name)
+	executor := NewTxExecutor(logger, settings.Backoff, name)
+	transactor, err := NewTransactorWithInterfaces(logger, db.DB, executor, settings)
+
+	if err != nil {
+		logger.Fatal(err, "can not create transactor")
+	}
+
+	replicas, err := ProvideReplicaSet(config, logger, name)
+
+	if err != nil {
+		logger.Fatal(err, "can not create replica set")
+	}
+
+	return NewClientWithInterfaces(logger, db, replicas, transactor)
 }
 
-func NewClientWithInterfaces(logger mon.Logger, db *sqlx.DB) Client {
+// NewClientWithInterfaces creates a client sending the reads to the replicas, which can be nil to read from the primary
+func NewClientWithInterfaces(logger mon.Logger, db *sqlx.DB, replicas ReplicaSet, transactor Transactor) Client {
 	if db == nil {
 		logger.WithContext(context.Background()).Fatal(errors.New("db not booted yet"), "db not booted yet")
 	}
 
 	return &ClientSqlx{
-		logger: logger.WithContext(context.Background()), // TODO: this is not nice, but we don't (yet) have a context when logging in this module
-		db:     db,
+		Transactor: transactor,
+		logger:     logger.WithContext(context.Background()), // TODO: this is not nice, but we don't (yet) have a context when logging in this module
+		db:         db,
+		replicas:   replicas,
 	}
 }
 
+// conn returns the transaction of a surrounding WithTx call or the connection itself
+func (c *ClientSqlx) conn(ctx context.Context) sqlxConn {
+	if tx, ok := TxFromContext(ctx, c.db.DB); ok {
+		return &sqlx.Tx{
+			Tx:     tx,
+			Mapper: c.db.Mapper,
+		}
+	}
+
+	return c.db
+}
+
+// readConn returns a healthy replica if the read isn't part of a transaction
+func (c *ClientSqlx) readConn(ctx context.Context) sqlxConn {
+	if _, ok := TxFromContext(ctx, c.db.DB); ok {
+		return c.conn(ctx)
+	}
+
+	if replica, ok := SelectReplica(ctx, c.replicas); ok {
+		return replica
+	}
+
+	return c.db
+}
+
 func (c *ClientSqlx) GetSingleScalarValue(query string, args ...interface{}) (int, error) {
+	return c.GetSingleScalarValueContext(context.Background(), query, args...)
+}
+
+func (c *ClientSqlx) GetResult(query string, args ...interface{}) (*Result, error) {
+	return c.GetResultContext(context.Background(), query, args...)
+}
+
+func (c *ClientSqlx) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.ExecContext(context.Background(), query, args...)
+}
+
+func (c *ClientSqlx) Prepare(query string) (*sql.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *ClientSqlx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.QueryContext(context.Background(), query, args...)
+}
+
+func (c *ClientSqlx) Queryx(query string, args ...interface{}) (*sqlx.Rows, error) {
+	return c.QueryxContext(context.Background(), query, args...)
+}
+
+func (c *ClientSqlx) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.QueryRowContext(context.Background(), query, args...)
+}
+
+func (c *ClientSqlx) Select(dest interface{}, query string, args ...interface{}) error {
+	return c.SelectContext(context.Background(), dest, query, args...)
+}
+
+func (c *ClientSqlx) Get(dest interface{}, query string, args ...interface{}) error {
+	return c.GetContext(context.Background(), dest, query, args...)
+}
+
+func (c *ClientSqlx) GetSingleScalarValueContext(ctx context.Context, query string, args ...interface{}) (int, error) {
 	var val sql.NullInt64
-	err := c.Get(&val, query, args...)
+	err := c.GetContext(ctx, &val, query, args...)
 
 	if err != nil {
 		return 0, err
@@ -79,9 +184,9 @@ func (c *ClientSqlx) GetSingleScalarValue(query string, args ...interface{}) (in
 	return int(val.Int64), err
 }
 
-func (c *ClientSqlx) GetResult(query string, args ...interface{}) (*Result, error) {
+func (c *ClientSqlx) GetResultContext(ctx context.Context, query string, args ...interface{}) (*Result, error) {
 	out := make(Result, 0, 32)
-	rows, err := c.Query(query, args...)
+	rows, err := c.QueryContext(ctx, query, args...)
 
 	if err != nil {
 		return nil, err
@@ -133,40 +238,40 @@ func (c *ClientSqlx) GetResult(query string, args ...interface{}) (*Result, erro
 	return &out, err
 }
 
-func (c *ClientSqlx) Exec(query string, args ...interface{}) (sql.Result, error) {
+func (c *ClientSqlx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
 	c.logger.Debugf("> %s %q", query, args)
 
-	return c.db.Exec(query, args...)
+	return c.conn(ctx).ExecContext(ctx, query, args...)
 }
 
-func (c *ClientSqlx) Prepare(query string) (*sql.Stmt, error) {
-	return c.db.Prepare(query)
+func (c *ClientSqlx) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return c.conn(ctx).PrepareContext(ctx, query)
 }
 
-func (c *ClientSqlx) Query(query string, args ...interface{}) (*sql.Rows, error) {
+func (c *ClientSqlx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
 	c.logger.Debugf("> %s %q", query, args)
 
-	return c.db.Query(query, args...)
+	return c.readConn(ctx).QueryContext(ctx, query, args...)
 }
 
-func (c *ClientSqlx) QueryRow(query string, args ...interface{}) *sql.Row {
-	return c.db.QueryRow(query, args...)
+func (c *ClientSqlx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.readConn(ctx).QueryRowContext(ctx, query, args...)
 }
 
-func (c *ClientSqlx) Queryx(query string, args ...interface{}) (*sqlx.Rows, error) {
+func (c *ClientSqlx) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
 	c.logger.Debugf("> %s %q", query, args)
 
-	return c.db.Queryx(query, args...)
+	return c.readConn(ctx).QueryxContext(ctx, query, args...)
 }
 
-func (c *ClientSqlx) Select(dest interface{}, query string, args ...interface{}) error {
+func (c *ClientSqlx) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
 	c.logger.Debugf("> %s %q", query, args)
 
-	return c.db.Select(dest, query, args...)
+	return c.readConn(ctx).SelectContext(ctx, dest, query, args...)
 }
 
-func (c *ClientSqlx) Get(dest interface{}, query string, args ...interface{}) error {
+func (c *ClientSqlx) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
 	c.logger.Debugf("> %s %q", query, args)
 
-	return c.db.Get(dest, query, args...)
+	return c.readConn(ctx).GetContext(ctx, dest, query, args...)
 }
@@ -3,6 +3,7 @@ package db_test
 import (
 	goSqlMock "github.com/DATA-DOG/go-sqlmock"
 	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/exec"
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
@@ -125,7 +126,11 @@ func getMocks() (db.Client, goSqlMock.Sqlmock) {
 	loggerMock := monMocks.NewLoggerMockedAll()
 	sqlxDB := sqlx.NewDb(dbMock, "sqlmock")
 
-	client := db.NewClientWithInterfaces(loggerMock, sqlxDB)
+	transactor, _ := db.NewTransactorWithInterfaces(loggerMock, dbMock, exec.NewDefaultExecutor(), &db.TransactionSettings{
+		Isolation: "default",
+	})
+
+	client := db.NewClientWithInterfaces(loggerMock, sqlxDB, nil, transactor)
 
 	return client, sqlMock
 }
@@ -25,6 +25,7 @@ type Settings struct {
 	MaxIdleConnections    int           `cfg:"max_idle_connections" default:"2"` // 0 or negative number=no idle connections, sql driver default=2
 	MaxOpenConnections    int           `cfg:"max_open_connections" default:"0"` // 0 or negative number=unlimited, sql driver default=0
 	ParseTime             bool          `cfg:"parse_time" default:"true"`
+	SslMode               string        `cfg:"ssl_mode" default:"disable"` // only used by postgres
 
 	Uri        Uri               `cfg:"uri"`
 	Migrations MigrationSettings `cfg:"migrations"`
@@ -79,6 +80,22 @@ func NewConnectionFromSettings(logger mon.Logger, settings Settings) (*sqlx.DB,
 }
 
 func NewConnectionWithInterfaces(settings Settings) (*sqlx.DB, error) {
+	db, err := openConnection(settings)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("can not connect: %w", err)
+	}
+
+	return db, nil
+}
+
+// openConnection creates the connection pool without connecting to the database yet
+func openConnection(settings Settings) (*sqlx.DB, error) {
 	driverFactory, err := GetDriverFactory(settings.Driver)
 	if err != nil {
 		return nil, fmt.Errorf("could not get dsn provider for driver %s", settings.Driver)
@@ -93,10 +110,10 @@ func NewConnectionWithInterfaces(settings Settings) (*sqlx.DB, error) {
 
 	metricDriverId := newMetricDriver(genDriver)
 
-	db, err := sqlx.Connect(metricDriverId, dsn)
+	db, err := sqlx.Open(metricDriverId, dsn)
 
 	if err != nil {
-		return nil, fmt.Errorf("can not connect: %w", err)
+		return nil, fmt.Errorf("can not open connection: %w", err)
 	}
 
 	db.SetConnMaxLifetime(settings.ConnectionMaxLifetime)
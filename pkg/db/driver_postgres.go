@@ -0,0 +1,44 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/lib/pq"
+	"net/url"
+)
+
+const DriverPostgres = "postgres"
+
+func init() {
+	connectionFactories[DriverPostgres] = NewPostgresDriverFactory()
+}
+
+func NewPostgresDriverFactory() DriverFactory {
+	return &postgresDriverFactory{}
+}
+
+type postgresDriverFactory struct{}
+
+func (p *postgresDriverFactory) GetDSN(settings Settings) string {
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(settings.Uri.User, settings.Uri.Password),
+		Host:   fmt.Sprintf("%s:%d", settings.Uri.Host, settings.Uri.Port),
+		Path:   settings.Uri.Database,
+	}
+
+	qry := dsn.Query()
+	qry.Set("sslmode", settings.SslMode)
+	dsn.RawQuery = qry.Encode()
+
+	return dsn.String()
+}
+
+func (p *postgresDriverFactory) GetMigrationDriver(db *sql.DB, database string, migrationsTable string) (database.Driver, error) {
+	return postgres.WithInstance(db, &postgres.Config{
+		MigrationsTable: migrationsTable,
+		DatabaseName:    database,
+	})
+}
@@ -4,7 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"github.com/VividCortex/mysqlerr"
+	"github.com/applike/gosoline/pkg/exec"
 	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+)
+
+const (
+	pqUniqueViolation      = "23505"
+	pqDeadlockDetected     = "40P01"
+	pqSerializationFailure = "40001"
 )
 
 type DuplicateEntryError struct {
@@ -42,5 +50,37 @@ func IsDuplicateEntryError(err error) bool {
 		return mysqlErr.Number == mysqlerr.ER_DUP_ENTRY
 	}
 
+	pqErr := &pq.Error{}
+
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == pqUniqueViolation
+	}
+
 	return errors.Is(err, &DuplicateEntryError{})
 }
+
+func IsDeadlockError(err error) bool {
+	mysqlErr := &mysql.MySQLError{}
+
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlerr.ER_LOCK_DEADLOCK
+	}
+
+	pqErr := &pq.Error{}
+
+	// postgres aborts serializable transactions with conflicting changes, they have to be retried as well
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == pqDeadlockDetected || pqErr.Code == pqSerializationFailure
+	}
+
+	return false
+}
+
+// CheckDeadlockError marks deadlocks as retryable, as the database has rolled back the whole transaction
+func CheckDeadlockError(_ interface{}, err error) exec.ErrorType {
+	if IsDeadlockError(err) {
+		return exec.ErrorTypeRetryable
+	}
+
+	return exec.ErrorTypeUnknown
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/applike/gosoline/pkg/db"
 	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -21,6 +22,9 @@ func TestIsDuplicateEntryError(t *testing.T) {
 			Number: 1062,
 		}),
 		fmt.Errorf("error: %w", &db.DuplicateEntryError{}),
+		&pq.Error{
+			Code: "23505",
+		},
 	}
 
 	invalid := []error{
@@ -29,6 +33,9 @@ func TestIsDuplicateEntryError(t *testing.T) {
 		&mysql.MySQLError{
 			Number: 42,
 		},
+		&pq.Error{
+			Code: "23503",
+		},
 	}
 
 	for _, validErr := range valid {
@@ -39,3 +46,35 @@ func TestIsDuplicateEntryError(t *testing.T) {
 		assert.False(t, db.IsDuplicateEntryError(invalidErr))
 	}
 }
+
+func TestIsDeadlockError(t *testing.T) {
+	valid := []error{
+		&mysql.MySQLError{
+			Number: 1213,
+		},
+		fmt.Errorf("error: %w", &pq.Error{
+			Code: "40P01",
+		}),
+		&pq.Error{
+			Code: "40001",
+		},
+	}
+
+	invalid := []error{
+		fmt.Errorf("foo"),
+		&mysql.MySQLError{
+			Number: 1062,
+		},
+		&pq.Error{
+			Code: "23505",
+		},
+	}
+
+	for _, validErr := range valid {
+		assert.True(t, db.IsDeadlockError(validErr))
+	}
+
+	for _, invalidErr := range invalid {
+		assert.False(t, db.IsDeadlockError(invalidErr))
+	}
+}
@@ -11,6 +11,8 @@ import (
 
 const (
 	metricNameDbConnectionCount = "DbConnectionCount"
+	metricNameDbReplicaHealthy  = "DbReplicaHealthy"
+	metricNameDbReplicaLag      = "DbReplicaLag"
 )
 
 type metricDriver struct {
@@ -88,3 +90,34 @@ func publishConnectionMetrics(conn *sqlx.DB) {
 		}
 	}()
 }
+
+func replicaMetrics(connection string, replica string, healthy bool, lag time.Duration) mon.MetricData {
+	healthyValue := 0.0
+
+	if healthy {
+		healthyValue = 1.0
+	}
+
+	return mon.MetricData{
+		&mon.MetricDatum{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameDbReplicaHealthy,
+			Dimensions: map[string]string{
+				"Connection": connection,
+				"Replica":    replica,
+			},
+			Unit:  mon.UnitCountAverage,
+			Value: healthyValue,
+		},
+		&mon.MetricDatum{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameDbReplicaLag,
+			Dimensions: map[string]string{
+				"Connection": connection,
+				"Replica":    replica,
+			},
+			Unit:  mon.UnitSecondsAverage,
+			Value: lag.Seconds(),
+		},
+	}
+}
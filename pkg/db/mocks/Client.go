@@ -2,6 +2,7 @@
 
 package mocks
 
+import context "context"
 import db "github.com/applike/gosoline/pkg/db"
 import mock "github.com/stretchr/testify/mock"
 import sql "database/sql"
@@ -38,6 +39,32 @@ func (_m *Client) Exec(query string, args ...interface{}) (sql.Result, error) {
 	return r0, r1
 }
 
+// ExecContext provides a mock function with given fields: ctx, query, args
+func (_m *Client) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 sql.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) sql.Result); ok {
+		r0 = rf(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(sql.Result)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = rf(ctx, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Get provides a mock function with given fields: dest, query, args
 func (_m *Client) Get(dest interface{}, query string, args ...interface{}) error {
 	var _ca []interface{}
@@ -55,6 +82,23 @@ func (_m *Client) Get(dest interface{}, query string, args ...interface{}) error
 	return r0
 }
 
+// GetContext provides a mock function with given fields: ctx, dest, query, args
+func (_m *Client) GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, dest, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string, ...interface{}) error); ok {
+		r0 = rf(ctx, dest, query, args...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetResult provides a mock function with given fields: query, args
 func (_m *Client) GetResult(query string, args ...interface{}) (*db.Result, error) {
 	var _ca []interface{}
@@ -81,6 +125,32 @@ func (_m *Client) GetResult(query string, args ...interface{}) (*db.Result, erro
 	return r0, r1
 }
 
+// GetResultContext provides a mock function with given fields: ctx, query, args
+func (_m *Client) GetResultContext(ctx context.Context, query string, args ...interface{}) (*db.Result, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 *db.Result
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) *db.Result); ok {
+		r0 = rf(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*db.Result)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = rf(ctx, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetSingleScalarValue provides a mock function with given fields: query, args
 func (_m *Client) GetSingleScalarValue(query string, args ...interface{}) (int, error) {
 	var _ca []interface{}
@@ -105,6 +175,30 @@ func (_m *Client) GetSingleScalarValue(query string, args ...interface{}) (int,
 	return r0, r1
 }
 
+// GetSingleScalarValueContext provides a mock function with given fields: ctx, query, args
+func (_m *Client) GetSingleScalarValueContext(ctx context.Context, query string, args ...interface{}) (int, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) int); ok {
+		r0 = rf(ctx, query, args...)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = rf(ctx, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Prepare provides a mock function with given fields: query
 func (_m *Client) Prepare(query string) (*sql.Stmt, error) {
 	ret := _m.Called(query)
@@ -128,6 +222,29 @@ func (_m *Client) Prepare(query string) (*sql.Stmt, error) {
 	return r0, r1
 }
 
+// PrepareContext provides a mock function with given fields: ctx, query
+func (_m *Client) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	ret := _m.Called(ctx, query)
+
+	var r0 *sql.Stmt
+	if rf, ok := ret.Get(0).(func(context.Context, string) *sql.Stmt); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.Stmt)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Query provides a mock function with given fields: query, args
 func (_m *Client) Query(query string, args ...interface{}) (*sql.Rows, error) {
 	var _ca []interface{}
@@ -154,6 +271,32 @@ func (_m *Client) Query(query string, args ...interface{}) (*sql.Rows, error) {
 	return r0, r1
 }
 
+// QueryContext provides a mock function with given fields: ctx, query, args
+func (_m *Client) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 *sql.Rows
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) *sql.Rows); ok {
+		r0 = rf(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.Rows)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = rf(ctx, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // QueryRow provides a mock function with given fields: query, args
 func (_m *Client) QueryRow(query string, args ...interface{}) *sql.Row {
 	var _ca []interface{}
@@ -173,6 +316,25 @@ func (_m *Client) QueryRow(query string, args ...interface{}) *sql.Row {
 	return r0
 }
 
+// QueryRowContext provides a mock function with given fields: ctx, query, args
+func (_m *Client) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 *sql.Row
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) *sql.Row); ok {
+		r0 = rf(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sql.Row)
+		}
+	}
+
+	return r0
+}
+
 // Queryx provides a mock function with given fields: query, args
 func (_m *Client) Queryx(query string, args ...interface{}) (*sqlx.Rows, error) {
 	var _ca []interface{}
@@ -199,6 +361,32 @@ func (_m *Client) Queryx(query string, args ...interface{}) (*sqlx.Rows, error)
 	return r0, r1
 }
 
+// QueryxContext provides a mock function with given fields: ctx, query, args
+func (_m *Client) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 *sqlx.Rows
+	if rf, ok := ret.Get(0).(func(context.Context, string, ...interface{}) *sqlx.Rows); ok {
+		r0 = rf(ctx, query, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sqlx.Rows)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, ...interface{}) error); ok {
+		r1 = rf(ctx, query, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Select provides a mock function with given fields: dest, query, args
 func (_m *Client) Select(dest interface{}, query string, args ...interface{}) error {
 	var _ca []interface{}
@@ -215,3 +403,41 @@ func (_m *Client) Select(dest interface{}, query string, args ...interface{}) er
 
 	return r0
 }
+
+// SelectContext provides a mock function with given fields: ctx, dest, query, args
+func (_m *Client) SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, dest, query)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}, string, ...interface{}) error); ok {
+		r0 = rf(ctx, dest, query, args...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithTx provides a mock function with given fields: ctx, f, options
+func (_m *Client) WithTx(ctx context.Context, f db.TxFunc, options ...db.TxOption) error {
+	_va := make([]interface{}, len(options))
+	for _i := range options {
+		_va[_i] = options[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, f)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, db.TxFunc, ...db.TxOption) error); ok {
+		r0 = rf(ctx, f, options...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
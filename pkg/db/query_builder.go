@@ -1,6 +1,7 @@
 package db
 
 import (
+	"fmt"
 	"github.com/Masterminds/squirrel"
 	"github.com/thoas/go-funk"
 )
@@ -52,7 +53,7 @@ func (b *RawQueryBuilder) GroupBy(field ...string) QueryBuilder {
 }
 
 func (b *RawQueryBuilder) OrderBy(field string, direction string) QueryBuilder {
-	b.Builder = b.Builder.OrderBy(field, direction)
+	b.Builder = b.Builder.OrderBy(fmt.Sprintf("%s %s", field, direction))
 
 	return b
 }
@@ -0,0 +1,400 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/jmoiron/sqlx"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReplicaUri overwrites host and port of the primary uri, all other settings are shared with the primary
+type ReplicaUri struct {
+	Host string `cfg:"host" validate:"required"`
+	Port int    `cfg:"port" validate:"required"`
+}
+
+// ReplicaSettings are read from db.<name>.replicas. Replicas failing the health check or lagging more than
+// max_lag behind the primary are ejected until they pass the check again. A max_lag of 0 disables the lag check.
+type ReplicaSettings struct {
+	Hosts         []ReplicaUri  `cfg:"hosts" validate:"dive"`
+	MaxLag        time.Duration `cfg:"max_lag" default:"0s"`
+	CheckInterval time.Duration `cfg:"check_interval" default:"10s"`
+	CheckTimeout  time.Duration `cfg:"check_timeout" default:"2s"`
+}
+
+type ReplicationLagCheck func(ctx context.Context, db *sqlx.DB) (time.Duration, error)
+
+var replicationLagChecks = map[string]ReplicationLagCheck{
+	DriverMysql:    mysqlReplicationLag,
+	DriverPostgres: postgresReplicationLag,
+}
+
+type primaryContextKey struct{}
+
+// WithPrimary routes all reads done with the context to the primary, e.g. to read a model right after writing it
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryContextKey{}, true)
+}
+
+// SelectReplica returns a healthy replica for a read outside of a transaction. The replicas can be nil.
+func SelectReplica(ctx context.Context, replicas ReplicaSet) (*sqlx.DB, bool) {
+	if replicas == nil {
+		return nil, false
+	}
+
+	if primary, ok := ctx.Value(primaryContextKey{}).(bool); ok && primary {
+		return nil, false
+	}
+
+	return replicas.Replica()
+}
+
+//go:generate mockery -name ReplicaSet
+type ReplicaSet interface {
+	// Replica returns the next healthy replica in round robin order or false if every replica is ejected
+	Replica() (*sqlx.DB, bool)
+	// CheckHealth ejects and re-admits the replicas
+	CheckHealth(ctx context.Context)
+	// Start checks the health of the replicas every check interval in the background until the set is stopped
+	Start()
+	Stop()
+}
+
+type Replica struct {
+	Name string
+	DB   *sqlx.DB
+}
+
+type replicaState struct {
+	Replica
+	healthy bool
+}
+
+type replicaSet struct {
+	logger       mon.Logger
+	metricWriter mon.MetricWriter
+	name         string
+	lagCheck     ReplicationLagCheck
+	settings     *ReplicaSettings
+
+	checkLck sync.Mutex
+	lck      sync.RWMutex
+	replicas []*replicaState
+	healthy  []*replicaState
+	next     uint32
+
+	runLck sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var defaultReplicaSets = struct {
+	lck       sync.Mutex
+	instances map[string]ReplicaSet
+}{
+	instances: make(map[string]ReplicaSet),
+}
+
+// ProvideReplicaSet returns the replicas of the connection with the name. The replicas are checked in the
+// background, so a replica which isn't reachable on startup is admitted as soon as it is. The checks are stopped
+// by the ReplicaSetsModule on shutdown.
+func ProvideReplicaSet(config cfg.Config, logger mon.Logger, name string) (ReplicaSet, error) {
+	defaultReplicaSets.lck.Lock()
+	defer defaultReplicaSets.lck.Unlock()
+
+	if instance, ok := defaultReplicaSets.instances[name]; ok {
+		return instance, nil
+	}
+
+	instance, err := NewReplicaSet(config, logger, name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defaultReplicaSets.instances[name] = instance
+
+	return instance, nil
+}
+
+func NewReplicaSet(config cfg.Config, logger mon.Logger, name string) (ReplicaSet, error) {
+	primary := createSettings(config, name)
+	settings := ReadReplicaSettings(config, name)
+	replicas := make([]Replica, 0, len(settings.Hosts))
+
+	for _, host := range settings.Hosts {
+		replicaSettings := primary
+		replicaSettings.Uri.Host = host.Host
+		replicaSettings.Uri.Port = host.Port
+
+		connection, err := openConnection(replicaSettings)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not open connection to replica %s:%d: %w", host.Host, host.Port, err)
+		}
+
+		replicas = append(replicas, Replica{
+			Name: fmt.Sprintf("%s:%d", host.Host, host.Port),
+			DB:   connection,
+		})
+	}
+
+	lagCheck := replicationLagChecks[primary.Driver]
+
+	if settings.MaxLag > 0 && lagCheck == nil {
+		return nil, fmt.Errorf("there is no replication lag check for driver %s", primary.Driver)
+	}
+
+	metricWriter := mon.NewMetricDaemonWriter()
+	set := NewReplicaSetWithInterfaces(logger, metricWriter, name, replicas, lagCheck, settings)
+
+	if len(replicas) == 0 {
+		return set, nil
+	}
+
+	set.CheckHealth(context.Background())
+	set.Start()
+
+	return set, nil
+}
+
+// NewReplicaSetWithInterfaces creates the set with every replica ejected until the first health check
+func NewReplicaSetWithInterfaces(logger mon.Logger, metricWriter mon.MetricWriter, name string, replicas []Replica, lagCheck ReplicationLagCheck, settings *ReplicaSettings) ReplicaSet {
+	states := make([]*replicaState, 0, len(replicas))
+
+	for _, replica := range replicas {
+		states = append(states, &replicaState{
+			Replica: replica,
+		})
+	}
+
+	return &replicaSet{
+		logger:       logger,
+		metricWriter: metricWriter,
+		name:         name,
+		lagCheck:     lagCheck,
+		settings:     settings,
+		replicas:     states,
+		healthy:      make([]*replicaState, 0),
+	}
+}
+
+func (s *replicaSet) Replica() (*sqlx.DB, bool) {
+	s.lck.RLock()
+	defer s.lck.RUnlock()
+
+	if len(s.healthy) == 0 {
+		return nil, false
+	}
+
+	next := atomic.AddUint32(&s.next, 1)
+
+	return s.healthy[int(next)%len(s.healthy)].DB, true
+}
+
+func (s *replicaSet) CheckHealth(ctx context.Context) {
+	s.checkLck.Lock()
+	defer s.checkLck.Unlock()
+
+	healthy := make([]*replicaState, 0, len(s.replicas))
+	logger := s.logger.WithContext(ctx)
+
+	for _, replica := range s.replicas {
+		lag, err := s.check(ctx, replica)
+
+		s.lck.RLock()
+		wasHealthy := replica.healthy
+		s.lck.RUnlock()
+
+		switch {
+		case err != nil && wasHealthy:
+			logger.Warnf("ejecting replica %s of db %s: %s", replica.Name, s.name, err.Error())
+		case err == nil && !wasHealthy:
+			logger.Infof("admitting replica %s of db %s", replica.Name, s.name)
+		}
+
+		s.metricWriter.Write(replicaMetrics(s.name, replica.Name, err == nil, lag))
+
+		if err == nil {
+			healthy = append(healthy, replica)
+		}
+	}
+
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	for _, replica := range s.replicas {
+		replica.healthy = false
+	}
+
+	for _, replica := range healthy {
+		replica.healthy = true
+	}
+
+	s.healthy = healthy
+}
+
+func (s *replicaSet) Start() {
+	s.runLck.Lock()
+	defer s.runLck.Unlock()
+
+	if s.cancel != nil {
+		return
+	}
+
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	s.done = make(chan struct{})
+
+	go s.run(ctx, s.done)
+}
+
+// Stop stops the background checks and waits for a running check to finish
+func (s *replicaSet) Stop() {
+	s.runLck.Lock()
+	defer s.runLck.Unlock()
+
+	if s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	<-s.done
+
+	s.cancel = nil
+}
+
+func (s *replicaSet) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.settings.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.CheckHealth(ctx)
+		}
+	}
+}
+
+func (s *replicaSet) check(ctx context.Context, replica *replicaState) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.settings.CheckTimeout)
+	defer cancel()
+
+	if err := replica.DB.PingContext(ctx); err != nil {
+		return 0, fmt.Errorf("can not ping replica: %w", err)
+	}
+
+	if s.settings.MaxLag <= 0 {
+		return 0, nil
+	}
+
+	lag, err := s.lagCheck(ctx, replica.DB)
+
+	if err != nil {
+		return 0, fmt.Errorf("can not get replication lag: %w", err)
+	}
+
+	if lag > s.settings.MaxLag {
+		return lag, fmt.Errorf("replication lag of %s exceeds the max lag of %s", lag, s.settings.MaxLag)
+	}
+
+	return lag, nil
+}
+
+func mysqlReplicationLag(ctx context.Context, db *sqlx.DB) (time.Duration, error) {
+	rows, err := db.QueryxContext(ctx, "SHOW SLAVE STATUS")
+
+	if err != nil {
+		return 0, err
+	}
+
+	defer rows.Close()
+
+	if !rows.Next() {
+		return 0, fmt.Errorf("the server is not a replica")
+	}
+
+	status := make(map[string]interface{})
+
+	if err = rows.MapScan(status); err != nil {
+		return 0, err
+	}
+
+	var seconds int64
+
+	switch value := status["Seconds_Behind_Master"].(type) {
+	case nil:
+		return 0, fmt.Errorf("the replication is not running")
+	case []byte:
+		if seconds, err = strconv.ParseInt(string(value), 10, 64); err != nil {
+			return 0, err
+		}
+	case int64:
+		seconds = value
+	default:
+		return 0, fmt.Errorf("unexpected type %T of Seconds_Behind_Master", value)
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// postgresReplicationLag reports no lag if the replica replayed everything it received, otherwise an idle primary
+// would look like a lagging replica
+func postgresReplicationLag(ctx context.Context, db *sqlx.DB) (time.Duration, error) {
+	var seconds float64
+
+	err := db.GetContext(ctx, &seconds, `SELECT CASE
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// ReplicaSetsModule is a kernel module which stops the health checks of the provided replica sets on shutdown
+type ReplicaSetsModule struct {
+	kernel.BackgroundModule
+	kernel.EssentialStage
+}
+
+func ReplicaSetsModuleFactory(_ cfg.Config, _ mon.Logger) (map[string]kernel.ModuleFactory, error) {
+	return map[string]kernel.ModuleFactory{
+		"db-replicas": func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+			return &ReplicaSetsModule{}, nil
+		},
+	}, nil
+}
+
+func (m *ReplicaSetsModule) Run(ctx context.Context) error {
+	<-ctx.Done()
+
+	defaultReplicaSets.lck.Lock()
+	defer defaultReplicaSets.lck.Unlock()
+
+	for _, set := range defaultReplicaSets.instances {
+		set.Stop()
+	}
+
+	return nil
+}
+
+func ReadReplicaSettings(config cfg.Config, name string) *ReplicaSettings {
+	settings := &ReplicaSettings{}
+	config.UnmarshalKey(fmt.Sprintf("db.%s.replicas", name), settings)
+
+	return settings
+}
@@ -0,0 +1,184 @@
+package db_test
+
+import (
+	"context"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/exec"
+	"github.com/applike/gosoline/pkg/mon"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+type replicaMock struct {
+	db      *sqlx.DB
+	sqlMock goSqlMock.Sqlmock
+}
+
+func newReplicaMock() *replicaMock {
+	dbMock, sqlMock, _ := goSqlMock.New(goSqlMock.MonitorPingsOption(true))
+
+	return &replicaMock{
+		db:      sqlx.NewDb(dbMock, "sqlmock"),
+		sqlMock: sqlMock,
+	}
+}
+
+func newReplicaSet(lagCheck db.ReplicationLagCheck, maxLag time.Duration, replicas ...*replicaMock) (db.ReplicaSet, *monMocks.MetricWriter) {
+	logger := monMocks.NewLoggerMockedAll()
+	metricWriter := new(monMocks.MetricWriter)
+	metricWriter.On("Write", mock.AnythingOfType("mon.MetricData"))
+
+	dbs := make([]db.Replica, 0, len(replicas))
+
+	for _, replica := range replicas {
+		dbs = append(dbs, db.Replica{
+			Name: "replica",
+			DB:   replica.db,
+		})
+	}
+
+	set := db.NewReplicaSetWithInterfaces(logger, metricWriter, "default", dbs, lagCheck, &db.ReplicaSettings{
+		MaxLag:        maxLag,
+		CheckInterval: time.Millisecond,
+		CheckTimeout:  time.Second,
+	})
+
+	return set, metricWriter
+}
+
+func TestReplicaSet_CheckHealth(t *testing.T) {
+	healthy := newReplicaMock()
+	broken := newReplicaMock()
+
+	set, metricWriter := newReplicaSet(nil, 0, healthy, broken)
+
+	_, ok := set.Replica()
+	assert.False(t, ok, "replicas are ejected until the first check")
+
+	healthy.sqlMock.ExpectPing()
+	broken.sqlMock.ExpectPing().WillReturnError(assert.AnError)
+	set.CheckHealth(context.Background())
+
+	for i := 0; i < 3; i++ {
+		replica, ok := set.Replica()
+		assert.True(t, ok)
+		assert.Equal(t, healthy.db, replica)
+	}
+
+	healthy.sqlMock.ExpectPing().WillReturnError(assert.AnError)
+	broken.sqlMock.ExpectPing()
+	set.CheckHealth(context.Background())
+
+	replica, ok := set.Replica()
+	assert.True(t, ok)
+	assert.Equal(t, broken.db, replica)
+
+	assert.NoError(t, healthy.sqlMock.ExpectationsWereMet())
+	assert.NoError(t, broken.sqlMock.ExpectationsWereMet())
+	metricWriter.AssertNumberOfCalls(t, "Write", 4)
+}
+
+func TestReplicaSet_CheckHealth_MaxLag(t *testing.T) {
+	replica := newReplicaMock()
+	lag := time.Minute
+
+	lagCheck := func(ctx context.Context, db *sqlx.DB) (time.Duration, error) {
+		return lag, nil
+	}
+
+	set, metricWriter := newReplicaSet(lagCheck, 10*time.Second, replica)
+
+	replica.sqlMock.ExpectPing()
+	set.CheckHealth(context.Background())
+
+	_, ok := set.Replica()
+	assert.False(t, ok)
+
+	lag = time.Second
+	replica.sqlMock.ExpectPing()
+	set.CheckHealth(context.Background())
+
+	_, ok = set.Replica()
+	assert.True(t, ok)
+
+	metricWriter.AssertCalled(t, "Write", mock.MatchedBy(func(data mon.MetricData) bool {
+		return data[1].MetricName == "DbReplicaLag" && data[1].Value == 60.0 && data[1].Dimensions["Connection"] == "default"
+	}))
+}
+
+func TestReplicaSet_StartStop(t *testing.T) {
+	replica := newReplicaMock()
+	checked := make(chan struct{})
+
+	lagCheck := func(ctx context.Context, db *sqlx.DB) (time.Duration, error) {
+		select {
+		case checked <- struct{}{}:
+		default:
+		}
+
+		return 0, nil
+	}
+
+	set, metricWriter := newReplicaSet(lagCheck, time.Second, replica)
+
+	for i := 0; i < 100; i++ {
+		replica.sqlMock.ExpectPing()
+	}
+
+	set.Start()
+	<-checked
+	set.Stop()
+
+	calls := len(metricWriter.Calls)
+	time.Sleep(10 * time.Millisecond)
+
+	assert.Len(t, metricWriter.Calls, calls, "there must be no check after the stop")
+}
+
+func TestClient_ReplicaRouting(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	primaryMock, primarySqlMock, _ := goSqlMock.New()
+	replica := newReplicaMock()
+
+	set, _ := newReplicaSet(nil, 0, replica)
+	replica.sqlMock.ExpectPing()
+	set.CheckHealth(context.Background())
+
+	transactor, _ := db.NewTransactorWithInterfaces(logger, primaryMock, exec.NewDefaultExecutor(), &db.TransactionSettings{
+		Isolation: "default",
+	})
+	client := db.NewClientWithInterfaces(logger, sqlx.NewDb(primaryMock, "sqlmock"), set, transactor)
+
+	replica.sqlMock.ExpectQuery("SELECT name FROM items").WillReturnRows(goSqlMock.NewRows([]string{"name"}).AddRow("replica"))
+	primarySqlMock.ExpectExec("UPDATE items").WillReturnResult(goSqlMock.NewResult(0, 1))
+	primarySqlMock.ExpectQuery("SELECT name FROM items").WillReturnRows(goSqlMock.NewRows([]string{"name"}).AddRow("primary"))
+	primarySqlMock.ExpectBegin()
+	primarySqlMock.ExpectQuery("SELECT name FROM items").WillReturnRows(goSqlMock.NewRows([]string{"name"}).AddRow("tx"))
+	primarySqlMock.ExpectCommit()
+
+	var name string
+	err := client.GetContext(context.Background(), &name, "SELECT name FROM items")
+	assert.NoError(t, err)
+	assert.Equal(t, "replica", name)
+
+	_, err = client.ExecContext(context.Background(), "UPDATE items SET name = 'primary'")
+	assert.NoError(t, err)
+
+	err = client.GetContext(db.WithPrimary(context.Background()), &name, "SELECT name FROM items")
+	assert.NoError(t, err)
+	assert.Equal(t, "primary", name)
+
+	err = client.WithTx(context.Background(), func(ctx context.Context) error {
+		return client.GetContext(ctx, &name, "SELECT name FROM items")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "tx", name)
+
+	assert.NoError(t, primarySqlMock.ExpectationsWereMet())
+	assert.NoError(t, replica.sqlMock.ExpectationsWereMet())
+}
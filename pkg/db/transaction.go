@@ -0,0 +1,237 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/exec"
+	"github.com/applike/gosoline/pkg/mon"
+	"sync"
+)
+
+var isolationLevels = map[string]sql.IsolationLevel{
+	"default":          sql.LevelDefault,
+	"read_uncommitted": sql.LevelReadUncommitted,
+	"read_committed":   sql.LevelReadCommitted,
+	"repeatable_read":  sql.LevelRepeatableRead,
+	"serializable":     sql.LevelSerializable,
+}
+
+// TransactionSettings are read from db.<name>.transaction. Transactions failing with a deadlock are retried
+// as a whole if the backoff is enabled.
+type TransactionSettings struct {
+	Isolation string               `cfg:"isolation" default:"default"`
+	Backoff   exec.BackoffSettings `cfg:"backoff"`
+}
+
+type TxFunc func(ctx context.Context) error
+type TxOption func(options *sql.TxOptions)
+
+// WithIsolation overwrites the configured isolation level of the transaction
+func WithIsolation(level sql.IsolationLevel) TxOption {
+	return func(options *sql.TxOptions) {
+		options.Isolation = level
+	}
+}
+
+func WithReadOnly() TxOption {
+	return func(options *sql.TxOptions) {
+		options.ReadOnly = true
+	}
+}
+
+type txContextKey struct {
+	db *sql.DB
+}
+
+type transaction struct {
+	lck        sync.Mutex
+	tx         *sql.Tx
+	savepoints int
+	values     map[interface{}]interface{}
+}
+
+// TxFromContext returns the transaction on the connection started by a surrounding WithTx call
+func TxFromContext(ctx context.Context, db *sql.DB) (*sql.Tx, bool) {
+	if state, ok := ctx.Value(txContextKey{db: db}).(*transaction); ok {
+		return state.tx, true
+	}
+
+	return nil, false
+}
+
+// TxValue returns the value stored under the key on the transaction of the context. The value is created on the first
+// call for the transaction, so handles bound to the transaction like an orm are built once per transaction.
+func TxValue(ctx context.Context, db *sql.DB, key interface{}, create func(tx *sql.Tx) (interface{}, error)) (interface{}, bool, error) {
+	state, ok := ctx.Value(txContextKey{db: db}).(*transaction)
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	state.lck.Lock()
+	defer state.lck.Unlock()
+
+	if value, ok := state.values[key]; ok {
+		return value, true, nil
+	}
+
+	value, err := create(state.tx)
+
+	if err != nil {
+		return nil, true, err
+	}
+
+	state.values[key] = value
+
+	return value, true, nil
+}
+
+//go:generate mockery -name Transactor
+type Transactor interface {
+	// WithTx runs the function in a transaction which is committed if the function doesn't return an error.
+	// The transaction is put on the context, so every db.Client and db_repo.Repository call with the context
+	// joins it. Nested calls create a savepoint in the surrounding transaction and ignore the options. They are
+	// never retried on a deadlock, as the deadlock rolls back the whole transaction: the error has to be returned
+	// to the outermost call, which retries the transaction as a whole.
+	WithTx(ctx context.Context, f TxFunc, options ...TxOption) error
+}
+
+type transactor struct {
+	logger    mon.Logger
+	db        *sql.DB
+	executor  exec.Executor
+	isolation sql.IsolationLevel
+}
+
+func NewTransactor(config cfg.Config, logger mon.Logger, name string) (Transactor, error) {
+	connection, err := ProvideConnection(config, logger, name)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not connect to sql database: %w", err)
+	}
+
+	settings := ReadTransactionSettings(config, name)
+	executor := NewTxExecutor(logger, settings.Backoff, name)
+
+	return NewTransactorWithInterfaces(logger, connection.DB, executor, settings)
+}
+
+func NewTransactorWithInterfaces(logger mon.Logger, db *sql.DB, executor exec.Executor, settings *TransactionSettings) (Transactor, error) {
+	isolation, ok := isolationLevels[settings.Isolation]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown transaction isolation level %s", settings.Isolation)
+	}
+
+	return &transactor{
+		logger:    logger,
+		db:        db,
+		executor:  executor,
+		isolation: isolation,
+	}, nil
+}
+
+func (t *transactor) WithTx(ctx context.Context, f TxFunc, options ...TxOption) error {
+	if state, ok := ctx.Value(txContextKey{db: t.db}).(*transaction); ok {
+		return t.withSavepoint(ctx, state, f)
+	}
+
+	txOptions := &sql.TxOptions{
+		Isolation: t.isolation,
+	}
+
+	for _, opt := range options {
+		opt(txOptions)
+	}
+
+	_, err := t.executor.Execute(ctx, func(ctx context.Context) (interface{}, error) {
+		return nil, t.run(ctx, txOptions, f)
+	})
+
+	return err
+}
+
+func (t *transactor) run(ctx context.Context, options *sql.TxOptions, f TxFunc) (err error) {
+	tx, err := t.db.BeginTx(ctx, options)
+
+	if err != nil {
+		return fmt.Errorf("can not begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			t.rollback(ctx, tx)
+			panic(p)
+		}
+	}()
+
+	txCtx := context.WithValue(ctx, txContextKey{db: t.db}, &transaction{
+		tx:     tx,
+		values: make(map[interface{}]interface{}),
+	})
+
+	if err = f(txCtx); err != nil {
+		t.rollback(ctx, tx)
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("can not commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (t *transactor) rollback(ctx context.Context, tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil {
+		t.logger.WithContext(ctx).Error(err, "can not rollback transaction")
+	}
+}
+
+// withSavepoint runs a nested call without the executor, so it isn't retried on its own
+func (t *transactor) withSavepoint(ctx context.Context, state *transaction, f TxFunc) error {
+	state.lck.Lock()
+	state.savepoints++
+	savepoint := fmt.Sprintf("sp_%d", state.savepoints)
+	state.lck.Unlock()
+
+	if _, err := state.tx.ExecContext(ctx, fmt.Sprintf("SAVEPOINT %s", savepoint)); err != nil {
+		return fmt.Errorf("can not create savepoint %s: %w", savepoint, err)
+	}
+
+	if err := f(ctx); err != nil {
+		if _, rbErr := state.tx.ExecContext(ctx, fmt.Sprintf("ROLLBACK TO SAVEPOINT %s", savepoint)); rbErr != nil {
+			t.logger.WithContext(ctx).Errorf(rbErr, "can not rollback to savepoint %s", savepoint)
+		}
+
+		return err
+	}
+
+	if _, err := state.tx.ExecContext(ctx, fmt.Sprintf("RELEASE SAVEPOINT %s", savepoint)); err != nil {
+		return fmt.Errorf("can not release savepoint %s: %w", savepoint, err)
+	}
+
+	return nil
+}
+
+func NewTxExecutor(logger mon.Logger, settings exec.BackoffSettings, name string) exec.Executor {
+	if !settings.Enabled {
+		return exec.NewDefaultExecutor()
+	}
+
+	res := &exec.ExecutableResource{
+		Type: "db",
+		Name: name,
+	}
+
+	return exec.NewBackoffExecutor(logger, res, &settings, CheckDeadlockError)
+}
+
+func ReadTransactionSettings(config cfg.Config, name string) *TransactionSettings {
+	settings := &TransactionSettings{}
+	config.UnmarshalKey(fmt.Sprintf("db.%s.transaction", name), settings)
+
+	return settings
+}
@@ -0,0 +1,220 @@
+package db_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	goSqlMock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/exec"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestClient_WithTx(t *testing.T) {
+	client, sqlMock := getMocks()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("INSERT INTO orders").WillReturnResult(goSqlMock.NewResult(1, 1))
+	sqlMock.ExpectExec("INSERT INTO items").WillReturnResult(goSqlMock.NewResult(1, 1))
+	sqlMock.ExpectCommit()
+
+	err := client.WithTx(context.Background(), func(ctx context.Context) error {
+		if _, err := client.ExecContext(ctx, "INSERT INTO orders VALUES (1)"); err != nil {
+			return err
+		}
+
+		_, err := client.ExecContext(ctx, "INSERT INTO items VALUES (1, 1)")
+
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestClient_WithTx_Rollback(t *testing.T) {
+	client, sqlMock := getMocks()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("INSERT INTO orders").WillReturnResult(goSqlMock.NewResult(1, 1))
+	sqlMock.ExpectRollback()
+
+	err := client.WithTx(context.Background(), func(ctx context.Context) error {
+		if _, err := client.ExecContext(ctx, "INSERT INTO orders VALUES (1)"); err != nil {
+			return err
+		}
+
+		return fmt.Errorf("out of stock")
+	})
+
+	assert.EqualError(t, err, "out of stock")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestClient_WithTx_Savepoint(t *testing.T) {
+	client, sqlMock := getMocks()
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("INSERT INTO orders").WillReturnResult(goSqlMock.NewResult(1, 1))
+	sqlMock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(goSqlMock.NewResult(0, 0))
+	sqlMock.ExpectExec("INSERT INTO items").WillReturnError(assert.AnError)
+	sqlMock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(goSqlMock.NewResult(0, 0))
+	sqlMock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(goSqlMock.NewResult(0, 0))
+	sqlMock.ExpectExec("INSERT INTO items").WillReturnResult(goSqlMock.NewResult(1, 1))
+	sqlMock.ExpectExec("RELEASE SAVEPOINT sp_2").WillReturnResult(goSqlMock.NewResult(0, 0))
+	sqlMock.ExpectCommit()
+
+	err := client.WithTx(context.Background(), func(ctx context.Context) error {
+		if _, err := client.ExecContext(ctx, "INSERT INTO orders VALUES (1)"); err != nil {
+			return err
+		}
+
+		err := client.WithTx(ctx, func(ctx context.Context) error {
+			_, err := client.ExecContext(ctx, "INSERT INTO items VALUES (1, 1)")
+			return err
+		})
+		assert.Equal(t, assert.AnError, err)
+
+		return client.WithTx(ctx, func(ctx context.Context) error {
+			_, err := client.ExecContext(ctx, "INSERT INTO items VALUES (1, 2)")
+			return err
+		})
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestClient_WithTx_DeadlockRetry(t *testing.T) {
+	dbMock, sqlMock, _ := goSqlMock.New()
+	logger := monMocks.NewLoggerMockedAll()
+
+	executor := db.NewTxExecutor(logger, exec.BackoffSettings{
+		Enabled:         true,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+		MaxElapsedTime:  time.Second,
+	}, "default")
+
+	transactor, err := db.NewTransactorWithInterfaces(logger, dbMock, executor, &db.TransactionSettings{
+		Isolation: "default",
+	})
+	assert.NoError(t, err)
+
+	client := db.NewClientWithInterfaces(logger, sqlx.NewDb(dbMock, "sqlmock"), nil, transactor)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("UPDATE stock").WillReturnError(&mysql.MySQLError{Number: 1213})
+	sqlMock.ExpectRollback()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("UPDATE stock").WillReturnResult(goSqlMock.NewResult(0, 1))
+	sqlMock.ExpectCommit()
+
+	err = client.WithTx(context.Background(), func(ctx context.Context) error {
+		_, err := client.ExecContext(ctx, "UPDATE stock SET amount = amount - 1")
+		return err
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestClient_WithTx_NestedDeadlockRetriesOutermost(t *testing.T) {
+	dbMock, sqlMock, _ := goSqlMock.New()
+	logger := monMocks.NewLoggerMockedAll()
+
+	executor := db.NewTxExecutor(logger, exec.BackoffSettings{
+		Enabled:         true,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+		MaxElapsedTime:  time.Second,
+	}, "default")
+
+	transactor, err := db.NewTransactorWithInterfaces(logger, dbMock, executor, &db.TransactionSettings{
+		Isolation: "default",
+	})
+	assert.NoError(t, err)
+
+	client := db.NewClientWithInterfaces(logger, sqlx.NewDb(dbMock, "sqlmock"), nil, transactor)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("INSERT INTO orders").WillReturnResult(goSqlMock.NewResult(1, 1))
+	sqlMock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(goSqlMock.NewResult(0, 0))
+	sqlMock.ExpectExec("UPDATE stock").WillReturnError(&mysql.MySQLError{Number: 1213})
+	sqlMock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(goSqlMock.NewResult(0, 0))
+	sqlMock.ExpectRollback()
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("INSERT INTO orders").WillReturnResult(goSqlMock.NewResult(1, 1))
+	sqlMock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(goSqlMock.NewResult(0, 0))
+	sqlMock.ExpectExec("UPDATE stock").WillReturnResult(goSqlMock.NewResult(0, 1))
+	sqlMock.ExpectExec("RELEASE SAVEPOINT sp_1").WillReturnResult(goSqlMock.NewResult(0, 0))
+	sqlMock.ExpectCommit()
+
+	err = client.WithTx(context.Background(), func(ctx context.Context) error {
+		if _, err := client.ExecContext(ctx, "INSERT INTO orders VALUES (1)"); err != nil {
+			return err
+		}
+
+		return client.WithTx(ctx, func(ctx context.Context) error {
+			_, err := client.ExecContext(ctx, "UPDATE stock SET amount = amount - 1")
+			return err
+		})
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestTxValue(t *testing.T) {
+	dbMock, sqlMock, _ := goSqlMock.New()
+	logger := monMocks.NewLoggerMockedAll()
+
+	transactor, err := db.NewTransactorWithInterfaces(logger, dbMock, exec.NewDefaultExecutor(), &db.TransactionSettings{
+		Isolation: "default",
+	})
+	assert.NoError(t, err)
+
+	_, ok, err := db.TxValue(context.Background(), dbMock, "key", nil)
+	assert.False(t, ok, "there is no value without a transaction")
+	assert.NoError(t, err)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectCommit()
+
+	created := 0
+	create := func(tx *sql.Tx) (interface{}, error) {
+		created++
+		return created, nil
+	}
+
+	err = transactor.WithTx(context.Background(), func(ctx context.Context) error {
+		for i := 0; i < 2; i++ {
+			value, ok, err := db.TxValue(ctx, dbMock, "key", create)
+
+			assert.True(t, ok)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, value)
+		}
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestNewTransactorWithInterfaces_UnknownIsolation(t *testing.T) {
+	dbMock, _, _ := goSqlMock.New()
+	logger := monMocks.NewLoggerMockedAll()
+
+	_, err := db.NewTransactorWithInterfaces(logger, dbMock, exec.NewDefaultExecutor(), &db.TransactionSettings{
+		Isolation: "snapshot",
+	})
+
+	assert.EqualError(t, err, "unknown transaction isolation level snapshot")
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"strconv"
 )
 
 //go:generate mockery -name PutItemBuilder
@@ -17,6 +18,8 @@ type PutItemBuilder interface {
 }
 
 type putItemBuilder struct {
+	versionState
+
 	metadata   *Metadata
 	condition  *expression.ConditionBuilder
 	returnType *string
@@ -24,6 +27,9 @@ type putItemBuilder struct {
 
 func NewPutItemBuilder(metadata *Metadata) PutItemBuilder {
 	return &putItemBuilder{
+		versionState: versionState{
+			metadata: metadata,
+		},
 		metadata: metadata,
 	}
 }
@@ -52,10 +58,26 @@ func (b *putItemBuilder) Build(item interface{}) (*dynamodb.PutItemInput, error)
 	}
 
 	var err error
+	var version int64
 	expr := expression.Expression{}
+	condition := b.condition
+
+	if b.metadata.Version.Enabled {
+		if version, err = b.read(item); err != nil {
+			return nil, fmt.Errorf("could not read version: %w", err)
+		}
+
+		if condition != nil {
+			if err = b.rememberConditionKey(item); err != nil {
+				return nil, err
+			}
+		}
+
+		condition = withVersionCondition(condition, b.metadata.Version.condition(version))
+	}
 
-	if b.condition != nil {
-		expr, err = expression.NewBuilder().WithCondition(*b.condition).Build()
+	if condition != nil {
+		expr, err = expression.NewBuilder().WithCondition(*condition).Build()
 	}
 
 	if err != nil {
@@ -77,5 +99,27 @@ func (b *putItemBuilder) Build(item interface{}) (*dynamodb.PutItemInput, error)
 
 	input.Item = marshalled
 
+	if b.metadata.Version.Enabled {
+		input.Item[b.metadata.Version.Field] = &dynamodb.AttributeValue{
+			N: aws.String(strconv.FormatInt(version+1, 10)),
+		}
+	}
+
 	return input, err
 }
+
+func (b *putItemBuilder) rememberConditionKey(item interface{}) error {
+	keyBuilder := keyBuilder{
+		metadata: b.metadata.Main,
+	}
+
+	key, err := keyBuilder.fromItem(item)
+
+	if err != nil {
+		return fmt.Errorf("could not build key: %w", err)
+	}
+
+	b.withConditionKey(key)
+
+	return nil
+}
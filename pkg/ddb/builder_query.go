@@ -23,6 +23,8 @@ type QueryOperation struct {
 	iterator   *pageIterator
 	targetType interface{}
 	result     *QueryResult
+	limiter    *capacityLimiter
+	segments   []resumeTokenSegment
 }
 
 type keyExprBuilder func() expression.KeyConditionBuilder
@@ -46,6 +48,10 @@ type QueryBuilder interface {
 	WithPageSize(size int) QueryBuilder
 	WithDescendingOrder() QueryBuilder
 	WithConsistentRead(consistentRead bool) QueryBuilder
+	// WithResumeToken continues after the last page returned by a QueryIterator
+	WithResumeToken(token string) QueryBuilder
+	// WithReadCapacityLimit delays the requests to consume at most the given read capacity units per second
+	WithReadCapacityLimit(unitsPerSecond float64) QueryBuilder
 	Build(result interface{}) (*QueryOperation, error)
 }
 
@@ -57,13 +63,15 @@ type queryBuilder struct {
 	selected  FieldAware
 	err       error
 
-	hashExprBuilder  keyExprBuilder
-	rangeExprBuilder keyExprBuilder
-	projection       interface{}
-	limit            *int64
-	pageSize         *int64
-	scanIndexForward *bool
-	consistentRead   *bool
+	hashExprBuilder   keyExprBuilder
+	rangeExprBuilder  keyExprBuilder
+	projection        interface{}
+	limit             *int64
+	pageSize          *int64
+	scanIndexForward  *bool
+	consistentRead    *bool
+	resumeToken       string
+	readCapacityLimit float64
 }
 
 func NewQueryBuilder(metadata *Metadata) QueryBuilder {
@@ -239,6 +247,18 @@ func (b *queryBuilder) WithConsistentRead(consistentRead bool) QueryBuilder {
 	return b
 }
 
+func (b *queryBuilder) WithResumeToken(token string) QueryBuilder {
+	b.resumeToken = token
+
+	return b
+}
+
+func (b *queryBuilder) WithReadCapacityLimit(unitsPerSecond float64) QueryBuilder {
+	b.readCapacityLimit = unitsPerSecond
+
+	return b
+}
+
 func (b *queryBuilder) Build(result interface{}) (*QueryOperation, error) {
 	var err error
 	var keyCondition expression.KeyConditionBuilder
@@ -276,7 +296,19 @@ func (b *queryBuilder) Build(result interface{}) (*QueryOperation, error) {
 		return nil, err
 	}
 
+	segments, err := decodeResumeToken(b.resumeToken, 1)
+
+	if err != nil {
+		return nil, err
+	}
+
 	progress := buildPageIterator(b.limit, b.pageSize)
+
+	if segments[0].Done {
+		// a limit of zero items finishes the operation without any request
+		progress = buildPageIterator(aws.Int64(0), nil)
+	}
+
 	input := &dynamodb.QueryInput{
 		TableName:                 aws.String(b.metadata.TableName),
 		IndexName:                 b.indexName,
@@ -288,6 +320,13 @@ func (b *queryBuilder) Build(result interface{}) (*QueryOperation, error) {
 		ProjectionExpression:      expr.Projection(),
 		Limit:                     progress.size,
 		ScanIndexForward:          b.scanIndexForward,
+		ExclusiveStartKey:         segments[0].Key,
+	}
+
+	limiter := newCapacityLimiter(b.readCapacityLimit)
+
+	if limiter != nil {
+		input.ReturnConsumedCapacity = aws.String(dynamodb.ReturnConsumedCapacityTotal)
 	}
 
 	operation := &QueryOperation{
@@ -295,6 +334,8 @@ func (b *queryBuilder) Build(result interface{}) (*QueryOperation, error) {
 		iterator:   progress,
 		targetType: targetType,
 		result:     newQueryResult(),
+		limiter:    limiter,
+		segments:   segments,
 	}
 
 	return operation, nil
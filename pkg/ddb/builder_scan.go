@@ -13,6 +13,9 @@ type ScanOperation struct {
 	iterator   *pageIterator
 	targetType interface{}
 	result     *ScanResult
+	limiter    *capacityLimiter
+	segments   []resumeTokenSegment
+	workers    int
 }
 
 //go:generate mockery -name ScanBuilder
@@ -25,22 +28,33 @@ type ScanBuilder interface {
 	WithPageSize(size int) ScanBuilder
 	WithSegment(segment int, total int) ScanBuilder
 	WithConsistentRead(consistentRead bool) ScanBuilder
+	// WithParallelScan splits the table into the given number of segments which are scanned concurrently by at most
+	// the given number of workers. The segments take turns if there are more segments than workers.
+	WithParallelScan(segments int, workers int) ScanBuilder
+	// WithResumeToken continues after the last page returned by a ScanIterator
+	WithResumeToken(token string) ScanBuilder
+	// WithReadCapacityLimit delays the requests of all workers to consume at most the given read capacity units per second
+	WithReadCapacityLimit(unitsPerSecond float64) ScanBuilder
 	Build(result interface{}) (*ScanOperation, error)
 }
 
 type scanBuilder struct {
 	filterBuilder
 
-	err            error
-	metadata       *Metadata
-	indexName      *string
-	selected       FieldAware
-	projection     interface{}
-	limit          *int64
-	pageSize       *int64
-	segment        *int64
-	segmentTotal   *int64
-	consistentRead *bool
+	err               error
+	metadata          *Metadata
+	indexName         *string
+	selected          FieldAware
+	projection        interface{}
+	limit             *int64
+	pageSize          *int64
+	segment           *int64
+	segmentTotal      *int64
+	consistentRead    *bool
+	segments          int
+	workers           int
+	resumeToken       string
+	readCapacityLimit float64
 }
 
 func NewScanBuilder(metadata *Metadata) ScanBuilder {
@@ -107,7 +121,48 @@ func (b *scanBuilder) WithConsistentRead(consistentRead bool) ScanBuilder {
 	return b
 }
 
+func (b *scanBuilder) WithParallelScan(segments int, workers int) ScanBuilder {
+	b.segments = segments
+	b.workers = workers
+
+	return b
+}
+
+func (b *scanBuilder) WithResumeToken(token string) ScanBuilder {
+	b.resumeToken = token
+
+	return b
+}
+
+func (b *scanBuilder) WithReadCapacityLimit(unitsPerSecond float64) ScanBuilder {
+	b.readCapacityLimit = unitsPerSecond
+
+	return b
+}
+
 func (b *scanBuilder) Build(result interface{}) (*ScanOperation, error) {
+	if b.segments > 1 && (b.limit != nil || b.segment != nil) {
+		return nil, fmt.Errorf("parallel scans on table %s can not be combined with a limit or a segment", b.metadata.TableName)
+	}
+
+	if b.segments > 1 && b.workers < 1 {
+		return nil, fmt.Errorf("parallel scans on table %s need at least one worker", b.metadata.TableName)
+	}
+
+	segmentCount := 1
+	workers := 1
+
+	if b.segments > 1 {
+		segmentCount = b.segments
+		workers = b.workers
+	}
+
+	segments, err := decodeResumeToken(b.resumeToken, segmentCount)
+
+	if err != nil {
+		return nil, err
+	}
+
 	targetType := resolveTargetType(b.selected, b.projection, result)
 	expr, err := b.buildExpression(targetType)
 
@@ -116,6 +171,12 @@ func (b *scanBuilder) Build(result interface{}) (*ScanOperation, error) {
 	}
 
 	progress := buildPageIterator(b.limit, b.pageSize)
+
+	if segments[0].Done && segmentCount == 1 {
+		// a limit of zero items finishes the operation without any request
+		progress = buildPageIterator(aws.Int64(0), nil)
+	}
+
 	input := &dynamodb.ScanInput{
 		TableName:                 aws.String(b.metadata.TableName),
 		IndexName:                 b.indexName,
@@ -129,11 +190,24 @@ func (b *scanBuilder) Build(result interface{}) (*ScanOperation, error) {
 		TotalSegments:             b.segmentTotal,
 	}
 
+	if segmentCount == 1 {
+		input.ExclusiveStartKey = segments[0].Key
+	}
+
+	limiter := newCapacityLimiter(b.readCapacityLimit)
+
+	if limiter != nil {
+		input.ReturnConsumedCapacity = aws.String(dynamodb.ReturnConsumedCapacityTotal)
+	}
+
 	operation := &ScanOperation{
 		input:      input,
 		iterator:   progress,
 		targetType: targetType,
 		result:     newScanResult(),
+		limiter:    limiter,
+		segments:   segments,
+		workers:    workers,
 	}
 
 	return operation, nil
@@ -166,3 +240,22 @@ func (b *scanBuilder) buildExpression(result interface{}) (expression.Expression
 
 	return exprBuilder.Build()
 }
+
+// forSegment copies the operation to read a single segment of a parallel scan
+func (o *ScanOperation) forSegment(segment int) *ScanOperation {
+	input := *o.input
+	input.Segment = aws.Int64(int64(segment))
+	input.TotalSegments = aws.Int64(int64(len(o.segments)))
+	input.ExclusiveStartKey = o.segments[segment].Key
+	input.Limit = o.iterator.size
+
+	return &ScanOperation{
+		input:      &input,
+		iterator:   buildPageIterator(nil, o.iterator.size),
+		targetType: o.targetType,
+		result:     newScanResult(),
+		limiter:    o.limiter,
+		segments:   o.segments[segment : segment+1],
+		workers:    1,
+	}
+}
@@ -41,3 +41,9 @@ func (b *TransactPutItem) Build() (*dynamodb.TransactWriteItem, error) {
 func (b *TransactPutItem) GetItem() interface{} {
 	return b.Item
 }
+
+func (b *TransactPutItem) versionBuilder() versionAware {
+	builder, _ := b.Builder.(versionAware)
+
+	return builder
+}
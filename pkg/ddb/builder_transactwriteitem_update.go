@@ -38,3 +38,9 @@ func (b *TransactUpdateItem) Build() (*dynamodb.TransactWriteItem, error) {
 func (b *TransactUpdateItem) GetItem() interface{} {
 	return b.Item
 }
+
+func (b *TransactUpdateItem) versionBuilder() versionAware {
+	builder, _ := b.Builder.(versionAware)
+
+	return builder
+}
@@ -28,6 +28,8 @@ type UpdateItemBuilder interface {
 }
 
 type updateItemBuilder struct {
+	versionState
+
 	metadata      *Metadata
 	keyBuilder    keyBuilder
 	condition     *expression.ConditionBuilder
@@ -36,12 +38,23 @@ type updateItemBuilder struct {
 }
 
 func NewUpdateItemBuilder(metadata *Metadata) UpdateItemBuilder {
-	return &updateItemBuilder{
+	builder := &updateItemBuilder{
+		versionState: versionState{
+			metadata: metadata,
+		},
 		metadata: metadata,
 		keyBuilder: keyBuilder{
 			metadata: metadata.Main,
 		},
 	}
+
+	if metadata.Version.Enabled {
+		builder.update(func() expression.UpdateBuilder {
+			return builder.updateBuilder.Set(expression.Name(metadata.Version.Field), metadata.Version.increment())
+		})
+	}
+
+	return builder
 }
 
 func (b *updateItemBuilder) WithHash(hashValue interface{}) UpdateItemBuilder {
@@ -144,7 +157,23 @@ func (b *updateItemBuilder) Build(item interface{}) (*dynamodb.UpdateItemInput,
 		return nil, fmt.Errorf("value for returning the updated item is not a pointer")
 	}
 
-	expr, err := b.buildExpression()
+	condition := b.condition
+
+	if b.metadata.Version.Enabled {
+		version, err := b.read(item)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not read version: %w", err)
+		}
+
+		if condition != nil {
+			b.withConditionKey(keys)
+		}
+
+		condition = withVersionCondition(condition, b.metadata.Version.condition(version))
+	}
+
+	expr, err := b.buildExpression(condition)
 
 	if err != nil {
 		return nil, err
@@ -163,8 +192,8 @@ func (b *updateItemBuilder) Build(item interface{}) (*dynamodb.UpdateItemInput,
 	return input, err
 }
 
-func (b *updateItemBuilder) buildExpression() (expression.Expression, error) {
-	if b.updateBuilder == nil && b.condition == nil {
+func (b *updateItemBuilder) buildExpression(condition *expression.ConditionBuilder) (expression.Expression, error) {
+	if b.updateBuilder == nil && condition == nil {
 		return expression.Expression{}, nil
 	}
 
@@ -174,8 +203,8 @@ func (b *updateItemBuilder) buildExpression() (expression.Expression, error) {
 		exprBuilder = exprBuilder.WithUpdate(*b.updateBuilder)
 	}
 
-	if b.condition != nil {
-		exprBuilder = exprBuilder.WithCondition(*b.condition)
+	if condition != nil {
+		exprBuilder = exprBuilder.WithCondition(*condition)
 	}
 
 	return exprBuilder.Build()
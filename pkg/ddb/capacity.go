@@ -13,11 +13,11 @@ func (c *Capacity) add(cc *dynamodb.Capacity) {
 		c.Total += *cc.CapacityUnits
 	}
 
-	if cc.CapacityUnits != nil {
+	if cc.ReadCapacityUnits != nil {
 		c.Read += *cc.ReadCapacityUnits
 	}
 
-	if cc.CapacityUnits != nil {
+	if cc.WriteCapacityUnits != nil {
 		c.Write += *cc.WriteCapacityUnits
 	}
 }
@@ -0,0 +1,100 @@
+package ddb
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/exec"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"sync"
+	"time"
+)
+
+// capacityLimiter is a token bucket holding the capacity units of up to one second. It starts empty to not burst
+// when an operation starts. As the capacity of a request is only known after it was executed, every request reserves
+// the capacity of the previous one before it is sent and the difference to the consumed capacity is settled afterwards.
+// The bucket can go into debt which delays the following requests.
+type capacityLimiter struct {
+	lck            sync.Mutex
+	unitsPerSecond float64
+	tokens         float64
+	estimate       float64
+	updated        time.Time
+}
+
+func newCapacityLimiter(unitsPerSecond float64) *capacityLimiter {
+	if unitsPerSecond <= 0 {
+		return nil
+	}
+
+	return &capacityLimiter{
+		unitsPerSecond: unitsPerSecond,
+		estimate:       1,
+		updated:        time.Now(),
+	}
+}
+
+// wait reserves the estimated capacity of a request and blocks until the bucket has paid off its debt. The returned
+// reservation has to be passed to consume once the request was executed.
+func (l *capacityLimiter) wait(ctx context.Context) (float64, error) {
+	if l == nil {
+		return 0, nil
+	}
+
+	l.lck.Lock()
+	l.refill()
+	reserved := l.estimate
+	l.tokens -= reserved
+	delay := time.Duration(-l.tokens / l.unitsPerSecond * float64(time.Second))
+	l.lck.Unlock()
+
+	if delay <= 0 {
+		return reserved, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.consume(reserved, nil)
+		return 0, exec.RequestCanceledError
+	case <-timer.C:
+		return reserved, nil
+	}
+}
+
+// consume settles the reservation of a request with its consumed capacity. Without consumed capacity the
+// reservation is returned to the bucket.
+func (l *capacityLimiter) consume(reserved float64, cc *dynamodb.ConsumedCapacity) {
+	if l == nil {
+		return
+	}
+
+	l.lck.Lock()
+	defer l.lck.Unlock()
+
+	l.refill()
+	l.tokens += reserved
+
+	if cc == nil || cc.CapacityUnits == nil {
+		return
+	}
+
+	l.tokens -= *cc.CapacityUnits
+	l.estimate = *cc.CapacityUnits
+}
+
+func (l *capacityLimiter) refill() {
+	now := time.Now()
+	elapsed := now.Sub(l.updated).Seconds()
+	l.updated = now
+
+	if elapsed <= 0 {
+		return
+	}
+
+	l.tokens += elapsed * l.unitsPerSecond
+
+	if l.tokens > l.unitsPerSecond {
+		l.tokens = l.unitsPerSecond
+	}
+}
@@ -28,3 +28,29 @@ func (t TableNotFoundError) Error() string {
 func (t TableNotFoundError) Unwrap() error {
 	return t.err
 }
+
+func IsVersionConflictError(err error) bool {
+	return errors.As(err, &VersionConflictError{})
+}
+
+type VersionConflictError struct {
+	TableName string
+	Version   int64
+	err       error
+}
+
+func NewVersionConflictError(tableName string, version int64, err error) VersionConflictError {
+	return VersionConflictError{
+		TableName: tableName,
+		Version:   version,
+		err:       err,
+	}
+}
+
+func (v VersionConflictError) Error() string {
+	return fmt.Sprintf("the item in ddb table %s was modified since version %d: %s", v.TableName, v.Version, v.err)
+}
+
+func (v VersionConflictError) Unwrap() error {
+	return v.err
+}
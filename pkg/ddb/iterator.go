@@ -0,0 +1,238 @@
+package ddb
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/exec"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+)
+
+//go:generate mockery -name QueryIterator
+type QueryIterator interface {
+	// Next reads the next page into the items, which have to be a pointer to a slice.
+	// It returns false once all pages were read.
+	Next(ctx context.Context, items interface{}) (bool, error)
+	// Token returns a token to resume after the last page returned by Next with QueryBuilder.WithResumeToken
+	Token() (string, error)
+	Result() *QueryResult
+}
+
+//go:generate mockery -name ScanIterator
+type ScanIterator interface {
+	// Next reads the next page into the items, which have to be a pointer to a slice. The pages of
+	// parallel scans are returned in the order they were read.
+	// It returns false once all pages were read.
+	Next(ctx context.Context, items interface{}) (bool, error)
+	// Token returns a token to resume after the last page returned by Next with ScanBuilder.WithResumeToken
+	Token() (string, error)
+	Result() *ScanResult
+}
+
+type resumeToken struct {
+	Segments []resumeTokenSegment `json:"segments"`
+}
+
+type resumeTokenSegment struct {
+	Key  map[string]*dynamodb.AttributeValue `json:"key,omitempty"`
+	Done bool                                `json:"done,omitempty"`
+}
+
+func decodeResumeToken(token string, segments int) ([]resumeTokenSegment, error) {
+	if token == "" {
+		return make([]resumeTokenSegment, segments), nil
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not decode resume token: %w", err)
+	}
+
+	rt := &resumeToken{}
+
+	if err = json.Unmarshal(decoded, rt); err != nil {
+		return nil, fmt.Errorf("can not decode resume token: %w", err)
+	}
+
+	if len(rt.Segments) != segments {
+		return nil, fmt.Errorf("the resume token contains %d segments but the operation reads %d", len(rt.Segments), segments)
+	}
+
+	return rt.Segments, nil
+}
+
+func encodeResumeToken(segments []resumeTokenSegment) (string, error) {
+	encoded, err := json.Marshal(&resumeToken{
+		Segments: segments,
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("can not encode resume token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(encoded), nil
+}
+
+type iteratorSegment struct {
+	read     func(ctx context.Context) (*readResult, error)
+	state    resumeTokenSegment
+	inFlight bool
+}
+
+type iteratorPage struct {
+	segment *iteratorSegment
+	result  *readResult
+	err     error
+}
+
+// segmentReader reads the pages of all segments concurrently with at most one request per segment and at most one
+// request per worker in flight.
+// The position of a segment only advances once its page was returned, so pages read ahead are read again
+// when resuming from a token.
+type segmentReader struct {
+	segments []*iteratorSegment
+	workers  int
+	position int
+	pages    chan *iteratorPage
+
+	requestCount     int64
+	itemCount        int64
+	scannedCount     int64
+	consumedCapacity *ConsumedCapacity
+}
+
+func newSegmentReader(segments []*iteratorSegment, workers int) *segmentReader {
+	return &segmentReader{
+		segments:         segments,
+		workers:          workers,
+		pages:            make(chan *iteratorPage, len(segments)),
+		consumedCapacity: newConsumedCapacity(),
+	}
+}
+
+func (r *segmentReader) next(ctx context.Context) (*readResult, bool, error) {
+	for {
+		inFlight := 0
+
+		for _, segment := range r.segments {
+			if segment.inFlight {
+				inFlight++
+			}
+		}
+
+		// the segments take turns if there are fewer workers than segments
+		for i := 0; i < len(r.segments) && inFlight < r.workers; i++ {
+			segment := r.segments[r.position]
+			r.position = (r.position + 1) % len(r.segments)
+
+			if !segment.state.Done && !segment.inFlight {
+				segment.inFlight = true
+				inFlight++
+				go r.read(ctx, segment)
+			}
+		}
+
+		if inFlight == 0 {
+			return nil, false, nil
+		}
+
+		var page *iteratorPage
+
+		select {
+		case page = <-r.pages:
+		case <-ctx.Done():
+			return nil, false, exec.RequestCanceledError
+		}
+
+		page.segment.inFlight = false
+
+		if page.err != nil {
+			return nil, false, page.err
+		}
+
+		r.requestCount += page.result.RequestCount
+		r.itemCount += page.result.Count
+		r.scannedCount += page.result.ScannedCount
+		r.consumedCapacity.add(page.result.ConsumedCapacity)
+
+		page.segment.state.Key = page.result.LastEvaluatedKey
+		page.segment.state.Done = page.result.LastEvaluatedKey == nil
+
+		if len(page.result.Items) == 0 {
+			continue
+		}
+
+		return page.result, true, nil
+	}
+}
+
+func (r *segmentReader) read(ctx context.Context, segment *iteratorSegment) {
+	result, err := segment.read(ctx)
+
+	r.pages <- &iteratorPage{
+		segment: segment,
+		result:  result,
+		err:     err,
+	}
+}
+
+func (r *segmentReader) Token() (string, error) {
+	states := make([]resumeTokenSegment, len(r.segments))
+
+	for i, segment := range r.segments {
+		states[i] = segment.state
+	}
+
+	return encodeResumeToken(states)
+}
+
+func (r *segmentReader) nextInto(ctx context.Context, items interface{}) (bool, error) {
+	page, ok, err := r.next(ctx)
+
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if err = dynamodbattribute.UnmarshalListOfMaps(page.Items, items); err != nil {
+		return false, fmt.Errorf("could not unmarshal items: %w", err)
+	}
+
+	return true, nil
+}
+
+type queryIterator struct {
+	*segmentReader
+}
+
+func (i *queryIterator) Next(ctx context.Context, items interface{}) (bool, error) {
+	return i.nextInto(ctx, items)
+}
+
+func (i *queryIterator) Result() *QueryResult {
+	return &QueryResult{
+		RequestCount:     i.requestCount,
+		ItemCount:        i.itemCount,
+		ScannedCount:     i.scannedCount,
+		ConsumedCapacity: i.consumedCapacity,
+	}
+}
+
+type scanIterator struct {
+	*segmentReader
+}
+
+func (i *scanIterator) Next(ctx context.Context, items interface{}) (bool, error) {
+	return i.nextInto(ctx, items)
+}
+
+func (i *scanIterator) Result() *ScanResult {
+	return &ScanResult{
+		RequestCount:     i.requestCount,
+		ItemCount:        i.itemCount,
+		ScannedCount:     i.scannedCount,
+		ConsumedCapacity: i.consumedCapacity,
+	}
+}
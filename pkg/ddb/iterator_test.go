@@ -0,0 +1,225 @@
+package ddb_test
+
+import (
+	"context"
+	gosoAws "github.com/applike/gosoline/pkg/cloud/aws"
+	cloudMocks "github.com/applike/gosoline/pkg/cloud/mocks"
+	"github.com/applike/gosoline/pkg/ddb"
+	"github.com/applike/gosoline/pkg/mdl"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// clientExecutor returns the output of the client mock, so concurrent requests get the output matching their input
+type clientExecutor struct{}
+
+func (e clientExecutor) Execute(_ context.Context, f gosoAws.RequestFunction) (interface{}, error) {
+	_, out := f()
+
+	return out, nil
+}
+
+func newIteratorRepository() (*cloudMocks.DynamoDBAPI, ddb.Repository) {
+	logger := monMocks.NewLoggerMockedAll()
+	client := new(cloudMocks.DynamoDBAPI)
+
+	repo := ddb.NewWithInterfaces(logger, tracing.NewNoopTracer(), client, clientExecutor{}, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Project:     "applike",
+			Environment: "test",
+			Family:      "gosoline",
+			Application: "ddb",
+			Name:        "myModel",
+		},
+		Main: ddb.MainSettings{
+			Model: model{},
+		},
+	})
+
+	return client, repo
+}
+
+func modelItem(id int, rev string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"id":  {N: aws.String(strconv.Itoa(id))},
+		"rev": {S: aws.String(rev)},
+	}
+}
+
+func TestRepository_QueryIterator_Resume(t *testing.T) {
+	client, repo := newIteratorRepository()
+	lastKey := modelItem(1, "0")
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String("applike-test-gosoline-ddb-myModel"),
+		KeyConditionExpression: aws.String("#0 = :0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#0": aws.String("id"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":0": {N: aws.String("1")},
+		},
+		Limit: aws.Int64(1),
+	}
+	client.On("QueryRequest", input).Return(nil, &dynamodb.QueryOutput{
+		Count:            aws.Int64(1),
+		ScannedCount:     aws.Int64(1),
+		Items:            []map[string]*dynamodb.AttributeValue{lastKey},
+		LastEvaluatedKey: lastKey,
+	}).Once()
+
+	iterator, err := repo.QueryIterator(repo.QueryBuilder().WithHash(1).WithPageSize(1), &[]model{})
+	assert.NoError(t, err)
+
+	items := make([]model, 0)
+	ok, err := iterator.Next(context.Background(), &items)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []model{{Id: 1, Rev: "0"}}, items)
+	assert.Equal(t, int64(1), iterator.Result().ItemCount)
+
+	token, err := iterator.Token()
+	assert.NoError(t, err)
+
+	resumedInput := *input
+	resumedInput.ExclusiveStartKey = lastKey
+	client.On("QueryRequest", &resumedInput).Return(nil, &dynamodb.QueryOutput{
+		Count:        aws.Int64(1),
+		ScannedCount: aws.Int64(1),
+		Items:        []map[string]*dynamodb.AttributeValue{modelItem(1, "1")},
+	}).Once()
+
+	iterator, err = repo.QueryIterator(repo.QueryBuilder().WithHash(1).WithPageSize(1).WithResumeToken(token), &[]model{})
+	assert.NoError(t, err)
+
+	ok, err = iterator.Next(context.Background(), &items)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []model{{Id: 1, Rev: "1"}}, items)
+
+	ok, err = iterator.Next(context.Background(), &items)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	token, err = iterator.Token()
+	assert.NoError(t, err)
+
+	iterator, err = repo.QueryIterator(repo.QueryBuilder().WithHash(1).WithResumeToken(token), &[]model{})
+	assert.NoError(t, err)
+
+	ok, err = iterator.Next(context.Background(), &items)
+	assert.NoError(t, err)
+	assert.False(t, ok, "a finished iteration doesn't read again")
+
+	client.AssertExpectations(t)
+}
+
+func TestRepository_Scan_Parallel(t *testing.T) {
+	client, repo := newIteratorRepository()
+
+	for segment := 0; segment < 3; segment++ {
+		client.On("ScanRequest", &dynamodb.ScanInput{
+			TableName:     aws.String("applike-test-gosoline-ddb-myModel"),
+			Segment:       aws.Int64(int64(segment)),
+			TotalSegments: aws.Int64(3),
+		}).Return(nil, &dynamodb.ScanOutput{
+			Count:        aws.Int64(1),
+			ScannedCount: aws.Int64(1),
+			Items:        []map[string]*dynamodb.AttributeValue{modelItem(segment, "0")},
+		}).Once()
+	}
+
+	items := make([]model, 0)
+	result, err := repo.Scan(context.Background(), repo.ScanBuilder().WithParallelScan(3, 3), &items)
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Id < items[j].Id
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model{{Id: 0, Rev: "0"}, {Id: 1, Rev: "0"}, {Id: 2, Rev: "0"}}, items)
+	assert.Equal(t, int64(3), result.RequestCount)
+	client.AssertExpectations(t)
+}
+
+func TestRepository_ScanIterator_ReadCapacityLimit(t *testing.T) {
+	client, repo := newIteratorRepository()
+	lastKey := modelItem(1, "0")
+
+	input := &dynamodb.ScanInput{
+		TableName:              aws.String("applike-test-gosoline-ddb-myModel"),
+		ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
+	}
+	client.On("ScanRequest", input).Return(nil, &dynamodb.ScanOutput{
+		Count:            aws.Int64(1),
+		ScannedCount:     aws.Int64(1),
+		Items:            []map[string]*dynamodb.AttributeValue{lastKey},
+		LastEvaluatedKey: lastKey,
+		ConsumedCapacity: &dynamodb.ConsumedCapacity{
+			CapacityUnits: aws.Float64(2),
+		},
+	}).Once()
+
+	secondInput := *input
+	secondInput.ExclusiveStartKey = lastKey
+	client.On("ScanRequest", &secondInput).Return(nil, &dynamodb.ScanOutput{
+		Count:        aws.Int64(0),
+		ScannedCount: aws.Int64(0),
+		Items:        []map[string]*dynamodb.AttributeValue{},
+	}).Once()
+
+	iterator, err := repo.ScanIterator(repo.ScanBuilder().WithReadCapacityLimit(20), &[]model{})
+	assert.NoError(t, err)
+
+	start := time.Now()
+	items := make([]model, 0)
+
+	ok, err := iterator.Next(context.Background(), &items)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = iterator.Next(context.Background(), &items)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.True(t, time.Since(start) >= 100*time.Millisecond, "2 units at 20 units per second delay the next request by 100ms")
+	assert.Equal(t, 2.0, iterator.Result().ConsumedCapacity.Total)
+	client.AssertExpectations(t)
+}
+
+func TestRepository_Scan_ParallelReadCapacityLimit(t *testing.T) {
+	client, repo := newIteratorRepository()
+
+	for segment := 0; segment < 4; segment++ {
+		client.On("ScanRequest", &dynamodb.ScanInput{
+			TableName:              aws.String("applike-test-gosoline-ddb-myModel"),
+			Segment:                aws.Int64(int64(segment)),
+			TotalSegments:          aws.Int64(4),
+			ReturnConsumedCapacity: aws.String(dynamodb.ReturnConsumedCapacityTotal),
+		}).Return(nil, &dynamodb.ScanOutput{
+			Count:        aws.Int64(1),
+			ScannedCount: aws.Int64(1),
+			Items:        []map[string]*dynamodb.AttributeValue{modelItem(segment, "0")},
+			ConsumedCapacity: &dynamodb.ConsumedCapacity{
+				CapacityUnits: aws.Float64(1),
+			},
+		}).Once()
+	}
+
+	start := time.Now()
+	items := make([]model, 0)
+	result, err := repo.Scan(context.Background(), repo.ScanBuilder().WithParallelScan(4, 2).WithReadCapacityLimit(20), &items)
+
+	assert.NoError(t, err)
+	assert.Len(t, items, 4)
+	assert.Equal(t, int64(4), result.RequestCount)
+	assert.True(t, time.Since(start) >= 200*time.Millisecond, "the workers reserve 1 unit per request at 20 units per second")
+	client.AssertExpectations(t)
+}
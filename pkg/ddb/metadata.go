@@ -26,6 +26,7 @@ type Metadata struct {
 	TableName  string
 	Attributes Attributes
 	TimeToLive metadataTtl
+	Version    metadataVersion
 	Main       metadataMain
 	Local      metaLocal
 	Global     metaGlobal
@@ -52,6 +53,12 @@ type metadataTtl struct {
 	Field   string
 }
 
+type metadataVersion struct {
+	Enabled   bool
+	Field     string
+	FieldName string
+}
+
 type metadataFields struct {
 	Model    interface{}
 	Fields   []string
@@ -29,6 +29,12 @@ func (f *metadataFactory) GetMetadata(settings *Settings) (*Metadata, error) {
 		return nil, fmt.Errorf("can not get ttl for table %s: %w", tableName, err)
 	}
 
+	version, err := f.getVersion(settings.Main.Model, attributes)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not get version for table %s: %w", tableName, err)
+	}
+
 	mainFields, err := f.getFields(settings.Main.Model, tagKey, tagKey)
 
 	if err != nil {
@@ -51,6 +57,7 @@ func (f *metadataFactory) GetMetadata(settings *Settings) (*Metadata, error) {
 		TableName:  tableName,
 		Attributes: attributes,
 		TimeToLive: ttl,
+		Version:    version,
 		Main: metadataMain{
 			metadataFields: mainFields,
 			metadataCapacity: metadataCapacity{
@@ -224,6 +231,33 @@ func (f *metadataFactory) getTimeToLive(attributes Attributes) (metadataTtl, err
 	return data, nil
 }
 
+func (f *metadataFactory) getVersion(model interface{}, attributes Attributes) (metadataVersion, error) {
+	data := metadataVersion{
+		Enabled: false,
+	}
+	version, err := attributes.GetByTag("version", "enabled")
+
+	if err != nil {
+		return data, err
+	}
+
+	if version == nil {
+		return data, nil
+	}
+
+	field, _ := findBaseType(model).FieldByName(version.FieldName)
+
+	if !isIntegerKind(field.Type.Kind()) {
+		return data, fmt.Errorf("the version field %s has to be an integer but is %s", version.FieldName, field.Type)
+	}
+
+	data.Enabled = true
+	data.Field = version.AttributeName
+	data.FieldName = version.FieldName
+
+	return data, nil
+}
+
 func ReadAttributes(model interface{}) (Attributes, error) {
 	t := findBaseType(model)
 	attributes := make(Attributes)
@@ -308,3 +308,35 @@ func (_m *QueryBuilder) WithRangeLte(value interface{}) ddb.QueryBuilder {
 
 	return r0
 }
+
+// WithReadCapacityLimit provides a mock function with given fields: unitsPerSecond
+func (_m *QueryBuilder) WithReadCapacityLimit(unitsPerSecond float64) ddb.QueryBuilder {
+	ret := _m.Called(unitsPerSecond)
+
+	var r0 ddb.QueryBuilder
+	if rf, ok := ret.Get(0).(func(float64) ddb.QueryBuilder); ok {
+		r0 = rf(unitsPerSecond)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.QueryBuilder)
+		}
+	}
+
+	return r0
+}
+
+// WithResumeToken provides a mock function with given fields: token
+func (_m *QueryBuilder) WithResumeToken(token string) ddb.QueryBuilder {
+	ret := _m.Called(token)
+
+	var r0 ddb.QueryBuilder
+	if rf, ok := ret.Get(0).(func(string) ddb.QueryBuilder); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.QueryBuilder)
+		}
+	}
+
+	return r0
+}
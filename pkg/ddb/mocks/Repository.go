@@ -267,6 +267,29 @@ func (_m *Repository) QueryBuilder() ddb.QueryBuilder {
 	return r0
 }
 
+// QueryIterator provides a mock function with given fields: qb, result
+func (_m *Repository) QueryIterator(qb ddb.QueryBuilder, result interface{}) (ddb.QueryIterator, error) {
+	ret := _m.Called(qb, result)
+
+	var r0 ddb.QueryIterator
+	if rf, ok := ret.Get(0).(func(ddb.QueryBuilder, interface{}) ddb.QueryIterator); ok {
+		r0 = rf(qb, result)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.QueryIterator)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ddb.QueryBuilder, interface{}) error); ok {
+		r1 = rf(qb, result)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Scan provides a mock function with given fields: ctx, sb, result
 func (_m *Repository) Scan(ctx context.Context, sb ddb.ScanBuilder, result interface{}) (*ddb.ScanResult, error) {
 	ret := _m.Called(ctx, sb, result)
@@ -306,6 +329,29 @@ func (_m *Repository) ScanBuilder() ddb.ScanBuilder {
 	return r0
 }
 
+// ScanIterator provides a mock function with given fields: sb, result
+func (_m *Repository) ScanIterator(sb ddb.ScanBuilder, result interface{}) (ddb.ScanIterator, error) {
+	ret := _m.Called(sb, result)
+
+	var r0 ddb.ScanIterator
+	if rf, ok := ret.Get(0).(func(ddb.ScanBuilder, interface{}) ddb.ScanIterator); ok {
+		r0 = rf(sb, result)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.ScanIterator)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(ddb.ScanBuilder, interface{}) error); ok {
+		r1 = rf(sb, result)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateItem provides a mock function with given fields: ctx, ub, item
 func (_m *Repository) UpdateItem(ctx context.Context, ub ddb.UpdateItemBuilder, item interface{}) (*ddb.UpdateItemResult, error) {
 	ret := _m.Called(ctx, ub, item)
@@ -130,6 +130,22 @@ func (_m *ScanBuilder) WithPageSize(size int) ddb.ScanBuilder {
 	return r0
 }
 
+// WithParallelScan provides a mock function with given fields: segments, workers
+func (_m *ScanBuilder) WithParallelScan(segments int, workers int) ddb.ScanBuilder {
+	ret := _m.Called(segments, workers)
+
+	var r0 ddb.ScanBuilder
+	if rf, ok := ret.Get(0).(func(int, int) ddb.ScanBuilder); ok {
+		r0 = rf(segments, workers)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.ScanBuilder)
+		}
+	}
+
+	return r0
+}
+
 // WithProjection provides a mock function with given fields: projection
 func (_m *ScanBuilder) WithProjection(projection interface{}) ddb.ScanBuilder {
 	ret := _m.Called(projection)
@@ -146,6 +162,38 @@ func (_m *ScanBuilder) WithProjection(projection interface{}) ddb.ScanBuilder {
 	return r0
 }
 
+// WithReadCapacityLimit provides a mock function with given fields: unitsPerSecond
+func (_m *ScanBuilder) WithReadCapacityLimit(unitsPerSecond float64) ddb.ScanBuilder {
+	ret := _m.Called(unitsPerSecond)
+
+	var r0 ddb.ScanBuilder
+	if rf, ok := ret.Get(0).(func(float64) ddb.ScanBuilder); ok {
+		r0 = rf(unitsPerSecond)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.ScanBuilder)
+		}
+	}
+
+	return r0
+}
+
+// WithResumeToken provides a mock function with given fields: token
+func (_m *ScanBuilder) WithResumeToken(token string) ddb.ScanBuilder {
+	ret := _m.Called(token)
+
+	var r0 ddb.ScanBuilder
+	if rf, ok := ret.Get(0).(func(string) ddb.ScanBuilder); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(ddb.ScanBuilder)
+		}
+	}
+
+	return r0
+}
+
 // WithSegment provides a mock function with given fields: segment, total
 func (_m *ScanBuilder) WithSegment(segment int, total int) ddb.ScanBuilder {
 	ret := _m.Called(segment, total)
@@ -32,6 +32,9 @@ const (
 	StreamViewTypeNewAndOldImages = dynamodb.StreamViewTypeNewAndOldImages
 	StreamViewTypeKeysOnly        = dynamodb.StreamViewTypeKeysOnly
 
+	BillingModeProvisioned   = dynamodb.BillingModeProvisioned
+	BillingModePayPerRequest = dynamodb.BillingModePayPerRequest
+
 	Create = "create"
 	Update = "update"
 	Delete = "delete"
@@ -48,7 +51,9 @@ type Repository interface {
 	GetItem(ctx context.Context, qb GetItemBuilder, result interface{}) (*GetItemResult, error)
 	PutItem(ctx context.Context, qb PutItemBuilder, item interface{}) (*PutItemResult, error)
 	Query(ctx context.Context, qb QueryBuilder, result interface{}) (*QueryResult, error)
+	QueryIterator(qb QueryBuilder, result interface{}) (QueryIterator, error)
 	Scan(ctx context.Context, sb ScanBuilder, result interface{}) (*ScanResult, error)
+	ScanIterator(sb ScanBuilder, result interface{}) (ScanIterator, error)
 	UpdateItem(ctx context.Context, ub UpdateItemBuilder, item interface{}) (*UpdateItemResult, error)
 
 	BatchGetItemsBuilder() BatchGetItemsBuilder
@@ -459,6 +464,10 @@ func (r *repository) PutItem(ctx context.Context, qb PutItemBuilder, item interf
 	result.ConditionalCheckFailed = isError(err, dynamodb.ErrCodeConditionalCheckFailedException)
 	result.ConsumedCapacity.add(out.ConsumedCapacity)
 
+	if err = r.checkVersion(ctx, qb, item, err); err != nil {
+		return result, err
+	}
+
 	if out.Attributes == nil {
 		result.IsReturnEmpty = true
 
@@ -499,15 +508,47 @@ func (r *repository) Query(ctx context.Context, qb QueryBuilder, items interface
 	return op.result, err
 }
 
+// QueryIterator returns the result of the query page by page. The result is only used to resolve the
+// projection of the query.
+func (r *repository) QueryIterator(qb QueryBuilder, result interface{}) (QueryIterator, error) {
+	op, err := qb.Build(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	segment := &iteratorSegment{
+		state: op.segments[0],
+		read: func(ctx context.Context) (*readResult, error) {
+			return r.doQuery(ctx, op)
+		},
+	}
+
+	return &queryIterator{
+		segmentReader: newSegmentReader([]*iteratorSegment{segment}, 1),
+	}, nil
+}
+
 func (r *repository) doQuery(ctx context.Context, op *QueryOperation) (*readResult, error) {
 	if op.iterator.isDone() {
 		return &readResult{}, nil
 	}
 
+	reserved, err := op.limiter.wait(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
 	outI, err := r.executor.Execute(ctx, func() (*request.Request, interface{}) {
 		return r.client.QueryRequest(op.input)
 	})
 
+	if err != nil {
+		// a failed request gives its reservation back
+		op.limiter.consume(reserved, nil)
+	}
+
 	if exec.IsRequestCanceled(err) {
 		return nil, exec.RequestCanceledError
 	}
@@ -521,6 +562,7 @@ func (r *repository) doQuery(ctx context.Context, op *QueryOperation) (*readResu
 	}
 
 	out := outI.(*dynamodb.QueryOutput)
+	op.limiter.consume(reserved, out.ConsumedCapacity)
 	op.result.RequestCount++
 	op.result.ItemCount += *out.Count
 	op.result.ScannedCount += *out.ScannedCount
@@ -535,6 +577,10 @@ func (r *repository) doQuery(ctx context.Context, op *QueryOperation) (*readResu
 		Items:            out.Items,
 		LastEvaluatedKey: out.LastEvaluatedKey,
 		Progress:         op.result,
+		RequestCount:     1,
+		Count:            *out.Count,
+		ScannedCount:     *out.ScannedCount,
+		ConsumedCapacity: out.ConsumedCapacity,
 	}
 
 	return resp, nil
@@ -571,6 +617,10 @@ func (r *repository) UpdateItem(ctx context.Context, ub UpdateItemBuilder, item
 	result.ConditionalCheckFailed = isError(err, dynamodb.ErrCodeConditionalCheckFailedException)
 	result.ConsumedCapacity.add(out.ConsumedCapacity)
 
+	if err = r.checkVersion(ctx, ub, item, err); err != nil {
+		return result, err
+	}
+
 	if out.Attributes == nil {
 		return result, nil
 	}
@@ -584,6 +634,67 @@ func (r *repository) UpdateItem(ctx context.Context, ub UpdateItemBuilder, item
 	return result, nil
 }
 
+// checkVersion turns a failed condition of a versioned write into a VersionConflictError or updates the version of the item.
+// If the write has a condition besides the version, the version is read again to tell which of them failed.
+func (r *repository) checkVersion(ctx context.Context, builder interface{}, item interface{}, err error) error {
+	versioned, ok := builder.(versionAware)
+
+	if !ok {
+		return nil
+	}
+
+	if err != nil {
+		conflict := versioned.versionConflict(err)
+
+		if conflict == nil || versioned.conflictKey() == nil {
+			return conflict
+		}
+
+		stored, err := r.readVersion(ctx, versioned.conflictKey())
+
+		if err != nil {
+			return err
+		}
+
+		matches, err := versioned.matchesVersion(stored)
+
+		if err != nil {
+			return fmt.Errorf("could not read the version of the item in table %s: %w", r.metadata.TableName, err)
+		}
+
+		if matches {
+			return nil
+		}
+
+		return conflict
+	}
+
+	if err = versioned.commitVersion(item); err != nil {
+		return fmt.Errorf("could not update the version of the item in table %s: %w", r.metadata.TableName, err)
+	}
+
+	return nil
+}
+
+// readVersion reads the version attribute of the stored item consistently, the attributes are nil if there is no item
+func (r *repository) readVersion(ctx context.Context, key KeyValues) (map[string]*dynamodb.AttributeValue, error) {
+	input := r.metadata.Version.readInput(r.metadata.TableName, key)
+
+	outI, err := r.executor.Execute(ctx, func() (*request.Request, interface{}) {
+		return r.client.GetItemRequest(input)
+	})
+
+	if exec.IsRequestCanceled(err) {
+		return nil, exec.RequestCanceledError
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read the version of the item in table %s: %w", r.metadata.TableName, err)
+	}
+
+	return outI.(*dynamodb.GetItemOutput).Item, nil
+}
+
 func (r *repository) Scan(ctx context.Context, sb ScanBuilder, items interface{}) (*ScanResult, error) {
 	_, span := r.tracer.StartSubSpan(ctx, "ddb.Scan")
 	defer span.Finish()
@@ -598,6 +709,10 @@ func (r *repository) Scan(ctx context.Context, sb ScanBuilder, items interface{}
 		return nil, fmt.Errorf("can not build scan operation: %w", err)
 	}
 
+	if len(op.segments) > 1 {
+		return r.scanParallel(ctx, op, items)
+	}
+
 	if callback, ok := isResultCallback(items); ok {
 		err = r.readCallback(ctx, op.targetType, callback, func() (*readResult, error) {
 			return r.doScan(ctx, op)
@@ -613,15 +728,125 @@ func (r *repository) Scan(ctx context.Context, sb ScanBuilder, items interface{}
 	return op.result, err
 }
 
+// ScanIterator returns the result of the scan page by page. The result is only used to resolve the
+// projection of the scan.
+func (r *repository) ScanIterator(sb ScanBuilder, result interface{}) (ScanIterator, error) {
+	if sb == nil {
+		sb = r.ScanBuilder()
+	}
+
+	op, err := sb.Build(result)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not build scan operation: %w", err)
+	}
+
+	return &scanIterator{
+		segmentReader: r.scanSegments(op),
+	}, nil
+}
+
+func (r *repository) scanSegments(op *ScanOperation) *segmentReader {
+	segments := make([]*iteratorSegment, len(op.segments))
+
+	for i := range op.segments {
+		segmentOp := op
+
+		if len(op.segments) > 1 {
+			segmentOp = op.forSegment(i)
+		}
+
+		segments[i] = &iteratorSegment{
+			state: op.segments[i],
+			read: func(ctx context.Context) (*readResult, error) {
+				return r.doScan(ctx, segmentOp)
+			},
+		}
+	}
+
+	return newSegmentReader(segments, op.workers)
+}
+
+// scanParallel reads all segments of a parallel scan into the items or passes the pages to the callback
+func (r *repository) scanParallel(ctx context.Context, op *ScanOperation, items interface{}) (*ScanResult, error) {
+	iterator := &scanIterator{
+		segmentReader: r.scanSegments(op),
+	}
+	callback, isCallback := isResultCallback(items)
+
+	var err error
+	var unmarshaller *Unmarshaller
+	var callbackErrors error
+
+	if isCallback {
+		unmarshaller, err = NewUnmarshallerFromStruct(op.targetType)
+	} else {
+		unmarshaller, err = NewUnmarshallerFromPtrSlice(items)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can not initialize unmarshaller for operation on table %s: %w", r.metadata.TableName, err)
+	}
+
+	for {
+		page, ok, err := iterator.next(ctx)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not execute read operation for table %s: %w", r.metadata.TableName, err)
+		}
+
+		if !ok {
+			break
+		}
+
+		if !isCallback {
+			if err = unmarshaller.Append(page.Items); err != nil {
+				return nil, fmt.Errorf("could not unmarshal items after Scan operation for table %s: %w", r.metadata.TableName, err)
+			}
+
+			continue
+		}
+
+		pageItems, err := unmarshaller.Unmarshal(page.Items)
+
+		if err != nil {
+			return nil, fmt.Errorf("could not unmarshal items after Scan operation for table %s: %w", r.metadata.TableName, err)
+		}
+
+		cont, err := callback(ctx, pageItems, iterator.Result())
+
+		if err != nil {
+			callbackErrors = multierror.Append(callbackErrors, err)
+		}
+
+		if err == nil && !cont {
+			break
+		}
+	}
+
+	return iterator.Result(), callbackErrors
+}
+
 func (r *repository) doScan(ctx context.Context, op *ScanOperation) (*readResult, error) {
 	if op.iterator.isDone() {
 		return &readResult{}, nil
 	}
 
+	reserved, err := op.limiter.wait(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
 	outI, err := r.executor.Execute(ctx, func() (*request.Request, interface{}) {
 		return r.client.ScanRequest(op.input)
 	})
 
+	if err != nil {
+		// a failed request gives its reservation back
+		op.limiter.consume(reserved, nil)
+	}
+
 	if exec.IsRequestCanceled(err) {
 		return nil, exec.RequestCanceledError
 	}
@@ -635,6 +860,7 @@ func (r *repository) doScan(ctx context.Context, op *ScanOperation) (*readResult
 	}
 
 	out := outI.(*dynamodb.ScanOutput)
+	op.limiter.consume(reserved, out.ConsumedCapacity)
 	op.result.RequestCount++
 	op.result.ItemCount += *out.Count
 	op.result.ScannedCount += *out.ScannedCount
@@ -649,6 +875,10 @@ func (r *repository) doScan(ctx context.Context, op *ScanOperation) (*readResult
 		Items:            out.Items,
 		LastEvaluatedKey: out.LastEvaluatedKey,
 		Progress:         op.result,
+		RequestCount:     1,
+		Count:            *out.Count,
+		ScannedCount:     *out.ScannedCount,
+		ConsumedCapacity: out.ConsumedCapacity,
 	}, nil
 }
 
@@ -25,6 +25,7 @@ func NewSimpleRepository(config cfg.Config, logger mon.Logger, settings *SimpleS
 		Main: MainSettings{
 			Model:              settings.Model,
 			StreamView:         settings.StreamView,
+			BillingMode:        settings.BillingMode,
 			ReadCapacityUnits:  settings.ReadCapacityUnits,
 			WriteCapacityUnits: settings.WriteCapacityUnits,
 		},
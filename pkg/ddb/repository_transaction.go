@@ -171,6 +171,10 @@ func (r transactionRepository) TransactWriteItemsIdempotent(ctx context.Context,
 	out := (outI).(*dynamodb.TransactWriteItemsOutput)
 	res.ConsumedCapacity.addSlice(out.ConsumedCapacity)
 
+	if err = commitTransactionVersions(itemBuilders); err != nil {
+		return nil, err
+	}
+
 	return res, parseTransactionError(err)
 }
 
@@ -182,6 +186,10 @@ func transformTransactionCanceledError(tcErr *dynamodb.TransactionCanceledExcept
 			continue
 		}
 
+		if conflict := transactionVersionConflict(itemBuilders[i]); conflict != nil {
+			multiErr = multierror.Append(multiErr, conflict)
+		}
+
 		err := dynamodbattribute.UnmarshalMap(reason.Item, itemBuilders[i].GetItem())
 		if err != nil {
 			unmarshalErr := fmt.Errorf("could not unmarshal partial response: %w", err)
@@ -191,3 +199,29 @@ func transformTransactionCanceledError(tcErr *dynamodb.TransactionCanceledExcept
 
 	return multiErr.ErrorOrNil()
 }
+
+func commitTransactionVersions(itemBuilders []TransactWriteItemBuilder) error {
+	for _, itemBuilder := range itemBuilders {
+		versioned, ok := itemBuilder.(versionedTransactItem)
+
+		if !ok || versioned.versionBuilder() == nil {
+			continue
+		}
+
+		if err := versioned.versionBuilder().commitVersion(itemBuilder.GetItem()); err != nil {
+			return fmt.Errorf("could not update the version of the transaction item: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func transactionVersionConflict(itemBuilder TransactWriteItemBuilder) error {
+	versioned, ok := itemBuilder.(versionedTransactItem)
+
+	if !ok || versioned.versionBuilder() == nil {
+		return nil
+	}
+
+	return versioned.versionBuilder().versionConflict(ErrorConditionalCheckFailed)
+}
@@ -384,3 +384,121 @@ func buildTransactGetItemBuilder(item *model) ddb.TransactGetItemBuilder {
 		Item:    item,
 	}
 }
+
+func (s *RepositoryTransactionTestSuite) TestTransactWriteItems_VersionConflict() {
+	_, versionedRepo := newVersionedRepository()
+
+	putItem := &versionedModel{
+		Id:      42,
+		Foo:     "bar",
+		Version: 2,
+	}
+	updateItem := &versionedModel{
+		Id:      43,
+		Version: 5,
+	}
+
+	items := []ddb.TransactWriteItemBuilder{
+		&ddb.TransactPutItem{
+			Builder: versionedRepo.PutItemBuilder(),
+			Item:    putItem,
+		},
+		&ddb.TransactUpdateItem{
+			Builder: versionedRepo.UpdateItemBuilder().Set("foo", "baz"),
+			Item:    updateItem,
+		},
+	}
+
+	ctx := context.Background()
+
+	s.tracer.
+		On("StartSubSpan", ctx, "ddb.TransactWriteItems").
+		Return(ctx, s.span)
+
+	s.span.
+		On("Finish").
+		Return()
+
+	requestErr := &dynamodb.TransactionCanceledException{
+		CancellationReasons: []*dynamodb.CancellationReason{
+			{
+				Code: aws.String("None"),
+			},
+			{
+				Code: aws.String("ConditionalCheckFailed"),
+			},
+		},
+	}
+
+	s.executor.
+		ExpectExecution("TransactWriteItemsRequest", mock.AnythingOfType("*dynamodb.TransactWriteItemsInput"), nil, requestErr)
+
+	result, err := s.repository.TransactWriteItems(ctx, items)
+
+	require.Nil(s.T(), result)
+	require.True(s.T(), ddb.IsVersionConflictError(err))
+
+	var conflict ddb.VersionConflictError
+	require.True(s.T(), errors.As(err, &conflict))
+
+	assert.Equal(s.T(), int64(5), conflict.Version)
+	assert.Equal(s.T(), int64(2), putItem.Version)
+	assert.Equal(s.T(), int64(5), updateItem.Version)
+}
+
+func (s *RepositoryTransactionTestSuite) TestTransactWriteItems_Versioned() {
+	_, versionedRepo := newVersionedRepository()
+
+	putItem := &versionedModel{
+		Id:      42,
+		Foo:     "bar",
+		Version: 2,
+	}
+
+	items := []ddb.TransactWriteItemBuilder{
+		&ddb.TransactPutItem{
+			Builder: versionedRepo.PutItemBuilder(),
+			Item:    putItem,
+		},
+	}
+
+	ctx := context.Background()
+
+	s.tracer.
+		On("StartSubSpan", ctx, "ddb.TransactWriteItems").
+		Return(ctx, s.span)
+
+	s.span.
+		On("Finish").
+		Return()
+
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: []*dynamodb.TransactWriteItem{
+			{
+				Put: &dynamodb.Put{
+					ConditionExpression: aws.String("#0 = :0"),
+					ExpressionAttributeNames: map[string]*string{
+						"#0": aws.String("version"),
+					},
+					ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+						":0": {N: aws.String("2")},
+					},
+					Item: map[string]*dynamodb.AttributeValue{
+						"id":      {N: aws.String("42")},
+						"foo":     {S: aws.String("bar")},
+						"version": {N: aws.String("3")},
+					},
+					TableName: aws.String("applike-test-gosoline-ddb-versionedModel"),
+				},
+			},
+		},
+	}
+
+	s.executor.
+		ExpectExecution("TransactWriteItemsRequest", input, &dynamodb.TransactWriteItemsOutput{}, nil)
+
+	_, err := s.repository.TransactWriteItems(ctx, items)
+
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(3), putItem.Version)
+}
@@ -18,6 +18,10 @@ type readResult struct {
 	Items            []map[string]*dynamodb.AttributeValue
 	LastEvaluatedKey map[string]*dynamodb.AttributeValue
 	Progress         Progress
+	RequestCount     int64
+	Count            int64
+	ScannedCount     int64
+	ConsumedCapacity *dynamodb.ConsumedCapacity
 }
 
 type OperationResult struct {
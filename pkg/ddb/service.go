@@ -16,6 +16,7 @@ import (
 type TableDescription struct {
 	Name      string
 	ItemCount int64
+	StreamArn string
 }
 
 type Service struct {
@@ -55,6 +56,10 @@ func (s *Service) DescribeTable(settings *Settings) (*TableDescription, error) {
 		ItemCount: *out.Table.ItemCount,
 	}
 
+	if out.Table.LatestStreamArn != nil {
+		description.StreamArn = *out.Table.LatestStreamArn
+	}
+
 	return description, nil
 }
 
@@ -77,7 +82,7 @@ func (s *Service) CreateTable(settings *Settings) (*Metadata, error) {
 	}
 
 	if exists {
-		return metadata, nil
+		return metadata, s.reconcileTable(settings, metadata)
 	}
 
 	mainKeySchema, err := s.getKeySchema(metadata.Main)
@@ -92,7 +97,7 @@ func (s *Service) CreateTable(settings *Settings) (*Metadata, error) {
 		return metadata, fmt.Errorf("can not create definitions for local secondary indices on table %s: %w", tableName, err)
 	}
 
-	globalIndices, err := s.getGlobalSecondaryIndices(metadata)
+	globalIndices, err := s.getGlobalSecondaryIndices(settings, metadata)
 
 	if err != nil {
 		return metadata, fmt.Errorf("can not create definitions for global secondary indices on table %s: %w", tableName, err)
@@ -116,10 +121,13 @@ func (s *Service) CreateTable(settings *Settings) (*Metadata, error) {
 		LocalSecondaryIndexes:  localIndices,
 		GlobalSecondaryIndexes: globalIndices,
 		StreamSpecification:    streamSpecification,
-		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
-			ReadCapacityUnits:  aws.Int64(metadata.Main.ReadCapacityUnits),
-			WriteCapacityUnits: aws.Int64(metadata.Main.WriteCapacityUnits),
-		},
+		SSESpecification:       s.getSseSpecification(settings),
+	}
+
+	if isPayPerRequest(settings) {
+		input.BillingMode = aws.String(BillingModePayPerRequest)
+	} else {
+		input.ProvisionedThroughput = s.getProvisionedThroughput(metadata.Main.metadataCapacity)
 	}
 
 	_, err = s.client.CreateTable(input)
@@ -140,11 +148,175 @@ func (s *Service) CreateTable(settings *Settings) (*Metadata, error) {
 
 	s.logger.Infof("created ddb table %s", tableName)
 
-	err = s.updateTtlSpecification(metadata)
+	if err = s.updateTtlSpecification(metadata); err != nil {
+		return metadata, err
+	}
+
+	err = s.updatePointInTimeRecovery(settings, metadata)
 
 	return metadata, err
 }
 
+// reconcileTable updates the billing mode, ttl and point in time recovery of an existing table if they drifted from the
+// settings. Only explicitly configured attributes are reconciled, so nothing is switched off or downgraded implicitly.
+func (s *Service) reconcileTable(settings *Settings, metadata *Metadata) error {
+	out, err := s.client.DescribeTable(&dynamodb.DescribeTableInput{
+		TableName: aws.String(metadata.TableName),
+	})
+
+	if err != nil {
+		return fmt.Errorf("can not describe ddb table %s: %w", metadata.TableName, err)
+	}
+
+	if err = s.reconcileBillingMode(settings, metadata, out.Table); err != nil {
+		return err
+	}
+
+	if err = s.reconcileTtl(metadata); err != nil {
+		return err
+	}
+
+	return s.reconcilePointInTimeRecovery(settings, metadata)
+}
+
+func (s *Service) reconcileBillingMode(settings *Settings, metadata *Metadata, table *dynamodb.TableDescription) error {
+	if settings.Main.BillingMode == "" {
+		return nil
+	}
+
+	current := BillingModeProvisioned
+	desired := settings.Main.BillingMode
+
+	if table.BillingModeSummary != nil && table.BillingModeSummary.BillingMode != nil {
+		current = *table.BillingModeSummary.BillingMode
+	}
+
+	if current == desired {
+		return nil
+	}
+
+	input := &dynamodb.UpdateTableInput{
+		TableName:   aws.String(metadata.TableName),
+		BillingMode: aws.String(desired),
+	}
+
+	if desired == BillingModeProvisioned {
+		input.ProvisionedThroughput = s.getProvisionedThroughput(metadata.Main.metadataCapacity)
+
+		for _, name := range s.getGlobalIndexNames(metadata) {
+			input.GlobalSecondaryIndexUpdates = append(input.GlobalSecondaryIndexUpdates, &dynamodb.GlobalSecondaryIndexUpdate{
+				Update: &dynamodb.UpdateGlobalSecondaryIndexAction{
+					IndexName:             aws.String(name),
+					ProvisionedThroughput: s.getProvisionedThroughput(metadata.Global[name].metadataCapacity),
+				},
+			})
+		}
+	}
+
+	_, err := s.client.UpdateTable(input)
+
+	if isError(err, dynamodb.ErrCodeLimitExceededException) {
+		s.logger.Warnf("can not update billing mode of ddb table %s from %s to %s yet: %s", metadata.TableName, current, desired, err)
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not update billing mode of ddb table %s: %w", metadata.TableName, err)
+	}
+
+	s.logger.Infof("updated billing mode of ddb table %s from %s to %s", metadata.TableName, current, desired)
+
+	return s.waitForTableGettingAvailable(metadata.TableName)
+}
+
+// reconcileTtl enables the ttl of the model, a ttl enabled on the table without a ttl in the model is kept
+func (s *Service) reconcileTtl(metadata *Metadata) error {
+	if !metadata.TimeToLive.Enabled {
+		return nil
+	}
+
+	out, err := s.client.DescribeTimeToLive(&dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(metadata.TableName),
+	})
+
+	if err != nil {
+		return fmt.Errorf("can not describe ttl of ddb table %s: %w", metadata.TableName, err)
+	}
+
+	status := dynamodb.TimeToLiveStatusDisabled
+	attributeName := ""
+
+	if out.TimeToLiveDescription != nil && out.TimeToLiveDescription.TimeToLiveStatus != nil {
+		status = *out.TimeToLiveDescription.TimeToLiveStatus
+	}
+
+	if out.TimeToLiveDescription != nil && out.TimeToLiveDescription.AttributeName != nil {
+		attributeName = *out.TimeToLiveDescription.AttributeName
+	}
+
+	enabled := status == dynamodb.TimeToLiveStatusEnabled || status == dynamodb.TimeToLiveStatusEnabling
+
+	switch {
+	case enabled && attributeName == metadata.TimeToLive.Field:
+		return nil
+	case status == dynamodb.TimeToLiveStatusDisabled:
+		return s.updateTtlSpecification(metadata)
+	default:
+		s.logger.Warnf("can not enable ttl on attribute %s of ddb table %s as the ttl is %s on attribute %s", metadata.TimeToLive.Field, metadata.TableName, status, attributeName)
+		return nil
+	}
+}
+
+// reconcilePointInTimeRecovery enables the point in time recovery if configured and not enabled on the table yet
+func (s *Service) reconcilePointInTimeRecovery(settings *Settings, metadata *Metadata) error {
+	if !settings.Main.PointInTimeRecovery {
+		return nil
+	}
+
+	out, err := s.client.DescribeContinuousBackups(&dynamodb.DescribeContinuousBackupsInput{
+		TableName: aws.String(metadata.TableName),
+	})
+
+	if err != nil {
+		return fmt.Errorf("can not describe continuous backups of ddb table %s: %w", metadata.TableName, err)
+	}
+
+	status := dynamodb.PointInTimeRecoveryStatusDisabled
+
+	if out.ContinuousBackupsDescription != nil &&
+		out.ContinuousBackupsDescription.PointInTimeRecoveryDescription != nil &&
+		out.ContinuousBackupsDescription.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus != nil {
+		status = *out.ContinuousBackupsDescription.PointInTimeRecoveryDescription.PointInTimeRecoveryStatus
+	}
+
+	if status == dynamodb.PointInTimeRecoveryStatusEnabled {
+		return nil
+	}
+
+	return s.updatePointInTimeRecovery(settings, metadata)
+}
+
+func (s *Service) updatePointInTimeRecovery(settings *Settings, metadata *Metadata) error {
+	if !settings.Main.PointInTimeRecovery {
+		return nil
+	}
+
+	_, err := s.client.UpdateContinuousBackups(&dynamodb.UpdateContinuousBackupsInput{
+		TableName: aws.String(metadata.TableName),
+		PointInTimeRecoverySpecification: &dynamodb.PointInTimeRecoverySpecification{
+			PointInTimeRecoveryEnabled: aws.Bool(true),
+		},
+	})
+
+	if err != nil {
+		return fmt.Errorf("could not enable point in time recovery for ddb table %s: %w", metadata.TableName, err)
+	}
+
+	s.logger.Infof("enabled point in time recovery for ddb table %s", metadata.TableName)
+
+	return nil
+}
+
 func (s *Service) updateTtlSpecification(metadata *Metadata) error {
 	ttlSpecification, err := s.getTimeToLiveSpecification(metadata)
 
@@ -277,21 +449,14 @@ func (s *Service) getLocalSecondaryIndices(meta *Metadata) ([]*dynamodb.LocalSec
 	return indices, nil
 }
 
-func (s *Service) getGlobalSecondaryIndices(meta *Metadata) ([]*dynamodb.GlobalSecondaryIndex, error) {
+func (s *Service) getGlobalSecondaryIndices(settings *Settings, meta *Metadata) ([]*dynamodb.GlobalSecondaryIndex, error) {
 	if len(meta.Global) == 0 {
 		return nil, nil
 	}
 
-	names := make([]string, 0)
 	indices := make([]*dynamodb.GlobalSecondaryIndex, 0, len(meta.Local))
 
-	for name := range meta.Global {
-		names = append(names, name)
-	}
-
-	sort.Strings(names)
-
-	for _, name := range names {
+	for _, name := range s.getGlobalIndexNames(meta) {
 		data := meta.Global[name]
 		keySchema, err := s.getKeySchema(data)
 
@@ -305,20 +470,62 @@ func (s *Service) getGlobalSecondaryIndices(meta *Metadata) ([]*dynamodb.GlobalS
 			return nil, err
 		}
 
-		indices = append(indices, &dynamodb.GlobalSecondaryIndex{
+		index := &dynamodb.GlobalSecondaryIndex{
 			IndexName:  aws.String(name),
 			KeySchema:  keySchema,
 			Projection: projection,
-			ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
-				ReadCapacityUnits:  aws.Int64(data.ReadCapacityUnits),
-				WriteCapacityUnits: aws.Int64(data.WriteCapacityUnits),
-			},
-		})
+		}
+
+		if !isPayPerRequest(settings) {
+			index.ProvisionedThroughput = s.getProvisionedThroughput(data.metadataCapacity)
+		}
+
+		indices = append(indices, index)
 	}
 
 	return indices, nil
 }
 
+func (s *Service) getGlobalIndexNames(meta *Metadata) []string {
+	names := make([]string, 0, len(meta.Global))
+
+	for name := range meta.Global {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (s *Service) getProvisionedThroughput(capacity metadataCapacity) *dynamodb.ProvisionedThroughput {
+	return &dynamodb.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(capacity.ReadCapacityUnits),
+		WriteCapacityUnits: aws.Int64(capacity.WriteCapacityUnits),
+	}
+}
+
+func (s *Service) getSseSpecification(settings *Settings) *dynamodb.SSESpecification {
+	if !settings.Main.ServerSideEncryption {
+		return nil
+	}
+
+	specification := &dynamodb.SSESpecification{
+		Enabled: aws.Bool(true),
+		SSEType: aws.String(dynamodb.SSETypeKms),
+	}
+
+	if settings.Main.KmsMasterKeyId != "" {
+		specification.KMSMasterKeyId = aws.String(settings.Main.KmsMasterKeyId)
+	}
+
+	return specification
+}
+
+func isPayPerRequest(settings *Settings) bool {
+	return settings.Main.BillingMode == BillingModePayPerRequest
+}
+
 func (s *Service) projectedFields(main FieldAware, second FieldAware) (*dynamodb.Projection, error) {
 	mainFields := main.GetFields()
 	secondFields := second.GetFields()
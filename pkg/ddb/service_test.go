@@ -58,13 +58,13 @@ func TestService_CreateTable(t *testing.T) {
 	client.On("DescribeTable", describeInput).Run(func(args mock.Arguments) {
 		describeCount++
 	}).Return(func(_ *dynamodb.DescribeTableInput) *dynamodb.DescribeTableOutput {
-		if describeCount == 0 {
+		if describeCount == 1 {
 			return nil
 		}
 
 		return describeOutput
 	}, func(_ *dynamodb.DescribeTableInput) error {
-		if describeCount == 0 {
+		if describeCount == 1 {
 			return awserr.New(dynamodb.ErrCodeResourceNotFoundException, "", nil)
 		}
 
@@ -264,4 +264,223 @@ func TestService_CreateTable(t *testing.T) {
 	})
 
 	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
+
+type onDemandModel struct {
+	Id  int   `json:"id" ddb:"key=hash"`
+	Ttl int64 `json:"ttl" ddb:"ttl=enabled"`
+}
+
+func onDemandSettings() *ddb.Settings {
+	return &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Project:     "applike",
+			Environment: "test",
+			Family:      "gosoline",
+			Application: "ddb",
+			Name:        "onDemand",
+		},
+		AutoCreate: true,
+		Main: ddb.MainSettings{
+			Model:                onDemandModel{},
+			BillingMode:          ddb.BillingModePayPerRequest,
+			PointInTimeRecovery:  true,
+			ServerSideEncryption: true,
+		},
+	}
+}
+
+func TestService_CreateTable_PayPerRequest(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	client := new(mocks.DynamoDBAPI)
+	tableName := aws.String("applike-test-gosoline-ddb-onDemand")
+
+	client.On("DescribeTable", &dynamodb.DescribeTableInput{TableName: tableName}).Return(nil, awserr.New(dynamodb.ErrCodeResourceNotFoundException, "", nil)).Once()
+	client.On("DescribeTable", &dynamodb.DescribeTableInput{TableName: tableName}).Return(&dynamodb.DescribeTableOutput{
+		Table: &dynamodb.TableDescription{
+			TableStatus: aws.String(dynamodb.TableStatusActive),
+		},
+	}, nil).Once()
+
+	client.On("CreateTable", &dynamodb.CreateTableInput{
+		TableName: tableName,
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{
+				AttributeName: aws.String("id"),
+				AttributeType: aws.String(dynamodb.ScalarAttributeTypeN),
+			},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{
+				AttributeName: aws.String("id"),
+				KeyType:       aws.String(dynamodb.KeyTypeHash),
+			},
+		},
+		BillingMode: aws.String(dynamodb.BillingModePayPerRequest),
+		StreamSpecification: &dynamodb.StreamSpecification{
+			StreamEnabled: aws.Bool(false),
+		},
+		SSESpecification: &dynamodb.SSESpecification{
+			Enabled: aws.Bool(true),
+			SSEType: aws.String(dynamodb.SSETypeKms),
+		},
+	}).Return(nil, nil).Once()
+
+	client.On("UpdateTimeToLive", &dynamodb.UpdateTimeToLiveInput{
+		TableName: tableName,
+		TimeToLiveSpecification: &dynamodb.TimeToLiveSpecification{
+			AttributeName: aws.String("ttl"),
+			Enabled:       aws.Bool(true),
+		},
+	}).Return(nil, nil).Once()
+
+	client.On("UpdateContinuousBackups", &dynamodb.UpdateContinuousBackupsInput{
+		TableName: tableName,
+		PointInTimeRecoverySpecification: &dynamodb.PointInTimeRecoverySpecification{
+			PointInTimeRecoveryEnabled: aws.Bool(true),
+		},
+	}).Return(nil, nil).Once()
+
+	svc := ddb.NewServiceWithInterfaces(logger, client)
+	_, err := svc.CreateTable(onDemandSettings())
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
+
+func TestService_CreateTable_Reconcile(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	client := new(mocks.DynamoDBAPI)
+	tableName := aws.String("applike-test-gosoline-ddb-onDemand")
+
+	client.On("DescribeTable", &dynamodb.DescribeTableInput{TableName: tableName}).Return(&dynamodb.DescribeTableOutput{
+		Table: &dynamodb.TableDescription{
+			TableStatus: aws.String(dynamodb.TableStatusActive),
+			BillingModeSummary: &dynamodb.BillingModeSummary{
+				BillingMode: aws.String(dynamodb.BillingModeProvisioned),
+			},
+		},
+	}, nil).Times(3)
+
+	client.On("UpdateTable", &dynamodb.UpdateTableInput{
+		TableName:   tableName,
+		BillingMode: aws.String(dynamodb.BillingModePayPerRequest),
+	}).Return(nil, nil).Once()
+
+	client.On("DescribeTimeToLive", &dynamodb.DescribeTimeToLiveInput{TableName: tableName}).Return(&dynamodb.DescribeTimeToLiveOutput{
+		TimeToLiveDescription: &dynamodb.TimeToLiveDescription{
+			TimeToLiveStatus: aws.String(dynamodb.TimeToLiveStatusDisabled),
+		},
+	}, nil).Once()
+
+	client.On("UpdateTimeToLive", &dynamodb.UpdateTimeToLiveInput{
+		TableName: tableName,
+		TimeToLiveSpecification: &dynamodb.TimeToLiveSpecification{
+			AttributeName: aws.String("ttl"),
+			Enabled:       aws.Bool(true),
+		},
+	}).Return(nil, nil).Once()
+
+	client.On("DescribeContinuousBackups", &dynamodb.DescribeContinuousBackupsInput{TableName: tableName}).Return(&dynamodb.DescribeContinuousBackupsOutput{
+		ContinuousBackupsDescription: &dynamodb.ContinuousBackupsDescription{
+			ContinuousBackupsStatus: aws.String(dynamodb.ContinuousBackupsStatusEnabled),
+			PointInTimeRecoveryDescription: &dynamodb.PointInTimeRecoveryDescription{
+				PointInTimeRecoveryStatus: aws.String(dynamodb.PointInTimeRecoveryStatusDisabled),
+			},
+		},
+	}, nil).Once()
+
+	client.On("UpdateContinuousBackups", &dynamodb.UpdateContinuousBackupsInput{
+		TableName: tableName,
+		PointInTimeRecoverySpecification: &dynamodb.PointInTimeRecoverySpecification{
+			PointInTimeRecoveryEnabled: aws.Bool(true),
+		},
+	}).Return(nil, nil).Once()
+
+	svc := ddb.NewServiceWithInterfaces(logger, client)
+	_, err := svc.CreateTable(onDemandSettings())
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+}
+
+type noTtlModel struct {
+	Id int `json:"id" ddb:"key=hash"`
+}
+
+func TestService_CreateTable_Reconcile_OnlyConfigured(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	client := new(mocks.DynamoDBAPI)
+	tableName := aws.String("applike-test-gosoline-ddb-noTtl")
+
+	client.On("DescribeTable", &dynamodb.DescribeTableInput{TableName: tableName}).Return(&dynamodb.DescribeTableOutput{
+		Table: &dynamodb.TableDescription{
+			TableStatus: aws.String(dynamodb.TableStatusActive),
+			BillingModeSummary: &dynamodb.BillingModeSummary{
+				BillingMode: aws.String(dynamodb.BillingModePayPerRequest),
+			},
+		},
+	}, nil).Twice()
+
+	svc := ddb.NewServiceWithInterfaces(logger, client)
+	_, err := svc.CreateTable(&ddb.Settings{
+		ModelId: mdl.ModelId{
+			Project:     "applike",
+			Environment: "test",
+			Family:      "gosoline",
+			Application: "ddb",
+			Name:        "noTtl",
+		},
+		AutoCreate: true,
+		Main: ddb.MainSettings{
+			Model: noTtlModel{},
+		},
+	})
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+	client.AssertNotCalled(t, "UpdateTable", mock.Anything)
+	client.AssertNotCalled(t, "DescribeTimeToLive", mock.Anything)
+	client.AssertNotCalled(t, "UpdateTimeToLive", mock.Anything)
+}
+
+func TestService_CreateTable_Reconcile_PointInTimeRecoveryEnabled(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	client := new(mocks.DynamoDBAPI)
+	tableName := aws.String("applike-test-gosoline-ddb-onDemand")
+
+	client.On("DescribeTable", &dynamodb.DescribeTableInput{TableName: tableName}).Return(&dynamodb.DescribeTableOutput{
+		Table: &dynamodb.TableDescription{
+			TableStatus: aws.String(dynamodb.TableStatusActive),
+			BillingModeSummary: &dynamodb.BillingModeSummary{
+				BillingMode: aws.String(dynamodb.BillingModePayPerRequest),
+			},
+		},
+	}, nil).Twice()
+
+	client.On("DescribeTimeToLive", &dynamodb.DescribeTimeToLiveInput{TableName: tableName}).Return(&dynamodb.DescribeTimeToLiveOutput{
+		TimeToLiveDescription: &dynamodb.TimeToLiveDescription{
+			AttributeName:    aws.String("ttl"),
+			TimeToLiveStatus: aws.String(dynamodb.TimeToLiveStatusEnabled),
+		},
+	}, nil).Once()
+
+	client.On("DescribeContinuousBackups", &dynamodb.DescribeContinuousBackupsInput{TableName: tableName}).Return(&dynamodb.DescribeContinuousBackupsOutput{
+		ContinuousBackupsDescription: &dynamodb.ContinuousBackupsDescription{
+			ContinuousBackupsStatus: aws.String(dynamodb.ContinuousBackupsStatusEnabled),
+			PointInTimeRecoveryDescription: &dynamodb.PointInTimeRecoveryDescription{
+				PointInTimeRecoveryStatus: aws.String(dynamodb.PointInTimeRecoveryStatusEnabled),
+			},
+		},
+	}, nil).Once()
+
+	svc := ddb.NewServiceWithInterfaces(logger, client)
+	_, err := svc.CreateTable(onDemandSettings())
+
+	assert.NoError(t, err)
+	client.AssertExpectations(t)
+	client.AssertNotCalled(t, "UpdateTable", mock.Anything)
+	client.AssertNotCalled(t, "UpdateTimeToLive", mock.Anything)
+	client.AssertNotCalled(t, "UpdateContinuousBackups", mock.Anything)
 }
@@ -21,10 +21,18 @@ type Settings struct {
 }
 
 type MainSettings struct {
-	Model              interface{}
-	StreamView         string
+	Model      interface{}
+	StreamView string
+	// BillingMode is either BillingModeProvisioned (the default for new tables) or BillingModePayPerRequest. The
+	// billing mode of an existing table is only changed if it is set explicitly.
+	BillingMode        string
 	ReadCapacityUnits  int64
 	WriteCapacityUnits int64
+	// PointInTimeRecovery enables continuous backups of the table
+	PointInTimeRecovery bool
+	// ServerSideEncryption encrypts the table with a KMS key, the AWS managed key is used if KmsMasterKeyId is empty
+	ServerSideEncryption bool
+	KmsMasterKeyId       string
 }
 
 type LocalSettings struct {
@@ -33,8 +41,9 @@ type LocalSettings struct {
 }
 
 type GlobalSettings struct {
-	Name               string
-	Model              interface{}
+	Name  string
+	Model interface{}
+	// the capacity units are ignored if the table uses BillingModePayPerRequest
 	ReadCapacityUnits  int64
 	WriteCapacityUnits int64
 }
@@ -44,6 +53,7 @@ type SimpleSettings struct {
 	AutoCreate         bool
 	Model              interface{}
 	StreamView         string
+	BillingMode        string
 	ReadCapacityUnits  int64
 	WriteCapacityUnits int64
 }
@@ -0,0 +1,192 @@
+package ddb
+
+import (
+	"fmt"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"reflect"
+	"strconv"
+)
+
+// versionAware is implemented by the write builders which increment the version attribute of an item
+type versionAware interface {
+	// commitVersion sets the written version on the item after a successful write
+	commitVersion(item interface{}) error
+	// versionConflict returns a VersionConflictError for a failed condition or nil if the item isn't versioned
+	versionConflict(err error) error
+	// conflictKey returns the key of the item if the write has a condition besides the version, so a failed
+	// condition has to be checked against the stored version
+	conflictKey() KeyValues
+	// matchesVersion tells if the stored attributes still have the version the item was built with
+	matchesVersion(stored map[string]*dynamodb.AttributeValue) (bool, error)
+}
+
+// versionedTransactItem is implemented by transact write items whose builder might be versionAware
+type versionedTransactItem interface {
+	versionBuilder() versionAware
+}
+
+// versionState keeps the version an item was built with until the write is committed
+type versionState struct {
+	metadata *Metadata
+	version  *int64
+	key      KeyValues
+}
+
+func (s *versionState) read(item interface{}) (int64, error) {
+	version, err := s.metadata.Version.get(item)
+
+	if err != nil {
+		return 0, err
+	}
+
+	s.version = &version
+
+	return version, nil
+}
+
+func (s *versionState) commitVersion(item interface{}) error {
+	if s.version == nil {
+		return nil
+	}
+
+	return s.metadata.Version.set(item, *s.version+1)
+}
+
+func (s *versionState) versionConflict(err error) error {
+	if s.version == nil {
+		return nil
+	}
+
+	return NewVersionConflictError(s.metadata.TableName, *s.version, err)
+}
+
+// withConditionKey remembers the key of an item whose write has a condition of the user besides the version
+func (s *versionState) withConditionKey(key KeyValues) {
+	s.key = key
+}
+
+func (s *versionState) conflictKey() KeyValues {
+	return s.key
+}
+
+func (s *versionState) matchesVersion(stored map[string]*dynamodb.AttributeValue) (bool, error) {
+	if s.version == nil {
+		return false, nil
+	}
+
+	attribute, ok := stored[s.metadata.Version.Field]
+
+	if !ok || attribute.N == nil {
+		return *s.version == 0, nil
+	}
+
+	version, err := strconv.ParseInt(*attribute.N, 10, 64)
+
+	if err != nil {
+		return false, fmt.Errorf("the stored version %s is not an integer: %w", *attribute.N, err)
+	}
+
+	return version == *s.version, nil
+}
+
+func isIntegerKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+
+	return false
+}
+
+func (v metadataVersion) field(item interface{}) (reflect.Value, error) {
+	if !isPointer(item) {
+		return reflect.Value{}, fmt.Errorf("items with a version attribute have to be a pointer")
+	}
+
+	value := reflect.ValueOf(item).Elem()
+
+	if value.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("items with a version attribute have to be a struct but are %T", item)
+	}
+
+	field := value.FieldByName(v.FieldName)
+
+	if !field.IsValid() || !isIntegerKind(field.Kind()) {
+		return reflect.Value{}, fmt.Errorf("the item %T has no integer version field %s", item, v.FieldName)
+	}
+
+	return field, nil
+}
+
+func (v metadataVersion) get(item interface{}) (int64, error) {
+	field, err := v.field(item)
+
+	if err != nil {
+		return 0, err
+	}
+
+	switch field.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(field.Uint()), nil
+	default:
+		return field.Int(), nil
+	}
+}
+
+func (v metadataVersion) set(item interface{}, version int64) error {
+	field, err := v.field(item)
+
+	if err != nil {
+		return err
+	}
+
+	switch field.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		field.SetUint(uint64(version))
+	default:
+		field.SetInt(version)
+	}
+
+	return nil
+}
+
+// condition matches the version the item was read with. A version of zero marks an item which wasn't written yet.
+func (v metadataVersion) condition(version int64) expression.ConditionBuilder {
+	if version == 0 {
+		return expression.AttributeNotExists(expression.Name(v.Field))
+	}
+
+	return expression.Name(v.Field).Equal(expression.Value(version))
+}
+
+func withVersionCondition(cond *expression.ConditionBuilder, versionCond expression.ConditionBuilder) *expression.ConditionBuilder {
+	if cond == nil {
+		return &versionCond
+	}
+
+	combined := versionCond.And(*cond)
+
+	return &combined
+}
+
+// readInput reads only the version of the stored item, consistently to see the result of the failed write
+func (v metadataVersion) readInput(tableName string, key KeyValues) *dynamodb.GetItemInput {
+	return &dynamodb.GetItemInput{
+		TableName:            aws.String(tableName),
+		Key:                  key,
+		ConsistentRead:       aws.Bool(true),
+		ProjectionExpression: aws.String("#0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#0": aws.String(v.Field),
+		},
+	}
+}
+
+// increment is the version stored in ddb plus one, the condition ensures the stored version matches the item
+func (v metadataVersion) increment() expression.OperandBuilder {
+	return expression.Plus(expression.IfNotExists(expression.Name(v.Field), expression.Value(0)), expression.Value(1))
+}
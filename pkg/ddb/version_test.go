@@ -0,0 +1,185 @@
+package ddb_test
+
+import (
+	"context"
+	gosoAws "github.com/applike/gosoline/pkg/cloud/aws"
+	cloudMocks "github.com/applike/gosoline/pkg/cloud/mocks"
+	"github.com/applike/gosoline/pkg/ddb"
+	"github.com/applike/gosoline/pkg/mdl"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/expression"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type versionedModel struct {
+	Id      int    `json:"id" ddb:"key=hash"`
+	Foo     string `json:"foo"`
+	Version int64  `json:"version" ddb:"version=enabled"`
+}
+
+func newVersionedRepository() (*gosoAws.TestableExecutor, ddb.Repository) {
+	logger := monMocks.NewLoggerMockedAll()
+	client := new(cloudMocks.DynamoDBAPI)
+	executor := gosoAws.NewTestableExecutor(&client.Mock)
+
+	repo := ddb.NewWithInterfaces(logger, tracing.NewNoopTracer(), client, executor, &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Project:     "applike",
+			Environment: "test",
+			Family:      "gosoline",
+			Application: "ddb",
+			Name:        "versionedModel",
+		},
+		Main: ddb.MainSettings{
+			Model: versionedModel{},
+		},
+	})
+
+	return executor, repo
+}
+
+func TestRepository_PutItem_Versioned(t *testing.T) {
+	executor, repo := newVersionedRepository()
+
+	input := &dynamodb.PutItemInput{
+		TableName:           aws.String("applike-test-gosoline-ddb-versionedModel"),
+		ConditionExpression: aws.String("attribute_not_exists (#0)"),
+		ExpressionAttributeNames: map[string]*string{
+			"#0": aws.String("version"),
+		},
+		Item: map[string]*dynamodb.AttributeValue{
+			"id":      {N: aws.String("1")},
+			"foo":     {S: aws.String("foo")},
+			"version": {N: aws.String("1")},
+		},
+	}
+	executor.ExpectExecution("PutItemRequest", input, &dynamodb.PutItemOutput{}, nil)
+
+	item := &versionedModel{
+		Id:  1,
+		Foo: "foo",
+	}
+	res, err := repo.PutItem(context.Background(), nil, item)
+
+	assert.NoError(t, err)
+	assert.False(t, res.ConditionalCheckFailed)
+	assert.Equal(t, int64(1), item.Version)
+
+	executor.AssertExpectations(t)
+}
+
+func TestRepository_UpdateItem_VersionConflict(t *testing.T) {
+	executor, repo := newVersionedRepository()
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String("applike-test-gosoline-ddb-versionedModel"),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {N: aws.String("1")},
+		},
+		ConditionExpression: aws.String("#0 = :0"),
+		ExpressionAttributeNames: map[string]*string{
+			"#0": aws.String("version"),
+			"#1": aws.String("foo"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":0": {N: aws.String("3")},
+			":1": {N: aws.String("0")},
+			":2": {N: aws.String("1")},
+			":3": {S: aws.String("bar")},
+		},
+		UpdateExpression: aws.String("SET #0 = if_not_exists(#0, :1) + :2, #1 = :3\n"),
+	}
+	conflict := awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "conditional check failed", nil)
+	executor.ExpectExecution("UpdateItemRequest", input, &dynamodb.UpdateItemOutput{}, conflict)
+
+	item := &versionedModel{
+		Id:      1,
+		Version: 3,
+	}
+	res, err := repo.UpdateItem(context.Background(), repo.UpdateItemBuilder().Set("foo", "bar"), item)
+
+	assert.True(t, ddb.IsVersionConflictError(err))
+	assert.EqualError(t, err, "the item in ddb table applike-test-gosoline-ddb-versionedModel was modified since version 3: ConditionalCheckFailedException: conditional check failed")
+	assert.True(t, res.ConditionalCheckFailed)
+	assert.Equal(t, int64(3), item.Version, "the version is only incremented after a successful write")
+
+	executor.AssertExpectations(t)
+}
+
+func TestRepository_PutItem_VersionedConditionFailed(t *testing.T) {
+	for name, test := range map[string]struct {
+		stored   map[string]*dynamodb.AttributeValue
+		conflict bool
+	}{
+		"user condition": {
+			stored:   map[string]*dynamodb.AttributeValue{"version": {N: aws.String("3")}},
+			conflict: false,
+		},
+		"version": {
+			stored:   map[string]*dynamodb.AttributeValue{"version": {N: aws.String("4")}},
+			conflict: true,
+		},
+		"deleted item": {
+			stored:   nil,
+			conflict: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			executor, repo := newVersionedRepository()
+
+			input := &dynamodb.PutItemInput{
+				TableName:           aws.String("applike-test-gosoline-ddb-versionedModel"),
+				ConditionExpression: aws.String("(#0 = :0) AND (#1 = :1)"),
+				ExpressionAttributeNames: map[string]*string{
+					"#0": aws.String("version"),
+					"#1": aws.String("foo"),
+				},
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":0": {N: aws.String("3")},
+					":1": {S: aws.String("foo")},
+				},
+				Item: map[string]*dynamodb.AttributeValue{
+					"id":      {N: aws.String("1")},
+					"foo":     {S: aws.String("bar")},
+					"version": {N: aws.String("4")},
+				},
+			}
+			failed := awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "conditional check failed", nil)
+			executor.ExpectExecution("PutItemRequest", input, &dynamodb.PutItemOutput{}, failed)
+
+			readInput := &dynamodb.GetItemInput{
+				TableName:            aws.String("applike-test-gosoline-ddb-versionedModel"),
+				Key:                  map[string]*dynamodb.AttributeValue{"id": {N: aws.String("1")}},
+				ConsistentRead:       aws.Bool(true),
+				ProjectionExpression: aws.String("#0"),
+				ExpressionAttributeNames: map[string]*string{
+					"#0": aws.String("version"),
+				},
+			}
+			executor.ExpectExecution("GetItemRequest", readInput, &dynamodb.GetItemOutput{Item: test.stored}, nil)
+
+			item := &versionedModel{
+				Id:      1,
+				Foo:     "bar",
+				Version: 3,
+			}
+			qb := repo.PutItemBuilder().WithCondition(expression.Name("foo").Equal(expression.Value("foo")))
+			res, err := repo.PutItem(context.Background(), qb, item)
+
+			assert.Equal(t, test.conflict, ddb.IsVersionConflictError(err))
+			assert.True(t, res.ConditionalCheckFailed)
+			assert.Equal(t, int64(3), item.Version)
+
+			if !test.conflict {
+				assert.NoError(t, err)
+			}
+
+			executor.AssertExpectations(t)
+		})
+	}
+}
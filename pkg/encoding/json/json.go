@@ -2,6 +2,8 @@ package json
 
 import "encoding/json"
 
+type RawMessage = json.RawMessage
+
 func Marshal(v interface{}) ([]byte, error) {
 	return json.Marshal(v)
 }
@@ -0,0 +1,34 @@
+package fixtures
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/mon"
+)
+
+// postgres has no switch for foreign key checks, so the truncate cascades to referencing tables instead
+const truncateTableCascadeStatement = "TRUNCATE TABLE %s RESTART IDENTITY CASCADE;"
+
+type postgresPurger struct {
+	client    db.Client
+	logger    mon.Logger
+	tableName string
+}
+
+func newPostgresPurger(config cfg.Config, logger mon.Logger, tableName string) *postgresPurger {
+	client := db.NewClient(config, logger, "default")
+
+	return &postgresPurger{client: client, logger: logger, tableName: tableName}
+}
+
+func (p *postgresPurger) purgePostgres() error {
+	_, err := p.client.Exec(fmt.Sprintf(truncateTableCascadeStatement, p.tableName))
+
+	if err != nil {
+		p.logger.Errorf(err, "error truncating table %s", p.tableName)
+		return err
+	}
+
+	return nil
+}
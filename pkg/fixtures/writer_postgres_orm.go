@@ -0,0 +1,90 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/mon"
+)
+
+// fixtures are written with their ids, so the sequence has to be moved behind the highest id afterwards
+const resetSequenceStatement = "SELECT setval(pg_get_serial_sequence('%s', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %s;"
+
+type postgresOrmFixtureWriter struct {
+	logger   mon.Logger
+	metadata *db_repo.Metadata
+	repo     db_repo.Repository
+	client   db.Client
+	purger   *postgresPurger
+}
+
+func PostgresOrmFixtureWriterFactory(metadata *db_repo.Metadata) FixtureWriterFactory {
+	return func(config cfg.Config, logger mon.Logger) (FixtureWriter, error) {
+		metadata.ModelId.PadFromConfig(config)
+
+		settings := db_repo.Settings{
+			AppId:    cfg.GetAppIdFromConfig(config),
+			Metadata: *metadata,
+		}
+
+		repo, err := db_repo.New(config, logger, settings)
+		if err != nil {
+			return nil, fmt.Errorf("can not create repo: %w", err)
+		}
+
+		client := db.NewClient(config, logger, "default")
+		purger := newPostgresPurger(config, logger, metadata.TableName)
+
+		return NewPostgresOrmFixtureWriterWithInterfaces(logger, metadata, repo, client, purger), nil
+	}
+}
+
+func NewPostgresOrmFixtureWriterWithInterfaces(logger mon.Logger, metadata *db_repo.Metadata, repo db_repo.Repository, client db.Client, purger *postgresPurger) FixtureWriter {
+	return &postgresOrmFixtureWriter{
+		logger:   logger,
+		metadata: metadata,
+		repo:     repo,
+		client:   client,
+		purger:   purger,
+	}
+}
+
+func (m *postgresOrmFixtureWriter) Purge() error {
+	err := m.purger.purgePostgres()
+
+	if err != nil {
+		m.logger.Errorf(err, "error occured during purging of table %s in orm postgres fixture loader", m.metadata.TableName)
+
+		return err
+	}
+
+	m.logger.Infof("purged table for orm postgres fixtures")
+
+	return nil
+}
+
+func (m *postgresOrmFixtureWriter) Write(fs *FixtureSet) error {
+	ctx := context.Background()
+
+	for _, item := range fs.Fixtures {
+		model := item.(db_repo.ModelBased)
+
+		err := m.repo.Update(ctx, model)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	qry := fmt.Sprintf(resetSequenceStatement, m.metadata.TableName, m.metadata.TableName)
+
+	if _, err := m.client.ExecContext(ctx, qry); err != nil {
+		return fmt.Errorf("can not reset the id sequence of table %s: %w", m.metadata.TableName, err)
+	}
+
+	m.logger.Infof("loaded %d postgres fixtures", len(fs.Fixtures))
+
+	return nil
+}
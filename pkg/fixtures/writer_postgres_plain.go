@@ -0,0 +1,111 @@
+package fixtures
+
+import (
+	"fmt"
+	"github.com/Masterminds/squirrel"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/db"
+	"github.com/applike/gosoline/pkg/mon"
+	"strings"
+)
+
+type PostgresPlainFixtureValues []interface{}
+
+type PostgresPlainMetaData struct {
+	TableName   string
+	Columns     []string
+	PrimaryKeys []string
+}
+
+type postgresPlainFixtureWriter struct {
+	logger   mon.Logger
+	client   db.Client
+	metadata *PostgresPlainMetaData
+	purger   *postgresPurger
+}
+
+func PostgresPlainFixtureWriterFactory(metadata *PostgresPlainMetaData) FixtureWriterFactory {
+	return func(config cfg.Config, logger mon.Logger) (FixtureWriter, error) {
+		dbClient := db.NewClient(config, logger, "default")
+		purger := newPostgresPurger(config, logger, metadata.TableName)
+
+		return NewPostgresPlainFixtureWriterWithInterfaces(logger, dbClient, metadata, purger), nil
+	}
+}
+
+func NewPostgresPlainFixtureWriterWithInterfaces(logger mon.Logger, client db.Client, metadata *PostgresPlainMetaData, purger *postgresPurger) FixtureWriter {
+	return &postgresPlainFixtureWriter{
+		logger:   logger,
+		client:   client,
+		metadata: metadata,
+		purger:   purger,
+	}
+}
+
+func (m *postgresPlainFixtureWriter) Purge() error {
+	err := m.purger.purgePostgres()
+
+	if err != nil {
+		m.logger.Errorf(err, "error occured during purging of table %s in plain postgres fixture loader", m.metadata.TableName)
+
+		return err
+	}
+
+	m.logger.Infof("purged table %s for plain postgres fixtures", m.metadata.TableName)
+
+	return nil
+}
+
+func (m *postgresPlainFixtureWriter) Write(fs *FixtureSet) error {
+	for _, item := range fs.Fixtures {
+		fixture := item.(PostgresPlainFixtureValues)
+
+		sql, args, err := m.buildSql(fixture)
+
+		if err != nil {
+			return err
+		}
+
+		res, err := m.client.Exec(sql, args...)
+
+		if err != nil {
+			return err
+		}
+
+		ar, err := res.RowsAffected()
+
+		if err != nil {
+			return err
+		}
+
+		m.logger.Info(fmt.Sprintf("affected rows while fixture loading: %d", ar))
+	}
+
+	m.logger.Infof("loaded %d plain postgres fixtures", len(fs.Fixtures))
+
+	return nil
+}
+
+// buildSql replaces existing rows like the mysql REPLACE does if primary keys are given
+func (m *postgresPlainFixtureWriter) buildSql(values PostgresPlainFixtureValues) (string, []interface{}, error) {
+	insertBuilder := squirrel.Insert(m.metadata.TableName).
+		PlaceholderFormat(squirrel.Dollar).
+		Columns(m.metadata.Columns...).
+		Values(values...)
+
+	if len(m.metadata.PrimaryKeys) > 0 {
+		insertBuilder = insertBuilder.Suffix(m.upsertSuffix())
+	}
+
+	return insertBuilder.ToSql()
+}
+
+func (m *postgresPlainFixtureWriter) upsertSuffix() string {
+	updates := make([]string, 0, len(m.metadata.Columns))
+
+	for _, column := range m.metadata.Columns {
+		updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", column, column))
+	}
+
+	return fmt.Sprintf("ON CONFLICT (%s) DO UPDATE SET %s", strings.Join(m.metadata.PrimaryKeys, ", "), strings.Join(updates, ", "))
+}
@@ -5,7 +5,9 @@ import (
 	"errors"
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/conc"
 	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/tracing"
 	"gopkg.in/resty.v1"
 	"net/http"
 	netUrl "net/url"
@@ -57,9 +59,20 @@ type headers map[string]string
 
 type client struct {
 	logger         mon.Logger
+	tracer         tracing.Tracer
 	defaultHeaders headers
 	http           restyClient
 	mo             mon.MetricWriter
+	rateLimiter    conc.RateLimiter
+}
+
+type ClientOption func(c *client)
+
+// WithRateLimiter waits for a token of the rate limiter before every request, the host of the request is used as key
+func WithRateLimiter(limiter conc.RateLimiter) ClientOption {
+	return func(c *client) {
+		c.rateLimiter = limiter
+	}
 }
 
 type Settings struct {
@@ -70,7 +83,7 @@ type Settings struct {
 	FollowRedirect   bool          `cfg:"follow_redirects"`
 }
 
-func NewHttpClient(config cfg.Config, logger mon.Logger) Client {
+func NewHttpClient(config cfg.Config, logger mon.Logger, options ...ClientOption) Client {
 	mo := mon.NewMetricDaemonWriter()
 
 	settings := &Settings{}
@@ -86,16 +99,25 @@ func NewHttpClient(config cfg.Config, logger mon.Logger) Client {
 	httpClient.SetRetryWaitTime(settings.RetryWaitTime)
 	httpClient.SetRetryMaxWaitTime(settings.RetryMaxWaitTime)
 
-	return NewHttpClientWithInterfaces(logger, mo, httpClient)
+	tracer := tracing.ProviderTracer(config, logger)
+
+	return NewHttpClientWithInterfaces(logger, tracer, mo, httpClient, options...)
 }
 
-func NewHttpClientWithInterfaces(logger mon.Logger, mo mon.MetricWriter, httpClient restyClient) Client {
-	return &client{
+func NewHttpClientWithInterfaces(logger mon.Logger, tracer tracing.Tracer, mo mon.MetricWriter, httpClient restyClient, options ...ClientOption) Client {
+	c := &client{
 		logger:         logger,
+		tracer:         tracer,
 		defaultHeaders: make(headers),
 		http:           httpClient,
 		mo:             mo,
 	}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
 }
 
 func (c *client) NewRequest() *Request {
@@ -185,13 +207,30 @@ func (c *client) do(ctx context.Context, method string, request *Request) (*Resp
 		return nil, fmt.Errorf("failed to assemble request: %w", err)
 	}
 
+	// the client span becomes the parent of the span of the called service
+	ctx, span := c.tracer.StartClientSpan(ctx, fmt.Sprintf("%s %s", method, request.url.Host))
+	defer span.Finish()
+
+	span.AddMetadata("http.method", method)
+	span.AddMetadata("http.url", url)
+
 	req.SetContext(ctx)
 	req.SetHeaders(c.defaultHeaders)
 
+	if traceParent := tracing.TraceParentFromContext(ctx); traceParent != "" {
+		req.SetHeader(tracing.HeaderTraceParent, traceParent)
+	}
+
 	if request.outputFile != nil {
 		req.SetOutput(*request.outputFile)
 	}
 
+	if c.rateLimiter != nil {
+		if err = c.rateLimiter.Wait(ctx, request.url.Host); err != nil {
+			return nil, fmt.Errorf("failed to wait for the rate limit of %s: %w", request.url.Host, err)
+		}
+	}
+
 	c.writeMetric(metricRequest, method, mon.UnitCount, 1.0)
 	resp, err := req.Execute(method, url)
 
@@ -204,12 +243,21 @@ func (c *client) do(ctx context.Context, method string, request *Request) (*Resp
 	// (or many users spam us because sometimes they cancel requests)
 	if err != nil {
 		c.writeMetric(metricError, method, mon.UnitCount, 1.0)
-		return nil, fmt.Errorf("failed to perform %s request to %s: %w", request.restyRequest.Method, request.url.String(), err)
+		err = fmt.Errorf("failed to perform %s request to %s: %w", request.restyRequest.Method, request.url.String(), err)
+		span.AddError(err)
+
+		return nil, err
 	}
 
 	metricName := fmt.Sprintf("%s%dXX", metricResponseCode, resp.StatusCode()/100)
 	c.writeMetric(metricName, method, mon.UnitCount, 1.0)
 
+	span.AddMetadata("http.status_code", resp.StatusCode())
+
+	if resp.StatusCode() >= http.StatusInternalServerError {
+		span.AddError(fmt.Errorf("the request to %s failed with status %d", request.url.Host, resp.StatusCode()))
+	}
+
 	response := buildResponse(resp)
 
 	// Only log the duration if we did not get an error.
@@ -217,7 +265,7 @@ func (c *client) do(ctx context.Context, method string, request *Request) (*Resp
 	// so the duration will be very low. If we get back an error (e.g., status 500),
 	// we log the duration as this is just a valid http response.
 	requestDurationMs := float64(resp.Time() / time.Millisecond)
-	c.writeMetric(metricRequestDuration, method, mon.UnitMillisecondsAverage, requestDurationMs)
+	c.writeMetric(metricRequestDuration, method, mon.UnitMillisecondsDistribution, requestDurationMs)
 
 	return response, nil
 }
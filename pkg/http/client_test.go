@@ -4,11 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
 	cfgMocks "github.com/applike/gosoline/pkg/cfg/mocks"
+	"github.com/applike/gosoline/pkg/clock"
+	concMocks "github.com/applike/gosoline/pkg/conc/mocks"
 	"github.com/applike/gosoline/pkg/http"
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	tracingMocks "github.com/applike/gosoline/pkg/tracing/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gopkg.in/resty.v1"
 	netHttp "net/http"
 	"net/http/httptest"
 	"testing"
@@ -50,6 +56,8 @@ func runTestServer(t *testing.T, method string, status int, delay time.Duration,
 func getConfig(retries int, timeout int) *cfgMocks.Config {
 	config := new(cfgMocks.Config)
 	config.On("UnmarshalKey", mock.AnythingOfType("string"), mock.AnythingOfType("*http.Settings"))
+	// the tracer is only created by the first client
+	config.On("UnmarshalKey", "tracing", mock.AnythingOfType("*tracing.TracerSettings")).Maybe()
 	config.On("GetInt", "http_client_retry_count").Return(retries)
 	config.On("GetDuration", "http_client_request_timeout").Return(time.Duration(timeout) * time.Second)
 
@@ -148,3 +156,91 @@ func TestClient_Post(t *testing.T) {
 
 	config.AssertExpectations(t)
 }
+
+func TestClient_WithRateLimiter(t *testing.T) {
+	config := getConfig(1, 1)
+	logger := monMocks.NewLoggerMockedAll()
+
+	runTestServer(t, "GET", 200, 0, func(host string) {
+		limiter := new(concMocks.RateLimiter)
+		limiter.On("Wait", mock.Anything, host).Return(nil).Once()
+		limiter.On("Wait", mock.Anything, host).Return(context.Canceled).Once()
+
+		client := http.NewHttpClient(config, logger, http.WithRateLimiter(limiter))
+		request := client.NewRequest().
+			WithUrl(fmt.Sprintf("http://%s", host))
+
+		response, err := client.Get(context.TODO(), request)
+		assert.NoError(t, err)
+		assert.Equal(t, 200, response.StatusCode)
+
+		response, err = client.Get(context.TODO(), request)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Nil(t, response)
+
+		limiter.AssertExpectations(t)
+	})
+
+	config.AssertExpectations(t)
+}
+
+func TestClient_TraceParent(t *testing.T) {
+	config := getConfig(1, 1)
+	logger := monMocks.NewLoggerMockedAll()
+
+	trace := &tracing.Trace{
+		TraceId: "4bf92f3577b34da6a3ce929d0e0e4736",
+		Id:      "00f067aa0ba902b7",
+		Sampled: true,
+	}
+	span := new(tracingMocks.Span)
+	span.On("GetTrace").Return(trace)
+
+	var traceParent string
+	testServer := httptest.NewServer(netHttp.HandlerFunc(func(res netHttp.ResponseWriter, req *netHttp.Request) {
+		traceParent = req.Header.Get(tracing.HeaderTraceParent)
+	}))
+	defer testServer.Close()
+
+	client := http.NewHttpClient(config, logger)
+	request := client.NewRequest().
+		WithUrl(testServer.URL)
+	_, err := client.Get(tracing.ContextWithSpan(context.Background(), span), request)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", traceParent)
+}
+
+func TestClient_ClientSpan(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracer := tracing.NewOtelTracerWithInterfaces(clock.NewFakeClock(), cfg.AppId{}, &tracing.SamplingConfiguration{
+		Default: tracing.SampleRule{FixedTarget: 100},
+	}, exporter)
+
+	var traceParent string
+	testServer := httptest.NewServer(netHttp.HandlerFunc(func(res netHttp.ResponseWriter, req *netHttp.Request) {
+		traceParent = req.Header.Get(tracing.HeaderTraceParent)
+		res.WriteHeader(netHttp.StatusBadGateway)
+	}))
+	defer testServer.Close()
+
+	client := http.NewHttpClientWithInterfaces(monMocks.NewLoggerMockedAll(), tracer, monMocks.NewMetricWriterMockedAll(), resty.New())
+	request := client.NewRequest().
+		WithUrl(testServer.URL)
+
+	ctx, trans := tracer.StartSpan("test_trans")
+	_, err := client.Get(ctx, request)
+	trans.Finish()
+
+	assert.NoError(t, err)
+
+	spans := exporter.GetSpans()
+
+	if assert.Len(t, spans, 2) {
+		assert.Equal(t, tracing.SpanKindClient, spans[0].Kind)
+		assert.Equal(t, trans.GetId(), spans[0].ParentSpanId)
+		assert.Equal(t, netHttp.StatusBadGateway, spans[0].Attributes["http.status_code"])
+		assert.Equal(t, tracing.SpanStatusError, spans[0].StatusCode)
+		assert.Equal(t, fmt.Sprintf("00-%s-%s-01", spans[0].TraceId, spans[0].SpanId), traceParent, "the client span should be the parent of the called service")
+	}
+}
@@ -289,6 +289,34 @@ func (s *chainKvStore) Put(ctx context.Context, key interface{}, value interface
 	return nil
 }
 
+// PutIfAbsent writes the value to the last element of the chain only if the key doesn't exist there yet and to the
+// other elements afterwards. It is atomic if the last element supports it, like the redis and ddb stores do. As the
+// other elements are not checked, they can still contain an outdated value of the key.
+func (s *chainKvStore) PutIfAbsent(ctx context.Context, key interface{}, value interface{}) (bool, error) {
+	lastElementIndex := len(s.chain) - 1
+	written, err := PutIfAbsent(ctx, s.chain[lastElementIndex], key, value)
+
+	if err != nil {
+		return false, fmt.Errorf("could not put %s to kvstore %T: %w", key, s.chain[lastElementIndex], err)
+	}
+
+	if !written {
+		return false, nil
+	}
+
+	for i := 0; i < lastElementIndex; i++ {
+		if err := s.chain[i].Put(ctx, key, value); err != nil {
+			s.logger.WithContext(ctx).Warnf("could not put %s to kvstore %T: %s", key, s.chain[i], err.Error())
+		}
+	}
+
+	if err := s.missingCache.Delete(ctx, key); err != nil {
+		s.logger.WithContext(ctx).Warnf("could not erase cached empty value for key %s: %s", key, err.Error())
+	}
+
+	return true, nil
+}
+
 func (s *chainKvStore) PutBatch(ctx context.Context, values interface{}) error {
 	mii, err := refl.InterfaceToMapInterfaceInterface(values)
 
@@ -370,6 +370,65 @@ func TestChainKvStore_Put(t *testing.T) {
 	element1.AssertExpectations(t)
 }
 
+func TestChainKvStore_PutIfAbsent(t *testing.T) {
+	ctx := context.Background()
+	item := Item{
+		Id:   "foo",
+		Body: "bar",
+	}
+
+	logger := monMocks.NewLoggerMockedAll()
+	element0 := new(kvStoreMocks.KvStore)
+	element1 := new(kvStoreMocks.PutIfAbsentStore)
+
+	store := kvstore.NewChainKvStoreWithInterfaces(logger, nilFactory, kvstore.NewEmptyKvStore(), &kvstore.Settings{})
+	store.AddStore(element0)
+	store.AddStore(element1)
+
+	element1.On("PutIfAbsent", ctx, "foo", item).Return(true, nil).Once()
+	element0.On("Put", ctx, "foo", item).Return(nil).Once()
+
+	written, err := kvstore.PutIfAbsent(ctx, store, "foo", item)
+	assert.NoError(t, err)
+	assert.True(t, written)
+
+	element1.On("PutIfAbsent", ctx, "foo", item).Return(false, nil).Once()
+
+	written, err = kvstore.PutIfAbsent(ctx, store, "foo", item)
+	assert.NoError(t, err)
+	assert.False(t, written, "the other elements are not written if the key exists")
+
+	element0.AssertExpectations(t)
+	element1.AssertExpectations(t)
+}
+
+func TestChainKvStore_PutIfAbsent_NotAtomic(t *testing.T) {
+	ctx := context.Background()
+	item := Item{
+		Id:   "foo",
+		Body: "bar",
+	}
+
+	store, element0, element1 := buildTestableChainStore(false)
+
+	element1.On("Contains", ctx, "foo").Return(false, nil).Once()
+	element1.On("Put", ctx, "foo", item).Return(nil).Once()
+	element0.On("Put", ctx, "foo", item).Return(nil).Once()
+
+	written, err := kvstore.PutIfAbsent(ctx, store, "foo", item)
+	assert.NoError(t, err)
+	assert.True(t, written)
+
+	element1.On("Contains", ctx, "foo").Return(true, nil).Once()
+
+	written, err = kvstore.PutIfAbsent(ctx, store, "foo", item)
+	assert.NoError(t, err)
+	assert.False(t, written)
+
+	element0.AssertExpectations(t)
+	element1.AssertExpectations(t)
+}
+
 func TestChainKvStore_PutBatch(t *testing.T) {
 	ctx := context.Background()
 	items := map[string]Item{
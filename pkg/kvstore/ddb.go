@@ -196,6 +196,34 @@ func (s *ddbKvStore) Put(ctx context.Context, key interface{}, value interface{}
 	return nil
 }
 
+func (s *ddbKvStore) PutIfAbsent(ctx context.Context, key interface{}, value interface{}) (bool, error) {
+	keyStr, err := CastKeyToString(key)
+
+	if err != nil {
+		return false, fmt.Errorf("can not cast key %T %v to string: %w", key, key, err)
+	}
+
+	bytes, err := Marshal(value)
+
+	if err != nil {
+		return false, fmt.Errorf("can not marshal value %s: %w", keyStr, err)
+	}
+
+	item := &DdbItem{
+		Key:   keyStr,
+		Value: string(bytes),
+	}
+
+	pb := s.repository.PutItemBuilder().WithCondition(ddb.AttributeNotExists("key"))
+	res, err := s.repository.PutItem(ctx, pb, item)
+
+	if err != nil {
+		return false, fmt.Errorf("can not put item %s into ddb store: %w", keyStr, err)
+	}
+
+	return !res.ConditionalCheckFailed, nil
+}
+
 func (s *ddbKvStore) PutBatch(ctx context.Context, values interface{}) error {
 	mii, err := refl.InterfaceToMapInterfaceInterface(values)
 
@@ -204,6 +204,40 @@ func TestDdbKvStore_Put(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+func TestDdbKvStore_PutIfAbsent(t *testing.T) {
+	store, repo := buildTestableDdbStore()
+
+	builder := new(ddbMocks.PutItemBuilder)
+	builder.On("WithCondition", ddb.AttributeNotExists("key")).Return(builder)
+
+	ddbItem := &kvstore.DdbItem{
+		Key:   "foo",
+		Value: `{"id":"foo","body":"bar"}`,
+	}
+
+	repo.On("PutItemBuilder").Return(builder)
+	repo.On("PutItem", mock.Anything, builder, ddbItem).Return(&ddb.PutItemResult{}, nil).Once()
+	repo.On("PutItem", mock.Anything, builder, ddbItem).Return(&ddb.PutItemResult{
+		ConditionalCheckFailed: true,
+	}, nil).Once()
+
+	item := &Item{
+		Id:   "foo",
+		Body: "bar",
+	}
+
+	written, err := kvstore.PutIfAbsent(context.Background(), store, "foo", item)
+	assert.NoError(t, err)
+	assert.True(t, written)
+
+	written, err = kvstore.PutIfAbsent(context.Background(), store, "foo", item)
+	assert.NoError(t, err)
+	assert.False(t, written, "the item exists already")
+
+	builder.AssertExpectations(t)
+	repo.AssertExpectations(t)
+}
+
 func TestDdbKvStore_PutBatch(t *testing.T) {
 	store, repo := buildTestableDdbStore()
 
@@ -58,6 +58,40 @@ type SizedStore interface {
 	EstimateSize() *int64
 }
 
+//go:generate mockery -name PutIfAbsentStore
+type PutIfAbsentStore interface {
+	KvStore
+	// Write a value to the store only if the key doesn't exist yet. The check
+	// and the write are done atomically. Returns false if the key exists, the
+	// value in the store is not modified then.
+	PutIfAbsent(ctx context.Context, key interface{}, value interface{}) (bool, error)
+}
+
+// PutIfAbsent writes the value only if the key doesn't exist in the store yet and returns true if the value
+// was written. This is atomic for stores implementing PutIfAbsentStore, like the redis and ddb stores. Other
+// stores are checked first and written afterwards, so a concurrent write of the same key can be overwritten.
+func PutIfAbsent(ctx context.Context, store KvStore, key interface{}, value interface{}) (bool, error) {
+	if conditionalStore, ok := store.(PutIfAbsentStore); ok {
+		return conditionalStore.PutIfAbsent(ctx, key, value)
+	}
+
+	exists, err := store.Contains(ctx, key)
+
+	if err != nil {
+		return false, err
+	}
+
+	if exists {
+		return false, nil
+	}
+
+	if err := store.Put(ctx, key, value); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 type Factory func(config cfg.Config, logger mon.Logger, settings *Settings) (KvStore, error)
 
 func buildFactory(config cfg.Config, logger mon.Logger) func(factory Factory, settings *Settings) (KvStore, error) {
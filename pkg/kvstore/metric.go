@@ -55,9 +55,32 @@ func NewMetricStoreWithInterfaces(store KvStore, settings *Settings) KvStore {
 		go s.recordSize(sizedStore)
 	}
 
+	if conditionalStore, ok := store.(PutIfAbsentStore); ok {
+		return &PutIfAbsentMetricStore{
+			MetricStore: s,
+			store:       conditionalStore,
+		}
+	}
+
 	return s
 }
 
+// PutIfAbsentMetricStore keeps the PutIfAbsent of the wrapped store available
+type PutIfAbsentMetricStore struct {
+	*MetricStore
+	store PutIfAbsentStore
+}
+
+func (s *PutIfAbsentMetricStore) PutIfAbsent(ctx context.Context, key interface{}, value interface{}) (bool, error) {
+	written, err := s.store.PutIfAbsent(ctx, key, value)
+
+	if written && err == nil {
+		s.recordWrites(1)
+	}
+
+	return written, err
+}
+
 func (s *MetricStore) Contains(ctx context.Context, key interface{}) (bool, error) {
 	s.recordReads(1)
 
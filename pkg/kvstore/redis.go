@@ -152,6 +152,28 @@ func (s *redisKvStore) Put(_ context.Context, key interface{}, value interface{}
 	return nil
 }
 
+func (s *redisKvStore) PutIfAbsent(_ context.Context, key interface{}, value interface{}) (bool, error) {
+	bytes, err := Marshal(value)
+
+	if err != nil {
+		return false, fmt.Errorf("can not marshal value %T %v: %w", value, value, err)
+	}
+
+	keyStr, err := s.key(key)
+
+	if err != nil {
+		return false, fmt.Errorf("can not get key to write value to redis: %w", err)
+	}
+
+	written, err := s.client.SetNX(keyStr, bytes, s.settings.Ttl)
+
+	if err != nil {
+		return false, fmt.Errorf("can not set value in redis store: %w", err)
+	}
+
+	return written, nil
+}
+
 func (s *redisKvStore) PutBatch(ctx context.Context, values interface{}) error {
 	mii, err := refl.InterfaceToMapInterfaceInterface(values)
 
@@ -87,6 +87,22 @@ func TestRedisKvStore_Put(t *testing.T) {
 	client.AssertExpectations(t)
 }
 
+func TestRedisKvStore_PutIfAbsent(t *testing.T) {
+	store, client := buildTestableRedisStore()
+	client.On("SetNX", "applike-gosoline-kvstore-kvstore-test-foo", []byte(`{"id":"foo","body":"bar"}`), time.Duration(0)).Return(true, nil).Once()
+	client.On("SetNX", "applike-gosoline-kvstore-kvstore-test-foo", []byte(`{"id":"foo","body":"baz"}`), time.Duration(0)).Return(false, nil).Once()
+
+	written, err := kvstore.PutIfAbsent(context.Background(), store, "foo", &Item{Id: "foo", Body: "bar"})
+	assert.NoError(t, err)
+	assert.True(t, written)
+
+	written, err = kvstore.PutIfAbsent(context.Background(), store, "foo", &Item{Id: "foo", Body: "baz"})
+	assert.NoError(t, err)
+	assert.False(t, written)
+
+	client.AssertExpectations(t)
+}
+
 func TestRedisKvStore_PutBatch(t *testing.T) {
 	store, client := buildTestableRedisStore()
 	client.On("Set", "applike-gosoline-kvstore-kvstore-test-foo", []byte(`{"id":"foo","body":"bar"}`), time.Duration(0)).Return(nil)
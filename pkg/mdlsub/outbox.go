@@ -0,0 +1,69 @@
+package mdlsub
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/stream"
+)
+
+type outboxPublisherSender struct {
+	publisher Publisher
+}
+
+// NewOutboxRelay creates a module relaying the messages of the outbox with the publisher configured at outbox.<name>
+func NewOutboxRelay(name string) kernel.ModuleFactory {
+	return func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+		settings := db_repo.ReadOutboxRelaySettings(config, name)
+
+		if settings.Publisher == "" {
+			return nil, fmt.Errorf("there is no publisher configured for the outbox relay %s", name)
+		}
+
+		publisher, err := NewPublisher(config, logger, settings.Publisher)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not create publisher %s for the outbox relay %s: %w", settings.Publisher, name, err)
+		}
+
+		// the publisher sets its own model id, so it must only relay the messages of its model
+		if settings.ModelId == "" {
+			settings.ModelId = publisher.settings.ModelId.String()
+		}
+
+		return db_repo.NewOutboxRelayWithSender(config, logger, NewOutboxPublisherSender(publisher), settings)
+	}
+}
+
+func NewOutboxPublisherSender(publisher Publisher) db_repo.OutboxSender {
+	return &outboxPublisherSender{
+		publisher: publisher,
+	}
+}
+
+// Send publishes the already encoded body of the outbox message. The attributes set by the publisher itself
+// are dropped, all others are passed on as custom attributes.
+func (s *outboxPublisherSender) Send(ctx context.Context, msg *db_repo.OutboxMessage) error {
+	streamMessage, err := msg.StreamMessage()
+
+	if err != nil {
+		return err
+	}
+
+	attributes := make(map[string]interface{})
+
+	for key, value := range streamMessage.Attributes {
+		switch key {
+		case AttributeModelId, AttributeType, AttributeVersion, stream.AttributeEncoding, stream.AttributeCompression:
+			continue
+		}
+
+		attributes[key] = value
+	}
+
+	return s.publisher.Publish(ctx, msg.Type, msg.Version, json.RawMessage(streamMessage.Body), attributes)
+}
@@ -0,0 +1,33 @@
+package mdlsub_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/mdlsub"
+	"github.com/applike/gosoline/pkg/mdlsub/mocks"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOutboxPublisherSender_Send(t *testing.T) {
+	ctx := context.Background()
+	publisher := new(mocks.Publisher)
+	publisher.On("Publish", ctx, "update", 2, json.RawMessage(`{"id":1}`), map[string]interface{}{
+		"tenant": "acme",
+	}).Return(nil).Once()
+
+	sender := mdlsub.NewOutboxPublisherSender(publisher)
+	err := sender.Send(ctx, &db_repo.OutboxMessage{
+		Id:         mdl.Uint(1),
+		ModelId:    "gosoline.test.app.event",
+		Type:       "update",
+		Version:    2,
+		Attributes: `{"encoding":"application/json","modelId":"gosoline.test.app.event","tenant":"acme","type":"update","version":2}`,
+		Body:       `{"id":1}`,
+	})
+
+	assert.NoError(t, err)
+	publisher.AssertExpectations(t)
+}
@@ -65,6 +65,11 @@ var formatters = map[string]formatter{
 	FormatJson:       formatterJson,
 }
 
+type loggerOutput struct {
+	level  int
+	output LogOutput
+}
+
 type GosoLog interface {
 	Logger
 	Option(options ...LoggerOption) error
@@ -97,6 +102,8 @@ type logger struct {
 	outputLck   *sync.Mutex
 	ctxResolver []ContextFieldsResolver
 	hooks       []LoggerHook
+	outputs     []loggerOutput
+	channels    *ChannelLevelRegistry
 
 	level           int
 	format          string
@@ -116,6 +123,7 @@ func NewLoggerWithInterfaces(clock clockwork.Clock, out io.Writer) *logger {
 		outputLck:       &sync.Mutex{},
 		ctxResolver:     make([]ContextFieldsResolver, 0),
 		hooks:           make([]LoggerHook, 0),
+		outputs:         make([]loggerOutput, 0),
 		level:           levelPriority(Info),
 		format:          FormatConsole,
 		timestampFormat: "15:04:05.000",
@@ -137,6 +145,8 @@ func (l *logger) copy() *logger {
 		output:          l.output,
 		ctxResolver:     l.ctxResolver,
 		hooks:           l.hooks,
+		outputs:         l.outputs,
+		channels:        l.channels,
 		level:           l.level,
 		format:          l.format,
 		timestampFormat: l.timestampFormat,
@@ -193,7 +203,7 @@ func (l *logger) Infof(msg string, args ...interface{}) {
 }
 
 func (l *logger) Debug(args ...interface{}) {
-	if l.level > levels[Debug] {
+	if l.minLevel(l.effectiveLevel()) > levels[Debug] {
 		return
 	}
 
@@ -201,7 +211,7 @@ func (l *logger) Debug(args ...interface{}) {
 }
 
 func (l *logger) Debugf(msg string, args ...interface{}) {
-	if l.level > levels[Debug] {
+	if l.minLevel(l.effectiveLevel()) > levels[Debug] {
 		return
 	}
 
@@ -252,28 +262,79 @@ func (l *logger) logError(level string, err error, msg string) {
 
 func (l *logger) log(level string, msg string, logErr error, fields Fields) {
 	levelNo := levels[level]
+	effectiveLevel := l.effectiveLevel()
 
-	if levelNo < l.level {
+	if levelNo < l.minLevel(effectiveLevel) {
 		return
 	}
 
+	now := l.clock.Now()
 	cpyData := l.data
 	cpyData.Fields = mergeMapStringInterface(cpyData.Fields, fields)
 
-	for _, h := range l.hooks {
-		if err := h.Fire(level, msg, logErr, &cpyData); err != nil {
-			l.err(err)
+	if levelNo >= effectiveLevel {
+		for _, h := range l.hooks {
+			if err := h.Fire(level, msg, logErr, &cpyData); err != nil {
+				l.err(err)
+			}
 		}
+
+		timestamp := now.Format(l.timestampFormat)
+		buffer, err := formatters[l.format](timestamp, level, msg, logErr, &cpyData)
+
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+		}
+
+		l.write(buffer)
 	}
 
-	timestamp := l.clock.Now().Format(l.timestampFormat)
-	buffer, err := formatters[l.format](timestamp, level, msg, logErr, &cpyData)
+	l.writeOutputs(levelNo, &LogEntry{
+		Time:    now,
+		Level:   level,
+		Message: msg,
+		Err:     logErr,
+		Data:    cpyData,
+	})
+}
 
-	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "Failed to write to log, %v\n", err)
+// writeOutputs hands the entry to all additional outputs with a minimum level of at most the one of the entry. The
+// outputs share the entry and must not modify it.
+func (l *logger) writeOutputs(levelNo int, entry *LogEntry) {
+	for _, o := range l.outputs {
+		if levelNo >= o.level {
+			o.output.Write(entry)
+		}
 	}
+}
 
-	l.write(buffer)
+// effectiveLevel is the level of the logger output, it is overridden by the level of the channel and lowered to debug
+// for debug contexts. The additional outputs keep their own levels.
+func (l *logger) effectiveLevel() int {
+	level := l.level
+
+	if l.channels != nil {
+		if channelLevel, ok := l.channels.get(l.data.Channel); ok {
+			level = channelLevel
+		}
+	}
+
+	if level > levels[Debug] && IsDebugContext(l.data.Context) {
+		level = levels[Debug]
+	}
+
+	return level
+}
+
+// minLevel is the lowest level which is written by the logger output with the given level or one of the additional outputs
+func (l *logger) minLevel(level int) int {
+	for _, o := range l.outputs {
+		if o.level < level {
+			level = o.level
+		}
+	}
+
+	return level
 }
 
 func (l *logger) err(err error) {
@@ -0,0 +1,200 @@
+package mon
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"github.com/jonboulle/clockwork"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type ChannelLevel struct {
+	Level     string     `json:"level"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+type channelLevelRequest struct {
+	Channel string `json:"channel"`
+	Level   string `json:"level"`
+	Ttl     string `json:"ttl"`
+}
+
+// ChannelLevelRegistry keeps the levels which override the level of the logger for single channels. An override with
+// a ttl is reverted automatically after it expired.
+type ChannelLevelRegistry struct {
+	lck    sync.RWMutex
+	clock  clockwork.Clock
+	levels map[string]ChannelLevel
+}
+
+var channelLevelRegistry = struct {
+	sync.Mutex
+	instance *ChannelLevelRegistry
+}{}
+
+func ProvideChannelLevelRegistry() *ChannelLevelRegistry {
+	channelLevelRegistry.Lock()
+	defer channelLevelRegistry.Unlock()
+
+	if channelLevelRegistry.instance == nil {
+		channelLevelRegistry.instance = NewChannelLevelRegistryWithInterfaces(clockwork.NewRealClock())
+	}
+
+	return channelLevelRegistry.instance
+}
+
+func NewChannelLevelRegistryWithInterfaces(clock clockwork.Clock) *ChannelLevelRegistry {
+	return &ChannelLevelRegistry{
+		clock:  clock,
+		levels: make(map[string]ChannelLevel),
+	}
+}
+
+// Set overrides the level of the channel, a ttl of 0 keeps the override until it is reset
+func (r *ChannelLevelRegistry) Set(channel string, level string, ttl time.Duration) error {
+	if _, ok := levels[level]; !ok {
+		return fmt.Errorf("unknown level %s for channel %s", level, channel)
+	}
+
+	if ttl < 0 {
+		return fmt.Errorf("the ttl of the level for channel %s can not be negative", channel)
+	}
+
+	override := ChannelLevel{
+		Level: level,
+	}
+
+	if ttl > 0 {
+		expiresAt := r.clock.Now().Add(ttl)
+		override.ExpiresAt = &expiresAt
+	}
+
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	r.levels[channel] = override
+
+	return nil
+}
+
+func (r *ChannelLevelRegistry) Reset(channel string) {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	delete(r.levels, channel)
+}
+
+// All returns the overrides which are not expired yet
+func (r *ChannelLevelRegistry) All() map[string]ChannelLevel {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	now := r.clock.Now()
+	all := make(map[string]ChannelLevel, len(r.levels))
+
+	for channel, override := range r.levels {
+		if override.expired(now) {
+			delete(r.levels, channel)
+			continue
+		}
+
+		all[channel] = override
+	}
+
+	return all
+}
+
+func (r *ChannelLevelRegistry) get(channel string) (int, bool) {
+	r.lck.RLock()
+	override, ok := r.levels[channel]
+	r.lck.RUnlock()
+
+	if !ok || override.expired(r.clock.Now()) {
+		return 0, false
+	}
+
+	return levels[override.Level], true
+}
+
+// ReadOnlyHandler only lists the overrides on GET and rejects the requests changing them with 403 Forbidden
+func (r *ChannelLevelRegistry) ReadOnlyHandler() http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			http.Error(writer, "changing the channel levels is disabled", http.StatusForbidden)
+			return
+		}
+
+		r.ServeHTTP(writer, request)
+	})
+}
+
+// ServeHTTP lists the overrides on GET, sets the override of a channel on PUT with a json body like
+// {"channel": "kvstore", "level": "debug", "ttl": "10m"} and resets it on DELETE with the channel as query parameter.
+func (r *ChannelLevelRegistry) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	switch request.Method {
+	case http.MethodGet:
+
+	case http.MethodPut:
+		body := &channelLevelRequest{}
+		ttl := time.Duration(0)
+
+		bytes, err := ioutil.ReadAll(request.Body)
+
+		if err == nil {
+			err = json.Unmarshal(bytes, body)
+		}
+
+		if err != nil {
+			http.Error(writer, fmt.Sprintf("can not decode the request body: %s", err.Error()), http.StatusBadRequest)
+			return
+		}
+
+		if body.Ttl != "" {
+			if ttl, err = time.ParseDuration(body.Ttl); err != nil {
+				http.Error(writer, fmt.Sprintf("can not parse the ttl %s: %s", body.Ttl, err.Error()), http.StatusBadRequest)
+				return
+			}
+		}
+
+		if body.Channel == "" {
+			http.Error(writer, "the channel is missing", http.StatusBadRequest)
+			return
+		}
+
+		if err := r.Set(body.Channel, body.Level, ttl); err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+	case http.MethodDelete:
+		channel := request.URL.Query().Get("channel")
+
+		if channel == "" {
+			http.Error(writer, "the channel is missing", http.StatusBadRequest)
+			return
+		}
+
+		r.Reset(channel)
+
+	default:
+		writer.Header().Set("Allow", "GET, PUT, DELETE")
+		http.Error(writer, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := json.Marshal(r.All())
+
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	_, _ = writer.Write(body)
+}
+
+func (l ChannelLevel) expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !now.Before(*l.ExpiresAt)
+}
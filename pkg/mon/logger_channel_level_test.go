@@ -0,0 +1,113 @@
+package mon_test
+
+import (
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChannelLevelRegistry_Set(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC))
+	registry := mon.NewChannelLevelRegistryWithInterfaces(clock)
+
+	err := registry.Set("kvstore", mon.Debug, time.Minute)
+	assert.NoError(t, err)
+
+	err = registry.Set("ddb", "verbose", 0)
+	assert.EqualError(t, err, "unknown level verbose for channel ddb")
+
+	expiresAt := time.Date(2020, 1, 1, 12, 1, 0, 0, time.UTC)
+	assert.Equal(t, map[string]mon.ChannelLevel{
+		"kvstore": {Level: mon.Debug, ExpiresAt: &expiresAt},
+	}, registry.All())
+
+	clock.Advance(time.Minute)
+	assert.Empty(t, registry.All())
+}
+
+func TestChannelLevelRegistry_ServeHTTP(t *testing.T) {
+	clock := clockwork.NewFakeClockAt(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC))
+	registry := mon.NewChannelLevelRegistryWithInterfaces(clock)
+
+	response := serveChannelLevels(registry, http.MethodPut, "/log-levels", `{"channel":"kvstore","level":"debug","ttl":"10m"}`)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"kvstore":{"level":"debug","expires_at":"2020-01-01T12:10:00Z"}}`, response.Body.String())
+
+	response = serveChannelLevels(registry, http.MethodPut, "/log-levels", `{"channel":"consumer","level":"warn"}`)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	response = serveChannelLevels(registry, http.MethodGet, "/log-levels", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Equal(t, "application/json", response.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"kvstore":{"level":"debug","expires_at":"2020-01-01T12:10:00Z"},"consumer":{"level":"warn"}}`, response.Body.String())
+
+	response = serveChannelLevels(registry, http.MethodDelete, "/log-levels?channel=kvstore", "")
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"consumer":{"level":"warn"}}`, response.Body.String())
+}
+
+func TestChannelLevelRegistry_ServeHTTP_BadRequest(t *testing.T) {
+	registry := mon.NewChannelLevelRegistryWithInterfaces(clockwork.NewFakeClock())
+
+	tests := map[string]struct {
+		method string
+		target string
+		body   string
+		status int
+	}{
+		"broken body":    {http.MethodPut, "/log-levels", `{`, http.StatusBadRequest},
+		"broken ttl":     {http.MethodPut, "/log-levels", `{"channel":"kvstore","level":"debug","ttl":"soon"}`, http.StatusBadRequest},
+		"no channel":     {http.MethodPut, "/log-levels", `{"level":"debug"}`, http.StatusBadRequest},
+		"unknown level":  {http.MethodPut, "/log-levels", `{"channel":"kvstore","level":"verbose"}`, http.StatusBadRequest},
+		"delete nothing": {http.MethodDelete, "/log-levels", "", http.StatusBadRequest},
+		"post":           {http.MethodPost, "/log-levels", "", http.StatusMethodNotAllowed},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			response := serveChannelLevels(registry, test.method, test.target, test.body)
+			assert.Equal(t, test.status, response.Code)
+		})
+	}
+
+	assert.Empty(t, registry.All())
+}
+
+func TestChannelLevelRegistry_ReadOnlyHandler(t *testing.T) {
+	registry := mon.NewChannelLevelRegistryWithInterfaces(clockwork.NewFakeClock())
+	err := registry.Set("kvstore", mon.Debug, 0)
+	assert.NoError(t, err)
+
+	handler := registry.ReadOnlyHandler()
+
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/log-levels", nil))
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"kvstore":{"level":"debug"}}`, response.Body.String())
+
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodPut, "/log-levels", strings.NewReader(`{"channel":"kvstore","level":"error"}`)))
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	response = httptest.NewRecorder()
+	handler.ServeHTTP(response, httptest.NewRequest(http.MethodDelete, "/log-levels?channel=kvstore", nil))
+	assert.Equal(t, http.StatusForbidden, response.Code)
+
+	assert.Equal(t, map[string]mon.ChannelLevel{
+		"kvstore": {Level: mon.Debug},
+	}, registry.All())
+}
+
+func serveChannelLevels(registry *mon.ChannelLevelRegistry, method string, target string, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	response := httptest.NewRecorder()
+
+	registry.ServeHTTP(response, request)
+
+	return response
+}
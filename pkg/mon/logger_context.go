@@ -4,7 +4,10 @@ import "context"
 
 type key int
 
-const contextFieldsKey key = 0
+const (
+	contextFieldsKey key = 0
+	contextDebugKey  key = 1
+)
 
 type ContextFieldsResolver func(ctx context.Context) map[string]interface{}
 
@@ -37,6 +40,22 @@ func AppendLoggerContextField(ctx context.Context, fields map[string]interface{}
 	return NewLoggerContext(ctx, newFields)
 }
 
+// WithDebugContext marks the context to log debug messages regardless of the level of the logger and the channel
+func WithDebugContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextDebugKey, true)
+}
+
+// IsDebugContext returns true if debug messages should be logged for the context
+func IsDebugContext(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+
+	debug, ok := ctx.Value(contextDebugKey).(bool)
+
+	return ok && debug
+}
+
 // ContextLoggerFieldsResolver extracts the ContextFields from ctx, if not present returns empty ContextFields
 func ContextLoggerFieldsResolver(ctx context.Context) map[string]interface{} {
 	contextFields, ok := ctx.Value(contextFieldsKey).(map[string]interface{})
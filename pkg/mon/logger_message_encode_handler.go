@@ -8,7 +8,10 @@ import (
 	"time"
 )
 
-const MessageAttributeLoggerContext = "logger:context"
+const (
+	MessageAttributeLoggerContext = "logger:context"
+	MessageAttributeLoggerDebug   = "logger:debug"
+)
 
 type MessageWithLoggingFieldsEncoder struct {
 	logger Logger
@@ -27,6 +30,10 @@ func NewMessageWithLoggingFieldsEncoderWithInterfaces(logger Logger) *MessageWit
 }
 
 func (m MessageWithLoggingFieldsEncoder) Encode(ctx context.Context, _ interface{}, attributes map[string]interface{}) (context.Context, map[string]interface{}, error) {
+	if IsDebugContext(ctx) {
+		attributes[MessageAttributeLoggerDebug] = "true"
+	}
+
 	fields := ContextLoggerFieldsResolver(ctx)
 
 	if len(fields) == 0 {
@@ -59,6 +66,8 @@ func (m MessageWithLoggingFieldsEncoder) Decode(ctx context.Context, _ interface
 	var str string
 	var ok bool
 
+	ctx = m.decodeDebug(ctx, attributes)
+
 	if _, ok = attributes[MessageAttributeLoggerContext]; !ok {
 		return ctx, attributes, nil
 	}
@@ -81,3 +90,25 @@ func (m MessageWithLoggingFieldsEncoder) Decode(ctx context.Context, _ interface
 
 	return ctx, attributes, nil
 }
+
+func (m MessageWithLoggingFieldsEncoder) decodeDebug(ctx context.Context, attributes map[string]interface{}) context.Context {
+	value, ok := attributes[MessageAttributeLoggerDebug]
+
+	if !ok {
+		return ctx
+	}
+
+	delete(attributes, MessageAttributeLoggerDebug)
+	debug, err := cast.ToBoolE(value)
+
+	if err != nil {
+		m.logger.Warnf("can not decode the logger debug flag of type %T during message decoding", value)
+		return ctx
+	}
+
+	if debug {
+		ctx = WithDebugContext(ctx)
+	}
+
+	return ctx
+}
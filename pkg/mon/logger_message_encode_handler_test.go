@@ -107,6 +107,47 @@ func (s *LoggerMessageEncodeHandlerTestSuite) TestDecodeSuccess() {
 	s.Equal(1.0, fields["fieldB"])
 }
 
+func (s *LoggerMessageEncodeHandlerTestSuite) TestEncodeDebug() {
+	ctx := mon.WithDebugContext(context.Background())
+	attributes := make(map[string]interface{})
+
+	_, attributes, err := s.encoder.Encode(ctx, nil, attributes)
+
+	s.NoError(err)
+	s.Equal(map[string]interface{}{
+		mon.MessageAttributeLoggerDebug: "true",
+	}, attributes)
+}
+
+func (s *LoggerMessageEncodeHandlerTestSuite) TestDecodeDebug() {
+	ctx := context.Background()
+	attributes := map[string]interface{}{
+		mon.MessageAttributeLoggerDebug: "true",
+	}
+
+	ctx, attributes, err := s.encoder.Decode(ctx, nil, attributes)
+
+	s.NoError(err)
+	s.Empty(attributes)
+	s.True(mon.IsDebugContext(ctx))
+}
+
+func (s *LoggerMessageEncodeHandlerTestSuite) TestDecodeDebugTypeError() {
+	s.logger.On("Warnf", "can not decode the logger debug flag of type %T during message decoding", "yes please")
+
+	ctx := context.Background()
+	attributes := map[string]interface{}{
+		mon.MessageAttributeLoggerDebug: "yes please",
+	}
+
+	ctx, attributes, err := s.encoder.Decode(ctx, nil, attributes)
+
+	s.NoError(err)
+	s.Empty(attributes)
+	s.False(mon.IsDebugContext(ctx))
+	s.logger.AssertExpectations(s.T())
+}
+
 func TestLoggerMessageEncodeHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(LoggerMessageEncodeHandlerTestSuite))
 }
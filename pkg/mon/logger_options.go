@@ -7,6 +7,15 @@ import (
 
 type LoggerOption func(logger *logger) error
 
+// WithChannelLevels lets the levels of the registry override the level of the logger for their channels
+func WithChannelLevels(registry *ChannelLevelRegistry) LoggerOption {
+	return func(logger *logger) error {
+		logger.channels = registry
+
+		return nil
+	}
+}
+
 func WithContextFieldsResolver(resolver ...ContextFieldsResolver) LoggerOption {
 	return func(logger *logger) error {
 		logger.ctxResolver = append(logger.ctxResolver, resolver...)
@@ -43,6 +52,23 @@ func WithLevel(level string) LoggerOption {
 	}
 }
 
+// WithLogOutput adds an output which receives all entries of at least the given level in addition to the output of
+// the logger. The level of the logger doesn't apply to the additional outputs.
+func WithLogOutput(output LogOutput, level string) LoggerOption {
+	return func(logger *logger) error {
+		if _, ok := levels[level]; !ok {
+			return fmt.Errorf("unknown level of log output: %s", level)
+		}
+
+		logger.outputs = append(logger.outputs, loggerOutput{
+			level:  levelPriority(level),
+			output: output,
+		})
+
+		return nil
+	}
+}
+
 func WithOutput(output io.Writer) LoggerOption {
 	return func(logger *logger) error {
 		logger.output = output
@@ -0,0 +1,333 @@
+package mon
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kernel/common"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	LogOutputTypeEs   = "es"
+	LogOutputTypeFile = "file"
+	LogOutputTypeLoki = "loki"
+
+	// DropPolicyNewest discards the entry which doesn't fit into the full buffer anymore
+	DropPolicyNewest = "drop_newest"
+	// DropPolicyOldest discards the oldest buffered entry to make room for the new one
+	DropPolicyOldest = "drop_oldest"
+	// DropPolicyBlock blocks the logging call until the buffer has room again or the output is closed
+	DropPolicyBlock = "block"
+
+	metricNameLogOutputDropped = "LogOutputDropped"
+)
+
+// LogEntry is a single log message as it is handed to the additional outputs of the logger
+type LogEntry struct {
+	Time    time.Time
+	Level   string
+	Message string
+	Err     error
+	Data    Metadata
+}
+
+//go:generate mockery -name LogOutput
+type LogOutput interface {
+	Write(entry *LogEntry)
+}
+
+//go:generate mockery -name LogSink
+type LogSink interface {
+	Write(batch []*LogEntry) error
+	Close() error
+}
+
+type LogOutputSettings struct {
+	Type          string                `cfg:"type" validate:"required"`
+	Level         string                `cfg:"level" default:"info"`
+	BufferSize    int                   `cfg:"buffer_size" default:"10000"`
+	DropPolicy    string                `cfg:"drop_policy" default:"drop_newest"`
+	BatchSize     int                   `cfg:"batch_size" default:"500"`
+	FlushInterval time.Duration         `cfg:"flush_interval" default:"1s"`
+	Es            LogOutputEsSettings   `cfg:"es"`
+	File          LogOutputFileSettings `cfg:"file"`
+	Loki          LogOutputLokiSettings `cfg:"loki"`
+}
+
+func ReadLogOutputSettings(config cfg.Config, name string) *LogOutputSettings {
+	key := fmt.Sprintf("mon.logger.output.%s", name)
+
+	settings := &LogOutputSettings{}
+	config.UnmarshalKey(key, settings)
+
+	return settings
+}
+
+type logSinkFactory func(config cfg.Config, logger Logger, settings *LogOutputSettings) (LogSink, error)
+
+var logSinkFactories = map[string]logSinkFactory{
+	LogOutputTypeEs:   NewLogEsSink,
+	LogOutputTypeFile: NewLogFileSink,
+	LogOutputTypeLoki: NewLogLokiSink,
+}
+
+// NewLogOutput creates the configured sink of the output and buffers the entries for it
+func NewLogOutput(config cfg.Config, logger Logger, name string, settings *LogOutputSettings) (*AsyncLogOutput, error) {
+	factory, ok := logSinkFactories[settings.Type]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown type %s of log output %s", settings.Type, name)
+	}
+
+	if _, ok := levels[settings.Level]; !ok {
+		return nil, fmt.Errorf("unknown level %s of log output %s", settings.Level, name)
+	}
+
+	sink, err := factory(config, logger, settings)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create sink of log output %s: %w", name, err)
+	}
+
+	metricWriter := NewMetricDaemonWriter(&MetricDatum{
+		Priority:   PriorityHigh,
+		MetricName: metricNameLogOutputDropped,
+		Dimensions: map[string]string{
+			"Output": name,
+		},
+		Unit:  UnitCount,
+		Value: 0.0,
+	})
+
+	return NewAsyncLogOutputWithInterfaces(metricWriter, sink, name, settings)
+}
+
+// AsyncLogOutput buffers the entries in a bounded buffer and writes them in batches to the sink. If the buffer is
+// full, entries are dropped according to the drop policy and the number of dropped entries is written as metric.
+type AsyncLogOutput struct {
+	name         string
+	metricWriter MetricWriter
+	sink         LogSink
+	settings     *LogOutputSettings
+
+	lck     sync.RWMutex
+	closed  bool
+	closing chan struct{}
+	blocked sync.WaitGroup
+	buffer  chan *LogEntry
+	dropped int64
+	done    chan struct{}
+}
+
+func NewAsyncLogOutputWithInterfaces(metricWriter MetricWriter, sink LogSink, name string, settings *LogOutputSettings) (*AsyncLogOutput, error) {
+	switch settings.DropPolicy {
+	case DropPolicyNewest, DropPolicyOldest, DropPolicyBlock:
+	default:
+		return nil, fmt.Errorf("unknown drop policy %s of log output %s", settings.DropPolicy, name)
+	}
+
+	if settings.BufferSize <= 0 || settings.BatchSize <= 0 || settings.FlushInterval <= 0 {
+		return nil, fmt.Errorf("buffer size, batch size and flush interval of log output %s have to be positive", name)
+	}
+
+	output := &AsyncLogOutput{
+		name:         name,
+		metricWriter: metricWriter,
+		sink:         sink,
+		settings:     settings,
+		closing:      make(chan struct{}),
+		buffer:       make(chan *LogEntry, settings.BufferSize),
+		done:         make(chan struct{}),
+	}
+
+	go output.run()
+
+	return output, nil
+}
+
+func (o *AsyncLogOutput) Write(entry *LogEntry) {
+	if o.enqueue(entry) {
+		return
+	}
+
+	defer o.blocked.Done()
+
+	select {
+	case o.buffer <- entry:
+	case <-o.closing:
+		o.drop()
+	}
+}
+
+// enqueue buffers the entry according to the drop policy. It returns false if the entry has to wait for room in
+// the buffer, which is done without holding the lock, so a full buffer doesn't block closing the output.
+func (o *AsyncLogOutput) enqueue(entry *LogEntry) bool {
+	o.lck.RLock()
+	defer o.lck.RUnlock()
+
+	if o.closed {
+		o.drop()
+		return true
+	}
+
+	switch o.settings.DropPolicy {
+	case DropPolicyBlock:
+		select {
+		case o.buffer <- entry:
+			return true
+		default:
+		}
+
+		o.blocked.Add(1)
+
+		return false
+
+	case DropPolicyOldest:
+		for {
+			select {
+			case o.buffer <- entry:
+				return true
+			default:
+			}
+
+			select {
+			case <-o.buffer:
+				o.drop()
+			default:
+			}
+		}
+
+	default:
+		select {
+		case o.buffer <- entry:
+		default:
+			o.drop()
+		}
+	}
+
+	return true
+}
+
+// Close stops accepting new entries, writes the remaining ones to the sink and closes it. Entries still waiting
+// for room in the buffer are dropped.
+func (o *AsyncLogOutput) Close() error {
+	o.lck.Lock()
+
+	if o.closed {
+		o.lck.Unlock()
+		return nil
+	}
+
+	o.closed = true
+	o.lck.Unlock()
+
+	close(o.closing)
+	o.blocked.Wait()
+	close(o.buffer)
+
+	<-o.done
+
+	return o.sink.Close()
+}
+
+func (o *AsyncLogOutput) drop() {
+	atomic.AddInt64(&o.dropped, 1)
+}
+
+func (o *AsyncLogOutput) run() {
+	defer close(o.done)
+
+	ticker := time.NewTicker(o.settings.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*LogEntry, 0, o.settings.BatchSize)
+
+	for {
+		select {
+		case entry, ok := <-o.buffer:
+			if !ok {
+				o.flush(batch)
+				return
+			}
+
+			batch = append(batch, entry)
+
+			if len(batch) >= o.settings.BatchSize {
+				batch = o.flush(batch)
+			}
+
+		case <-ticker.C:
+			batch = o.flush(batch)
+		}
+	}
+}
+
+func (o *AsyncLogOutput) flush(batch []*LogEntry) []*LogEntry {
+	o.writeDropped()
+
+	if len(batch) == 0 {
+		return batch
+	}
+
+	// the logger can't be used to report its own failures, so they end up on stderr like the ones of the logger
+	if err := o.sink.Write(batch); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "Failed to write %d entries to log output %s, %v\n", len(batch), o.name, err)
+	}
+
+	return make([]*LogEntry, 0, o.settings.BatchSize)
+}
+
+func (o *AsyncLogOutput) writeDropped() {
+	dropped := atomic.SwapInt64(&o.dropped, 0)
+
+	if dropped == 0 {
+		return
+	}
+
+	o.metricWriter.WriteOne(&MetricDatum{
+		Priority:   PriorityHigh,
+		MetricName: metricNameLogOutputDropped,
+		Dimensions: map[string]string{
+			"Output": o.name,
+		},
+		Unit:  UnitCount,
+		Value: float64(dropped),
+	})
+}
+
+// LogOutputCloser is a kernel module which flushes the log outputs when the application shuts down
+type LogOutputCloser struct {
+	outputs []*AsyncLogOutput
+}
+
+func NewLogOutputCloser(outputs ...*AsyncLogOutput) *LogOutputCloser {
+	return &LogOutputCloser{
+		outputs: outputs,
+	}
+}
+
+func (c *LogOutputCloser) GetType() string {
+	return common.TypeBackground
+}
+
+func (c *LogOutputCloser) GetStage() int {
+	return common.StageEssential
+}
+
+func (c *LogOutputCloser) Run(ctx context.Context) error {
+	<-ctx.Done()
+
+	var result error
+
+	for _, output := range c.outputs {
+		if err := output.Close(); err != nil && result == nil {
+			result = fmt.Errorf("can not close log output %s: %w", output.name, err)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,98 @@
+package mon
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"github.com/applike/gosoline/pkg/es"
+	"io/ioutil"
+	"time"
+)
+
+type LogOutputEsSettings struct {
+	// Client is the name of the es client, it is configured with es_<client>_endpoint and es_<client>_type
+	Client string `cfg:"client" default:"logs"`
+	// IndexPrefix is completed by the day of the entry, e.g. logs-20200101
+	IndexPrefix string `cfg:"index_prefix" default:"logs"`
+}
+
+type esLogDocument struct {
+	Timestamp string `json:"@timestamp"`
+	Channel   string `json:"channel"`
+	Level     int    `json:"level"`
+	LevelName string `json:"level_name"`
+	Message   string `json:"message"`
+	Err       string `json:"err,omitempty"`
+	Fields    Fields `json:"fields"`
+	Context   Fields `json:"context"`
+}
+
+type esLogSink struct {
+	client      *es.ClientV7
+	indexPrefix string
+}
+
+func NewLogEsSink(config cfg.Config, logger Logger, settings *LogOutputSettings) (LogSink, error) {
+	client := es.ProvideClient(config, logger, settings.Es.Client)
+
+	return NewLogEsSinkWithInterfaces(client, settings.Es.IndexPrefix), nil
+}
+
+func NewLogEsSinkWithInterfaces(client *es.ClientV7, indexPrefix string) *esLogSink {
+	return &esLogSink{
+		client:      client,
+		indexPrefix: indexPrefix,
+	}
+}
+
+func (s *esLogSink) Write(batch []*LogEntry) error {
+	var buf bytes.Buffer
+
+	for _, entry := range batch {
+		doc := esLogDocument{
+			Timestamp: entry.Time.UTC().Format(time.RFC3339Nano),
+			Channel:   entry.Data.Channel,
+			Level:     levels[entry.Level],
+			LevelName: entry.Level,
+			Message:   entry.Message,
+			Fields:    entry.Data.Fields,
+			Context:   entry.Data.ContextFields,
+		}
+
+		if entry.Err != nil {
+			doc.Err = entry.Err.Error()
+		}
+
+		data, err := json.Marshal(doc)
+
+		if err != nil {
+			return fmt.Errorf("can not marshal log entry: %w", err)
+		}
+
+		index := fmt.Sprintf("%s-%s", s.indexPrefix, entry.Time.UTC().Format("20060102"))
+
+		buf.WriteString(fmt.Sprintf(`{ "index" : { "_index" : "%s", "_type" : "_doc" } }%s`, index, "\n"))
+		buf.Write(data)
+		buf.WriteByte('\n')
+	}
+
+	res, err := s.client.Bulk(bytes.NewReader(buf.Bytes()))
+
+	if err != nil {
+		return fmt.Errorf("can not bulk write %d log entries to es: %w", len(batch), err)
+	}
+
+	defer res.Body.Close()
+
+	if res.IsError() {
+		body, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("es responded with status %d to the bulk write of %d log entries: %s", res.StatusCode, len(batch), body)
+	}
+
+	return nil
+}
+
+func (s *esLogSink) Close() error {
+	return nil
+}
@@ -0,0 +1,193 @@
+package mon
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/jonboulle/clockwork"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+type LogOutputFileSettings struct {
+	Path   string `cfg:"path" default:"logs/{app_name}.log"`
+	Format string `cfg:"format" default:"json"`
+	// MaxSize is the size in bytes after which the file is rotated, 0 disables the rotation by size
+	MaxSize int64 `cfg:"max_size" default:"104857600"`
+	// MaxAge is the duration after which the file is rotated, 0 disables the rotation by time
+	MaxAge time.Duration `cfg:"max_age" default:"24h"`
+	// MaxBackups is the number of rotated files to keep, 0 keeps all of them
+	MaxBackups int `cfg:"max_backups" default:"5"`
+}
+
+// fileLogSink appends the formatted entries to a file and renames it to <path>.<timestamp> if it gets too big or old
+type fileLogSink struct {
+	clock    clockwork.Clock
+	settings *LogOutputFileSettings
+	format   formatter
+
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func NewLogFileSink(_ cfg.Config, _ Logger, settings *LogOutputSettings) (LogSink, error) {
+	return NewLogFileSinkWithInterfaces(clockwork.NewRealClock(), &settings.File)
+}
+
+func NewLogFileSinkWithInterfaces(clock clockwork.Clock, settings *LogOutputFileSettings) (*fileLogSink, error) {
+	format, ok := formatters[settings.Format]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown file format: %s", settings.Format)
+	}
+
+	sink := &fileLogSink{
+		clock:    clock,
+		settings: settings,
+		format:   format,
+	}
+
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+
+	return sink, nil
+}
+
+func (s *fileLogSink) Write(batch []*LogEntry) error {
+	for _, entry := range batch {
+		line, err := s.format(entry.Time.UTC().Format(time.RFC3339Nano), entry.Level, entry.Message, entry.Err, &entry.Data)
+
+		if err != nil {
+			return fmt.Errorf("can not format log entry: %w", err)
+		}
+
+		if s.shouldRotate(int64(len(line))) {
+			s.rotate()
+		}
+
+		n, err := s.file.Write(line)
+		s.size += int64(n)
+
+		if err != nil {
+			return fmt.Errorf("can not write to log file %s: %w", s.settings.Path, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *fileLogSink) Close() error {
+	if err := s.file.Close(); err != nil {
+		return fmt.Errorf("can not close log file %s: %w", s.settings.Path, err)
+	}
+
+	return nil
+}
+
+func (s *fileLogSink) shouldRotate(lineSize int64) bool {
+	if s.size == 0 {
+		return false
+	}
+
+	if s.settings.MaxSize > 0 && s.size+lineSize > s.settings.MaxSize {
+		return true
+	}
+
+	return s.settings.MaxAge > 0 && s.clock.Now().Sub(s.openedAt) >= s.settings.MaxAge
+}
+
+func (s *fileLogSink) open() error {
+	if err := os.MkdirAll(filepath.Dir(s.settings.Path), 0755); err != nil {
+		return fmt.Errorf("can not create the directory of log file %s: %w", s.settings.Path, err)
+	}
+
+	file, err := os.OpenFile(s.settings.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+
+	if err != nil {
+		return fmt.Errorf("can not open log file %s: %w", s.settings.Path, err)
+	}
+
+	info, err := file.Stat()
+
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("can not stat log file %s: %w", s.settings.Path, err)
+	}
+
+	s.file = file
+	s.size = info.Size()
+	s.openedAt = s.clock.Now()
+
+	// a file written before a restart keeps its age, the last modification is the best guess for it
+	if s.size > 0 && info.ModTime().Before(s.openedAt) {
+		s.openedAt = info.ModTime()
+	}
+
+	return nil
+}
+
+// rotate renames the current file and continues with a new one. If the rotation fails, the error is reported and
+// the entries are written to the current file until the next rotation is due.
+func (s *fileLogSink) rotate() {
+	current := s.file
+	backup := fmt.Sprintf("%s.%s", s.settings.Path, s.clock.Now().UTC().Format("20060102T150405.000000000"))
+
+	if err := os.Rename(s.settings.Path, backup); err != nil {
+		s.reportRotationError(fmt.Errorf("can not rename log file %s: %w", s.settings.Path, err))
+		return
+	}
+
+	if err := s.open(); err != nil {
+		// the renamed file is still open, so the entries end up in the backup
+		s.file = current
+		s.reportRotationError(err)
+		return
+	}
+
+	if err := current.Close(); err != nil {
+		s.reportRotationError(fmt.Errorf("can not close log file backup %s: %w", backup, err))
+	}
+
+	if err := s.removeBackups(); err != nil {
+		s.reportRotationError(err)
+	}
+}
+
+// reportRotationError writes the error to stderr like the other failures of the log outputs and postpones the
+// next rotation by resetting the size and age of the current file
+func (s *fileLogSink) reportRotationError(err error) {
+	_, _ = fmt.Fprintf(os.Stderr, "Failed to rotate log file %s, %v\n", s.settings.Path, err)
+
+	s.size = 0
+	s.openedAt = s.clock.Now()
+}
+
+func (s *fileLogSink) removeBackups() error {
+	if s.settings.MaxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := filepath.Glob(s.settings.Path + ".*")
+
+	if err != nil {
+		return fmt.Errorf("can not list the backups of log file %s: %w", s.settings.Path, err)
+	}
+
+	if len(backups) <= s.settings.MaxBackups {
+		return nil
+	}
+
+	// the timestamp suffix sorts the backups from the oldest to the newest one
+	sort.Strings(backups)
+
+	for _, backup := range backups[:len(backups)-s.settings.MaxBackups] {
+		if err := os.Remove(backup); err != nil {
+			return fmt.Errorf("can not remove log file backup %s: %w", backup, err)
+		}
+	}
+
+	return nil
+}
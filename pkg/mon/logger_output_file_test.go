@@ -0,0 +1,143 @@
+package mon_test
+
+import (
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileLogSink_RotateBySize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gosoline-log")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clock := clockwork.NewFakeClockAt(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC))
+	path := filepath.Join(dir, "logs", "app.log")
+
+	sink, err := mon.NewLogFileSinkWithInterfaces(clock, &mon.LogOutputFileSettings{
+		Path:       path,
+		Format:     mon.FormatJson,
+		MaxSize:    200,
+		MaxBackups: 2,
+	})
+	assert.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		clock.Advance(time.Second)
+		err = sink.Write(getLogEntries(1))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, sink.Close())
+
+	backups, err := filepath.Glob(path + ".*")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		path + ".20200101T120003.000000000",
+		path + ".20200101T120004.000000000",
+	}, backups, "the oldest backup has to be removed")
+
+	content, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(content), "message 0"))
+}
+
+func TestFileLogSink_RotateByAge(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gosoline-log")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clock := clockwork.NewFakeClockAt(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC))
+	path := filepath.Join(dir, "app.log")
+
+	sink, err := mon.NewLogFileSinkWithInterfaces(clock, &mon.LogOutputFileSettings{
+		Path:   path,
+		Format: mon.FormatJson,
+		MaxAge: time.Hour,
+	})
+	assert.NoError(t, err)
+
+	err = sink.Write(getLogEntries(3))
+	assert.NoError(t, err)
+
+	clock.Advance(time.Hour)
+
+	err = sink.Write(getLogEntries(1))
+	assert.NoError(t, err)
+	assert.NoError(t, sink.Close())
+
+	rotated, err := ioutil.ReadFile(path + ".20200101T130000.000000000")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(rotated), "\n"))
+
+	content, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(content), "\n"))
+}
+
+func TestFileLogSink_RotateByAgeAfterRestart(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gosoline-log")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := clockwork.NewFakeClockAt(now)
+	path := filepath.Join(dir, "app.log")
+
+	assert.NoError(t, ioutil.WriteFile(path, []byte("{}\n"), 0644))
+	assert.NoError(t, os.Chtimes(path, now.Add(-2*time.Hour), now.Add(-2*time.Hour)))
+
+	sink, err := mon.NewLogFileSinkWithInterfaces(clock, &mon.LogOutputFileSettings{
+		Path:   path,
+		Format: mon.FormatJson,
+		MaxAge: time.Hour,
+	})
+	assert.NoError(t, err)
+
+	err = sink.Write(getLogEntries(1))
+	assert.NoError(t, err)
+	assert.NoError(t, sink.Close())
+
+	rotated, err := ioutil.ReadFile(path + ".20200101T120000.000000000")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}\n", string(rotated), "the file of the previous run has to be rotated")
+}
+
+func TestFileLogSink_RotateFailed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gosoline-log")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	clock := clockwork.NewFakeClockAt(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC))
+	path := filepath.Join(dir, "app.log")
+
+	// a non-empty directory with the name of the backup can't be replaced by the rename
+	backup := path + ".20200101T120001.000000000"
+	assert.NoError(t, os.MkdirAll(filepath.Join(backup, "blocked"), 0755))
+
+	sink, err := mon.NewLogFileSinkWithInterfaces(clock, &mon.LogOutputFileSettings{
+		Path:    path,
+		Format:  mon.FormatJson,
+		MaxSize: 100,
+	})
+	assert.NoError(t, err)
+
+	err = sink.Write(getLogEntries(1))
+	assert.NoError(t, err)
+
+	clock.Advance(time.Second)
+
+	err = sink.Write(getLogEntries(2))
+	assert.NoError(t, err, "a failed rotation must not fail the write")
+	assert.NoError(t, sink.Close())
+
+	content, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(content), "\n"), "the entries have to be written to the current file")
+}
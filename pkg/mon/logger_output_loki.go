@@ -0,0 +1,135 @@
+package mon
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type LogOutputLokiSettings struct {
+	Url     string        `cfg:"url" default:"http://localhost:3100/loki/api/v1/push"`
+	Format  string        `cfg:"format" default:"json"`
+	Timeout time.Duration `cfg:"timeout" default:"10s"`
+	// Labels are added to the channel and level labels of every stream
+	Labels map[string]string `cfg:"labels"`
+}
+
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// lokiLogSink pushes the entries json encoded to loki, every combination of channel and level is a stream
+type lokiLogSink struct {
+	client *http.Client
+	url    string
+	format formatter
+	labels map[string]string
+}
+
+func NewLogLokiSink(_ cfg.Config, _ Logger, settings *LogOutputSettings) (LogSink, error) {
+	client := &http.Client{
+		Timeout: settings.Loki.Timeout,
+	}
+
+	return NewLogLokiSinkWithInterfaces(client, &settings.Loki)
+}
+
+func NewLogLokiSinkWithInterfaces(client *http.Client, settings *LogOutputLokiSettings) (*lokiLogSink, error) {
+	format, ok := formatters[settings.Format]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown loki format: %s", settings.Format)
+	}
+
+	return &lokiLogSink{
+		client: client,
+		url:    settings.Url,
+		format: format,
+		labels: settings.Labels,
+	}, nil
+}
+
+func (s *lokiLogSink) Write(batch []*LogEntry) error {
+	streams := make(map[string]*lokiStream)
+	keys := make([]string, 0)
+
+	for _, entry := range batch {
+		line, err := s.format(entry.Time.UTC().Format(time.RFC3339Nano), entry.Level, entry.Message, entry.Err, &entry.Data)
+
+		if err != nil {
+			return fmt.Errorf("can not format log entry: %w", err)
+		}
+
+		key := fmt.Sprintf("%s/%s", entry.Data.Channel, entry.Level)
+
+		if _, ok := streams[key]; !ok {
+			streams[key] = &lokiStream{
+				Stream: s.streamLabels(entry),
+				Values: make([][2]string, 0),
+			}
+			keys = append(keys, key)
+		}
+
+		streams[key].Values = append(streams[key].Values, [2]string{
+			strconv.FormatInt(entry.Time.UnixNano(), 10),
+			strings.TrimSuffix(string(line), "\n"),
+		})
+	}
+
+	sort.Strings(keys)
+	push := lokiPush{
+		Streams: make([]lokiStream, len(keys)),
+	}
+
+	for i, key := range keys {
+		push.Streams[i] = *streams[key]
+	}
+
+	body, err := json.Marshal(push)
+
+	if err != nil {
+		return fmt.Errorf("can not marshal %d log entries: %w", len(batch), err)
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+
+	if err != nil {
+		return fmt.Errorf("can not push %d log entries to %s: %w", len(batch), s.url, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("loki at %s responded with status %d to %d log entries", s.url, resp.StatusCode, len(batch))
+	}
+
+	return nil
+}
+
+func (s *lokiLogSink) Close() error {
+	return nil
+}
+
+func (s *lokiLogSink) streamLabels(entry *LogEntry) map[string]string {
+	labels := make(map[string]string, len(s.labels)+2)
+
+	for k, v := range s.labels {
+		labels[k] = v
+	}
+
+	labels["channel"] = entry.Data.Channel
+	labels["level"] = entry.Level
+
+	return labels
+}
@@ -0,0 +1,257 @@
+package mon_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAsyncLogOutput_Batching(t *testing.T) {
+	metricWriter := new(mocks.MetricWriter)
+	sink := new(mocks.LogSink)
+
+	batches := make([][]*mon.LogEntry, 0)
+	sink.On("Write", mock.Anything).Run(func(args mock.Arguments) {
+		batches = append(batches, args.Get(0).([]*mon.LogEntry))
+	}).Return(nil)
+	sink.On("Close").Return(nil).Once()
+
+	output, err := mon.NewAsyncLogOutputWithInterfaces(metricWriter, sink, "test", getLogOutputSettings(mon.DropPolicyNewest, 10, 2))
+	assert.NoError(t, err)
+
+	entries := getLogEntries(3)
+
+	for _, entry := range entries {
+		output.Write(entry)
+	}
+
+	assert.NoError(t, output.Close())
+	assert.NoError(t, output.Close(), "closing twice has to be a noop")
+
+	assert.Equal(t, [][]*mon.LogEntry{entries[0:2], entries[2:3]}, batches)
+	sink.AssertExpectations(t)
+	metricWriter.AssertExpectations(t)
+}
+
+func TestAsyncLogOutput_DropNewest(t *testing.T) {
+	entries, batches := testAsyncLogOutputDrop(t, mon.DropPolicyNewest)
+
+	assert.Equal(t, [][]*mon.LogEntry{entries[0:1], entries[1:2]}, batches)
+}
+
+func TestAsyncLogOutput_DropOldest(t *testing.T) {
+	entries, batches := testAsyncLogOutputDrop(t, mon.DropPolicyOldest)
+
+	assert.Equal(t, [][]*mon.LogEntry{entries[0:1], entries[3:4]}, batches)
+}
+
+func TestAsyncLogOutput_BlockClose(t *testing.T) {
+	metricWriter := new(mocks.MetricWriter)
+	metricWriter.On("WriteOne", mock.AnythingOfType("*mon.MetricDatum")).Once()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	sink := new(mocks.LogSink)
+	batches := make([][]*mon.LogEntry, 0)
+	sink.On("Write", mock.Anything).Run(func(args mock.Arguments) {
+		batches = append(batches, args.Get(0).([]*mon.LogEntry))
+
+		if len(batches) == 1 {
+			close(started)
+			<-release
+		}
+	}).Return(nil)
+	sink.On("Close").Return(nil).Once()
+
+	output, err := mon.NewAsyncLogOutputWithInterfaces(metricWriter, sink, "test", getLogOutputSettings(mon.DropPolicyBlock, 1, 1))
+	assert.NoError(t, err)
+
+	entries := getLogEntries(3)
+	output.Write(entries[0])
+	<-started
+	output.Write(entries[1])
+
+	blocked := make(chan struct{})
+	go func() {
+		output.Write(entries[2])
+		close(blocked)
+	}()
+
+	closed := make(chan error)
+	go func() {
+		closed <- output.Close()
+	}()
+
+	select {
+	case <-blocked:
+	case <-time.After(time.Second):
+		assert.Fail(t, "closing the output has to release the blocked write while the sink is busy")
+	}
+
+	close(release)
+	assert.NoError(t, <-closed)
+
+	assert.Equal(t, [][]*mon.LogEntry{entries[0:1], entries[1:2]}, batches)
+	sink.AssertExpectations(t)
+	metricWriter.AssertExpectations(t)
+}
+
+func TestAsyncLogOutput_UnknownDropPolicy(t *testing.T) {
+	_, err := mon.NewAsyncLogOutputWithInterfaces(new(mocks.MetricWriter), new(mocks.LogSink), "test", getLogOutputSettings("drop_all", 1, 1))
+
+	assert.EqualError(t, err, "unknown drop policy drop_all of log output test")
+}
+
+// testAsyncLogOutputDrop blocks the sink with the first entry and fills the buffer of size 1 with the remaining three
+// entries. It returns the entries and the written batches.
+func testAsyncLogOutputDrop(t *testing.T, policy string) ([]*mon.LogEntry, [][]*mon.LogEntry) {
+	metricWriter := new(mocks.MetricWriter)
+	metricWriter.On("WriteOne", &mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		MetricName: "LogOutputDropped",
+		Dimensions: map[string]string{
+			"Output": "test",
+		},
+		Unit:  mon.UnitCount,
+		Value: 2,
+	}).Once()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	sink := new(mocks.LogSink)
+	batches := make([][]*mon.LogEntry, 0)
+	sink.On("Write", mock.Anything).Run(func(args mock.Arguments) {
+		batches = append(batches, args.Get(0).([]*mon.LogEntry))
+
+		if len(batches) == 1 {
+			close(started)
+			<-release
+		}
+	}).Return(nil)
+	sink.On("Close").Return(nil).Once()
+
+	output, err := mon.NewAsyncLogOutputWithInterfaces(metricWriter, sink, "test", getLogOutputSettings(policy, 1, 1))
+	assert.NoError(t, err)
+
+	entries := getLogEntries(4)
+	output.Write(entries[0])
+	<-started
+
+	for _, entry := range entries[1:] {
+		output.Write(entry)
+	}
+
+	close(release)
+	assert.NoError(t, output.Close())
+
+	sink.AssertExpectations(t)
+	metricWriter.AssertExpectations(t)
+
+	return entries, batches
+}
+
+func TestLokiLogSink_Write(t *testing.T) {
+	var body []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/loki/api/v1/push", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sink, err := mon.NewLogLokiSinkWithInterfaces(server.Client(), &mon.LogOutputLokiSettings{
+		Url:    server.URL + "/loki/api/v1/push",
+		Format: mon.FormatJson,
+		Labels: map[string]string{
+			"app": "gosoline",
+		},
+	})
+	assert.NoError(t, err)
+
+	entries := getLogEntries(3)
+	entries[1].Level = mon.Warn
+	entries[1].Err = fmt.Errorf("boom")
+
+	err = sink.Write(entries)
+	assert.NoError(t, err)
+
+	push := struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}{}
+
+	err = json.Unmarshal(body, &push)
+	assert.NoError(t, err)
+
+	assert.Len(t, push.Streams, 2)
+	assert.Equal(t, map[string]string{"app": "gosoline", "channel": "test", "level": "info"}, push.Streams[0].Stream)
+	assert.Equal(t, map[string]string{"app": "gosoline", "channel": "test", "level": "warn"}, push.Streams[1].Stream)
+
+	assert.Len(t, push.Streams[0].Values, 2)
+	assert.Equal(t, "1577880000000000000", push.Streams[0].Values[0][0])
+	assert.Equal(t, "1577880002000000000", push.Streams[0].Values[1][0])
+	assert.JSONEq(t, `{"channel":"test","context":{},"fields":{},"level":2,"level_name":"info","message":"message 0","timestamp":"2020-01-01T12:00:00Z"}`, push.Streams[0].Values[0][1])
+	assert.JSONEq(t, `{"channel":"test","context":{},"err":"boom","fields":{},"level":3,"level_name":"warn","message":"message 1","timestamp":"2020-01-01T12:00:01Z"}`, push.Streams[1].Values[0][1])
+}
+
+func TestLokiLogSink_WriteFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	url := server.URL + "/loki/api/v1/push"
+	sink, err := mon.NewLogLokiSinkWithInterfaces(server.Client(), &mon.LogOutputLokiSettings{
+		Url:    url,
+		Format: mon.FormatJson,
+	})
+	assert.NoError(t, err)
+
+	err = sink.Write(getLogEntries(1))
+	assert.EqualError(t, err, fmt.Sprintf("loki at %s responded with status 400 to 1 log entries", url))
+}
+
+func getLogOutputSettings(policy string, bufferSize int, batchSize int) *mon.LogOutputSettings {
+	return &mon.LogOutputSettings{
+		Level:         mon.Info,
+		BufferSize:    bufferSize,
+		DropPolicy:    policy,
+		BatchSize:     batchSize,
+		FlushInterval: time.Hour,
+	}
+}
+
+func getLogEntries(count int) []*mon.LogEntry {
+	entries := make([]*mon.LogEntry, count)
+
+	for i := range entries {
+		entries[i] = &mon.LogEntry{
+			Time:    time.Date(2020, 1, 1, 12, 0, i, 0, time.UTC),
+			Level:   mon.Info,
+			Message: fmt.Sprintf("message %d", i),
+			Data: mon.Metadata{
+				Channel:       "test",
+				ContextFields: mon.Fields{},
+				Fields:        mon.Fields{},
+				Tags:          mon.Tags{},
+			},
+		}
+	}
+
+	return entries
+}
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/mon/mocks"
 	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"testing"
 	"time"
 )
@@ -240,6 +243,83 @@ func TestClient_Infof(t *testing.T) {
 	assert.JSONEq(t, expected, out.String(), "output should match")
 }
 
+func TestLogger_WithLogOutput(t *testing.T) {
+	logger, out := getLogger()
+
+	debugOutput := new(mocks.LogOutput)
+	errorOutput := new(mocks.LogOutput)
+
+	err := logger.Option(mon.WithLogOutput(debugOutput, mon.Debug), mon.WithLogOutput(errorOutput, mon.Error))
+	assert.NoError(t, err)
+
+	debugOutput.On("Write", mock.MatchedBy(func(entry *mon.LogEntry) bool {
+		return entry.Level == mon.Debug && entry.Message == "debug message" && entry.Data.Channel == "test"
+	})).Once()
+	debugOutput.On("Write", mock.MatchedBy(func(entry *mon.LogEntry) bool {
+		return entry.Level == mon.Error && entry.Message == "error message"
+	})).Once()
+	errorOutput.On("Write", mock.MatchedBy(func(entry *mon.LogEntry) bool {
+		return entry.Level == mon.Error && entry.Err.Error() == "boom"
+	})).Once()
+
+	channelLogger := logger.WithChannel("test")
+	channelLogger.Debug("debug message")
+	assert.Empty(t, out.String(), "the logger level has to apply to the output of the logger")
+
+	channelLogger.Error(fmt.Errorf("boom"), "error message")
+	assert.Contains(t, out.String(), "error message")
+
+	debugOutput.AssertExpectations(t)
+	errorOutput.AssertExpectations(t)
+}
+
+func TestLogger_WithLogOutput_UnknownLevel(t *testing.T) {
+	logger, _ := getLogger()
+
+	err := logger.Option(mon.WithLogOutput(new(mocks.LogOutput), "verbose"))
+	assert.EqualError(t, err, "unknown level of log output: verbose")
+}
+
+func TestLogger_WithChannelLevels(t *testing.T) {
+	clock := clockwork.NewFakeClock()
+	registry := mon.NewChannelLevelRegistryWithInterfaces(clock)
+
+	logger, out := getLogger()
+	err := logger.Option(mon.WithChannelLevels(registry))
+	assert.NoError(t, err)
+
+	err = registry.Set("kvstore", mon.Debug, time.Minute)
+	assert.NoError(t, err)
+
+	err = registry.Set("consumer", mon.Error, 0)
+	assert.NoError(t, err)
+
+	logger.WithChannel("kvstore").Debug("kvstore debug")
+	logger.WithChannel("consumer").Warn("consumer warn")
+	logger.WithChannel("ddb").Debug("ddb debug")
+
+	assert.Contains(t, out.String(), "kvstore debug")
+	assert.NotContains(t, out.String(), "consumer warn")
+	assert.NotContains(t, out.String(), "ddb debug")
+
+	out.Reset()
+	clock.Advance(time.Minute)
+
+	logger.WithChannel("kvstore").Debug("kvstore debug")
+	assert.Empty(t, out.String(), "the level of the channel has to be reverted after the ttl")
+}
+
+func TestLogger_WithDebugContext(t *testing.T) {
+	logger, out := getLogger()
+
+	logger.WithContext(context.Background()).Debug("background debug")
+	assert.Empty(t, out.String())
+
+	ctx := mon.WithDebugContext(context.Background())
+	logger.WithContext(ctx).Debugf("request %s", "debug")
+	assert.Contains(t, out.String(), "request debug")
+}
+
 func getLogger() (mon.GosoLog, *bytes.Buffer) {
 	clock := clockwork.NewFakeClock()
 	out := bytes.NewBuffer([]byte{})
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/applike/gosoline/pkg/cfg"
 	"github.com/applike/gosoline/pkg/kernel/common"
+	"math"
 	"sync"
 	"time"
 )
@@ -16,6 +17,9 @@ type MetricSettings struct {
 	Enabled  bool          `cfg:"enabled" default:"false"`
 	Interval time.Duration `cfg:"interval" default:"60s"`
 	Writers  []string      `cfg:"writers"`
+	// Distributions enables writing all values of the distribution units to compute percentiles, if disabled
+	// they are written as averages like the corresponding average units.
+	Distributions bool `cfg:"distributions" default:"false"`
 }
 
 func getMetricSettings(config cfg.Config) *MetricSettings {
@@ -127,13 +131,11 @@ func (d *MetricDaemon) appendBatch(data MetricData) {
 func (d *MetricDaemon) append(datum *MetricDatum) {
 	d.dataPointCount++
 
-	dimKey := datum.DimensionKey()
-	timeKey := datum.Timestamp.Format(defaultTimeFormat)
-
-	key := fmt.Sprintf("%s-%s-%s", datum.MetricName, dimKey, timeKey)
+	key := batchKey(datum)
 
 	if _, ok := d.batch[key]; !ok {
 		d.amendFromDefault(datum)
+		datum.Unit = d.resolveUnit(datum.Unit)
 
 		if err := datum.IsValid(); err != nil {
 			d.logger.Warnf("invalid metric: %s", err.Error())
@@ -172,6 +174,23 @@ func (d *MetricDaemon) amendFromDefault(datum *MetricDatum) {
 	}
 }
 
+func (d *MetricDaemon) resolveUnit(unit string) string {
+	if d.settings.Distributions {
+		return unit
+	}
+
+	switch unit {
+	case UnitCountDistribution:
+		return UnitCountAverage
+	case UnitMillisecondsDistribution:
+		return UnitMillisecondsAverage
+	case UnitSecondsDistribution:
+		return UnitSecondsAverage
+	}
+
+	return unit
+}
+
 func (d *MetricDaemon) resetBatch() {
 	d.batch = make(map[string]*BatchedMetricDatum)
 	d.dataPointCount = 0
@@ -181,6 +200,11 @@ func (d *MetricDaemon) resetBatch() {
 		cpy.Timestamp = time.Now()
 
 		d.append(&cpy)
+
+		// the default value of a distribution would distort its percentiles
+		if batched, ok := d.batch[batchKey(&cpy)]; ok && isDistributionUnit(batched.Unit) {
+			batched.Values = batched.Values[:0]
+		}
 	}
 }
 
@@ -192,8 +216,14 @@ func (d *MetricDaemon) publish() {
 	}
 
 	data := d.buildMetricData()
+	batch := d.buildBatchedMetricData()
 
 	for _, w := range d.writers {
+		if batchWriter, ok := w.(MetricBatchWriter); ok {
+			batchWriter.WriteBatch(batch)
+			continue
+		}
+
 		w.Write(data)
 	}
 
@@ -216,23 +246,37 @@ func (d *MetricDaemon) buildMetricData() MetricData {
 			Value:      value,
 		}
 
+		if isDistributionUnit(v.Unit) {
+			datum.Values = v.Values
+		}
+
 		data = append(data, datum)
 	}
 
 	return data
 }
 
+func (d *MetricDaemon) buildBatchedMetricData() []*BatchedMetricDatum {
+	batch := make([]*BatchedMetricDatum, 0, len(d.batch))
+
+	for _, v := range d.batch {
+		batch = append(batch, v)
+	}
+
+	return batch
+}
+
 func (d *MetricDaemon) calcValue(unit string, values []float64) (string, float64) {
 	value := 0.0
 
 	switch unit {
-	case UnitCountAverage:
+	case UnitCountAverage, UnitCountDistribution:
 		unit = UnitCount
 		value = average(values)
-	case UnitMillisecondsAverage:
+	case UnitMillisecondsAverage, UnitMillisecondsDistribution:
 		unit = UnitMilliseconds
 		value = average(values)
-	case UnitSecondsAverage:
+	case UnitSecondsAverage, UnitSecondsDistribution:
 		unit = UnitSeconds
 		value = average(values)
 	default:
@@ -242,7 +286,33 @@ func (d *MetricDaemon) calcValue(unit string, values []float64) (string, float64
 	return unit, value
 }
 
+func batchKey(datum *MetricDatum) string {
+	dimKey := datum.DimensionKey()
+	timeKey := datum.Timestamp.Format(defaultTimeFormat)
+
+	return fmt.Sprintf("%s-%s-%s", datum.MetricName, dimKey, timeKey)
+}
+
+func isDistributionUnit(unit string) bool {
+	return unit == UnitCountDistribution || unit == UnitMillisecondsDistribution || unit == UnitSecondsDistribution
+}
+
+// percentile uses the nearest rank of the sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
 func average(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+
 	return sum(xs) / float64(len(xs))
 }
 
@@ -0,0 +1,299 @@
+package mon
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	promTypeCounter   = "counter"
+	promTypeGauge     = "gauge"
+	promTypeHistogram = "histogram"
+
+	PromContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+var promRegistryContainer = struct {
+	sync.Mutex
+	instance *PromRegistry
+}{}
+
+// ProvidePromRegistry returns the registry shared by the prom metric writer and the /metrics endpoint
+func ProvidePromRegistry() *PromRegistry {
+	promRegistryContainer.Lock()
+	defer promRegistryContainer.Unlock()
+
+	if promRegistryContainer.instance != nil {
+		return promRegistryContainer.instance
+	}
+
+	promRegistryContainer.instance = NewPromRegistry()
+
+	return promRegistryContainer.instance
+}
+
+// PromRegistry keeps the current state of all prometheus metrics and writes them in the text exposition format
+type PromRegistry struct {
+	lck      sync.Mutex
+	families map[string]*promFamily
+}
+
+type promFamily struct {
+	name       string
+	help       string
+	typ        string
+	labelNames []string
+	buckets    []float64
+	series     map[string]*promSeries
+}
+
+type promSeries struct {
+	labelValues  []string
+	value        float64
+	bucketCounts []uint64
+	count        uint64
+}
+
+func NewPromRegistry() *PromRegistry {
+	return &PromRegistry{
+		families: make(map[string]*promFamily),
+	}
+}
+
+func (r *PromRegistry) Add(name string, help string, labels map[string]string, value float64) error {
+	if value < 0 {
+		return fmt.Errorf("counter %s can not be decreased by %f", name, value)
+	}
+
+	return r.update(name, help, promTypeCounter, nil, labels, func(_ *promFamily, series *promSeries) {
+		series.value += value
+	})
+}
+
+func (r *PromRegistry) Set(name string, help string, labels map[string]string, value float64) error {
+	return r.update(name, help, promTypeGauge, nil, labels, func(_ *promFamily, series *promSeries) {
+		series.value = value
+	})
+}
+
+func (r *PromRegistry) Observe(name string, help string, buckets []float64, labels map[string]string, values ...float64) error {
+	return r.update(name, help, promTypeHistogram, buckets, labels, func(family *promFamily, series *promSeries) {
+		for _, value := range values {
+			series.value += value
+			series.count++
+
+			for i, bound := range family.buckets {
+				if value <= bound {
+					series.bucketCounts[i]++
+				}
+			}
+		}
+	})
+}
+
+func (r *PromRegistry) update(name string, help string, typ string, buckets []float64, labels map[string]string, apply func(family *promFamily, series *promSeries)) error {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	labelNames := make([]string, 0, len(labels))
+
+	for labelName := range labels {
+		labelNames = append(labelNames, labelName)
+	}
+
+	sort.Strings(labelNames)
+
+	family, ok := r.families[name]
+
+	if !ok {
+		family = &promFamily{
+			name:       name,
+			help:       help,
+			typ:        typ,
+			labelNames: labelNames,
+			buckets:    buckets,
+			series:     make(map[string]*promSeries),
+		}
+
+		r.families[name] = family
+	}
+
+	if family.typ != typ {
+		return fmt.Errorf("metric %s is a %s and can not be used as %s", name, family.typ, typ)
+	}
+
+	if strings.Join(family.labelNames, ",") != strings.Join(labelNames, ",") {
+		return fmt.Errorf("metric %s has the labels [%s] and can not be used with [%s]", name, strings.Join(family.labelNames, ","), strings.Join(labelNames, ","))
+	}
+
+	labelValues := make([]string, len(labelNames))
+
+	for i, labelName := range labelNames {
+		labelValues[i] = labels[labelName]
+	}
+
+	key := strings.Join(labelValues, "\xff")
+	series, ok := family.series[key]
+
+	if !ok {
+		series = &promSeries{
+			labelValues:  labelValues,
+			bucketCounts: make([]uint64, len(family.buckets)),
+		}
+
+		family.series[key] = series
+	}
+
+	apply(family, series)
+
+	return nil
+}
+
+func (r *PromRegistry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", PromContentType)
+
+	if err := r.Expose(w); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Expose writes all metrics sorted by name and labels in the prometheus text exposition format
+func (r *PromRegistry) Expose(w io.Writer) error {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	buf := bufio.NewWriter(w)
+	names := make([]string, 0, len(r.families))
+
+	for name := range r.families {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		r.families[name].write(buf)
+	}
+
+	return buf.Flush()
+}
+
+func (f *promFamily) write(buf *bufio.Writer) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", f.name, escapePromHelp(f.help))
+	fmt.Fprintf(buf, "# TYPE %s %s\n", f.name, f.typ)
+
+	keys := make([]string, 0, len(f.series))
+
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		series := f.series[key]
+
+		if f.typ != promTypeHistogram {
+			fmt.Fprintf(buf, "%s%s %s\n", f.name, f.formatLabels(series, "", ""), formatPromValue(series.value))
+			continue
+		}
+
+		for i, bound := range f.buckets {
+			fmt.Fprintf(buf, "%s_bucket%s %d\n", f.name, f.formatLabels(series, "le", formatPromValue(bound)), series.bucketCounts[i])
+		}
+
+		fmt.Fprintf(buf, "%s_bucket%s %d\n", f.name, f.formatLabels(series, "le", "+Inf"), series.count)
+		fmt.Fprintf(buf, "%s_sum%s %s\n", f.name, f.formatLabels(series, "", ""), formatPromValue(series.value))
+		fmt.Fprintf(buf, "%s_count%s %d\n", f.name, f.formatLabels(series, "", ""), series.count)
+	}
+}
+
+func (f *promFamily) formatLabels(series *promSeries, extraName string, extraValue string) string {
+	pairs := make([]string, 0, len(f.labelNames)+1)
+
+	for i, labelName := range f.labelNames {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labelName, escapePromLabelValue(series.labelValues[i])))
+	}
+
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extraName, extraValue))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatPromValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func escapePromHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func escapePromLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`).Replace(value)
+}
+
+// promName converts metric and dimension names like ApiRequestCount or my-metric into api_request_count and my_metric
+func promName(name string) string {
+	runes := []rune(name)
+	builder := strings.Builder{}
+
+	for i, r := range runes {
+		isUpper := r >= 'A' && r <= 'Z'
+		isLower := r >= 'a' && r <= 'z'
+		isDigit := r >= '0' && r <= '9'
+
+		if !isUpper && !isLower && !isDigit {
+			builder.WriteRune('_')
+			continue
+		}
+
+		if isUpper && i > 0 {
+			prev := runes[i-1]
+			prevLowerOrDigit := (prev >= 'a' && prev <= 'z') || (prev >= '0' && prev <= '9')
+			nextLower := i+1 < len(runes) && runes[i+1] >= 'a' && runes[i+1] <= 'z'
+			prevUpper := prev >= 'A' && prev <= 'Z'
+
+			if prevLowerOrDigit || (prevUpper && nextLower) {
+				builder.WriteRune('_')
+			}
+		}
+
+		builder.WriteRune(r)
+	}
+
+	converted := strings.ToLower(builder.String())
+
+	for strings.Contains(converted, "__") {
+		converted = strings.Replace(converted, "__", "_", -1)
+	}
+
+	converted = strings.Trim(converted, "_")
+
+	if converted != "" && converted[0] >= '0' && converted[0] <= '9' {
+		converted = "_" + converted
+	}
+
+	return converted
+}
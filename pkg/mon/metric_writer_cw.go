@@ -17,14 +17,20 @@ const (
 	PriorityLow  = 1
 	PriorityHigh = 2
 
-	UnitCount               = cloudwatch.StandardUnitCount
-	UnitCountAverage        = "UnitCountAverage"
-	UnitSeconds             = cloudwatch.StandardUnitSeconds
-	UnitSecondsAverage      = "UnitSecondsAverage"
-	UnitMilliseconds        = cloudwatch.StandardUnitMilliseconds
-	UnitMillisecondsAverage = "UnitMillisecondsAverage"
+	UnitCount                    = cloudwatch.StandardUnitCount
+	UnitCountAverage             = "UnitCountAverage"
+	UnitCountDistribution        = "UnitCountDistribution"
+	UnitSeconds                  = cloudwatch.StandardUnitSeconds
+	UnitSecondsAverage           = "UnitSecondsAverage"
+	UnitSecondsDistribution      = "UnitSecondsDistribution"
+	UnitMilliseconds             = cloudwatch.StandardUnitMilliseconds
+	UnitMillisecondsAverage      = "UnitMillisecondsAverage"
+	UnitMillisecondsDistribution = "UnitMillisecondsDistribution"
 
 	chunkSizeCloudWatch = 20
+	// maxValuesCloudWatch is the maximum number of distinct values of a single datum, distributions with more values
+	// are split into several datums
+	maxValuesCloudWatch = 150
 	minusOneWeek        = -1 * 7 * 24 * time.Hour
 	plusOneHour         = 1 * time.Hour
 )
@@ -38,6 +44,18 @@ type MetricDatum struct {
 	Dimensions MetricDimensions `json:"dimensions"`
 	Value      float64          `json:"value"`
 	Unit       string           `json:"unit"`
+	// Values are all values of a period of the distribution units, they are set by the MetricDaemon
+	Values []float64 `json:"-"`
+}
+
+type MetricStatistics struct {
+	SampleCount float64 `json:"sampleCount"`
+	Sum         float64 `json:"sum"`
+	Minimum     float64 `json:"min"`
+	Maximum     float64 `json:"max"`
+	P50         float64 `json:"p50"`
+	P90         float64 `json:"p90"`
+	P99         float64 `json:"p99"`
 }
 
 func (d *MetricDatum) Id() string {
@@ -58,6 +76,27 @@ func (d *MetricDatum) DimensionKey() string {
 	return dimKey
 }
 
+// Statistics summarizes the Values of a distribution, it is nil for other data
+func (d *MetricDatum) Statistics() *MetricStatistics {
+	if len(d.Values) == 0 {
+		return nil
+	}
+
+	sorted := make([]float64, len(d.Values))
+	copy(sorted, d.Values)
+	sort.Float64s(sorted)
+
+	return &MetricStatistics{
+		SampleCount: float64(len(sorted)),
+		Sum:         sum(sorted),
+		Minimum:     sorted[0],
+		Maximum:     sorted[len(sorted)-1],
+		P50:         percentile(sorted, 50),
+		P90:         percentile(sorted, 90),
+		P99:         percentile(sorted, 99),
+	}
+}
+
 func (d *MetricDatum) IsValid() error {
 	if d.MetricName == "" {
 		return fmt.Errorf("missing metric name")
@@ -201,13 +240,65 @@ func (w *cwWriter) buildMetricData(batch MetricData) ([]*cloudwatch.MetricDatum,
 			Unit: aws.String(data.Unit),
 		}
 
-		if err := datum.Validate(); err != nil {
-			w.logger.Error(err, "invalid metric datum")
-			continue
+		datums := []*cloudwatch.MetricDatum{datum}
+
+		if len(data.Values) > 0 {
+			datums = w.buildDistribution(datum, data)
 		}
 
-		metricData = append(metricData, datum)
+		for _, datum := range datums {
+			if err := datum.Validate(); err != nil {
+				w.logger.Error(err, "invalid metric datum")
+				continue
+			}
+
+			metricData = append(metricData, datum)
+		}
 	}
 
 	return metricData, nil
 }
+
+// buildDistribution writes the distinct values with their counts, cloudwatch can compute percentiles from those. The
+// values are split into several datums of the same metric if there are too many of them for a single one.
+func (w *cwWriter) buildDistribution(datum *cloudwatch.MetricDatum, data *MetricDatum) []*cloudwatch.MetricDatum {
+	counts := make(map[float64]float64)
+
+	for _, value := range data.Values {
+		counts[value]++
+	}
+
+	values := make([]float64, 0, len(counts))
+
+	for value := range counts {
+		values = append(values, value)
+	}
+
+	sort.Float64s(values)
+
+	datums := make([]*cloudwatch.MetricDatum, 0, len(values)/maxValuesCloudWatch+1)
+
+	for i := 0; i < len(values); i += maxValuesCloudWatch {
+		end := i + maxValuesCloudWatch
+
+		if end > len(values) {
+			end = len(values)
+		}
+
+		part := &cloudwatch.MetricDatum{
+			MetricName: datum.MetricName,
+			Dimensions: datum.Dimensions,
+			Timestamp:  datum.Timestamp,
+			Unit:       datum.Unit,
+		}
+
+		for _, value := range values[i:end] {
+			part.Values = append(part.Values, aws.Float64(value))
+			part.Counts = append(part.Counts, aws.Float64(counts[value]))
+		}
+
+		datums = append(datums, part)
+	}
+
+	return datums
+}
@@ -8,6 +8,8 @@ import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"testing"
 	"time"
 )
@@ -77,3 +79,104 @@ func buildMocksAndWrite(now time.Time, metricTimeStamp time.Time) *cloudMocks.Cl
 
 	return cwClient
 }
+
+func TestOutput_WriteDistribution(t *testing.T) {
+	timestamp := time.Unix(1549283566, 0)
+	cwClient := new(cloudMocks.CloudWatchAPI)
+
+	cwClient.On("PutMetricData", &cloudwatch.PutMetricDataInput{
+		Namespace: aws.String("my/test/namespace/app"),
+		MetricData: []*cloudwatch.MetricDatum{{
+			MetricName: aws.String("latency"),
+			Dimensions: []*cloudwatch.Dimension{},
+			Timestamp:  aws.Time(timestamp),
+			Values:     aws.Float64Slice([]float64{5, 20, 300}),
+			Counts:     aws.Float64Slice([]float64{2, 1, 1}),
+			Unit:       aws.String(mon.UnitMilliseconds),
+		}},
+	}).Return(nil, nil)
+
+	writer := mon.NewMetricCwWriterWithInterfaces(monMocks.NewLoggerMockedAll(), clockwork.NewFakeClockAt(timestamp), cwClient, &mon.MetricSettings{
+		AppId: cfg.AppId{
+			Project:     "my",
+			Environment: "test",
+			Family:      "namespace",
+			Application: "app",
+		},
+		Enabled: true,
+	})
+
+	writer.WriteOne(&mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		Timestamp:  timestamp,
+		MetricName: "latency",
+		Unit:       mon.UnitMilliseconds,
+		Value:      82.5,
+		Values:     []float64{20, 5, 300, 5},
+	})
+
+	cwClient.AssertExpectations(t)
+}
+
+func TestOutput_WriteDistribution_Split(t *testing.T) {
+	timestamp := time.Unix(1549283566, 0)
+	cwClient := new(cloudMocks.CloudWatchAPI)
+
+	values := make([]float64, 0, 200)
+
+	for i := 200; i > 0; i-- {
+		values = append(values, float64(i))
+	}
+
+	var written []*cloudwatch.MetricDatum
+	cwClient.On("PutMetricData", mock.AnythingOfType("*cloudwatch.PutMetricDataInput")).Run(func(args mock.Arguments) {
+		written = args.Get(0).(*cloudwatch.PutMetricDataInput).MetricData
+	}).Return(nil, nil).Once()
+
+	writer := mon.NewMetricCwWriterWithInterfaces(monMocks.NewLoggerMockedAll(), clockwork.NewFakeClockAt(timestamp), cwClient, &mon.MetricSettings{
+		Enabled: true,
+	})
+
+	writer.WriteOne(&mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		Timestamp:  timestamp,
+		MetricName: "latency",
+		Unit:       mon.UnitMilliseconds,
+		Value:      100.5,
+		Values:     values,
+	})
+
+	cwClient.AssertExpectations(t)
+
+	if assert.Len(t, written, 2) {
+		assert.Len(t, written[0].Values, 150)
+		assert.Len(t, written[1].Values, 50)
+		assert.Equal(t, float64(1), *written[0].Values[0])
+		assert.Equal(t, float64(200), *written[1].Values[49])
+		assert.Nil(t, written[0].Value)
+	}
+}
+
+func TestMetricDatum_Statistics(t *testing.T) {
+	values := make([]float64, 0, 100)
+
+	for i := 100; i > 0; i-- {
+		values = append(values, float64(i))
+	}
+
+	datum := &mon.MetricDatum{
+		Values: values,
+	}
+
+	assert.Equal(t, &mon.MetricStatistics{
+		SampleCount: 100,
+		Sum:         5050,
+		Minimum:     1,
+		Maximum:     100,
+		P50:         50,
+		P90:         90,
+		P99:         99,
+	}, datum.Statistics())
+	assert.Equal(t, float64(100), values[0], "the values should not be sorted in place")
+	assert.Nil(t, (&mon.MetricDatum{Value: 1}).Statistics())
+}
@@ -11,7 +11,8 @@ import (
 
 type esMetricDatum struct {
 	*MetricDatum
-	Namespace string `json:"namespace"`
+	Namespace  string            `json:"namespace"`
+	Statistics *MetricStatistics `json:"statistics,omitempty"`
 }
 
 type esWriter struct {
@@ -76,6 +77,7 @@ func (w esWriter) Write(batch MetricData) {
 		m := esMetricDatum{
 			MetricDatum: batch[i],
 			Namespace:   w.namespace,
+			Statistics:  batch[i].Statistics(),
 		}
 
 		data, err := json.Marshal(m)
@@ -6,8 +6,9 @@ import (
 )
 
 const (
-	MetricWriterTypeCw = "cw"
-	MetricWriterTypeES = "es"
+	MetricWriterTypeCw   = "cw"
+	MetricWriterTypeES   = "es"
+	MetricWriterTypeProm = "prom"
 )
 
 func ProvideMetricWriterByType(config cfg.Config, logger Logger, typ string) MetricWriter {
@@ -16,6 +17,8 @@ func ProvideMetricWriterByType(config cfg.Config, logger Logger, typ string) Met
 		return NewMetricCwWriter(config, logger)
 	case MetricWriterTypeES:
 		return NewMetricEsWriter(config, logger)
+	case MetricWriterTypeProm:
+		return NewMetricPromWriter(config, logger)
 	}
 
 	logger.Fatalf(fmt.Errorf("unknown metric writer type"), "metric writer type of %s not found", typ)
@@ -0,0 +1,122 @@
+package mon
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+)
+
+// PromDefaultBuckets are the upper bounds in seconds of the histograms written for durations
+var PromDefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// PromCountBuckets are the upper bounds of the histograms written for count distributions
+var PromCountBuckets = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000}
+
+// MetricBatchWriter is implemented by writers which need all values of a period instead of their aggregate
+type MetricBatchWriter interface {
+	WriteBatch(batch []*BatchedMetricDatum)
+}
+
+type MetricPromSettings struct {
+	// Namespace is prepended to all metric names, it is omitted if empty
+	Namespace string `cfg:"namespace" default:"{app_project}"`
+}
+
+type promWriter struct {
+	logger    Logger
+	registry  *PromRegistry
+	namespace string
+}
+
+func NewMetricPromWriter(config cfg.Config, logger Logger) *promWriter {
+	settings := &MetricPromSettings{}
+	config.UnmarshalKey("mon.metric.prom", settings)
+
+	registry := ProvidePromRegistry()
+
+	return NewMetricPromWriterWithInterfaces(logger, registry, settings)
+}
+
+func NewMetricPromWriterWithInterfaces(logger Logger, registry *PromRegistry, settings *MetricPromSettings) *promWriter {
+	return &promWriter{
+		logger:    logger.WithChannel("metrics"),
+		registry:  registry,
+		namespace: promName(settings.Namespace),
+	}
+}
+
+func (w *promWriter) GetPriority() int {
+	return PriorityLow
+}
+
+func (w *promWriter) WriteOne(data *MetricDatum) {
+	w.Write(MetricData{data})
+}
+
+func (w *promWriter) Write(batch MetricData) {
+	batched := make([]*BatchedMetricDatum, len(batch))
+
+	for i, datum := range batch {
+		batched[i] = &BatchedMetricDatum{
+			Priority:   datum.Priority,
+			Timestamp:  datum.Timestamp,
+			MetricName: datum.MetricName,
+			Dimensions: datum.Dimensions,
+			Values:     []float64{datum.Value},
+			Unit:       datum.Unit,
+		}
+	}
+
+	w.WriteBatch(batched)
+}
+
+// WriteBatch keeps the semantics of the metric daemon: the values of units without average are summed up by counters
+// and histograms, the averaged units are written as gauges and distributions as histograms. Durations are converted to
+// seconds.
+func (w *promWriter) WriteBatch(batch []*BatchedMetricDatum) {
+	for _, datum := range batch {
+		if err := w.write(datum); err != nil {
+			w.logger.Warnf("can not write metric %s to prometheus: %s", datum.MetricName, err.Error())
+		}
+	}
+}
+
+func (w *promWriter) write(datum *BatchedMetricDatum) error {
+	if len(datum.Values) == 0 {
+		return nil
+	}
+
+	name := promName(datum.MetricName)
+	help := fmt.Sprintf("%s in %s", datum.MetricName, datum.Unit)
+	labels := make(map[string]string, len(datum.Dimensions))
+
+	if w.namespace != "" {
+		name = fmt.Sprintf("%s_%s", w.namespace, name)
+	}
+
+	for dimension, value := range datum.Dimensions {
+		labels[promName(dimension)] = value
+	}
+
+	switch datum.Unit {
+	case UnitCountAverage:
+		return w.registry.Set(name, help, labels, average(datum.Values))
+	case UnitSecondsAverage:
+		return w.registry.Set(name+"_seconds", help, labels, average(datum.Values))
+	case UnitMillisecondsAverage:
+		return w.registry.Set(name+"_seconds", help, labels, average(datum.Values)/1000)
+	case UnitCountDistribution:
+		return w.registry.Observe(name, help, PromCountBuckets, labels, datum.Values...)
+	case UnitSeconds, UnitSecondsDistribution:
+		return w.registry.Observe(name+"_seconds", help, PromDefaultBuckets, labels, datum.Values...)
+	case UnitMilliseconds, UnitMillisecondsDistribution:
+		seconds := make([]float64, len(datum.Values))
+
+		for i, value := range datum.Values {
+			seconds[i] = value / 1000
+		}
+
+		return w.registry.Observe(name+"_seconds", help, PromDefaultBuckets, labels, seconds...)
+	}
+
+	return w.registry.Add(name+"_total", help, labels, sum(datum.Values))
+}
@@ -0,0 +1,79 @@
+package mon_test
+
+import (
+	"bytes"
+	"github.com/applike/gosoline/pkg/mon"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPromWriter_WriteBatch(t *testing.T) {
+	registry := mon.NewPromRegistry()
+	writer := mon.NewMetricPromWriterWithInterfaces(monMocks.NewLoggerMockedAll(), registry, &mon.MetricPromSettings{
+		Namespace: "gosoline",
+	})
+
+	dimensions := mon.MetricDimensions{"StreamName": "events"}
+
+	for i := 0; i < 2; i++ {
+		writer.WriteBatch([]*mon.BatchedMetricDatum{
+			{MetricName: "ConsumerProcessed", Dimensions: dimensions, Unit: mon.UnitCount, Values: []float64{2, 3}},
+			{MetricName: "ConsumerLag", Dimensions: dimensions, Unit: mon.UnitCountAverage, Values: []float64{float64(i), 4}},
+			{MetricName: "ConsumerWait", Dimensions: dimensions, Unit: mon.UnitSecondsAverage, Values: []float64{1, 2}},
+			{MetricName: "ConsumerDuration", Dimensions: dimensions, Unit: mon.UnitMilliseconds, Values: []float64{20, 300}},
+		})
+	}
+
+	expected := `# HELP gosoline_consumer_duration_seconds ConsumerDuration in Milliseconds
+# TYPE gosoline_consumer_duration_seconds histogram
+gosoline_consumer_duration_seconds_bucket{stream_name="events",le="0.005"} 0
+gosoline_consumer_duration_seconds_bucket{stream_name="events",le="0.01"} 0
+gosoline_consumer_duration_seconds_bucket{stream_name="events",le="0.025"} 2
+gosoline_consumer_duration_seconds_bucket{stream_name="events",le="0.05"} 2
+gosoline_consumer_duration_seconds_bucket{stream_name="events",le="0.1"} 2
+gosoline_consumer_duration_seconds_bucket{stream_name="events",le="0.25"} 2
+gosoline_consumer_duration_seconds_bucket{stream_name="events",le="0.5"} 4
+gosoline_consumer_duration_seconds_bucket{stream_name="events",le="1"} 4
+gosoline_consumer_duration_seconds_bucket{stream_name="events",le="2.5"} 4
+gosoline_consumer_duration_seconds_bucket{stream_name="events",le="5"} 4
+gosoline_consumer_duration_seconds_bucket{stream_name="events",le="10"} 4
+gosoline_consumer_duration_seconds_bucket{stream_name="events",le="+Inf"} 4
+gosoline_consumer_duration_seconds_sum{stream_name="events"} 0.64
+gosoline_consumer_duration_seconds_count{stream_name="events"} 4
+# HELP gosoline_consumer_lag ConsumerLag in UnitCountAverage
+# TYPE gosoline_consumer_lag gauge
+gosoline_consumer_lag{stream_name="events"} 2.5
+# HELP gosoline_consumer_processed_total ConsumerProcessed in Count
+# TYPE gosoline_consumer_processed_total counter
+gosoline_consumer_processed_total{stream_name="events"} 10
+# HELP gosoline_consumer_wait_seconds ConsumerWait in UnitSecondsAverage
+# TYPE gosoline_consumer_wait_seconds gauge
+gosoline_consumer_wait_seconds{stream_name="events"} 1.5
+`
+
+	buf := bytes.NewBuffer(nil)
+	err := registry.Expose(buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestPromWriter_WriteConflictingLabels(t *testing.T) {
+	registry := mon.NewPromRegistry()
+	logger := monMocks.NewLoggerMockedAll()
+	writer := mon.NewMetricPromWriterWithInterfaces(logger, registry, &mon.MetricPromSettings{})
+
+	writer.Write(mon.MetricData{
+		{MetricName: "ApiRequestCount", Dimensions: mon.MetricDimensions{"path": "/"}, Unit: mon.UnitCount, Value: 1},
+		{MetricName: "ApiRequestCount", Dimensions: mon.MetricDimensions{"method": "GET"}, Unit: mon.UnitCount, Value: 1},
+		{MetricName: "api-request-count", Dimensions: mon.MetricDimensions{"path": "/"}, Unit: mon.UnitCount, Value: 2},
+	})
+
+	buf := bytes.NewBuffer(nil)
+	err := registry.Expose(buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "# HELP api_request_count_total ApiRequestCount in Count\n# TYPE api_request_count_total counter\napi_request_count_total{path=\"/\"} 3\n", buf.String())
+	logger.AssertCalled(t, "Warnf", "can not write metric %s to prometheus: %s", "ApiRequestCount", "metric api_request_count_total has the labels [path] and can not be used with [method]")
+}
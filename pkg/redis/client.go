@@ -34,6 +34,7 @@ type Client interface {
 	Get(key string) (string, error)
 	MGet(keys ...string) ([]interface{}, error)
 	Del(keys ...string) (int64, error)
+	Eval(script string, keys []string, args ...interface{}) (interface{}, error)
 
 	BLPop(timeout time.Duration, keys ...string) ([]string, error)
 	LPop(key string) (string, error)
@@ -194,6 +195,14 @@ func (c *redisClient) Del(keys ...string) (int64, error) {
 	return cmd.(*baseRedis.IntCmd).Val(), err
 }
 
+func (c *redisClient) Eval(script string, keys []string, args ...interface{}) (interface{}, error) {
+	cmd, err := c.execute(func() ErrCmder {
+		return c.base.Eval(script, keys, args...)
+	})
+
+	return cmd.(*baseRedis.Cmd).Val(), err
+}
+
 func (c *redisClient) BLPop(timeout time.Duration, keys ...string) ([]string, error) {
 	cmd, err := c.execute(func() ErrCmder {
 		return c.base.BLPop(timeout, keys...)
@@ -9,6 +9,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 )
 
+const fifoSuffix = ".fifo"
+
 var namingStrategy = func(appId cfg.AppId, topicId string) string {
 	return fmt.Sprintf("%v-%v-%v-%v-%v", appId.Project, appId.Environment, appId.Family, appId.Application, topicId)
 }
@@ -17,9 +19,19 @@ func WithNamingStrategy(strategy func(appId cfg.AppId, topicId string) string) {
 	namingStrategy = strategy
 }
 
-func CreateTopic(logger mon.Logger, client snsiface.SNSAPI, s *Settings) (string, error) {
+func TopicName(s *Settings) string {
 	name := namingStrategy(s.AppId, s.TopicId)
 
+	if s.Fifo.Enabled {
+		name = name + fifoSuffix
+	}
+
+	return name
+}
+
+func CreateTopic(logger mon.Logger, client snsiface.SNSAPI, s *Settings) (string, error) {
+	name := TopicName(s)
+
 	logger.WithFields(mon.Fields{
 		"name": name,
 	}).Info("looking for sns topic")
@@ -27,6 +39,16 @@ func CreateTopic(logger mon.Logger, client snsiface.SNSAPI, s *Settings) (string
 	input := &sns.CreateTopicInput{
 		Name: aws.String(name),
 	}
+
+	if s.Fifo.Enabled {
+		input.Attributes = map[string]*string{
+			"FifoTopic": aws.String("true"),
+		}
+	}
+
+	if s.Fifo.Enabled && s.Fifo.ContentBasedDeduplication {
+		input.Attributes["ContentBasedDeduplication"] = aws.String("true")
+	}
 	out, err := client.CreateTopic(input)
 
 	if err != nil {
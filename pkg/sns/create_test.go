@@ -40,6 +40,42 @@ func TestCreateTopic(t *testing.T) {
 	client.AssertExpectations(t)
 }
 
+func TestCreateTopicFifo(t *testing.T) {
+	s := &sns.Settings{
+		AppId: cfg.AppId{
+			Project:     "mcoins",
+			Environment: "test",
+			Family:      "analytics",
+			Application: "topicker",
+		},
+		TopicId: "topic",
+		Fifo: sns.FifoSettings{
+			Enabled:                   true,
+			ContentBasedDeduplication: true,
+		},
+	}
+
+	client := new(snsMocks.Client)
+	client.On("CreateTopic", &awsSns.CreateTopicInput{
+		Name: aws.String("mcoins-test-analytics-topicker-topic.fifo"),
+		Attributes: map[string]*string{
+			"FifoTopic":                 aws.String("true"),
+			"ContentBasedDeduplication": aws.String("true"),
+		},
+	}).Return(&awsSns.CreateTopicOutput{
+		TopicArn: aws.String("arn.fifo"),
+	}, nil)
+
+	logger := mocks.NewLoggerMockedAll()
+
+	arn, err := sns.CreateTopic(logger, client, s)
+
+	assert.Equal(t, "arn.fifo", arn)
+	assert.NoError(t, err)
+
+	client.AssertExpectations(t)
+}
+
 func TestCreateTopicFailing(t *testing.T) {
 	s := &sns.Settings{
 		AppId: cfg.AppId{
@@ -0,0 +1,81 @@
+package sns
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/sqs"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/spf13/cast"
+)
+
+// fifoPublishInput is the publish input extended by the fifo parameters, which the used version of the aws sdk
+// doesn't know yet. It replaces the parameters of a publish request and is marshalled by the query protocol of
+// the sdk like its own input.
+type fifoPublishInput struct {
+	_ struct{} `type:"structure"`
+
+	Message                *string                               `type:"string" required:"true"`
+	MessageAttributes      map[string]*sns.MessageAttributeValue `locationNameKey:"Name" locationNameValue:"Value" type:"map"`
+	MessageDeduplicationId *string                               `type:"string"`
+	MessageGroupId         *string                               `type:"string"`
+	MessageStructure       *string                               `type:"string"`
+	PhoneNumber            *string                               `type:"string"`
+	Subject                *string                               `type:"string"`
+	TargetArn              *string                               `type:"string"`
+	TopicArn               *string                               `type:"string"`
+}
+
+func (s *fifoPublishInput) Validate() error {
+	input := &sns.PublishInput{
+		Message:           s.Message,
+		MessageAttributes: s.MessageAttributes,
+	}
+
+	return input.Validate()
+}
+
+// buildFifoPublishInput reads the group and deduplication id of a message from the same attributes the sqs queues use
+func buildFifoPublishInput(settings FifoSettings, input *sns.PublishInput, attributes []map[string]interface{}) (*fifoPublishInput, error) {
+	fifoInput := &fifoPublishInput{
+		Message:           input.Message,
+		MessageAttributes: input.MessageAttributes,
+		MessageStructure:  input.MessageStructure,
+		PhoneNumber:       input.PhoneNumber,
+		Subject:           input.Subject,
+		TargetArn:         input.TargetArn,
+		TopicArn:          input.TopicArn,
+	}
+
+	sources := map[string]**string{
+		sqs.AttributeSqsMessageGroupId:         &fifoInput.MessageGroupId,
+		sqs.AttributeSqsMessageDeduplicationId: &fifoInput.MessageDeduplicationId,
+	}
+
+	for _, attrs := range attributes {
+		for attribute, parameter := range sources {
+			value, ok := attrs[attribute]
+
+			if !ok {
+				continue
+			}
+
+			str, err := cast.ToStringE(value)
+
+			if err != nil {
+				return nil, fmt.Errorf("the attribute %s is not a valid string: %w", attribute, err)
+			}
+
+			*parameter = aws.String(str)
+		}
+	}
+
+	if aws.StringValue(fifoInput.MessageGroupId) == "" {
+		return nil, fmt.Errorf("the message has no attribute %s", sqs.AttributeSqsMessageGroupId)
+	}
+
+	if aws.StringValue(fifoInput.MessageDeduplicationId) == "" && !settings.ContentBasedDeduplication {
+		return nil, fmt.Errorf("the message has no attribute %s and content based deduplication is disabled", sqs.AttributeSqsMessageDeduplicationId)
+	}
+
+	return fifoInput, nil
+}
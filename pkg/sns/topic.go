@@ -14,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sns/snsiface"
 	"github.com/thoas/go-funk"
+	"strings"
 )
 
 //go:generate mockery -name Topic
@@ -22,10 +23,16 @@ type Topic interface {
 	SubscribeSqs(queueArn string, attributes map[string]interface{}) error
 }
 
+type FifoSettings struct {
+	Enabled                   bool `cfg:"enabled" default:"false"`
+	ContentBasedDeduplication bool `cfg:"content_based_deduplication" default:"false"`
+}
+
 type Settings struct {
 	cfg.AppId
 	TopicId string
 	Arn     string
+	Fifo    FifoSettings
 	Client  cloud.ClientSettings
 	Backoff exec.BackoffSettings
 }
@@ -52,7 +59,7 @@ func NewTopic(config cfg.Config, logger mon.Logger, settings *Settings) *snsTopi
 
 	res := &exec.ExecutableResource{
 		Type: "sns",
-		Name: namingStrategy(settings.AppId, settings.TopicId),
+		Name: TopicName(settings),
 	}
 	executor := gosoAws.NewExecutor(logger, res, &settings.Backoff)
 
@@ -81,8 +88,22 @@ func (t *snsTopic) Publish(ctx context.Context, msg *string, attributes ...map[s
 		MessageAttributes: inputAttributes,
 	}
 
+	var fifoInput *fifoPublishInput
+
+	if t.settings.Fifo.Enabled {
+		if fifoInput, err = buildFifoPublishInput(t.settings.Fifo, input, attributes); err != nil {
+			return fmt.Errorf("can not publish to fifo topic %s: %w", t.settings.Arn, err)
+		}
+	}
+
 	_, err = t.executor.Execute(ctx, func() (*request.Request, interface{}) {
-		return t.client.PublishRequest(input)
+		req, out := t.client.PublishRequest(input)
+
+		if req != nil && fifoInput != nil {
+			req.Params = fifoInput
+		}
+
+		return req, out
 	})
 
 	if exec.IsRequestCanceled(err) {
@@ -97,6 +118,14 @@ func (t *snsTopic) Publish(ctx context.Context, msg *string, attributes ...map[s
 }
 
 func (t *snsTopic) SubscribeSqs(queueArn string, attributes map[string]interface{}) error {
+	if t.settings.Fifo.Enabled && !strings.HasSuffix(queueArn, fifoSuffix) {
+		return fmt.Errorf("the fifo topic %s can only be subscribed by fifo queues but %s is a standard queue", t.settings.Arn, queueArn)
+	}
+
+	if !t.settings.Fifo.Enabled && strings.HasSuffix(queueArn, fifoSuffix) {
+		return fmt.Errorf("the fifo queue %s can only subscribe to fifo topics but %s is a standard topic", queueArn, t.settings.Arn)
+	}
+
 	exists, err := t.subscriptionExists(queueArn, attributes)
 
 	if err != nil {
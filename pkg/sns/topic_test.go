@@ -8,9 +8,14 @@ import (
 	"github.com/applike/gosoline/pkg/sns"
 	snsMocks "github.com/applike/gosoline/pkg/sns/mocks"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
 	awsSns "github.com/aws/aws-sdk-go/service/sns"
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"io/ioutil"
+	"net/url"
 	"testing"
 )
 
@@ -186,6 +191,84 @@ func (s *TopicTestSuite) TestSubscribeSqsError() {
 	s.client.AssertExpectations(s.T())
 }
 
+func TestPublishFifo(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	client := new(snsMocks.Client)
+	executor := gosoAws.NewTestableExecutor(&client.Mock, gosoAws.TestExecution{})
+
+	topic := sns.NewTopicWithInterfaces(logger, client, executor, &sns.Settings{
+		Arn: "topicArn.fifo",
+		Fifo: sns.FifoSettings{
+			Enabled: true,
+		},
+	})
+
+	input := &awsSns.PublishInput{
+		TopicArn: aws.String("topicArn.fifo"),
+		Message:  aws.String("test"),
+		MessageAttributes: map[string]*awsSns.MessageAttributeValue{
+			"sqsMessageGroupId": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String("group"),
+			},
+			"sqsMessageDeduplicationId": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String("dedup"),
+			},
+		},
+	}
+
+	sess := session.Must(session.NewSession(&aws.Config{
+		Region:      aws.String("eu-central-1"),
+		Credentials: credentials.NewStaticCredentials("id", "secret", ""),
+	}))
+	req, out := awsSns.New(sess).PublishRequest(input)
+	client.On("PublishRequest", input).Return(req, out).Once()
+
+	err := topic.Publish(context.Background(), aws.String("test"), map[string]interface{}{
+		"sqsMessageGroupId":         "group",
+		"sqsMessageDeduplicationId": "dedup",
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, req.Build())
+	body, err := ioutil.ReadAll(req.GetBody())
+	assert.NoError(t, err)
+
+	values, err := url.ParseQuery(string(body))
+	assert.NoError(t, err)
+	assert.Equal(t, "group", values.Get("MessageGroupId"))
+	assert.Equal(t, "dedup", values.Get("MessageDeduplicationId"))
+
+	err = topic.Publish(context.Background(), aws.String("test"), map[string]interface{}{
+		"sqsMessageGroupId": "group",
+	})
+	assert.EqualError(t, err, "can not publish to fifo topic topicArn.fifo: the message has no attribute sqsMessageDeduplicationId and content based deduplication is disabled")
+
+	client.AssertExpectations(t)
+}
+
+func TestSubscribeSqsFifoMismatch(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	client := new(snsMocks.Client)
+	executor := gosoAws.NewTestableExecutor(&client.Mock)
+
+	fifoTopic := sns.NewTopicWithInterfaces(logger, client, executor, &sns.Settings{
+		Arn: "topicArn.fifo",
+		Fifo: sns.FifoSettings{
+			Enabled: true,
+		},
+	})
+	err := fifoTopic.SubscribeSqs("queueArn", map[string]interface{}{})
+	assert.EqualError(t, err, "the fifo topic topicArn.fifo can only be subscribed by fifo queues but queueArn is a standard queue")
+
+	topic := sns.NewTopicWithInterfaces(logger, client, executor, &sns.Settings{
+		Arn: "topicArn",
+	})
+	err = topic.SubscribeSqs("queueArn.fifo", map[string]interface{}{})
+	assert.EqualError(t, err, "the fifo queue queueArn.fifo can only subscribe to fifo topics but topicArn is a standard topic")
+}
+
 func TestTopicTestSuite(t *testing.T) {
 	suite.Run(t, new(TopicTestSuite))
 }
@@ -200,6 +200,7 @@ func (q *queue) Receive(ctx context.Context, maxNumberOfMessages int64, waitTime
 	logger := q.logger.WithContext(ctx)
 
 	input := &sqs.ReceiveMessageInput{
+		AttributeNames:        []*string{aws.String(sqs.MessageSystemAttributeNameMessageGroupId)},
 		MessageAttributeNames: []*string{aws.String("ALL")},
 		MaxNumberOfMessages:   aws.Int64(maxNumberOfMessages),
 		QueueUrl:              aws.String(q.properties.Url),
@@ -3,12 +3,20 @@ package stream
 import (
 	"bytes"
 	"compress/gzip"
+	"encoding/binary"
 	"fmt"
+	"github.com/DataDog/zstd"
+	"github.com/bkaradzic/go-lz4"
+	"github.com/golang/snappy"
+	"github.com/pierrec/xxHash/xxHash32"
 )
 
 const (
-	CompressionNone = "none"
-	CompressionGZip = "application/gzip"
+	CompressionNone   = "none"
+	CompressionGZip   = "application/gzip"
+	CompressionZstd   = "application/zstd"
+	CompressionSnappy = "application/x-snappy"
+	CompressionLz4    = "application/x-lz4"
 )
 
 type MessageBodyCompressor interface {
@@ -17,8 +25,27 @@ type MessageBodyCompressor interface {
 }
 
 var messageBodyCompressors = map[string]MessageBodyCompressor{
-	CompressionNone: new(noopCompressor),
-	CompressionGZip: new(gZipCompressor),
+	CompressionNone:   new(noopCompressor),
+	CompressionGZip:   new(gZipCompressor),
+	CompressionZstd:   new(zstdCompressor),
+	CompressionSnappy: new(snappyCompressor),
+	CompressionLz4:    new(lz4Compressor),
+}
+
+func AddMessageBodyCompressor(compression string, compressor MessageBodyCompressor) {
+	messageBodyCompressors[compression] = compressor
+}
+
+func checkCompression(compression string) error {
+	if compression == "" {
+		return nil
+	}
+
+	if _, ok := messageBodyCompressors[compression]; !ok {
+		return fmt.Errorf("there is no compressor for compression '%s'", compression)
+	}
+
+	return nil
 }
 
 type noopCompressor struct {
@@ -78,3 +105,305 @@ func (g gZipCompressor) Decompress(body []byte) ([]byte, error) {
 
 	return uncompressed, nil
 }
+
+type zstdCompressor struct {
+}
+
+func (z zstdCompressor) Compress(body []byte) ([]byte, error) {
+	if body == nil {
+		return body, nil
+	}
+
+	compressed, err := zstd.Compress(nil, body)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not compress body with zstd: %w", err)
+	}
+
+	return compressed, nil
+}
+
+func (z zstdCompressor) Decompress(body []byte) ([]byte, error) {
+	if body == nil {
+		return body, nil
+	}
+
+	uncompressed, err := zstd.Decompress(nil, body)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not decompress body with zstd: %w", err)
+	}
+
+	return uncompressed, nil
+}
+
+type snappyCompressor struct {
+}
+
+func (s snappyCompressor) Compress(body []byte) ([]byte, error) {
+	if body == nil {
+		return body, nil
+	}
+
+	return snappy.Encode(nil, body), nil
+}
+
+func (s snappyCompressor) Decompress(body []byte) ([]byte, error) {
+	if body == nil {
+		return body, nil
+	}
+
+	uncompressed, err := snappy.Decode(nil, body)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not decompress body with snappy: %w", err)
+	}
+
+	return uncompressed, nil
+}
+
+// lz4Compressor writes the lz4 frame format with independent blocks and a content checksum, so
+// the bodies can be read by every lz4 implementation. The blocks are compressed with go-lz4.
+type lz4Compressor struct {
+}
+
+const (
+	lz4FrameMagic        = 0x184D2204
+	lz4FrameVersion      = 0x40
+	lz4FrameIndependent  = 0x20
+	lz4FrameBlockSum     = 0x10
+	lz4FrameContentSize  = 0x08
+	lz4FrameContentSum   = 0x04
+	lz4FrameDictId       = 0x01
+	lz4FrameUncompressed = 0x80000000
+	lz4BlockMaxSize4M    = 0x70
+)
+
+var lz4BlockMaxSizes = map[byte]int{
+	0x40: 64 << 10,
+	0x50: 256 << 10,
+	0x60: 1 << 20,
+	0x70: 4 << 20,
+}
+
+func (l lz4Compressor) Compress(body []byte) ([]byte, error) {
+	if body == nil {
+		return body, nil
+	}
+
+	descriptor := []byte{lz4FrameVersion | lz4FrameIndependent | lz4FrameContentSum, lz4BlockMaxSize4M}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(body)/2+16))
+	_ = binary.Write(out, binary.LittleEndian, uint32(lz4FrameMagic))
+	out.Write(descriptor)
+	out.WriteByte(byte(xxHash32.Checksum(descriptor, 0) >> 8))
+
+	blockMaxSize := lz4BlockMaxSizes[lz4BlockMaxSize4M]
+
+	for pos := 0; pos < len(body); pos += blockMaxSize {
+		end := pos + blockMaxSize
+
+		if end > len(body) {
+			end = len(body)
+		}
+
+		block := body[pos:end]
+		compressed, err := lz4.Encode(nil, block)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not compress body with lz4: %w", err)
+		}
+
+		// go-lz4 prefixes the block with its uncompressed size which is not part of the frame format
+		compressed = compressed[4:]
+
+		if len(compressed) >= len(block) {
+			_ = binary.Write(out, binary.LittleEndian, uint32(len(block))|lz4FrameUncompressed)
+			out.Write(block)
+			continue
+		}
+
+		_ = binary.Write(out, binary.LittleEndian, uint32(len(compressed)))
+		out.Write(compressed)
+	}
+
+	_ = binary.Write(out, binary.LittleEndian, uint32(0))
+	_ = binary.Write(out, binary.LittleEndian, xxHash32.Checksum(body, 0))
+
+	return out.Bytes(), nil
+}
+
+func (l lz4Compressor) Decompress(body []byte) ([]byte, error) {
+	if body == nil {
+		return body, nil
+	}
+
+	if len(body) < 7 || binary.LittleEndian.Uint32(body) != lz4FrameMagic {
+		return nil, fmt.Errorf("can not decompress body with lz4: the body is no lz4 frame")
+	}
+
+	flags := body[4]
+	blockMaxSize, ok := lz4BlockMaxSizes[body[5]&0x70]
+
+	if flags&0xC0 != lz4FrameVersion || !ok {
+		return nil, fmt.Errorf("can not decompress body with lz4: unsupported frame descriptor %x", body[4:6])
+	}
+
+	if flags&lz4FrameDictId != 0 {
+		return nil, fmt.Errorf("can not decompress body with lz4: frames with a dictionary are not supported")
+	}
+
+	headerSize := 6
+
+	if flags&lz4FrameContentSize != 0 {
+		headerSize += 8
+	}
+
+	if len(body) < headerSize+1 {
+		return nil, fmt.Errorf("can not decompress body with lz4: the frame header is truncated")
+	}
+
+	if byte(xxHash32.Checksum(body[4:headerSize], 0)>>8) != body[headerSize] {
+		return nil, fmt.Errorf("can not decompress body with lz4: the checksum of the frame header does not match")
+	}
+
+	pos := headerSize + 1
+	out := make([]byte, 0, len(body)*2)
+
+	for {
+		if pos+4 > len(body) {
+			return nil, fmt.Errorf("can not decompress body with lz4: the frame is truncated")
+		}
+
+		size := binary.LittleEndian.Uint32(body[pos:])
+		pos += 4
+
+		if size == 0 {
+			break
+		}
+
+		length := int(size &^ lz4FrameUncompressed)
+
+		if pos+length > len(body) {
+			return nil, fmt.Errorf("can not decompress body with lz4: the frame is truncated")
+		}
+
+		block := body[pos:(pos + length)]
+		pos += length
+
+		if flags&lz4FrameBlockSum != 0 {
+			pos += 4
+		}
+
+		if size&lz4FrameUncompressed != 0 {
+			out = append(out, block...)
+			continue
+		}
+
+		var err error
+
+		if out, err = lz4DecodeBlock(out, block, blockMaxSize, flags&lz4FrameIndependent != 0); err != nil {
+			return nil, fmt.Errorf("can not decompress body with lz4: %w", err)
+		}
+	}
+
+	if flags&lz4FrameContentSum != 0 {
+		if pos+4 > len(body) {
+			return nil, fmt.Errorf("can not decompress body with lz4: the frame is truncated")
+		}
+
+		if binary.LittleEndian.Uint32(body[pos:]) != xxHash32.Checksum(out, 0) {
+			return nil, fmt.Errorf("can not decompress body with lz4: the checksum of the content does not match")
+		}
+	}
+
+	return out, nil
+}
+
+// lz4DecodeBlock appends the decoded lz4 block to dst. go-lz4 can not be used for decoding as it
+// requires the exact uncompressed size of a block, which is not part of the frame format. Matches of
+// dependent blocks may reference the data of the previous blocks.
+func lz4DecodeBlock(dst []byte, block []byte, maxSize int, independent bool) ([]byte, error) {
+	start := len(dst)
+	window := 0
+	pos := 0
+
+	if independent {
+		window = start
+	}
+
+	readLength := func(length int) (int, error) {
+		if length != 15 {
+			return length, nil
+		}
+
+		for {
+			if pos >= len(block) {
+				return 0, fmt.Errorf("the block is truncated")
+			}
+
+			b := block[pos]
+			pos++
+			length += int(b)
+
+			if b != 255 {
+				return length, nil
+			}
+		}
+	}
+
+	for pos < len(block) {
+		token := block[pos]
+		pos++
+
+		literals, err := readLength(int(token >> 4))
+
+		if err != nil {
+			return nil, err
+		}
+
+		if pos+literals > len(block) {
+			return nil, fmt.Errorf("the block is truncated")
+		}
+
+		dst = append(dst, block[pos:pos+literals]...)
+		pos += literals
+
+		// the last sequence of a block consists of literals only
+		if pos == len(block) {
+			break
+		}
+
+		if pos+2 > len(block) {
+			return nil, fmt.Errorf("the block is truncated")
+		}
+
+		offset := int(block[pos]) | int(block[pos+1])<<8
+		pos += 2
+
+		if offset == 0 || offset > len(dst)-window {
+			return nil, fmt.Errorf("the block contains an invalid offset %d", offset)
+		}
+
+		match, err := readLength(int(token & 0x0F))
+
+		if err != nil {
+			return nil, err
+		}
+
+		match += 4
+
+		if len(dst)-start+match > maxSize {
+			return nil, fmt.Errorf("the block exceeds the maximum block size")
+		}
+
+		// the match may overlap with the bytes it is copying, so it has to be copied byte by byte
+		ref := len(dst) - offset
+
+		for i := 0; i < match; i++ {
+			dst = append(dst, dst[ref+i])
+		}
+	}
+
+	return dst, nil
+}
@@ -68,12 +68,14 @@ func (c *Consumer) run(ctx context.Context) error {
 	defer c.logger.Debug("runConsuming is ending")
 	defer c.wg.Done()
 
+	data := c.dispatcher.runnerData()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return fmt.Errorf("return from consuming as the coffin is dying")
 
-		case msg, ok := <-c.input.Data():
+		case msg, ok := <-data:
 			if !ok {
 				return nil
 			}
@@ -123,31 +125,75 @@ func (c *Consumer) processSingleMessage(ctx context.Context, msg *Message) {
 	c.writeMetrics(duration, 1)
 }
 
-func (c *Consumer) process(ctx context.Context, msg *Message) bool {
+func (c *Consumer) process(ctx context.Context, msg *Message) (ack bool) {
 	defer c.recover()
 
 	var err error
-	var ack bool
+	var msgCtx context.Context
 	var model interface{}
 	var attributes map[string]interface{}
 
-	if model = c.callback.GetModel(msg.Attributes); model == nil {
-		err := fmt.Errorf("can not get model for message attributes %v", msg.Attributes)
-		c.handleError(ctx, err, "an error occurred during the consume operation")
+	switch c.dedup.claim(ctx, msg) {
+	case dedupResultConsumed:
+		return true
+	case dedupResultInProgress:
 		return false
 	}
 
+	defer func() {
+		if !ack {
+			c.dedup.release(ctx, msg)
+		}
+	}()
+
+	for attempt := 1; ; attempt++ {
+		// decode a fresh copy on every attempt as decoding strips attributes from the message
+		// and the callback might have modified the model during the previous attempt
+		if msgCtx, model, attributes, err = c.decode(ctx, copyMessage(msg)); err != nil {
+			c.handleError(ctx, err, "an error occurred during the decode operation")
+			return c.retry.deadLetter(ctx, msg, err, attempt)
+		}
+
+		if ack, err = c.consume(msgCtx, model, attributes); err == nil {
+			if ack {
+				c.dedup.record(ctx, msg)
+			}
+
+			return ack
+		}
+
+		c.handleError(msgCtx, err, "an error occurred during the consume operation")
+
+		// without retries the message is acknowledged as requested by the callback like before
+		if !c.retry.settings.Enabled {
+			return ack
+		}
+
+		if !c.retry.wait(ctx, attempt) {
+			return c.retry.deadLetter(ctx, msg, err, attempt)
+		}
+	}
+}
+
+func (c *Consumer) decode(ctx context.Context, msg *Message) (context.Context, interface{}, map[string]interface{}, error) {
+	var err error
+	var model interface{}
+	var attributes map[string]interface{}
+
+	if model = c.callback.GetModel(msg.Attributes); model == nil {
+		return ctx, nil, nil, fmt.Errorf("can not get model for message attributes %v", msg.Attributes)
+	}
+
 	if ctx, attributes, err = c.encoder.Decode(ctx, msg, model); err != nil {
-		c.handleError(ctx, err, "an error occurred during the consume operation")
-		return false
+		return ctx, nil, nil, err
 	}
 
+	return ctx, model, attributes, nil
+}
+
+func (c *Consumer) consume(ctx context.Context, model interface{}, attributes map[string]interface{}) (bool, error) {
 	ctx, span := c.tracer.StartSpanFromContext(ctx, c.id)
 	defer span.Finish()
 
-	if ack, err = c.callback.Consume(ctx, model, attributes); err != nil {
-		c.handleError(ctx, err, "an error occurred during the consume operation")
-	}
-
-	return ack
+	return c.callback.Consume(ctx, model, attributes)
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/applike/gosoline/pkg/clock"
 	"github.com/applike/gosoline/pkg/coffin"
 	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/kvstore"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/applike/gosoline/pkg/tracing"
 	"sync"
@@ -30,10 +31,30 @@ type BaseConsumerCallback interface {
 }
 
 type ConsumerSettings struct {
-	Input       string        `cfg:"input" default:"consumer" validate:"required"`
-	RunnerCount int           `cfg:"runner_count" default:"1" validate:"min=1"`
-	Encoding    string        `cfg:"encoding" default:"application/json"`
-	IdleTimeout time.Duration `cfg:"idle_timeout" default:"10s"`
+	Input       string                   `cfg:"input" default:"consumer" validate:"required"`
+	RunnerCount int                      `cfg:"runner_count" default:"1" validate:"min=1"`
+	Encoding    string                   `cfg:"encoding" default:"application/json"`
+	IdleTimeout time.Duration            `cfg:"idle_timeout" default:"10s"`
+	Retry       ConsumerRetrySettings    `cfg:"retry"`
+	Dedup       ConsumerDedupSettings    `cfg:"dedup"`
+	Ordering    ConsumerOrderingSettings `cfg:"ordering"`
+}
+
+// BaseConsumerOption sets an optional dependency of the base consumer
+type BaseConsumerOption func(c *baseConsumer)
+
+// WithDeadLetterOutput writes the messages which failed all retries to the output
+func WithDeadLetterOutput(output Output) BaseConsumerOption {
+	return func(c *baseConsumer) {
+		c.retry.output = output
+	}
+}
+
+// WithDedupStore skips the messages which were already consumed by recording their ids in the store
+func WithDedupStore(store kvstore.KvStore) BaseConsumerOption {
+	return func(c *baseConsumer) {
+		c.dedup.store = store
+	}
 }
 
 type baseConsumer struct {
@@ -46,6 +67,9 @@ type baseConsumer struct {
 	metricWriter mon.MetricWriter
 	tracer       tracing.Tracer
 	encoder      MessageEncoder
+	retry        *consumerRetryHandler
+	dedup        *consumerDedupHandler
+	dispatcher   *consumerDispatcher
 
 	wg     sync.WaitGroup
 	cancel context.CancelFunc
@@ -63,6 +87,9 @@ func NewBaseConsumer(config cfg.Config, logger mon.Logger, name string, consumer
 	tracer := tracing.ProviderTracer(config, logger)
 
 	defaultMetrics := getConsumerDefaultMetrics(name, settings.RunnerCount)
+	defaultMetrics = append(defaultMetrics, getConsumerRetryDefaultMetrics(name, settings.Retry)...)
+	defaultMetrics = append(defaultMetrics, getConsumerDedupDefaultMetrics(name, settings.Dedup)...)
+	defaultMetrics = append(defaultMetrics, getConsumerOrderingDefaultMetrics(name, settings.RunnerCount, settings.Ordering)...)
 	metricWriter := mon.NewMetricDaemonWriter(defaultMetrics...)
 
 	input, err := NewConfigurableInput(config, logger, settings.Input)
@@ -70,27 +97,42 @@ func NewBaseConsumer(config cfg.Config, logger mon.Logger, name string, consumer
 		return nil, err
 	}
 
+	var deadLetterOutput Output
+
+	if settings.Retry.Enabled && settings.Retry.DeadLetterOutput != "" {
+		if deadLetterOutput, err = NewConfigurableOutput(config, logger, settings.Retry.DeadLetterOutput); err != nil {
+			return nil, fmt.Errorf("can not create dead letter output %s: %w", settings.Retry.DeadLetterOutput, err)
+		}
+	}
+
+	var dedupStore kvstore.KvStore
+
+	if settings.Dedup.Enabled {
+		if settings.Dedup.Store == "" {
+			return nil, fmt.Errorf("the dedup of consumer %s requires a kvstore", name)
+		}
+
+		if dedupStore, err = kvstore.NewConfigurableKvStore(config, logger, settings.Dedup.Store); err != nil {
+			return nil, fmt.Errorf("can not create dedup kvstore %s: %w", settings.Dedup.Store, err)
+		}
+	}
+
 	encoder := NewMessageEncoder(&MessageEncoderSettings{
 		Encoding: settings.Encoding,
 	})
 
-	return NewBaseConsumerWithInterfaces(logger, metricWriter, tracer, input, encoder, consumerCallback, settings, name, appId), nil
+	options := []BaseConsumerOption{
+		WithDeadLetterOutput(deadLetterOutput),
+		WithDedupStore(dedupStore),
+	}
+
+	return NewBaseConsumerWithInterfaces(logger, metricWriter, tracer, input, encoder, consumerCallback, settings, name, appId, options...), nil
 }
 
-func NewBaseConsumerWithInterfaces(
-	logger mon.Logger,
-	metricWriter mon.MetricWriter,
-	tracer tracing.Tracer,
-	input Input,
-	encoder MessageEncoder,
-	consumerCallback interface{},
-	settings *ConsumerSettings,
-	name string,
-	appId cfg.AppId,
-) *baseConsumer {
+func NewBaseConsumerWithInterfaces(logger mon.Logger, metricWriter mon.MetricWriter, tracer tracing.Tracer, input Input, encoder MessageEncoder, consumerCallback interface{}, settings *ConsumerSettings, name string, appId cfg.AppId, options ...BaseConsumerOption) *baseConsumer {
 	logger = logger.WithChannel("consumer")
 
-	return &baseConsumer{
+	consumer := &baseConsumer{
 		name:                name,
 		id:                  fmt.Sprintf("consumer-%s-%s-%s", appId.Family, appId.Application, name),
 		logger:              logger,
@@ -98,10 +140,19 @@ func NewBaseConsumerWithInterfaces(
 		tracer:              tracer,
 		ConsumerAcknowledge: NewConsumerAcknowledgeWithInterfaces(logger, input),
 		encoder:             encoder,
+		retry:               newConsumerRetryHandler(logger, metricWriter, nil, name, settings.Retry),
+		dedup:               newConsumerDedupHandler(logger, metricWriter, nil, name, settings.Dedup),
+		dispatcher:          newConsumerDispatcher(logger, metricWriter, input, name, settings.RunnerCount, settings.Ordering),
 		settings:            settings,
 		consumerCallback:    consumerCallback,
 		clock:               clock.Provider,
 	}
+
+	for _, option := range options {
+		option(consumer)
+	}
+
+	return consumer
 }
 
 func (c *baseConsumer) run(kernelCtx context.Context, inputRunner func(ctx context.Context) error) error {
@@ -121,6 +172,10 @@ func (c *baseConsumer) run(kernelCtx context.Context, inputRunner func(ctx conte
 	// die just because Run() immediately returns
 	cfn.GoWithContextf(dyingCtx, c.input.Run, "panic during run of the consumer input")
 
+	if c.settings.Ordering.Enabled {
+		cfn.GoWithContextf(manualCtx, c.dispatcher.run, "panic during dispatching")
+	}
+
 	c.wg.Add(c.settings.RunnerCount)
 	cfn.Go(c.stopConsuming)
 
@@ -159,6 +214,8 @@ func (c *baseConsumer) logConsumeCounter(ctx context.Context) error {
 				"count": processed,
 				"name":  c.name,
 			}).Infof("processed %v messages", processed)
+
+			c.dispatcher.writeQueueDepthMetrics()
 		}
 	}
 }
@@ -194,7 +251,7 @@ func (c *baseConsumer) recover() {
 }
 
 func (c *baseConsumer) handleError(ctx context.Context, err error, msg string) {
-	c.logger.WithContext(ctx).Error(err, "an error occurred during disaggregation of the message")
+	c.logger.WithContext(ctx).Error(err, msg)
 
 	c.metricWriter.Write(mon.MetricData{
 		&mon.MetricDatum{
@@ -215,7 +272,7 @@ func (c *baseConsumer) writeMetrics(duration time.Duration, processedCount int)
 			Dimensions: map[string]string{
 				"Consumer": c.name,
 			},
-			Unit:  mon.UnitMillisecondsAverage,
+			Unit:  mon.UnitMillisecondsDistribution,
 			Value: float64(duration.Milliseconds()),
 		},
 		&mon.MetricDatum{
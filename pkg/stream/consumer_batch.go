@@ -89,6 +89,8 @@ func (c *BatchConsumer) run(ctx context.Context) error {
 	defer c.wg.Done()
 	defer c.processBatch(context.Background())
 
+	data := c.dispatcher.runnerData()
+
 	for {
 		force := false
 
@@ -96,7 +98,7 @@ func (c *BatchConsumer) run(ctx context.Context) error {
 		case <-ctx.Done():
 			return fmt.Errorf("return from consuming as the coffin is dying")
 
-		case msg, ok := <-c.input.Data():
+		case msg, ok := <-data:
 			if !ok {
 				return nil
 			}
@@ -162,51 +164,118 @@ func (c *BatchConsumer) consumeBatch(kernelCtx context.Context, batch []*Message
 		return
 	}
 
-	messages, models, attributes, subSpans := c.decodeMessages(batchCtx, batch)
+	var err error
+	var acked, failed []*Message
+
+	logger := c.logger.WithContext(batchCtx)
+	ackMessages := make([]*Message, 0, len(batch))
+	claimed, duplicates := c.dedup.filter(batchCtx, batch)
+	ackMessages = append(ackMessages, duplicates...)
+	pending := claimed
+
+	for attempt := 1; len(pending) > 0; attempt++ {
+		acked, failed, err = c.consumeMessages(batchCtx, pending, attempt)
+		ackMessages = append(ackMessages, acked...)
+
+		if err == nil {
+			break
+		}
+
+		logger.Error(err, "an error occurred during the consume batch operation")
+
+		if !c.retry.wait(batchCtx, attempt) {
+			ackMessages = append(ackMessages, c.deadLetterMessages(batchCtx, failed, err, attempt)...)
+			break
+		}
+
+		pending = failed
+	}
+
+	c.dedup.releaseUnacked(batchCtx, claimed, ackMessages)
+	c.AcknowledgeBatch(batchCtx, ackMessages)
+
+	duration := c.clock.Now().Sub(start)
+	atomic.AddInt32(&c.processed, int32(len(ackMessages)))
+
+	c.writeMetrics(duration, len(batch))
+}
+
+// consumeMessages hands the batch to the callback and returns the messages which should be acknowledged and,
+// in case the callback returned an error, the messages which were not acknowledged by the callback.
+func (c *BatchConsumer) consumeMessages(batchCtx context.Context, batch []*Message, attempt int) ([]*Message, []*Message, error) {
+	messages, models, attributes, subSpans, undecodable := c.decodeMessages(batchCtx, batch)
 	defer func() {
 		for i := range subSpans {
 			subSpans[i].Finish()
 		}
 	}()
 
-	logger := c.logger.WithContext(batchCtx)
+	acked := make([]*Message, 0, len(batch))
+	failed := make([]*Message, 0)
 
-	acks, err := c.callback.Consume(batchCtx, models, attributes)
-	if err != nil {
-		logger.Error(err, "an error occurred during the consume batch operation")
+	for _, msg := range undecodable {
+		if c.retry.deadLetter(batchCtx, msg.msg, msg.err, attempt) {
+			acked = append(acked, msg.msg)
+		}
+	}
+
+	if len(messages) == 0 {
+		return acked, failed, nil
 	}
 
+	acks, err := c.callback.Consume(batchCtx, models, attributes)
+
 	if len(messages) != len(acks) {
-		logger.Panic(err, "number of acks does not match number of messages in batch")
+		c.logger.WithContext(batchCtx).Panic(err, "number of acks does not match number of messages in batch")
 	}
 
-	ackMessages := make([]*Message, 0, len(messages))
 	for i, ack := range acks {
 		if ack {
-			ackMessages = append(ackMessages, messages[i])
+			c.dedup.record(batchCtx, messages[i])
+			acked = append(acked, messages[i])
+			continue
+		}
+
+		if err != nil {
+			failed = append(failed, messages[i])
 		}
 	}
 
-	c.AcknowledgeBatch(batchCtx, ackMessages)
+	return acked, failed, err
+}
 
-	duration := c.clock.Now().Sub(start)
-	atomic.AddInt32(&c.processed, int32(len(ackMessages)))
+func (c *BatchConsumer) deadLetterMessages(batchCtx context.Context, batch []*Message, cause error, attempts int) []*Message {
+	deadLettered := make([]*Message, 0, len(batch))
 
-	c.writeMetrics(duration, len(batch))
+	for _, msg := range batch {
+		if c.retry.deadLetter(batchCtx, msg, cause, attempts) {
+			deadLettered = append(deadLettered, msg)
+		}
+	}
+
+	return deadLettered
+}
+
+type undecodableMessage struct {
+	msg *Message
+	err error
 }
 
-func (c *BatchConsumer) decodeMessages(batchCtx context.Context, batch []*Message) ([]*Message, []interface{}, []map[string]interface{}, []tracing.Span) {
+func (c *BatchConsumer) decodeMessages(batchCtx context.Context, batch []*Message) ([]*Message, []interface{}, []map[string]interface{}, []tracing.Span, []undecodableMessage) {
 	models := make([]interface{}, 0, len(batch))
 	attributes := make([]map[string]interface{}, 0, len(batch))
 	spans := make([]tracing.Span, 0, len(batch))
 	newBatch := make([]*Message, 0, len(batch))
+	undecodable := make([]undecodableMessage, 0)
 
 	for _, msg := range batch {
 		model := c.callback.GetModel(msg.Attributes)
 
-		msgCtx, attribute, err := c.encoder.Decode(batchCtx, msg, model)
+		// decode a copy of the message to keep the original attributes for retries and dead letters
+		msgCtx, attribute, err := c.encoder.Decode(batchCtx, copyMessage(msg), model)
 		if err != nil {
 			c.logger.WithContext(msgCtx).Error(err, "an error occurred during the batch decode message operation")
+			undecodable = append(undecodable, undecodableMessage{msg: msg, err: err})
 			continue
 		}
 
@@ -218,5 +287,5 @@ func (c *BatchConsumer) decodeMessages(batchCtx context.Context, batch []*Messag
 		spans = append(spans, span)
 	}
 
-	return newBatch, models, attributes, spans
+	return newBatch, models, attributes, spans, undecodable
 }
@@ -317,3 +317,95 @@ func (s *BatchConsumerTestSuite) TestRun_AggregateMessage() {
 	s.input.AcknowledgeableInput.AssertExpectations(s.T())
 	s.callback.AssertExpectations(s.T())
 }
+
+func (s *BatchConsumerTestSuite) TestRun_RetryAndDeadLetter() {
+	deadLetterOutput := new(mocks.Output)
+
+	logger := monMocks.NewLoggerMockedAll()
+	tracer := tracing.NewNoopTracer()
+	mw := monMocks.NewMetricWriterMockedAll()
+	me := stream.NewMessageEncoder(&stream.MessageEncoderSettings{})
+	ticker := time.NewTicker(time.Second)
+	settings := &stream.ConsumerSettings{
+		Input:       "test",
+		RunnerCount: 1,
+		IdleTimeout: time.Second,
+		Retry: stream.ConsumerRetrySettings{
+			Enabled:         true,
+			MaxAttempts:     2,
+			InitialInterval: time.Millisecond,
+			MaxInterval:     time.Millisecond,
+			Multiplier:      2,
+		},
+	}
+	batchSettings := &stream.BatchConsumerSettings{
+		IdleTimeout: time.Second,
+		BatchSize:   2,
+	}
+
+	baseConsumer := stream.NewBaseConsumerWithInterfaces(logger, mw, tracer, s.input, me, s.callback, settings, "test", cfg.AppId{}, stream.WithDeadLetterOutput(deadLetterOutput))
+	batchConsumer := stream.NewBatchConsumerWithInterfaces(baseConsumer, s.callback, ticker, batchSettings)
+
+	s.input.Input.
+		On("Data").
+		Return(s.data)
+
+	s.input.Input.
+		On("Run", mock.AnythingOfType("*context.cancelCtx")).
+		Run(func(args mock.Arguments) {
+			s.data <- stream.NewJsonMessage(`"foo"`)
+			s.data <- stream.NewJsonMessage(`"bar"`)
+		}).Return(nil)
+
+	s.input.Input.
+		On("Stop").
+		Return()
+
+	acked := make([]*stream.Message, 0)
+	s.input.AcknowledgeableInput.
+		On("AckBatch", mock.AnythingOfType("[]*stream.Message")).
+		Run(func(args mock.Arguments) {
+			acked = append(acked, args[0].([]*stream.Message)...)
+			s.stop()
+		}).
+		Return(nil)
+
+	s.callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("[]interface {}"), mock.AnythingOfType("[]map[string]interface {}")).
+		Return([]bool{true, false}, fmt.Errorf("can not consume bar")).
+		Once()
+
+	s.callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("[]interface {}"), mock.AnythingOfType("[]map[string]interface {}")).
+		Return([]bool{false}, fmt.Errorf("can not consume bar")).
+		Once()
+
+	s.callback.On("GetModel", mock.AnythingOfType("map[string]interface {}")).
+		Return(func(_ map[string]interface{}) interface{} {
+			return mdl.String("")
+		})
+
+	s.callback.On("Run", mock.AnythingOfType("*context.cancelCtx")).
+		Return(nil)
+
+	var deadLetter *stream.Message
+	deadLetterOutput.On("WriteOne", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*stream.Message")).
+		Run(func(args mock.Arguments) {
+			deadLetter = args.Get(1).(*stream.Message)
+		}).
+		Return(nil).
+		Once()
+
+	err := batchConsumer.Run(context.Background())
+
+	s.NoError(err, "there should be no error during run")
+	s.Len(acked, 2)
+
+	s.Require().NotNil(deadLetter)
+	s.Equal(`"bar"`, deadLetter.Body)
+	s.Equal("can not consume bar", deadLetter.Attributes[stream.AttributeDeadLetterError])
+	s.Equal(2, deadLetter.Attributes[stream.AttributeDeadLetterAttempts])
+
+	s.input.Input.AssertExpectations(s.T())
+	s.input.AcknowledgeableInput.AssertExpectations(s.T())
+	s.callback.AssertExpectations(s.T())
+	deadLetterOutput.AssertExpectations(s.T())
+}
@@ -0,0 +1,244 @@
+package stream
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/kvstore"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/spf13/cast"
+	"time"
+)
+
+const (
+	metricNameConsumerDuplicate = "Duplicate"
+
+	dedupStatusProcessing = "processing"
+	dedupStatusConsumed   = "consumed"
+)
+
+// ConsumerDedupSettings configure the skipping of already consumed messages. The id of a message is claimed in the
+// kvstore configured at kvstore.<store> before it is processed. Claims of messages which were not acknowledged are
+// released again, claims of crashed consumers expire after the claim timeout. The ids of consumed messages are
+// remembered for the ttl, the ttl of the kvstore itself should not be shorter.
+//
+// The claim is atomic if the store supports it, like redis and ddb or a chain ending with one of them do. Otherwise,
+// the id is checked and claimed in two steps and a message received twice at the same time can be processed twice.
+// The same applies to taking over expired claims and to the elements in front of the last one of a chain, which
+// can keep an outdated claim of another instance until it expires.
+type ConsumerDedupSettings struct {
+	Enabled      bool          `cfg:"enabled" default:"false"`
+	Attribute    string        `cfg:"attribute"`
+	Store        string        `cfg:"store"`
+	Ttl          time.Duration `cfg:"ttl" default:"24h" validate:"min=1"`
+	ClaimTimeout time.Duration `cfg:"claim_timeout" default:"5m" validate:"min=1"`
+}
+
+type consumerDedupResult int
+
+const (
+	// dedupResultNew marks a message which was claimed and has to be processed
+	dedupResultNew consumerDedupResult = iota
+	// dedupResultInProgress marks a message which is being processed by another runner or instance
+	dedupResultInProgress
+	// dedupResultConsumed marks a duplicate of a message which was already consumed
+	dedupResultConsumed
+)
+
+type consumerDedupRecord struct {
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type consumerDedupHandler struct {
+	logger       mon.Logger
+	metricWriter mon.MetricWriter
+	clock        clock.Clock
+	store        kvstore.KvStore
+	name         string
+	settings     ConsumerDedupSettings
+}
+
+func newConsumerDedupHandler(logger mon.Logger, metricWriter mon.MetricWriter, store kvstore.KvStore, name string, settings ConsumerDedupSettings) *consumerDedupHandler {
+	return &consumerDedupHandler{
+		logger:       logger,
+		metricWriter: metricWriter,
+		clock:        clock.Provider,
+		store:        store,
+		name:         name,
+		settings:     settings,
+	}
+}
+
+// claim marks the message as being processed if it wasn't claimed or consumed before. Only duplicates of consumed
+// messages can be acknowledged without processing them. A message which is still processed somewhere else is left
+// on the input, so it is received again if the other attempt fails. If the store can't be used, the message is
+// treated as new to not lose any messages.
+func (d *consumerDedupHandler) claim(ctx context.Context, msg *Message) consumerDedupResult {
+	if !d.isEnabled() {
+		return dedupResultNew
+	}
+
+	id := d.messageId(msg)
+	now := d.clock.Now()
+	claim := &consumerDedupRecord{
+		Status:    dedupStatusProcessing,
+		ExpiresAt: now.Add(d.settings.ClaimTimeout),
+	}
+
+	claimed, err := kvstore.PutIfAbsent(ctx, d.store, id, claim)
+
+	if err != nil {
+		d.logger.WithContext(ctx).Errorf(err, "can not claim message %s", id)
+		return dedupResultNew
+	}
+
+	if claimed {
+		return dedupResultNew
+	}
+
+	existing := &consumerDedupRecord{}
+	found, err := d.store.Get(ctx, id, existing)
+
+	if err != nil {
+		d.logger.WithContext(ctx).Errorf(err, "can not check if message %s is a duplicate", id)
+		return dedupResultNew
+	}
+
+	// the previous claim was released or expired in the meantime
+	if !found || !existing.ExpiresAt.After(now) {
+		if err := d.store.Put(ctx, id, claim); err != nil {
+			d.logger.WithContext(ctx).Errorf(err, "can not claim message %s", id)
+		}
+
+		return dedupResultNew
+	}
+
+	if existing.Status != dedupStatusConsumed {
+		d.logger.WithContext(ctx).Infof("message %s is being processed already, leaving it for a redelivery", id)
+		return dedupResultInProgress
+	}
+
+	d.logger.WithContext(ctx).Infof("skipping duplicate message %s", id)
+	d.metricWriter.WriteOne(&mon.MetricDatum{
+		MetricName: metricNameConsumerDuplicate,
+		Dimensions: map[string]string{
+			"Consumer": d.name,
+		},
+		Value: 1,
+	})
+
+	return dedupResultConsumed
+}
+
+// filter claims the messages of the batch and splits it into new messages and duplicates of consumed ones.
+// Messages which are being processed somewhere else are part of neither.
+func (d *consumerDedupHandler) filter(ctx context.Context, batch []*Message) ([]*Message, []*Message) {
+	if !d.isEnabled() {
+		return batch, nil
+	}
+
+	messages := make([]*Message, 0, len(batch))
+	duplicates := make([]*Message, 0)
+
+	for _, msg := range batch {
+		switch d.claim(ctx, msg) {
+		case dedupResultNew:
+			messages = append(messages, msg)
+		case dedupResultConsumed:
+			duplicates = append(duplicates, msg)
+		}
+	}
+
+	return messages, duplicates
+}
+
+// record remembers the messages as consumed for the ttl
+func (d *consumerDedupHandler) record(ctx context.Context, msgs ...*Message) {
+	if !d.isEnabled() {
+		return
+	}
+
+	for _, msg := range msgs {
+		id := d.messageId(msg)
+		record := &consumerDedupRecord{
+			Status:    dedupStatusConsumed,
+			ExpiresAt: d.clock.Now().Add(d.settings.Ttl),
+		}
+
+		if err := d.store.Put(ctx, id, record); err != nil {
+			d.logger.WithContext(ctx).Errorf(err, "can not record message %s as consumed", id)
+		}
+	}
+}
+
+// release removes the claims of messages which were not acknowledged, so they are processed if they are received again
+func (d *consumerDedupHandler) release(ctx context.Context, msgs ...*Message) {
+	if !d.isEnabled() {
+		return
+	}
+
+	for _, msg := range msgs {
+		id := d.messageId(msg)
+
+		if err := d.store.Delete(ctx, id); err != nil {
+			d.logger.WithContext(ctx).Errorf(err, "can not release the claim of message %s", id)
+		}
+	}
+}
+
+// releaseUnacked releases the claimed messages which are not part of the acknowledged ones
+func (d *consumerDedupHandler) releaseUnacked(ctx context.Context, claimed []*Message, acked []*Message) {
+	if !d.isEnabled() {
+		return
+	}
+
+	ackedSet := make(map[*Message]struct{}, len(acked))
+
+	for _, msg := range acked {
+		ackedSet[msg] = struct{}{}
+	}
+
+	for _, msg := range claimed {
+		if _, ok := ackedSet[msg]; !ok {
+			d.release(ctx, msg)
+		}
+	}
+}
+
+func (d *consumerDedupHandler) isEnabled() bool {
+	return d.settings.Enabled && d.store != nil
+}
+
+// messageId uses the configured attribute as id of the message and falls back to a hash of the body
+func (d *consumerDedupHandler) messageId(msg *Message) string {
+	if value, ok := msg.Attributes[d.settings.Attribute]; ok && d.settings.Attribute != "" {
+		if id, err := cast.ToStringE(value); err == nil && id != "" {
+			return fmt.Sprintf("consumer-dedup-%s-%s", d.name, id)
+		}
+	}
+
+	hash := sha256.Sum256([]byte(msg.Body))
+
+	return fmt.Sprintf("consumer-dedup-%s-%s", d.name, hex.EncodeToString(hash[:]))
+}
+
+func getConsumerDedupDefaultMetrics(name string, settings ConsumerDedupSettings) mon.MetricData {
+	if !settings.Enabled {
+		return mon.MetricData{}
+	}
+
+	return mon.MetricData{
+		{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameConsumerDuplicate,
+			Dimensions: map[string]string{
+				"Consumer": name,
+			},
+			Unit:  mon.UnitCount,
+			Value: 0.0,
+		},
+	}
+}
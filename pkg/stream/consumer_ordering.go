@@ -0,0 +1,162 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/spf13/cast"
+	"hash/fnv"
+	"sync/atomic"
+)
+
+const metricNameConsumerRunnerQueueDepth = "RunnerQueueDepth"
+
+// ConsumerOrderingSettings enable the ordered processing of messages. All messages with the same value of
+// the attribute are handed to the same runner, so they are processed in the order they were received
+// while messages with different values are still processed in parallel. Messages without the attribute
+// are spread over all runners. As aggregates are dispatched as a whole, the messages of an aggregate
+// are only ordered if the aggregate itself carries the attribute. The sqs input fills sqsMessageGroupId from the
+// group id of fifo queues and the kinesis input provides the partition key as kinesisPartitionKey.
+type ConsumerOrderingSettings struct {
+	Enabled   bool   `cfg:"enabled" default:"false"`
+	Attribute string `cfg:"attribute" default:"sqsMessageGroupId"`
+	QueueSize int    `cfg:"queue_size" default:"10" validate:"min=0"`
+}
+
+type consumerDispatcher struct {
+	logger       mon.Logger
+	metricWriter mon.MetricWriter
+	input        Input
+	name         string
+	settings     ConsumerOrderingSettings
+
+	channels   []chan *Message
+	nextRunner int32
+	roundRobin uint32
+}
+
+func newConsumerDispatcher(logger mon.Logger, metricWriter mon.MetricWriter, input Input, name string, runnerCount int, settings ConsumerOrderingSettings) *consumerDispatcher {
+	dispatcher := &consumerDispatcher{
+		logger:       logger,
+		metricWriter: metricWriter,
+		input:        input,
+		name:         name,
+		settings:     settings,
+	}
+
+	if !settings.Enabled {
+		return dispatcher
+	}
+
+	dispatcher.channels = make([]chan *Message, runnerCount)
+
+	for i := range dispatcher.channels {
+		dispatcher.channels[i] = make(chan *Message, settings.QueueSize)
+	}
+
+	return dispatcher
+}
+
+// runnerData returns the channel a runner should consume from. Every call assigns the next runner queue.
+func (d *consumerDispatcher) runnerData() chan *Message {
+	if !d.settings.Enabled {
+		return d.input.Data()
+	}
+
+	runner := atomic.AddInt32(&d.nextRunner, 1) - 1
+
+	return d.channels[int(runner)%len(d.channels)]
+}
+
+// run moves the messages of the input to the queues of the runners until the input is drained
+func (d *consumerDispatcher) run(ctx context.Context) error {
+	defer d.logger.Debug("dispatcher is ending")
+	defer d.closeChannels()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case msg, ok := <-d.input.Data():
+			if !ok {
+				return nil
+			}
+
+			select {
+			case <-ctx.Done():
+				return nil
+			case d.channels[d.runnerIndex(msg)] <- msg:
+			}
+		}
+	}
+}
+
+func (d *consumerDispatcher) runnerIndex(msg *Message) int {
+	value, ok := msg.Attributes[d.settings.Attribute]
+
+	if !ok {
+		return int(atomic.AddUint32(&d.roundRobin, 1) % uint32(len(d.channels)))
+	}
+
+	key, err := cast.ToStringE(value)
+
+	if err != nil {
+		key = fmt.Sprint(value)
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key))
+
+	return int(hash.Sum32() % uint32(len(d.channels)))
+}
+
+func (d *consumerDispatcher) closeChannels() {
+	for _, channel := range d.channels {
+		close(channel)
+	}
+}
+
+func (d *consumerDispatcher) writeQueueDepthMetrics() {
+	if !d.settings.Enabled {
+		return
+	}
+
+	data := make(mon.MetricData, 0, len(d.channels))
+
+	for i, channel := range d.channels {
+		data = append(data, &mon.MetricDatum{
+			MetricName: metricNameConsumerRunnerQueueDepth,
+			Dimensions: map[string]string{
+				"Consumer": d.name,
+				"Runner":   fmt.Sprint(i),
+			},
+			Unit:  mon.UnitCountAverage,
+			Value: float64(len(channel)),
+		})
+	}
+
+	d.metricWriter.Write(data)
+}
+
+func getConsumerOrderingDefaultMetrics(name string, runnerCount int, settings ConsumerOrderingSettings) mon.MetricData {
+	if !settings.Enabled {
+		return mon.MetricData{}
+	}
+
+	data := make(mon.MetricData, 0, runnerCount)
+
+	for i := 0; i < runnerCount; i++ {
+		data = append(data, &mon.MetricDatum{
+			MetricName: metricNameConsumerRunnerQueueDepth,
+			Dimensions: map[string]string{
+				"Consumer": name,
+				"Runner":   fmt.Sprint(i),
+			},
+			Unit:  mon.UnitCountAverage,
+			Value: 0.0,
+		})
+	}
+
+	return data
+}
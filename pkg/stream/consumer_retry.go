@@ -0,0 +1,155 @@
+package stream
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/mon"
+	"math"
+	"time"
+)
+
+const (
+	AttributeDeadLetterConsumer = "goso.deadLetter.consumer"
+	AttributeDeadLetterError    = "goso.deadLetter.error"
+	AttributeDeadLetterAttempts = "goso.deadLetter.attempts"
+	AttributeDeadLetterTime     = "goso.deadLetter.time"
+
+	metricNameConsumerRetry      = "Retry"
+	metricNameConsumerDeadLetter = "DeadLetter"
+)
+
+type ConsumerRetrySettings struct {
+	Enabled          bool          `cfg:"enabled" default:"false"`
+	MaxAttempts      int           `cfg:"max_attempts" default:"3" validate:"min=1"`
+	InitialInterval  time.Duration `cfg:"initial_interval" default:"1s"`
+	MaxInterval      time.Duration `cfg:"max_interval" default:"30s"`
+	Multiplier       float64       `cfg:"multiplier" default:"2"`
+	DeadLetterOutput string        `cfg:"dead_letter_output"`
+}
+
+type consumerRetryHandler struct {
+	logger       mon.Logger
+	metricWriter mon.MetricWriter
+	clock        clock.Clock
+	output       Output
+	name         string
+	settings     ConsumerRetrySettings
+}
+
+func newConsumerRetryHandler(logger mon.Logger, metricWriter mon.MetricWriter, output Output, name string, settings ConsumerRetrySettings) *consumerRetryHandler {
+	return &consumerRetryHandler{
+		logger:       logger,
+		metricWriter: metricWriter,
+		clock:        clock.Provider,
+		output:       output,
+		name:         name,
+		settings:     settings,
+	}
+}
+
+// wait blocks for the backoff interval of the given attempt and returns true if another attempt should be made.
+func (r *consumerRetryHandler) wait(ctx context.Context, attempt int) bool {
+	if !r.settings.Enabled || attempt >= r.settings.MaxAttempts {
+		return false
+	}
+
+	r.writeMetric(metricNameConsumerRetry, 1)
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-r.clock.After(r.interval(attempt)):
+		return true
+	}
+}
+
+func (r *consumerRetryHandler) interval(attempt int) time.Duration {
+	interval := float64(r.settings.InitialInterval) * math.Pow(r.settings.Multiplier, float64(attempt-1))
+
+	if r.settings.MaxInterval > 0 && interval > float64(r.settings.MaxInterval) {
+		return r.settings.MaxInterval
+	}
+
+	return time.Duration(interval)
+}
+
+// deadLetter writes the original message to the dead letter output. It returns true if the message
+// was handed over successfully and can be acknowledged on the input.
+func (r *consumerRetryHandler) deadLetter(ctx context.Context, msg *Message, cause error, attempts int) bool {
+	if !r.settings.Enabled || r.output == nil {
+		return false
+	}
+
+	deadLetter := copyMessage(msg)
+	delete(deadLetter.Attributes, AttributeSqsReceiptHandle)
+	delete(deadLetter.Attributes, AttributeKafkaTopic)
+	delete(deadLetter.Attributes, AttributeKafkaPartition)
+	delete(deadLetter.Attributes, AttributeKafkaOffset)
+
+	deadLetter.Attributes[AttributeDeadLetterConsumer] = r.name
+	deadLetter.Attributes[AttributeDeadLetterError] = cause.Error()
+	deadLetter.Attributes[AttributeDeadLetterAttempts] = attempts
+	deadLetter.Attributes[AttributeDeadLetterTime] = r.clock.Now().Format(time.RFC3339)
+
+	if err := r.output.WriteOne(ctx, deadLetter); err != nil {
+		r.logger.WithContext(ctx).Error(err, "can not write message to the dead letter output")
+		return false
+	}
+
+	r.logger.WithContext(ctx).Warnf("moved message to the dead letter output after %d attempts: %s", attempts, cause.Error())
+	r.writeMetric(metricNameConsumerDeadLetter, 1)
+
+	return true
+}
+
+func (r *consumerRetryHandler) writeMetric(metricName string, value float64) {
+	r.metricWriter.WriteOne(&mon.MetricDatum{
+		Priority:   mon.PriorityHigh,
+		MetricName: metricName,
+		Dimensions: map[string]string{
+			"Consumer": r.name,
+		},
+		Unit:  mon.UnitCount,
+		Value: value,
+	})
+}
+
+func copyMessage(msg *Message) *Message {
+	attributes := make(map[string]interface{}, len(msg.Attributes))
+
+	for k, v := range msg.Attributes {
+		attributes[k] = v
+	}
+
+	return &Message{
+		Attributes: attributes,
+		Body:       msg.Body,
+	}
+}
+
+func getConsumerRetryDefaultMetrics(name string, settings ConsumerRetrySettings) mon.MetricData {
+	if !settings.Enabled {
+		return mon.MetricData{}
+	}
+
+	return mon.MetricData{
+		{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameConsumerRetry,
+			Dimensions: map[string]string{
+				"Consumer": name,
+			},
+			Unit:  mon.UnitCount,
+			Value: 0.0,
+		},
+		{
+			Priority:   mon.PriorityHigh,
+			MetricName: metricNameConsumerDeadLetter,
+			Dimensions: map[string]string{
+				"Consumer": name,
+			},
+			Unit:  mon.UnitCount,
+			Value: 0.0,
+		},
+	}
+}
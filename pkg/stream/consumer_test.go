@@ -3,12 +3,17 @@ package stream_test
 import (
 	"context"
 	"fmt"
+	"github.com/alicebob/miniredis"
 	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/exec"
+	"github.com/applike/gosoline/pkg/kvstore"
 	"github.com/applike/gosoline/pkg/mdl"
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/redis"
 	"github.com/applike/gosoline/pkg/stream"
 	"github.com/applike/gosoline/pkg/stream/mocks"
 	"github.com/applike/gosoline/pkg/tracing"
+	baseRedis "github.com/go-redis/redis"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"strings"
@@ -26,6 +31,12 @@ type ConsumerTestSuite struct {
 
 	input *mocks.Input
 
+	logger   *monMocks.Logger
+	tracer   tracing.Tracer
+	mw       *monMocks.MetricWriter
+	encoder  stream.MessageEncoder
+	settings *stream.ConsumerSettings
+
 	callback *mocks.RunnableConsumerCallback
 	consumer *stream.Consumer
 }
@@ -42,18 +53,24 @@ func (s *ConsumerTestSuite) SetupTest() {
 	s.input = new(mocks.Input)
 	s.callback = new(mocks.RunnableConsumerCallback)
 
-	logger := monMocks.NewLoggerMockedAll()
-	tracer := tracing.NewNoopTracer()
-	mw := monMocks.NewMetricWriterMockedAll()
-	me := stream.NewMessageEncoder(&stream.MessageEncoderSettings{})
-	settings := &stream.ConsumerSettings{
+	s.logger = monMocks.NewLoggerMockedAll()
+	s.tracer = tracing.NewNoopTracer()
+	s.mw = monMocks.NewMetricWriterMockedAll()
+	s.encoder = stream.NewMessageEncoder(&stream.MessageEncoderSettings{})
+	s.settings = &stream.ConsumerSettings{
 		Input:       "test",
 		RunnerCount: 1,
 		IdleTimeout: time.Second,
 	}
 
-	baseConsumer := stream.NewBaseConsumerWithInterfaces(logger, mw, tracer, s.input, me, s.callback, settings, "test", cfg.AppId{})
-	s.consumer = stream.NewConsumerWithInterfaces(baseConsumer, s.callback)
+	s.consumer = s.newConsumer()
+}
+
+// newConsumer creates the consumer again to apply changes of the settings
+func (s *ConsumerTestSuite) newConsumer(options ...stream.BaseConsumerOption) *stream.Consumer {
+	baseConsumer := stream.NewBaseConsumerWithInterfaces(s.logger, s.mw, s.tracer, s.input, s.encoder, s.callback, s.settings, "test", cfg.AppId{}, options...)
+
+	return stream.NewConsumerWithInterfaces(baseConsumer, s.callback)
 }
 
 func (s *ConsumerTestSuite) TestGetModelNil() {
@@ -295,6 +312,312 @@ func (s *ConsumerTestSuite) TestRun_AggregateMessage() {
 	s.callback.AssertExpectations(s.T())
 }
 
+func (s *ConsumerTestSuite) TestRun_RetryAndDeadLetter() {
+	deadLetterOutput := new(mocks.Output)
+
+	s.settings.Retry = stream.ConsumerRetrySettings{
+		Enabled:         true,
+		MaxAttempts:     3,
+		InitialInterval: time.Millisecond,
+		MaxInterval:     time.Millisecond,
+		Multiplier:      2,
+	}
+	s.consumer = s.newConsumer(stream.WithDeadLetterOutput(deadLetterOutput))
+
+	s.input.On("Data").Return(s.data)
+	s.input.On("Run", mock.AnythingOfType("*context.cancelCtx")).Run(func(args mock.Arguments) {
+		s.data <- stream.NewJsonMessage(`"foo"`, map[string]interface{}{
+			"attr1": "a",
+		})
+		s.data <- stream.NewJsonMessage(`"bar"`)
+		s.stop()
+	}).Return(nil)
+	s.input.On("Stop")
+
+	attempts := map[string]int{}
+	s.callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), mock.AnythingOfType("map[string]interface {}")).
+		Return(func(_ context.Context, model interface{}, _ map[string]interface{}) bool {
+			return *model.(*string) == "bar"
+		}, func(_ context.Context, model interface{}, _ map[string]interface{}) error {
+			body := *model.(*string)
+			attempts[body]++
+
+			if body == "bar" && attempts[body] > 1 {
+				return nil
+			}
+
+			return fmt.Errorf("can not consume %s", body)
+		})
+
+	s.callback.On("GetModel", mock.AnythingOfType("map[string]interface {}")).
+		Return(func(_ map[string]interface{}) interface{} {
+			return mdl.String("")
+		})
+
+	s.callback.On("Run", mock.AnythingOfType("*context.cancelCtx")).Return(nil)
+
+	var deadLetter *stream.Message
+	deadLetterOutput.On("WriteOne", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*stream.Message")).
+		Run(func(args mock.Arguments) {
+			deadLetter = args.Get(1).(*stream.Message)
+		}).
+		Return(nil).
+		Once()
+
+	err := s.consumer.Run(context.Background())
+
+	s.NoError(err, "there should be no error during run")
+	s.Equal(map[string]int{"foo": 3, "bar": 2}, attempts)
+
+	s.Require().NotNil(deadLetter)
+	s.Equal(`"foo"`, deadLetter.Body)
+	s.Equal("a", deadLetter.Attributes["attr1"])
+	s.Equal(stream.EncodingJson, deadLetter.Attributes[stream.AttributeEncoding])
+	s.Equal("test", deadLetter.Attributes[stream.AttributeDeadLetterConsumer])
+	s.Equal("can not consume foo", deadLetter.Attributes[stream.AttributeDeadLetterError])
+	s.Equal(3, deadLetter.Attributes[stream.AttributeDeadLetterAttempts])
+
+	s.input.AssertExpectations(s.T())
+	s.callback.AssertExpectations(s.T())
+	deadLetterOutput.AssertExpectations(s.T())
+}
+
+func (s *ConsumerTestSuite) newDedupStore() kvstore.KvStore {
+	server, err := miniredis.Run()
+	s.NoError(err)
+	s.T().Cleanup(server.Close)
+
+	baseClient := baseRedis.NewClient(&baseRedis.Options{
+		Addr: server.Addr(),
+	})
+	client := redis.NewClientWithInterfaces(s.logger, baseClient, exec.NewDefaultExecutor(), &redis.Settings{})
+
+	return kvstore.NewRedisKvStoreWithInterfaces(client, &kvstore.Settings{
+		Name: "consumer-dedup",
+		Ttl:  time.Hour,
+	})
+}
+
+func (s *ConsumerTestSuite) TestRun_Dedup() {
+	s.settings.Dedup = stream.ConsumerDedupSettings{
+		Enabled:      true,
+		Attribute:    "eventId",
+		Ttl:          time.Hour,
+		ClaimTimeout: time.Minute,
+	}
+	s.consumer = s.newConsumer(stream.WithDedupStore(s.newDedupStore()))
+
+	s.input.On("Data").Return(s.data)
+	s.input.On("Run", mock.AnythingOfType("*context.cancelCtx")).Run(func(args mock.Arguments) {
+		s.data <- stream.NewJsonMessage(`"foo"`, map[string]interface{}{"eventId": 1})
+		s.data <- stream.NewJsonMessage(`"foo"`, map[string]interface{}{"eventId": 1})
+		s.data <- stream.NewJsonMessage(`"bar"`, map[string]interface{}{"eventId": 2})
+		s.data <- stream.NewJsonMessage(`"baz"`)
+		s.data <- stream.NewJsonMessage(`"baz"`)
+		s.stop()
+	}).Return(nil)
+	s.input.On("Stop")
+
+	consumed := make([]string, 0)
+	s.callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), mock.AnythingOfType("map[string]interface {}")).
+		Run(func(args mock.Arguments) {
+			consumed = append(consumed, *args.Get(1).(*string))
+		}).
+		Return(true, nil)
+
+	s.callback.On("GetModel", mock.AnythingOfType("map[string]interface {}")).
+		Return(func(_ map[string]interface{}) interface{} {
+			return mdl.String("")
+		})
+
+	s.callback.On("Run", mock.AnythingOfType("*context.cancelCtx")).Return(nil)
+
+	err := s.consumer.Run(context.Background())
+
+	s.NoError(err, "there should be no error during run")
+	s.Equal([]string{"foo", "bar", "baz"}, consumed)
+
+	s.input.AssertExpectations(s.T())
+	s.callback.AssertExpectations(s.T())
+}
+
+func (s *ConsumerTestSuite) TestRun_DedupReleasesUnacked() {
+	s.settings.Dedup = stream.ConsumerDedupSettings{
+		Enabled:      true,
+		Attribute:    "eventId",
+		Ttl:          time.Hour,
+		ClaimTimeout: time.Minute,
+	}
+	s.consumer = s.newConsumer(stream.WithDedupStore(s.newDedupStore()))
+
+	s.input.On("Data").Return(s.data)
+	s.input.On("Run", mock.AnythingOfType("*context.cancelCtx")).Run(func(args mock.Arguments) {
+		s.data <- stream.NewJsonMessage(`"foo"`, map[string]interface{}{"eventId": 1})
+		s.data <- stream.NewJsonMessage(`"foo"`, map[string]interface{}{"eventId": 1})
+		s.data <- stream.NewJsonMessage(`"foo"`, map[string]interface{}{"eventId": 1})
+		s.stop()
+	}).Return(nil)
+	s.input.On("Stop")
+
+	s.callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), mock.AnythingOfType("map[string]interface {}")).
+		Return(false, nil).Once()
+	s.callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), mock.AnythingOfType("map[string]interface {}")).
+		Return(true, nil).Once()
+
+	s.callback.On("GetModel", mock.AnythingOfType("map[string]interface {}")).
+		Return(func(_ map[string]interface{}) interface{} {
+			return mdl.String("")
+		})
+
+	s.callback.On("Run", mock.AnythingOfType("*context.cancelCtx")).Return(nil)
+
+	err := s.consumer.Run(context.Background())
+
+	s.NoError(err, "there should be no error during run")
+	s.callback.AssertNumberOfCalls(s.T(), "Consume", 2)
+
+	s.input.AssertExpectations(s.T())
+	s.callback.AssertExpectations(s.T())
+}
+
+func (s *ConsumerTestSuite) TestRun_DedupInProgress() {
+	s.settings.Dedup = stream.ConsumerDedupSettings{
+		Enabled:      true,
+		Attribute:    "eventId",
+		Ttl:          time.Hour,
+		ClaimTimeout: time.Minute,
+	}
+
+	// another runner claimed the first message and is still processing it, the second one was consumed already
+	store := s.newDedupStore()
+	err := store.Put(context.Background(), "consumer-dedup-test-1", map[string]interface{}{
+		"status":    "processing",
+		"expiresAt": time.Now().Add(time.Minute),
+	})
+	s.NoError(err)
+
+	err = store.Put(context.Background(), "consumer-dedup-test-2", map[string]interface{}{
+		"status":    "consumed",
+		"expiresAt": time.Now().Add(time.Hour),
+	})
+	s.NoError(err)
+
+	// the claim of the third message expired as its consumer crashed
+	err = store.Put(context.Background(), "consumer-dedup-test-3", map[string]interface{}{
+		"status":    "processing",
+		"expiresAt": time.Now().Add(-time.Second),
+	})
+	s.NoError(err)
+
+	input := new(acknowledgeableInput)
+	baseConsumer := stream.NewBaseConsumerWithInterfaces(s.logger, s.mw, s.tracer, input, s.encoder, s.callback, s.settings, "test", cfg.AppId{}, stream.WithDedupStore(store))
+	s.consumer = stream.NewConsumerWithInterfaces(baseConsumer, s.callback)
+
+	inProgress := stream.NewJsonMessage(`"foo"`, map[string]interface{}{"eventId": 1})
+	duplicate := stream.NewJsonMessage(`"bar"`, map[string]interface{}{"eventId": 2})
+	expired := stream.NewJsonMessage(`"baz"`, map[string]interface{}{"eventId": 3})
+
+	input.Input.On("Data").Return(s.data)
+	input.Input.On("Run", mock.AnythingOfType("*context.cancelCtx")).Run(func(args mock.Arguments) {
+		s.data <- inProgress
+		s.data <- duplicate
+		s.data <- expired
+		s.stop()
+	}).Return(nil)
+	input.Input.On("Stop")
+
+	// the message in progress is not acknowledged, so it is received again if the other runner fails
+	input.AcknowledgeableInput.On("Ack", duplicate).Return(nil).Once()
+	input.AcknowledgeableInput.On("Ack", expired).Return(nil).Once()
+
+	consumed := make([]string, 0)
+	s.callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), mock.AnythingOfType("map[string]interface {}")).
+		Run(func(args mock.Arguments) {
+			consumed = append(consumed, *args.Get(1).(*string))
+		}).
+		Return(true, nil)
+
+	s.callback.On("GetModel", mock.AnythingOfType("map[string]interface {}")).
+		Return(func(_ map[string]interface{}) interface{} {
+			return mdl.String("")
+		})
+
+	s.callback.On("Run", mock.AnythingOfType("*context.cancelCtx")).Return(nil)
+
+	err = s.consumer.Run(context.Background())
+
+	s.NoError(err, "there should be no error during run")
+	s.Equal([]string{"baz"}, consumed)
+
+	found, err := store.Contains(context.Background(), "consumer-dedup-test-1")
+	s.NoError(err)
+	s.True(found, "the claim of the other runner must not be released")
+
+	input.Input.AssertExpectations(s.T())
+	input.AcknowledgeableInput.AssertExpectations(s.T())
+	s.callback.AssertExpectations(s.T())
+}
+
+func (s *ConsumerTestSuite) TestRun_Ordering() {
+	s.settings.RunnerCount = 3
+	s.settings.Ordering = stream.ConsumerOrderingSettings{
+		Enabled:   true,
+		Attribute: "groupId",
+		QueueSize: 2,
+	}
+	s.consumer = s.newConsumer()
+
+	s.input.On("Data").Return(s.data)
+	s.input.On("Run", mock.AnythingOfType("*context.cancelCtx")).Run(func(args mock.Arguments) {
+		for i := 0; i < 9; i++ {
+			s.data <- stream.NewJsonMessage(fmt.Sprintf(`"%d"`, i), map[string]interface{}{
+				"groupId": fmt.Sprintf("group-%d", i%3),
+			})
+		}
+		s.stop()
+	}).Return(nil)
+	s.input.On("Stop")
+
+	lck := sync.Mutex{}
+	consumed := map[interface{}][]string{}
+
+	s.callback.On("Consume", mock.AnythingOfType("*context.cancelCtx"), mock.AnythingOfType("*string"), mock.AnythingOfType("map[string]interface {}")).
+		Run(func(args mock.Arguments) {
+			body := *args.Get(1).(*string)
+			group := args.Get(2).(map[string]interface{})["groupId"]
+
+			// let the later messages of a group overtake the earlier ones if they were not ordered
+			if body < "3" {
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			lck.Lock()
+			defer lck.Unlock()
+
+			consumed[group] = append(consumed[group], body)
+		}).
+		Return(true, nil)
+
+	s.callback.On("GetModel", mock.AnythingOfType("map[string]interface {}")).
+		Return(func(_ map[string]interface{}) interface{} {
+			return mdl.String("")
+		})
+
+	s.callback.On("Run", mock.AnythingOfType("*context.cancelCtx")).Return(nil)
+
+	err := s.consumer.Run(context.Background())
+
+	s.NoError(err, "there should be no error during run")
+	s.Equal(map[interface{}][]string{
+		"group-0": {"0", "3", "6"},
+		"group-1": {"1", "4", "7"},
+		"group-2": {"2", "5", "8"},
+	}, consumed)
+
+	s.input.AssertExpectations(s.T())
+	s.callback.AssertExpectations(s.T())
+}
+
 func TestConsumerTestSuite(t *testing.T) {
 	suite.Run(t, new(ConsumerTestSuite))
 }
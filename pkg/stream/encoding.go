@@ -23,9 +23,19 @@ type MessageBodyEncoder interface {
 	Decode(data []byte, out interface{}) error
 }
 
+// AttributeAwareMessageBodyEncoder is implemented by encoders which have to stamp information like
+// a schema id on the message attributes during encoding or need them to decode the body again
+type AttributeAwareMessageBodyEncoder interface {
+	MessageBodyEncoder
+	EncodeWithAttributes(data interface{}, attributes map[string]interface{}) ([]byte, error)
+	DecodeWithAttributes(data []byte, attributes map[string]interface{}, out interface{}) error
+}
+
 var messageBodyEncoders = map[string]MessageBodyEncoder{
-	EncodingJson: new(jsonEncoder),
-	EncodingText: new(textEncoder),
+	EncodingJson:     new(jsonEncoder),
+	EncodingText:     new(textEncoder),
+	EncodingProtobuf: NewProtobufEncoder(nil),
+	EncodingAvro:     NewAvroEncoder(nil),
 }
 
 func AddMessageBodyEncoder(encoding string, encoder MessageBodyEncoder) {
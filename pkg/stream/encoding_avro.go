@@ -0,0 +1,417 @@
+package stream
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/encoding/base64"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"github.com/linkedin/goavro/v2"
+	"strings"
+	"sync"
+)
+
+const EncodingAvro = "avro/binary"
+
+// AvroSchemaProvider has to be implemented by models which should be encoded with avro
+type AvroSchemaProvider interface {
+	AvroSchema() string
+}
+
+// avroEncoder encodes models into the base64 encoded avro binary format. Models are mapped onto the
+// schema by their json representation, so the field names of the schema have to match the json tags.
+// If a schema registry is used, the writer schema is resolved by the schema id of the message and
+// the model is filled with all fields it shares with the writer schema.
+type avroEncoder struct {
+	registry SchemaRegistry
+	codecs   sync.Map
+}
+
+func NewAvroEncoder(registry SchemaRegistry) *avroEncoder {
+	return &avroEncoder{
+		registry: registry,
+	}
+}
+
+func (e *avroEncoder) Encode(data interface{}) ([]byte, error) {
+	return e.EncodeWithAttributes(data, map[string]interface{}{})
+}
+
+func (e *avroEncoder) EncodeWithAttributes(data interface{}, attributes map[string]interface{}) ([]byte, error) {
+	provider, ok := data.(AvroSchemaProvider)
+
+	if !ok {
+		return nil, fmt.Errorf("the data of type %T has to implement the AvroSchemaProvider", data)
+	}
+
+	schema := provider.AvroSchema()
+	codec, err := e.getCodec(schema)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = stampSchema(e.registry, attributes, codec.name, schema); err != nil {
+		return nil, err
+	}
+
+	textual, err := json.Marshal(data)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not marshal data to json: %w", err)
+	}
+
+	native, _, err := codec.NativeFromTextual(textual)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not convert data into avro record %s: %w", codec.name, err)
+	}
+
+	binary, err := codec.BinaryFromNative(nil, native)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not encode avro record %s: %w", codec.name, err)
+	}
+
+	return base64.Encode(binary), nil
+}
+
+func (e *avroEncoder) Decode(data []byte, out interface{}) error {
+	return e.DecodeWithAttributes(data, map[string]interface{}{}, out)
+}
+
+func (e *avroEncoder) DecodeWithAttributes(data []byte, attributes map[string]interface{}, out interface{}) error {
+	schema, err := e.getWriterSchema(attributes, out)
+
+	if err != nil {
+		return err
+	}
+
+	codec, err := e.getCodec(schema)
+
+	if err != nil {
+		return err
+	}
+
+	binary, err := base64.Decode(data)
+
+	if err != nil {
+		return fmt.Errorf("can not base64 decode the avro record: %w", err)
+	}
+
+	native, _, err := codec.NativeFromBinary(binary)
+
+	if err != nil {
+		return fmt.Errorf("can not decode avro record %s: %w", codec.name, err)
+	}
+
+	textual, err := json.Marshal(codec.unwrapUnions(native))
+
+	if err != nil {
+		return fmt.Errorf("can not marshal avro record %s to json: %w", codec.name, err)
+	}
+
+	if err = json.Unmarshal(textual, out); err != nil {
+		return fmt.Errorf("can not unmarshal avro record %s into model: %w", codec.name, err)
+	}
+
+	return nil
+}
+
+// getWriterSchema returns the schema the message was written with. This is the schema of the model
+// itself as long as there is no registry which knows the schema id stamped on the message.
+func (e *avroEncoder) getWriterSchema(attributes map[string]interface{}, out interface{}) (string, error) {
+	var readerSchema string
+
+	if provider, ok := out.(AvroSchemaProvider); ok {
+		readerSchema = provider.AvroSchema()
+	}
+
+	id, ok, err := readSchemaId(e.registry, attributes)
+
+	if err != nil {
+		return "", err
+	}
+
+	if !ok {
+		if readerSchema == "" {
+			return "", fmt.Errorf("the out parameter of type %T has to implement the AvroSchemaProvider if there is no schema id available", out)
+		}
+
+		return readerSchema, nil
+	}
+
+	if readerSchema != "" {
+		if err = checkSchemaCompatibility(e.registry, attributes, readerSchema); err != nil {
+			return "", err
+		}
+	}
+
+	writerSchema, err := e.registry.GetSchema(id)
+
+	if err != nil {
+		return "", fmt.Errorf("can not get schema %d from registry: %w", id, err)
+	}
+
+	return writerSchema, nil
+}
+
+func (e *avroEncoder) getCodec(schema string) (*avroCodec, error) {
+	if codec, ok := e.codecs.Load(schema); ok {
+		return codec.(*avroCodec), nil
+	}
+
+	codec, err := newAvroCodec(schema)
+
+	if err != nil {
+		return nil, err
+	}
+
+	e.codecs.Store(schema, codec)
+
+	return codec, nil
+}
+
+type avroCodec struct {
+	*goavro.Codec
+	name   string
+	schema interface{}
+	types  map[string]interface{}
+}
+
+func newAvroCodec(schema string) (*avroCodec, error) {
+	codec, err := goavro.NewCodecForStandardJSON(schema)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create avro codec: %w", err)
+	}
+
+	c := &avroCodec{
+		Codec: codec,
+		types: make(map[string]interface{}),
+	}
+
+	if err = json.Unmarshal([]byte(schema), &c.schema); err != nil {
+		return nil, fmt.Errorf("can not unmarshal avro schema: %w", err)
+	}
+
+	c.name = c.collectTypes(c.schema, "")
+
+	return c, nil
+}
+
+// collectTypes registers all named types of the schema by their full name and returns the full name of the given type
+func (c *avroCodec) collectTypes(schema interface{}, namespace string) string {
+	switch s := schema.(type) {
+	case string:
+		return c.resolveTypeName(s, namespace)
+
+	case []interface{}:
+		for _, member := range s {
+			c.collectTypes(member, namespace)
+		}
+
+		return "union"
+
+	case map[string]interface{}:
+		typ, _ := s["type"].(string)
+
+		switch typ {
+		case "record", "error", "enum", "fixed":
+			fullName, ns := avroFullName(s, namespace)
+			c.types[fullName] = s
+
+			if fields, ok := s["fields"].([]interface{}); ok {
+				for _, field := range fields {
+					if f, ok := field.(map[string]interface{}); ok {
+						c.collectTypes(f["type"], ns)
+					}
+				}
+			}
+
+			return fullName
+
+		case "array":
+			c.collectTypes(s["items"], namespace)
+			return typ
+
+		case "map":
+			c.collectTypes(s["values"], namespace)
+			return typ
+
+		default:
+			if logicalType, ok := s["logicalType"].(string); ok {
+				return fmt.Sprintf("%s.%s", typ, logicalType)
+			}
+
+			return c.collectTypes(s["type"], namespace)
+		}
+	}
+
+	return ""
+}
+
+func (c *avroCodec) resolveTypeName(name string, namespace string) string {
+	if _, ok := c.types[name]; ok || namespace == "" || strings.Contains(name, ".") {
+		return name
+	}
+
+	if fullName := fmt.Sprintf("%s.%s", namespace, name); c.types[fullName] != nil {
+		return fullName
+	}
+
+	return name
+}
+
+func (c *avroCodec) unwrapUnions(native interface{}) interface{} {
+	return c.unwrap(c.schema, "", native)
+}
+
+// unwrap replaces the union values of goavro, which are maps of the form {"type": value},
+// with their actual value so the result can be mapped onto a model
+func (c *avroCodec) unwrap(schema interface{}, namespace string, native interface{}) interface{} {
+	if native == nil {
+		return nil
+	}
+
+	switch s := schema.(type) {
+	case string:
+		if named, ok := c.types[c.resolveTypeName(s, namespace)]; ok {
+			return c.unwrap(named, namespace, native)
+		}
+
+		return native
+
+	case []interface{}:
+		union, ok := native.(map[string]interface{})
+
+		if !ok || len(union) != 1 {
+			return native
+		}
+
+		for typeName, value := range union {
+			for _, member := range s {
+				if c.memberName(member, namespace) == typeName {
+					return c.unwrap(member, namespace, value)
+				}
+			}
+
+			return value
+		}
+
+	case map[string]interface{}:
+		typ, _ := s["type"].(string)
+
+		switch typ {
+		case "record", "error":
+			record, ok := native.(map[string]interface{})
+			fields, _ := s["fields"].([]interface{})
+			_, ns := avroFullName(s, namespace)
+
+			if !ok {
+				return native
+			}
+
+			for _, field := range fields {
+				f, ok := field.(map[string]interface{})
+
+				if !ok {
+					continue
+				}
+
+				name, _ := f["name"].(string)
+
+				if value, ok := record[name]; ok {
+					record[name] = c.unwrap(f["type"], ns, value)
+				}
+			}
+
+			return record
+
+		case "array":
+			items, ok := native.([]interface{})
+
+			if !ok {
+				return native
+			}
+
+			for i := range items {
+				items[i] = c.unwrap(s["items"], namespace, items[i])
+			}
+
+			return items
+
+		case "map":
+			values, ok := native.(map[string]interface{})
+
+			if !ok {
+				return native
+			}
+
+			for k := range values {
+				values[k] = c.unwrap(s["values"], namespace, values[k])
+			}
+
+			return values
+
+		case "enum", "fixed":
+			return native
+
+		default:
+			if _, ok := s["logicalType"]; ok {
+				return native
+			}
+
+			return c.unwrap(s["type"], namespace, native)
+		}
+	}
+
+	return native
+}
+
+// memberName returns the name goavro uses as key for a union member
+func (c *avroCodec) memberName(member interface{}, namespace string) string {
+	switch m := member.(type) {
+	case string:
+		return c.resolveTypeName(m, namespace)
+
+	case map[string]interface{}:
+		typ, _ := m["type"].(string)
+
+		switch typ {
+		case "record", "error", "enum", "fixed":
+			fullName, _ := avroFullName(m, namespace)
+			return fullName
+
+		case "array", "map":
+			return typ
+		}
+
+		if logicalType, ok := m["logicalType"].(string); ok {
+			return fmt.Sprintf("%s.%s", typ, logicalType)
+		}
+
+		return c.memberName(m["type"], namespace)
+	}
+
+	return ""
+}
+
+// avroFullName returns the full name of a named type and the namespace its children are defined in
+func avroFullName(schema map[string]interface{}, enclosingNamespace string) (string, string) {
+	name, _ := schema["name"].(string)
+
+	if idx := strings.LastIndex(name, "."); idx != -1 {
+		return name, name[:idx]
+	}
+
+	namespace := enclosingNamespace
+
+	if ns, ok := schema["namespace"].(string); ok {
+		namespace = ns
+	}
+
+	if namespace == "" {
+		return name, namespace
+	}
+
+	return fmt.Sprintf("%s.%s", namespace, name), namespace
+}
@@ -0,0 +1,157 @@
+package stream_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/stream"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type avroAddress struct {
+	City string `json:"city"`
+}
+
+type avroUserV1 struct {
+	Id      int          `json:"id"`
+	Name    string       `json:"name"`
+	Email   *string      `json:"email"`
+	Address *avroAddress `json:"address"`
+	Tags    []string     `json:"tags"`
+}
+
+func (u avroUserV1) AvroSchema() string {
+	return `{
+		"type": "record",
+		"name": "User",
+		"namespace": "com.example",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"},
+			{"name": "email", "type": ["null", "string"], "default": null},
+			{"name": "address", "type": ["null", {"type": "record", "name": "Address", "fields": [{"name": "city", "type": "string"}]}], "default": null},
+			{"name": "tags", "type": {"type": "array", "items": "string"}}
+		]
+	}`
+}
+
+type avroUserV2 struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func (u avroUserV2) AvroSchema() string {
+	return `{
+		"type": "record",
+		"name": "User",
+		"namespace": "com.example",
+		"fields": [
+			{"name": "id", "type": "int"},
+			{"name": "name", "type": "string"}
+		]
+	}`
+}
+
+func TestAvroEncoder_EncodeDecode(t *testing.T) {
+	encoder := stream.NewAvroEncoder(nil)
+	email := "john@example.com"
+
+	body, err := encoder.Encode(&avroUserV1{
+		Id:    1,
+		Name:  "John",
+		Email: &email,
+		Address: &avroAddress{
+			City: "Cologne",
+		},
+		Tags: []string{"a", "b"},
+	})
+	assert.NoError(t, err)
+
+	out := &avroUserV1{}
+	err = encoder.Decode(body, out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &avroUserV1{
+		Id:    1,
+		Name:  "John",
+		Email: &email,
+		Address: &avroAddress{
+			City: "Cologne",
+		},
+		Tags: []string{"a", "b"},
+	}, out)
+
+	body, err = encoder.Encode(&avroUserV1{
+		Id:   2,
+		Name: "Jane",
+		Tags: []string{},
+	})
+	assert.NoError(t, err)
+
+	out = &avroUserV1{}
+	err = encoder.Decode(body, out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &avroUserV1{
+		Id:   2,
+		Name: "Jane",
+		Tags: []string{},
+	}, out)
+}
+
+func TestAvroEncoder_NoSchema(t *testing.T) {
+	encoder := stream.NewAvroEncoder(nil)
+
+	_, err := encoder.Encode("foobar")
+	assert.EqualError(t, err, "the data of type string has to implement the AvroSchemaProvider")
+
+	out := ""
+	err = encoder.Decode([]byte{}, &out)
+	assert.EqualError(t, err, "the out parameter of type *string has to implement the AvroSchemaProvider if there is no schema id available")
+}
+
+func TestAvroEncoder_WithSchemaRegistry(t *testing.T) {
+	registry := stream.NewInMemorySchemaRegistry()
+	stream.WithSchemaRegistry(registry)
+	defer stream.WithSchemaRegistry(nil)
+
+	_, err := registry.Register("com.example.User", avroUserV2{}.AvroSchema())
+	assert.NoError(t, err)
+
+	encoder := stream.NewMessageEncoder(&stream.MessageEncoderSettings{
+		Encoding: stream.EncodingAvro,
+	})
+
+	ctx := context.Background()
+	msg, err := encoder.Encode(ctx, &avroUserV1{
+		Id:   1,
+		Name: "John",
+		Tags: []string{"a"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "com.example.User", msg.Attributes[stream.AttributeSchemaSubject])
+	assert.Equal(t, 2, msg.Attributes[stream.AttributeSchemaId])
+
+	// simulate the transport of the message as json
+	msg.Attributes[stream.AttributeSchemaId] = float64(2)
+
+	out := &avroUserV2{}
+	_, _, err = encoder.Decode(ctx, msg, out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &avroUserV2{Id: 1, Name: "John"}, out)
+
+	msg, err = encoder.Encode(ctx, &avroUserV1{Id: 1, Name: "John", Tags: []string{}})
+	assert.NoError(t, err)
+
+	_, _, err = encoder.Decode(ctx, msg, &avroOther{})
+	assert.EqualError(t, err, "can not decode message body: can not decode message body with encoding 'avro/binary': the schema 2 of the message is not compatible with the schema of the model")
+}
+
+type avroOther struct {
+	Id int `json:"id"`
+}
+
+func (o avroOther) AvroSchema() string {
+	return `{"type": "record", "name": "Other", "fields": [{"name": "id", "type": "int"}]}`
+}
@@ -0,0 +1,137 @@
+package stream
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/encoding/base64"
+	"github.com/golang/protobuf/descriptor"
+	"github.com/golang/protobuf/proto"
+	"reflect"
+	"sync"
+)
+
+const (
+	EncodingProtobuf         = "application/x-protobuf"
+	AttributeProtobufMessage = "protobufMessage"
+)
+
+// protobufEncoder encodes proto messages into their base64 encoded wire format. The file descriptor of
+// the message type is registered as schema and the full name of the type is stamped on the message to be
+// able to detect mismatching models.
+type protobufEncoder struct {
+	registry SchemaRegistry
+	schemas  sync.Map
+}
+
+func NewProtobufEncoder(registry SchemaRegistry) *protobufEncoder {
+	return &protobufEncoder{
+		registry: registry,
+	}
+}
+
+func (e *protobufEncoder) Encode(data interface{}) ([]byte, error) {
+	return e.EncodeWithAttributes(data, map[string]interface{}{})
+}
+
+func (e *protobufEncoder) EncodeWithAttributes(data interface{}, attributes map[string]interface{}) ([]byte, error) {
+	msg, ok := data.(proto.Message)
+
+	if !ok {
+		return nil, fmt.Errorf("the data of type %T has to implement proto.Message", data)
+	}
+
+	name := protobufMessageName(msg)
+	attributes[AttributeProtobufMessage] = name
+
+	if e.registry == nil {
+		attributes[AttributeSchemaSubject] = schemaSubject(attributes, name)
+	} else {
+		schema, err := e.getSchema(msg)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err = stampSchema(e.registry, attributes, name, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	bytes, err := proto.Marshal(msg)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not marshal proto message %s: %w", name, err)
+	}
+
+	return base64.Encode(bytes), nil
+}
+
+func (e *protobufEncoder) Decode(data []byte, out interface{}) error {
+	return e.DecodeWithAttributes(data, map[string]interface{}{}, out)
+}
+
+func (e *protobufEncoder) DecodeWithAttributes(data []byte, attributes map[string]interface{}, out interface{}) error {
+	msg, ok := out.(proto.Message)
+
+	if !ok {
+		return fmt.Errorf("the out parameter of type %T has to implement proto.Message", out)
+	}
+
+	name := protobufMessageName(msg)
+
+	if typ, ok := attributes[AttributeProtobufMessage]; ok && typ != name {
+		return fmt.Errorf("can not decode a message of type %v into a model of type %s", typ, name)
+	}
+
+	if _, ok := attributes[AttributeSchemaId]; ok && e.registry != nil {
+		schema, err := e.getSchema(msg)
+
+		if err != nil {
+			return err
+		}
+
+		if err = checkSchemaCompatibility(e.registry, attributes, schema); err != nil {
+			return err
+		}
+	}
+
+	bytes, err := base64.Decode(data)
+
+	if err != nil {
+		return fmt.Errorf("can not base64 decode the proto message: %w", err)
+	}
+
+	if err = proto.Unmarshal(bytes, msg); err != nil {
+		return fmt.Errorf("can not unmarshal proto message %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// getSchema returns the file descriptor the message type is defined in as text
+func (e *protobufEncoder) getSchema(msg proto.Message) (string, error) {
+	typ := reflect.TypeOf(msg)
+
+	if schema, ok := e.schemas.Load(typ); ok {
+		return schema.(string), nil
+	}
+
+	described, ok := msg.(descriptor.Message)
+
+	if !ok {
+		return "", fmt.Errorf("the proto message %s has no descriptor to register as schema", protobufMessageName(msg))
+	}
+
+	file, _ := descriptor.ForMessage(described)
+	schema := proto.MarshalTextString(file)
+	e.schemas.Store(typ, schema)
+
+	return schema, nil
+}
+
+func protobufMessageName(msg proto.Message) string {
+	if name := proto.MessageName(msg); name != "" {
+		return name
+	}
+
+	return fmt.Sprintf("%T", msg)
+}
@@ -0,0 +1,70 @@
+package stream_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/stream"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestProtobufEncoder_EncodeDecode(t *testing.T) {
+	encoder := stream.NewProtobufEncoder(nil)
+
+	body, err := encoder.Encode(&wrappers.StringValue{Value: "foobar"})
+	assert.NoError(t, err)
+
+	out := &wrappers.StringValue{}
+	err = encoder.Decode(body, out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "foobar", out.Value)
+}
+
+func TestProtobufEncoder_NoProtoMessage(t *testing.T) {
+	encoder := stream.NewProtobufEncoder(nil)
+
+	_, err := encoder.Encode("foobar")
+	assert.EqualError(t, err, "the data of type string has to implement proto.Message")
+
+	out := ""
+	err = encoder.Decode([]byte{}, &out)
+	assert.EqualError(t, err, "the out parameter of type *string has to implement proto.Message")
+}
+
+func TestProtobufEncoder_WithSchemaRegistry(t *testing.T) {
+	registry := stream.NewInMemorySchemaRegistry()
+	stream.AddMessageBodyEncoder(stream.EncodingProtobuf, stream.NewProtobufEncoder(registry))
+	defer stream.AddMessageBodyEncoder(stream.EncodingProtobuf, stream.NewProtobufEncoder(nil))
+
+	encoder := stream.NewMessageEncoder(&stream.MessageEncoderSettings{
+		Encoding:      stream.EncodingProtobuf,
+		SchemaSubject: "numbers-value",
+	})
+
+	ctx := context.Background()
+	msg, err := encoder.Encode(ctx, &wrappers.Int64Value{Value: 42})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "numbers-value", msg.Attributes[stream.AttributeSchemaSubject])
+	assert.Equal(t, "google.protobuf.Int64Value", msg.Attributes[stream.AttributeProtobufMessage])
+	assert.Equal(t, 1, msg.Attributes[stream.AttributeSchemaId])
+
+	schema, err := registry.GetSchema(1)
+	assert.NoError(t, err)
+	assert.Contains(t, schema, `name: "google/protobuf/wrappers.proto"`)
+	assert.Contains(t, schema, `name: "Int64Value"`)
+
+	out := &wrappers.Int64Value{}
+	_, attributes, err := encoder.Decode(ctx, stream.NewMessage(msg.Body, msg.Attributes), out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), out.Value)
+	assert.Equal(t, 1, attributes[stream.AttributeSchemaId])
+
+	msg, err = encoder.Encode(ctx, &wrappers.Int64Value{Value: 42})
+	assert.NoError(t, err)
+
+	_, _, err = encoder.Decode(ctx, msg, &wrappers.StringValue{})
+	assert.EqualError(t, err, "can not decode message body: can not decode message body with encoding 'application/x-protobuf': can not decode a message of type google.protobuf.Int64Value into a model of type google.protobuf.StringValue")
+}
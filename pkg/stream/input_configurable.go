@@ -6,29 +6,34 @@ import (
 	"github.com/applike/gosoline/pkg/cloud"
 	"github.com/applike/gosoline/pkg/cloud/aws/kinesis"
 	"github.com/applike/gosoline/pkg/exec"
+	"github.com/applike/gosoline/pkg/mdl"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/applike/gosoline/pkg/sqs"
 	"time"
 )
 
 const (
-	InputTypeFile     = "file"
-	InputTypeInMemory = "inMemory"
-	InputTypeKinesis  = "kinesis"
-	InputTypeRedis    = "redis"
-	InputTypeSns      = "sns"
-	InputTypeSqs      = "sqs"
+	InputTypeDdbStream = "ddbStream"
+	InputTypeFile      = "file"
+	InputTypeInMemory  = "inMemory"
+	InputTypeKafka     = "kafka"
+	InputTypeKinesis   = "kinesis"
+	InputTypeRedis     = "redis"
+	InputTypeSns       = "sns"
+	InputTypeSqs       = "sqs"
 )
 
 type InputFactory func(config cfg.Config, logger mon.Logger, name string) (Input, error)
 
 var inputFactories = map[string]InputFactory{
-	InputTypeFile:     newFileInputFromConfig,
-	InputTypeInMemory: newInMemoryInputFromConfig,
-	InputTypeKinesis:  newKinesisInputFromConfig,
-	InputTypeRedis:    newRedisInputFromConfig,
-	InputTypeSns:      newSnsInputFromConfig,
-	InputTypeSqs:      newSqsInputFromConfig,
+	InputTypeDdbStream: newDdbStreamInputFromConfig,
+	InputTypeFile:      newFileInputFromConfig,
+	InputTypeInMemory:  newInMemoryInputFromConfig,
+	InputTypeKafka:     newKafkaInputFromConfig,
+	InputTypeKinesis:   newKinesisInputFromConfig,
+	InputTypeRedis:     newRedisInputFromConfig,
+	InputTypeSns:       newSnsInputFromConfig,
+	InputTypeSqs:       newSqsInputFromConfig,
 }
 
 func SetInputFactory(typ string, factory InputFactory) {
@@ -71,6 +76,51 @@ func NewConfigurableInput(config cfg.Config, logger mon.Logger, name string) (In
 	return input, nil
 }
 
+type ddbStreamInputConfiguration struct {
+	Project              string               `cfg:"project"`
+	Family               string               `cfg:"family"`
+	Application          string               `cfg:"application"`
+	Table                string               `cfg:"table" validate:"required"`
+	StreamArn            string               `cfg:"stream_arn"`
+	CheckpointStore      string               `cfg:"checkpoint_store"`
+	StartPosition        string               `cfg:"start_position" default:"TRIM_HORIZON" validate:"oneof=TRIM_HORIZON LATEST"`
+	BatchSize            int64                `cfg:"batch_size" default:"100" validate:"min=1,max=1000"`
+	WaitTime             time.Duration        `cfg:"wait_time" default:"1s"`
+	ShardRefreshInterval time.Duration        `cfg:"shard_refresh_interval" default:"1m"`
+	LeaseTime            time.Duration        `cfg:"lease_time" default:"1m"`
+	AckTimeout           time.Duration        `cfg:"ack_timeout" default:"5m"`
+	Client               cloud.ClientSettings `cfg:"client"`
+	Backoff              exec.BackoffSettings `cfg:"backoff"`
+}
+
+func newDdbStreamInputFromConfig(config cfg.Config, logger mon.Logger, name string) (Input, error) {
+	key := ConfigurableInputKey(name)
+
+	configuration := ddbStreamInputConfiguration{}
+	config.UnmarshalKey(key, &configuration, cfg.UnmarshalWithDefaultsFromKey(ConfigKeyStreamBackoff, "backoff"))
+
+	settings := DdbStreamInputSettings{
+		ModelId: mdl.ModelId{
+			Project:     configuration.Project,
+			Family:      configuration.Family,
+			Application: configuration.Application,
+			Name:        configuration.Table,
+		},
+		StreamArn:            configuration.StreamArn,
+		CheckpointStore:      configuration.CheckpointStore,
+		StartPosition:        configuration.StartPosition,
+		BatchSize:            configuration.BatchSize,
+		WaitTime:             configuration.WaitTime,
+		ShardRefreshInterval: configuration.ShardRefreshInterval,
+		LeaseTime:            configuration.LeaseTime,
+		AckTimeout:           configuration.AckTimeout,
+		Client:               configuration.Client,
+		Backoff:              configuration.Backoff,
+	}
+
+	return NewDdbStreamInput(config, logger, settings)
+}
+
 func newFileInputFromConfig(config cfg.Config, logger mon.Logger, name string) (Input, error) {
 	key := ConfigurableInputKey(name)
 	settings := FileSettings{}
@@ -87,6 +137,14 @@ func newInMemoryInputFromConfig(config cfg.Config, _ mon.Logger, name string) (I
 	return ProvideInMemoryInput(name, settings), nil
 }
 
+func newKafkaInputFromConfig(config cfg.Config, logger mon.Logger, name string) (Input, error) {
+	key := ConfigurableInputKey(name)
+	settings := KafkaInputSettings{}
+	config.UnmarshalKey(key, &settings, cfg.UnmarshalWithDefaultsFromKey(ConfigKeyStreamBackoff, "backoff"))
+
+	return NewKafkaInput(config, logger, settings), nil
+}
+
 type kinesisInputConfiguration struct {
 	StreamName      string `cfg:"stream_name" validate:"required"`
 	ApplicationName string `cfg:"application_name" validate:"required"`
@@ -136,10 +194,11 @@ func newRedisInputFromConfig(config cfg.Config, logger mon.Logger, name string)
 }
 
 type SnsInputTargetConfiguration struct {
-	Family      string                 `cfg:"family"`
-	Application string                 `cfg:"application" validate:"required"`
-	TopicId     string                 `cfg:"topic_id" validate:"required"`
-	Attributes  map[string]interface{} `cfg:"attributes"`
+	Family                    string                 `cfg:"family"`
+	Application               string                 `cfg:"application" validate:"required"`
+	TopicId                   string                 `cfg:"topic_id" validate:"required"`
+	Attributes                map[string]interface{} `cfg:"attributes"`
+	ContentBasedDeduplication bool                   `cfg:"content_based_deduplication" default:"false"`
 }
 
 type SnsInputConfiguration struct {
@@ -152,6 +211,7 @@ type SnsInputConfiguration struct {
 	WaitTime            int64                         `cfg:"wait_time" default:"3" validate:"min=1"`
 	VisibilityTimeout   int                           `cfg:"visibility_timeout" default:"30" validate:"min=1"`
 	RunnerCount         int                           `cfg:"runner_count" default:"1" validate:"min=1"`
+	Fifo                sqs.FifoSettings              `cfg:"fifo"`
 	RedrivePolicy       sqs.RedrivePolicy             `cfg:"redrive_policy"`
 	Client              cloud.ClientSettings          `cfg:"client"`
 	Backoff             exec.BackoffSettings          `cfg:"backoff"`
@@ -173,6 +233,7 @@ func readSnsInputSettings(config cfg.Config, name string) (SnsInputSettings, []S
 		WaitTime:            configuration.WaitTime,
 		VisibilityTimeout:   configuration.VisibilityTimeout,
 		RunnerCount:         configuration.RunnerCount,
+		Fifo:                configuration.Fifo,
 		RedrivePolicy:       configuration.RedrivePolicy,
 		Client:              configuration.Client,
 		Backoff:             configuration.Backoff,
@@ -187,8 +248,9 @@ func readSnsInputSettings(config cfg.Config, name string) (SnsInputSettings, []S
 				Family:      t.Family,
 				Application: t.Application,
 			},
-			TopicId:    t.TopicId,
-			Attributes: t.Attributes,
+			TopicId:                   t.TopicId,
+			Attributes:                t.Attributes,
+			ContentBasedDeduplication: t.ContentBasedDeduplication,
 		}
 	}
 
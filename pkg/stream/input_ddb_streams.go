@@ -0,0 +1,740 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/cloud"
+	"github.com/applike/gosoline/pkg/coffin"
+	"github.com/applike/gosoline/pkg/conc"
+	"github.com/applike/gosoline/pkg/ddb"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"github.com/applike/gosoline/pkg/exec"
+	"github.com/applike/gosoline/pkg/kvstore"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"github.com/spf13/cast"
+	"reflect"
+	"sync"
+	"time"
+)
+
+const (
+	AttributeDdbStreamEventName      = "ddbStreamEventName"
+	AttributeDdbStreamShardId        = "ddbStreamShardId"
+	AttributeDdbStreamSequenceNumber = "ddbStreamSequenceNumber"
+)
+
+//go:generate mockery -name DdbStreamsClient
+type DdbStreamsClient interface {
+	DescribeStreamWithContext(ctx aws.Context, input *dynamodbstreams.DescribeStreamInput, opts ...request.Option) (*dynamodbstreams.DescribeStreamOutput, error)
+	GetRecordsWithContext(ctx aws.Context, input *dynamodbstreams.GetRecordsInput, opts ...request.Option) (*dynamodbstreams.GetRecordsOutput, error)
+	GetShardIteratorWithContext(ctx aws.Context, input *dynamodbstreams.GetShardIteratorInput, opts ...request.Option) (*dynamodbstreams.GetShardIteratorOutput, error)
+}
+
+type DdbStreamInputSettings struct {
+	ModelId mdl.ModelId
+	// the images of the records are decoded into the model, a map is used if no model is given
+	Model interface{}
+	// the arn is read from the table description if empty
+	StreamArn string
+	// name of a configurable kvstore keeping the checkpoints, a ddb kvstore is used if empty
+	CheckpointStore      string
+	StartPosition        string
+	BatchSize            int64
+	WaitTime             time.Duration
+	ShardRefreshInterval time.Duration
+	LeaseTime            time.Duration
+	// time to wait for the acknowledgement of the remaining records of a closed shard before it is released
+	// and the unacknowledged records are read again
+	AckTimeout time.Duration
+	Client     cloud.ClientSettings
+	Backoff    exec.BackoffSettings
+}
+
+// DdbStreamCheckpoint is the position of the input in a shard. A shard is finished once all of its records
+// were acknowledged and the shards split from it can be read.
+type DdbStreamCheckpoint struct {
+	SequenceNumber string `json:"sequenceNumber"`
+	Finished       bool   `json:"finished"`
+}
+
+type ddbStreamShard struct {
+	lck  sync.Mutex
+	id   string
+	sent string
+	// sequence numbers of the records which were sent but not checkpointed yet, in the order of the shard
+	pending []string
+	// records of pending which were acknowledged already
+	acked  map[string]bool
+	closed bool
+}
+
+type ddbStreamInput struct {
+	logger      mon.Logger
+	client      DdbStreamsClient
+	checkpoints kvstore.KvStore
+	locks       conc.DistributedLockProvider
+	settings    DdbStreamInputSettings
+	tableName   string
+	modelType   reflect.Type
+
+	cfn     coffin.Coffin
+	channel chan *Message
+
+	lck     sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stopped bool
+	shards  map[string]*ddbStreamShard
+}
+
+func NewDdbStreamInput(config cfg.Config, logger mon.Logger, settings DdbStreamInputSettings) (*ddbStreamInput, error) {
+	settings.ModelId.PadFromConfig(config)
+
+	ddbSettings := &ddb.Settings{
+		ModelId: settings.ModelId,
+		Client:  settings.Client,
+		Backoff: settings.Backoff,
+		Main: ddb.MainSettings{
+			Model: settings.Model,
+		},
+	}
+
+	tableName := ddb.TableName(ddbSettings)
+
+	if settings.Model != nil {
+		if _, err := ddb.NewMetadataFactory().GetMetadata(ddbSettings); err != nil {
+			return nil, fmt.Errorf("can not read the metadata of the model of table %s: %w", tableName, err)
+		}
+	}
+
+	if settings.StreamArn == "" {
+		description, err := ddb.NewService(config, logger).DescribeTable(ddbSettings)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not read the stream arn: %w", err)
+		}
+
+		if description.StreamArn == "" {
+			return nil, fmt.Errorf("there is no stream enabled on the table %s", description.Name)
+		}
+
+		settings.StreamArn = description.StreamArn
+	}
+
+	checkpoints, err := newDdbStreamCheckpointStore(config, logger, settings)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create checkpoint store: %w", err)
+	}
+
+	locks, err := conc.NewDdbLockProvider(config, logger, conc.DistributedLockSettings{
+		Backoff:         settings.Backoff,
+		DefaultLockTime: settings.LeaseTime,
+		Domain:          fmt.Sprintf("ddbStream-%s", tableName),
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create lease provider: %w", err)
+	}
+
+	awsConfig := cloud.GetAwsConfig(config, logger, "dynamoDbStreams", &settings.Client)
+	client := dynamodbstreams.New(session.Must(session.NewSession(awsConfig)))
+
+	return NewDdbStreamInputWithInterfaces(logger, client, checkpoints, locks, tableName, settings), nil
+}
+
+func NewDdbStreamInputWithInterfaces(logger mon.Logger, client DdbStreamsClient, checkpoints kvstore.KvStore, locks conc.DistributedLockProvider, tableName string, settings DdbStreamInputSettings) *ddbStreamInput {
+	var modelType reflect.Type
+
+	if settings.Model != nil {
+		modelType = reflect.TypeOf(settings.Model)
+
+		if modelType.Kind() == reflect.Ptr {
+			modelType = modelType.Elem()
+		}
+	}
+
+	return &ddbStreamInput{
+		logger:      logger.WithChannel("ddbStream"),
+		client:      client,
+		checkpoints: checkpoints,
+		locks:       locks,
+		settings:    settings,
+		tableName:   tableName,
+		modelType:   modelType,
+		ctx:         context.Background(),
+		cfn:         coffin.New(),
+		channel:     make(chan *Message),
+		shards:      make(map[string]*ddbStreamShard),
+	}
+}
+
+func newDdbStreamCheckpointStore(config cfg.Config, logger mon.Logger, settings DdbStreamInputSettings) (kvstore.KvStore, error) {
+	if settings.CheckpointStore != "" {
+		return kvstore.NewConfigurableKvStore(config, logger, settings.CheckpointStore)
+	}
+
+	return kvstore.NewDdbKvStore(config, logger, &kvstore.Settings{
+		AppId: cfg.AppId{
+			Project:     settings.ModelId.Project,
+			Environment: settings.ModelId.Environment,
+			Family:      settings.ModelId.Family,
+			Application: settings.ModelId.Application,
+		},
+		Name: fmt.Sprintf("%s-checkpoints", settings.ModelId.Name),
+	})
+}
+
+func (i *ddbStreamInput) Data() chan *Message {
+	return i.channel
+}
+
+func (i *ddbStreamInput) Run(ctx context.Context) error {
+	defer close(i.channel)
+	defer i.logger.Info("leaving ddb stream input")
+
+	if ctx = i.start(ctx); ctx == nil {
+		return nil
+	}
+
+	i.logger.Infof("starting ddb stream input for table %s", i.tableName)
+
+	i.cfn.GoWithContextf(i.cfn.Context(ctx), i.runShardDiscovery, "panic in ddb stream shard discovery")
+
+	return i.cfn.Wait()
+}
+
+// start derives a context which is canceled on stop. It returns nil if the input was already stopped. The
+// checkpoints are written with the context of the input, so the records still processed after a stop can be
+// checkpointed.
+func (i *ddbStreamInput) start(ctx context.Context) context.Context {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	if i.stopped {
+		return nil
+	}
+
+	i.ctx = ctx
+	ctx, i.cancel = context.WithCancel(ctx)
+
+	return ctx
+}
+
+func (i *ddbStreamInput) Stop() {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	i.stopped = true
+
+	if i.cancel != nil {
+		i.cancel()
+	}
+}
+
+func (i *ddbStreamInput) Ack(msg *Message) error {
+	return i.AckBatch([]*Message{msg})
+}
+
+// AckBatch checkpoints every shard at the last record before the first record which isn't acknowledged yet. As
+// the checkpoint is the position to continue reading after, no record is skipped if the messages are processed
+// and acknowledged out of order.
+func (i *ddbStreamInput) AckBatch(msgs []*Message) error {
+	shards := make(map[string][]string)
+
+	for _, msg := range msgs {
+		shardId, sequenceNumber, err := messageToDdbStreamPosition(msg)
+
+		if err != nil {
+			return err
+		}
+
+		shards[shardId] = append(shards[shardId], sequenceNumber)
+	}
+
+	i.lck.Lock()
+	ctx := i.ctx
+	i.lck.Unlock()
+
+	for shardId, sequenceNumbers := range shards {
+		if err := i.ackShard(ctx, shardId, sequenceNumbers); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *ddbStreamInput) ackShard(ctx context.Context, shardId string, sequenceNumbers []string) error {
+	i.lck.Lock()
+	shard, ok := i.shards[shardId]
+	i.lck.Unlock()
+
+	if !ok {
+		return fmt.Errorf("the lease of the shard %s was lost", shardId)
+	}
+
+	shard.lck.Lock()
+	defer shard.lck.Unlock()
+
+	for _, sequenceNumber := range sequenceNumbers {
+		// records read during an earlier lease of the shard are ignored, they are sent again by this lease
+		if _, ok := shard.acked[sequenceNumber]; ok {
+			shard.acked[sequenceNumber] = true
+		}
+	}
+
+	done := 0
+
+	for done < len(shard.pending) && shard.acked[shard.pending[done]] {
+		done++
+	}
+
+	if done == 0 {
+		return nil
+	}
+
+	checkpoint := &DdbStreamCheckpoint{
+		SequenceNumber: shard.pending[done-1],
+	}
+
+	if err := i.checkpoints.Put(ctx, i.checkpointKey(shardId), checkpoint); err != nil {
+		return fmt.Errorf("can not checkpoint shard %s: %w", shardId, err)
+	}
+
+	for _, sequenceNumber := range shard.pending[:done] {
+		delete(shard.acked, sequenceNumber)
+	}
+
+	shard.pending = shard.pending[done:]
+
+	return nil
+}
+
+func (i *ddbStreamInput) runShardDiscovery(ctx context.Context) error {
+	ticker := time.NewTicker(i.settings.ShardRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := i.discoverShards(ctx); err != nil && ctx.Err() == nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// discoverShards starts a reader for every shard which isn't read yet. Shards split from a parent are
+// only read after the parent was finished to keep the order of the records per item.
+func (i *ddbStreamInput) discoverShards(ctx context.Context) error {
+	shards, err := i.describeShards(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for shardId, shard := range shards {
+		i.lck.Lock()
+		_, running := i.shards[shardId]
+		i.lck.Unlock()
+
+		if running {
+			continue
+		}
+
+		if shard.ParentShardId != nil {
+			if _, ok := shards[*shard.ParentShardId]; ok {
+				checkpoint, err := i.readCheckpoint(ctx, *shard.ParentShardId)
+
+				if err != nil {
+					return err
+				}
+
+				if !checkpoint.Finished {
+					continue
+				}
+			}
+		}
+
+		state := &ddbStreamShard{
+			id:    shardId,
+			acked: make(map[string]bool),
+		}
+
+		i.lck.Lock()
+		i.shards[shardId] = state
+		i.lck.Unlock()
+
+		i.cfn.GoWithContextf(ctx, func(ctx context.Context) error {
+			if err := i.consumeShard(ctx, state); err != nil {
+				i.logger.WithContext(ctx).Errorf(err, "can not consume shard %s, it is leased again on the next discovery", state.id)
+			}
+
+			return nil
+		}, "panic in ddb stream shard %s", shardId)
+	}
+
+	return nil
+}
+
+func (i *ddbStreamInput) describeShards(ctx context.Context) (map[string]*dynamodbstreams.Shard, error) {
+	shards := make(map[string]*dynamodbstreams.Shard)
+	input := &dynamodbstreams.DescribeStreamInput{
+		StreamArn: aws.String(i.settings.StreamArn),
+	}
+
+	for {
+		out, err := i.client.DescribeStreamWithContext(ctx, input)
+
+		if err != nil {
+			return nil, fmt.Errorf("can not describe the stream of table %s: %w", i.tableName, err)
+		}
+
+		for _, shard := range out.StreamDescription.Shards {
+			shards[*shard.ShardId] = shard
+		}
+
+		if out.StreamDescription.LastEvaluatedShardId == nil {
+			return shards, nil
+		}
+
+		input.ExclusiveStartShardId = out.StreamDescription.LastEvaluatedShardId
+	}
+}
+
+func (i *ddbStreamInput) consumeShard(ctx context.Context, shard *ddbStreamShard) error {
+	logger := i.logger.WithContext(ctx).WithFields(mon.Fields{
+		"ddb_stream_shard_id": shard.id,
+	})
+
+	lease, err := i.locks.Acquire(ctx, shard.id)
+
+	if errors.Is(err, conc.ErrOwnedLock) {
+		// the shard is read by another instance, we try again on the next discovery
+		i.removeShard(shard)
+		return nil
+	}
+
+	if err != nil {
+		i.removeShard(shard)
+		return fmt.Errorf("can not acquire the lease of shard %s: %w", shard.id, err)
+	}
+
+	// the lease is renewed independently of the reading, which blocks as long as the records aren't consumed
+	ctx, stopLease := context.WithCancel(ctx)
+	leaseStopped := make(chan struct{})
+
+	i.cfn.GoWithContextf(ctx, func(ctx context.Context) error {
+		defer close(leaseStopped)
+		i.renewLease(ctx, stopLease, shard, lease, logger)
+
+		return nil
+	}, "panic in lease renewal of ddb stream shard %s", shard.id)
+
+	defer func() {
+		stopLease()
+		<-leaseStopped
+		i.releaseShard(shard, lease, logger)
+	}()
+
+	checkpoint, err := i.readCheckpoint(ctx, shard.id)
+
+	if err != nil {
+		return err
+	}
+
+	if checkpoint.Finished {
+		return nil
+	}
+
+	shard.lck.Lock()
+	shard.sent = checkpoint.SequenceNumber
+	shard.lck.Unlock()
+
+	logger.Infof("leased shard %s starting after %q", shard.id, checkpoint.SequenceNumber)
+
+	iterator, err := i.getShardIterator(ctx, shard.id, checkpoint.SequenceNumber)
+
+	if err != nil {
+		return err
+	}
+
+	for iterator != nil {
+		out, err := i.client.GetRecordsWithContext(ctx, &dynamodbstreams.GetRecordsInput{
+			ShardIterator: iterator,
+			Limit:         aws.Int64(i.settings.BatchSize),
+		})
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if isAwsErrorCode(err, dynamodbstreams.ErrCodeExpiredIteratorException) {
+			shard.lck.Lock()
+			sent := shard.sent
+			shard.lck.Unlock()
+
+			if iterator, err = i.getShardIterator(ctx, shard.id, sent); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("can not get records of shard %s: %w", shard.id, err)
+		}
+
+		for _, record := range out.Records {
+			msg, err := i.recordToMessage(shard.id, record)
+
+			if err != nil {
+				return err
+			}
+
+			sequenceNumber := *record.Dynamodb.SequenceNumber
+
+			// the record is pending before it is sent, as it can be acknowledged before the send returns
+			shard.lck.Lock()
+			shard.pending = append(shard.pending, sequenceNumber)
+			shard.acked[sequenceNumber] = false
+			shard.lck.Unlock()
+
+			select {
+			case i.channel <- msg:
+			case <-ctx.Done():
+				return nil
+			}
+
+			shard.lck.Lock()
+			shard.sent = sequenceNumber
+			shard.lck.Unlock()
+		}
+
+		iterator = out.NextShardIterator
+
+		if iterator == nil || len(out.Records) > 0 {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(i.settings.WaitTime):
+		}
+	}
+
+	return i.finishShard(ctx, shard, logger)
+}
+
+// renewLease renews the lease of the shard until the context is canceled. If the lease is lost, the reading of
+// the shard is stopped by canceling its context.
+func (i *ddbStreamInput) renewLease(ctx context.Context, stopLease context.CancelFunc, shard *ddbStreamShard, lease conc.DistributedLock, logger mon.Logger) {
+	ticker := time.NewTicker(i.settings.LeaseTime / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := lease.Renew(ctx, i.settings.LeaseTime); err != nil {
+			if ctx.Err() == nil {
+				logger.Warnf("lost the lease of shard %s: %s", shard.id, err)
+			}
+
+			stopLease()
+
+			return
+		}
+	}
+}
+
+// finishShard waits for the acknowledgement of all records of a closed shard and marks the shard as finished. If
+// the records aren't acknowledged within the ack timeout, the shard is released and read again from its last
+// checkpoint on the next discovery.
+func (i *ddbStreamInput) finishShard(ctx context.Context, shard *ddbStreamShard, logger mon.Logger) error {
+	var sent string
+	var pending int
+
+	timeout := time.NewTimer(i.settings.AckTimeout)
+	defer timeout.Stop()
+
+	for {
+		shard.lck.Lock()
+		pending = len(shard.pending)
+		sent = shard.sent
+		shard.lck.Unlock()
+
+		if pending == 0 {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timeout.C:
+			logger.Warnf("%d records of the closed shard %s were not acknowledged within %s, releasing the shard to read them again", pending, shard.id, i.settings.AckTimeout)
+			return nil
+		case <-time.After(i.settings.WaitTime):
+		}
+	}
+
+	checkpoint := &DdbStreamCheckpoint{
+		SequenceNumber: sent,
+		Finished:       true,
+	}
+
+	if err := i.checkpoints.Put(ctx, i.checkpointKey(shard.id), checkpoint); err != nil {
+		return fmt.Errorf("can not mark shard %s as finished: %w", shard.id, err)
+	}
+
+	shard.lck.Lock()
+	shard.closed = true
+	shard.lck.Unlock()
+
+	logger.Infof("finished shard %s at %q", shard.id, sent)
+
+	return nil
+}
+
+// releaseShard releases the lease of a shard. Unfinished shards are forgotten, so they are leased again
+// on the next discovery.
+func (i *ddbStreamInput) releaseShard(shard *ddbStreamShard, lease conc.DistributedLock, logger mon.Logger) {
+	if err := lease.Release(); err != nil && !errors.Is(err, conc.ErrNotOwned) {
+		logger.Warnf("can not release the lease of shard %s: %s", shard.id, err)
+	}
+
+	shard.lck.Lock()
+	closed := shard.closed
+	shard.lck.Unlock()
+
+	if !closed {
+		i.removeShard(shard)
+	}
+}
+
+func (i *ddbStreamInput) removeShard(shard *ddbStreamShard) {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	delete(i.shards, shard.id)
+}
+
+func (i *ddbStreamInput) getShardIterator(ctx context.Context, shardId string, sequenceNumber string) (*string, error) {
+	input := &dynamodbstreams.GetShardIteratorInput{
+		StreamArn:         aws.String(i.settings.StreamArn),
+		ShardId:           aws.String(shardId),
+		ShardIteratorType: aws.String(i.settings.StartPosition),
+	}
+
+	if sequenceNumber != "" {
+		input.ShardIteratorType = aws.String(dynamodbstreams.ShardIteratorTypeAfterSequenceNumber)
+		input.SequenceNumber = aws.String(sequenceNumber)
+	}
+
+	out, err := i.client.GetShardIteratorWithContext(ctx, input)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not get iterator for shard %s: %w", shardId, err)
+	}
+
+	return out.ShardIterator, nil
+}
+
+func (i *ddbStreamInput) readCheckpoint(ctx context.Context, shardId string) (*DdbStreamCheckpoint, error) {
+	checkpoint := &DdbStreamCheckpoint{}
+
+	if _, err := i.checkpoints.Get(ctx, i.checkpointKey(shardId), checkpoint); err != nil {
+		return nil, fmt.Errorf("can not read checkpoint of shard %s: %w", shardId, err)
+	}
+
+	return checkpoint, nil
+}
+
+func (i *ddbStreamInput) checkpointKey(shardId string) string {
+	return fmt.Sprintf("%s-%s", i.tableName, shardId)
+}
+
+// recordToMessage decodes the new image of the record into the model. The old image is used for removed
+// items and the keys if the stream doesn't contain images.
+func (i *ddbStreamInput) recordToMessage(shardId string, record *dynamodbstreams.Record) (*Message, error) {
+	image := record.Dynamodb.NewImage
+
+	if len(image) == 0 {
+		image = record.Dynamodb.OldImage
+	}
+
+	if len(image) == 0 {
+		image = record.Dynamodb.Keys
+	}
+
+	var model interface{} = &map[string]interface{}{}
+
+	if i.modelType != nil {
+		model = reflect.New(i.modelType).Interface()
+	}
+
+	if err := dynamodbattribute.UnmarshalMap(image, model); err != nil {
+		return nil, fmt.Errorf("can not decode record %s of shard %s: %w", *record.Dynamodb.SequenceNumber, shardId, err)
+	}
+
+	body, err := json.Marshal(model)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not encode record %s of shard %s: %w", *record.Dynamodb.SequenceNumber, shardId, err)
+	}
+
+	return &Message{
+		Attributes: map[string]interface{}{
+			AttributeEncoding:                EncodingJson,
+			AttributeDdbStreamEventName:      *record.EventName,
+			AttributeDdbStreamShardId:        shardId,
+			AttributeDdbStreamSequenceNumber: *record.Dynamodb.SequenceNumber,
+		},
+		Body: string(body),
+	}, nil
+}
+
+func messageToDdbStreamPosition(msg *Message) (string, string, error) {
+	for _, attribute := range []string{AttributeDdbStreamShardId, AttributeDdbStreamSequenceNumber} {
+		if _, ok := msg.Attributes[attribute]; !ok {
+			return "", "", fmt.Errorf("the message has no attribute %s", attribute)
+		}
+	}
+
+	shardId, err := cast.ToStringE(msg.Attributes[AttributeDdbStreamShardId])
+
+	if err != nil {
+		return "", "", fmt.Errorf("the attribute %s of the message is not a valid shard id: %w", AttributeDdbStreamShardId, err)
+	}
+
+	sequenceNumber, err := cast.ToStringE(msg.Attributes[AttributeDdbStreamSequenceNumber])
+
+	if err != nil {
+		return "", "", fmt.Errorf("the attribute %s of the message is not a valid sequence number: %w", AttributeDdbStreamSequenceNumber, err)
+	}
+
+	return shardId, sequenceNumber, nil
+}
+
+func isAwsErrorCode(err error, code string) bool {
+	var aerr awserr.Error
+
+	return errors.As(err, &aerr) && aerr.Code() == code
+}
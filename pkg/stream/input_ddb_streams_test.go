@@ -0,0 +1,225 @@
+package stream_test
+
+import (
+	"context"
+	concMocks "github.com/applike/gosoline/pkg/conc/mocks"
+	kvStoreMocks "github.com/applike/gosoline/pkg/kvstore/mocks"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/stream"
+	"github.com/applike/gosoline/pkg/stream/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+type ddbStreamItem struct {
+	Id   string `json:"id" ddb:"key=hash"`
+	Name string `json:"name"`
+}
+
+func TestDdbStreamInput_Run(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	client := new(mocks.DdbStreamsClient)
+	checkpoints := new(kvStoreMocks.KvStore)
+	locks := new(concMocks.DistributedLockProvider)
+	lease := new(concMocks.DistributedLock)
+
+	client.On("DescribeStreamWithContext", mock.Anything, &dynamodbstreams.DescribeStreamInput{
+		StreamArn: aws.String("arn"),
+	}).Return(&dynamodbstreams.DescribeStreamOutput{
+		StreamDescription: &dynamodbstreams.StreamDescription{
+			Shards: []*dynamodbstreams.Shard{
+				{ShardId: aws.String("shard-1")},
+			},
+		},
+	}, nil)
+	client.On("GetShardIteratorWithContext", mock.Anything, &dynamodbstreams.GetShardIteratorInput{
+		StreamArn:         aws.String("arn"),
+		ShardId:           aws.String("shard-1"),
+		ShardIteratorType: aws.String(dynamodbstreams.ShardIteratorTypeTrimHorizon),
+	}).Return(&dynamodbstreams.GetShardIteratorOutput{
+		ShardIterator: aws.String("iterator"),
+	}, nil).Once()
+	client.On("GetRecordsWithContext", mock.Anything, &dynamodbstreams.GetRecordsInput{
+		ShardIterator: aws.String("iterator"),
+		Limit:         aws.Int64(100),
+	}).Return(&dynamodbstreams.GetRecordsOutput{
+		Records: []*dynamodbstreams.Record{
+			{
+				EventName: aws.String(dynamodbstreams.OperationTypeInsert),
+				Dynamodb: &dynamodbstreams.StreamRecord{
+					SequenceNumber: aws.String("100"),
+					NewImage: map[string]*dynamodb.AttributeValue{
+						"id":   {S: aws.String("1")},
+						"name": {S: aws.String("foo")},
+					},
+				},
+			},
+			{
+				EventName: aws.String(dynamodbstreams.OperationTypeRemove),
+				Dynamodb: &dynamodbstreams.StreamRecord{
+					SequenceNumber: aws.String("200"),
+					OldImage: map[string]*dynamodb.AttributeValue{
+						"id":   {S: aws.String("1")},
+						"name": {S: aws.String("foo")},
+					},
+				},
+			},
+		},
+	}, nil).Once()
+
+	locks.On("Acquire", mock.Anything, "shard-1").Return(lease, nil).Once()
+	lease.On("Release").Return(nil).Once()
+
+	finished := make(chan struct{})
+	checkpoints.On("Get", mock.Anything, "table-shard-1", &stream.DdbStreamCheckpoint{}).Return(false, nil).Once()
+	checkpoints.On("Put", mock.Anything, "table-shard-1", &stream.DdbStreamCheckpoint{SequenceNumber: "200"}).Return(nil).Once()
+	checkpoints.On("Put", mock.Anything, "table-shard-1", &stream.DdbStreamCheckpoint{SequenceNumber: "200", Finished: true}).Run(func(args mock.Arguments) {
+		close(finished)
+	}).Return(nil).Once()
+
+	input := stream.NewDdbStreamInputWithInterfaces(logger, client, checkpoints, locks, "table", stream.DdbStreamInputSettings{
+		Model:                &ddbStreamItem{},
+		StreamArn:            "arn",
+		StartPosition:        dynamodbstreams.ShardIteratorTypeTrimHorizon,
+		BatchSize:            100,
+		WaitTime:             time.Millisecond,
+		ShardRefreshInterval: time.Hour,
+		LeaseTime:            time.Hour,
+		AckTimeout:           time.Hour,
+	})
+
+	waitRunDone := make(chan struct{})
+
+	go func() {
+		err := input.Run(context.Background())
+		assert.NoError(t, err)
+
+		close(waitRunDone)
+	}()
+
+	inserted := <-input.Data()
+	removed := <-input.Data()
+
+	assert.Equal(t, &stream.Message{
+		Attributes: map[string]interface{}{
+			stream.AttributeEncoding:                stream.EncodingJson,
+			stream.AttributeDdbStreamEventName:      dynamodbstreams.OperationTypeInsert,
+			stream.AttributeDdbStreamShardId:        "shard-1",
+			stream.AttributeDdbStreamSequenceNumber: "100",
+		},
+		Body: `{"id":"1","name":"foo"}`,
+	}, inserted)
+	assert.Equal(t, dynamodbstreams.OperationTypeRemove, removed.Attributes[stream.AttributeDdbStreamEventName])
+	assert.Equal(t, `{"id":"1","name":"foo"}`, removed.Body)
+
+	assert.NoError(t, input.AckBatch([]*stream.Message{removed, inserted}))
+
+	<-finished
+	input.Stop()
+	<-waitRunDone
+
+	client.AssertExpectations(t)
+	checkpoints.AssertExpectations(t)
+	locks.AssertExpectations(t)
+	lease.AssertExpectations(t)
+}
+
+func TestDdbStreamInput_AckBatch_OutOfOrder(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	client := new(mocks.DdbStreamsClient)
+	checkpoints := new(kvStoreMocks.KvStore)
+	locks := new(concMocks.DistributedLockProvider)
+	lease := new(concMocks.DistributedLock)
+
+	records := make([]*dynamodbstreams.Record, 0)
+
+	for _, sequenceNumber := range []string{"100", "200", "300"} {
+		records = append(records, &dynamodbstreams.Record{
+			EventName: aws.String(dynamodbstreams.OperationTypeModify),
+			Dynamodb: &dynamodbstreams.StreamRecord{
+				SequenceNumber: aws.String(sequenceNumber),
+				Keys: map[string]*dynamodb.AttributeValue{
+					"id": {S: aws.String(sequenceNumber)},
+				},
+			},
+		})
+	}
+
+	client.On("DescribeStreamWithContext", mock.Anything, mock.Anything).Return(&dynamodbstreams.DescribeStreamOutput{
+		StreamDescription: &dynamodbstreams.StreamDescription{
+			Shards: []*dynamodbstreams.Shard{
+				{ShardId: aws.String("shard-1")},
+			},
+		},
+	}, nil)
+	client.On("GetShardIteratorWithContext", mock.Anything, mock.Anything).Return(&dynamodbstreams.GetShardIteratorOutput{
+		ShardIterator: aws.String("iterator"),
+	}, nil).Once()
+	client.On("GetRecordsWithContext", mock.Anything, mock.Anything).Return(&dynamodbstreams.GetRecordsOutput{
+		Records:           records,
+		NextShardIterator: aws.String("iterator"),
+	}, nil).Once()
+	client.On("GetRecordsWithContext", mock.Anything, mock.Anything).Return(&dynamodbstreams.GetRecordsOutput{
+		NextShardIterator: aws.String("iterator"),
+	}, nil)
+
+	locks.On("Acquire", mock.Anything, "shard-1").Return(lease, nil).Once()
+	lease.On("Release").Return(nil).Once()
+
+	checkpoints.On("Get", mock.Anything, "table-shard-1", &stream.DdbStreamCheckpoint{}).Return(false, nil).Once()
+	checkpoints.On("Put", mock.Anything, "table-shard-1", &stream.DdbStreamCheckpoint{SequenceNumber: "100"}).Return(nil).Once()
+	checkpoints.On("Put", mock.Anything, "table-shard-1", &stream.DdbStreamCheckpoint{SequenceNumber: "300"}).Return(nil).Once()
+
+	input := stream.NewDdbStreamInputWithInterfaces(logger, client, checkpoints, locks, "table", stream.DdbStreamInputSettings{
+		StreamArn:            "arn",
+		StartPosition:        dynamodbstreams.ShardIteratorTypeTrimHorizon,
+		BatchSize:            100,
+		WaitTime:             time.Millisecond,
+		ShardRefreshInterval: time.Hour,
+		LeaseTime:            time.Hour,
+		AckTimeout:           time.Hour,
+	})
+
+	waitRunDone := make(chan struct{})
+
+	go func() {
+		err := input.Run(context.Background())
+		assert.NoError(t, err)
+
+		close(waitRunDone)
+	}()
+
+	first := <-input.Data()
+	second := <-input.Data()
+	third := <-input.Data()
+
+	assert.NoError(t, input.Ack(third), "the checkpoint must not move past the unacknowledged records")
+	assert.NoError(t, input.Ack(first))
+	assert.NoError(t, input.Ack(second))
+
+	input.Stop()
+	<-waitRunDone
+
+	checkpoints.AssertExpectations(t)
+	locks.AssertExpectations(t)
+	lease.AssertExpectations(t)
+}
+
+func TestDdbStreamInput_Ack_UnknownShard(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	input := stream.NewDdbStreamInputWithInterfaces(logger, new(mocks.DdbStreamsClient), new(kvStoreMocks.KvStore), new(concMocks.DistributedLockProvider), "table", stream.DdbStreamInputSettings{})
+
+	err := input.Ack(&stream.Message{
+		Attributes: map[string]interface{}{
+			stream.AttributeDdbStreamShardId:        "shard-1",
+			stream.AttributeDdbStreamSequenceNumber: "100",
+		},
+	})
+
+	assert.EqualError(t, err, "the lease of the shard shard-1 was lost")
+}
@@ -0,0 +1,386 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"github.com/applike/gosoline/pkg/exec"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/hashicorp/go-multierror"
+	"github.com/segmentio/kafka-go"
+	"github.com/spf13/cast"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	AttributeKafkaKey       = "kafkaKey"
+	AttributeKafkaTopic     = "kafkaTopic"
+	AttributeKafkaPartition = "kafkaPartition"
+	AttributeKafkaOffset    = "kafkaOffset"
+)
+
+//go:generate mockery -name KafkaReader
+type KafkaReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+type KafkaInputSettings struct {
+	Brokers        []string      `cfg:"brokers" validate:"min=1"`
+	Topic          string        `cfg:"topic" validate:"required"`
+	GroupId        string        `cfg:"group_id" validate:"required"`
+	MinBytes       int           `cfg:"min_bytes" default:"1"`
+	MaxBytes       int           `cfg:"max_bytes" default:"10485760"`
+	MaxWait        time.Duration `cfg:"max_wait" default:"3s"`
+	CommitInterval time.Duration `cfg:"commit_interval" default:"0s"`
+	StartOffset    string        `cfg:"start_offset" default:"first" validate:"oneof=first last"`
+	// MaxPending is the number of messages of a partition which can be fetched after the oldest one which isn't
+	// acknowledged yet, 0 disables the limit. As offsets are committed in order, a message which is never
+	// acknowledged blocks the commits of its partition and the input fails once the limit is reached.
+	MaxPending int `cfg:"max_pending" default:"10000" validate:"min=0"`
+	// transient errors on fetching messages are retried with this backoff
+	Backoff exec.BackoffSettings `cfg:"backoff"`
+}
+
+// kafkaPartition keeps the offsets which were fetched from a partition but not committed yet
+type kafkaPartition struct {
+	pending []int64
+	acked   map[int64]bool
+	last    int64
+}
+
+func newKafkaPartition() *kafkaPartition {
+	return &kafkaPartition{
+		acked: make(map[int64]bool),
+		last:  -1,
+	}
+}
+
+type kafkaInput struct {
+	logger   mon.Logger
+	reader   KafkaReader
+	executor exec.Executor
+	settings KafkaInputSettings
+
+	// acks are committed one after another, so the pending offsets aren't removed twice
+	ackLck     sync.Mutex
+	lck        sync.Mutex
+	ctx        context.Context
+	cancel     context.CancelFunc
+	channel    chan *Message
+	stopped    bool
+	closed     bool
+	partitions map[int]*kafkaPartition
+}
+
+func NewKafkaInput(_ cfg.Config, logger mon.Logger, settings KafkaInputSettings) *kafkaInput {
+	startOffset := kafka.FirstOffset
+
+	if settings.StartOffset == "last" {
+		startOffset = kafka.LastOffset
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        settings.Brokers,
+		Topic:          settings.Topic,
+		GroupID:        settings.GroupId,
+		MinBytes:       settings.MinBytes,
+		MaxBytes:       settings.MaxBytes,
+		MaxWait:        settings.MaxWait,
+		CommitInterval: settings.CommitInterval,
+		StartOffset:    startOffset,
+	})
+
+	resource := &exec.ExecutableResource{
+		Type: "kafka",
+		Name: settings.Topic,
+	}
+
+	executor := exec.NewBackoffExecutor(logger, resource, &settings.Backoff, CheckKafkaTemporaryError, exec.CheckConnectionError, exec.CheckTimeoutError)
+
+	return NewKafkaInputWithInterfaces(logger, reader, executor, settings)
+}
+
+func NewKafkaInputWithInterfaces(logger mon.Logger, reader KafkaReader, executor exec.Executor, settings KafkaInputSettings) *kafkaInput {
+	return &kafkaInput{
+		logger:     logger,
+		reader:     reader,
+		executor:   executor,
+		settings:   settings,
+		ctx:        context.Background(),
+		channel:    make(chan *Message),
+		partitions: make(map[int]*kafkaPartition),
+	}
+}
+
+func (i *kafkaInput) Data() chan *Message {
+	return i.channel
+}
+
+func (i *kafkaInput) Run(ctx context.Context) error {
+	defer close(i.channel)
+	defer i.logger.Info("leaving kafka input")
+
+	if ctx = i.start(ctx); ctx == nil {
+		return nil
+	}
+
+	i.logger.Infof("starting kafka input for topic %s with group %s", i.settings.Topic, i.settings.GroupId)
+
+	for {
+		res, err := i.executor.Execute(ctx, func(ctx context.Context) (interface{}, error) {
+			return i.reader.FetchMessage(ctx)
+		})
+
+		if errors.Is(err, context.Canceled) || ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("can not fetch message from kafka topic %s: %w", i.settings.Topic, err)
+		}
+
+		kafkaMessage := res.(kafka.Message)
+		msg := KafkaToMessage(kafkaMessage)
+
+		// the offset is pending before it is sent, as it can be acknowledged before the send returns
+		if err = i.addPending(kafkaMessage); err != nil {
+			return err
+		}
+
+		select {
+		case i.channel <- msg:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// start derives a context which is canceled on stop. It returns nil if the input was already stopped.
+func (i *kafkaInput) start(ctx context.Context) context.Context {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	if i.stopped {
+		return nil
+	}
+
+	i.ctx = ctx
+	ctx, i.cancel = context.WithCancel(ctx)
+
+	return ctx
+}
+
+// Stop stops fetching messages and closes the reader, which leaves the consumer group. Messages which are
+// acknowledged after the stop are not committed anymore and are consumed again.
+func (i *kafkaInput) Stop() {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	i.stopped = true
+
+	if i.cancel != nil {
+		i.cancel()
+	}
+
+	if i.closed {
+		return
+	}
+
+	i.closed = true
+
+	if err := i.reader.Close(); err != nil {
+		i.logger.Error(err, "can not close the kafka reader")
+	}
+}
+
+func (i *kafkaInput) Ack(msg *Message) error {
+	return i.AckBatch([]*Message{msg})
+}
+
+// AckBatch commits for every partition the offset of the last message before the first message which isn't
+// acknowledged yet. As kafka commits offsets per partition, no message is skipped if the messages are processed
+// and acknowledged out of order.
+func (i *kafkaInput) AckBatch(msgs []*Message) error {
+	i.ackLck.Lock()
+	defer i.ackLck.Unlock()
+
+	multiError := new(multierror.Error)
+
+	i.lck.Lock()
+	ctx := i.ctx
+
+	for _, msg := range msgs {
+		kafkaMessage, err := messageToKafkaCommit(msg)
+
+		if err != nil {
+			multiError = multierror.Append(multiError, err)
+			continue
+		}
+
+		// messages fetched before a restart of the input are consumed again, their acknowledgement is ignored
+		if partition, ok := i.partitions[kafkaMessage.Partition]; ok {
+			if _, ok := partition.acked[kafkaMessage.Offset]; ok {
+				partition.acked[kafkaMessage.Offset] = true
+			}
+		}
+	}
+
+	commits, done := i.committableOffsets()
+	i.lck.Unlock()
+
+	if len(commits) == 0 {
+		return multiError.ErrorOrNil()
+	}
+
+	if err := i.reader.CommitMessages(ctx, commits...); err != nil {
+		return multierror.Append(multiError, fmt.Errorf("can not commit messages on kafka topic %s: %w", i.settings.Topic, err)).ErrorOrNil()
+	}
+
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	// a partition reset by a rebalance in the meantime is replaced, so the removal doesn't affect its new offsets
+	for partition, count := range done {
+		for _, offset := range partition.pending[:count] {
+			delete(partition.acked, offset)
+		}
+
+		partition.pending = partition.pending[count:]
+	}
+
+	return multiError.ErrorOrNil()
+}
+
+func (i *kafkaInput) addPending(kafkaMessage kafka.Message) error {
+	i.lck.Lock()
+	defer i.lck.Unlock()
+
+	partition, ok := i.partitions[kafkaMessage.Partition]
+
+	// a partition which is assigned again after a rebalance is fetched from its committed offset,
+	// so the offsets pending from the previous assignment are fetched again
+	if !ok || kafkaMessage.Offset <= partition.last {
+		partition = newKafkaPartition()
+		i.partitions[kafkaMessage.Partition] = partition
+	}
+
+	if i.settings.MaxPending > 0 && len(partition.pending) >= i.settings.MaxPending {
+		return fmt.Errorf("the offset %d of partition %d of kafka topic %s is not acknowledged while %d messages of the partition are pending, which blocks its commits", partition.pending[0], kafkaMessage.Partition, i.settings.Topic, len(partition.pending))
+	}
+
+	partition.pending = append(partition.pending, kafkaMessage.Offset)
+	partition.acked[kafkaMessage.Offset] = false
+	partition.last = kafkaMessage.Offset
+
+	return nil
+}
+
+// committableOffsets returns the messages to commit and the number of pending offsets they cover per partition
+func (i *kafkaInput) committableOffsets() ([]kafka.Message, map[*kafkaPartition]int) {
+	commits := make([]kafka.Message, 0)
+	done := make(map[*kafkaPartition]int)
+
+	for partitionId, partition := range i.partitions {
+		count := 0
+
+		for count < len(partition.pending) && partition.acked[partition.pending[count]] {
+			count++
+		}
+
+		if count == 0 {
+			continue
+		}
+
+		done[partition] = count
+		commits = append(commits, kafka.Message{
+			Topic:     i.settings.Topic,
+			Partition: partitionId,
+			Offset:    partition.pending[count-1],
+		})
+	}
+
+	sort.Slice(commits, func(a, b int) bool {
+		return commits[a].Partition < commits[b].Partition
+	})
+
+	return commits, done
+}
+
+// CheckKafkaTemporaryError retries the errors which kafka marks as temporary
+func CheckKafkaTemporaryError(_ interface{}, err error) exec.ErrorType {
+	var kafkaErr kafka.Error
+
+	if errors.As(err, &kafkaErr) && kafkaErr.Temporary() {
+		return exec.ErrorTypeRetryable
+	}
+
+	return exec.ErrorTypeUnknown
+}
+
+// KafkaToMessage converts a kafka message into a stream message. The value of the kafka message is used
+// as body and the headers are mapped onto the attributes.
+func KafkaToMessage(kafkaMessage kafka.Message) *Message {
+	attributes := make(map[string]interface{}, len(kafkaMessage.Headers)+4)
+
+	for _, header := range kafkaMessage.Headers {
+		var value interface{}
+
+		// headers are written as json, but we keep foreign headers as plain strings
+		if err := json.Unmarshal(header.Value, &value); err != nil {
+			value = string(header.Value)
+		}
+
+		attributes[header.Key] = value
+	}
+
+	if len(kafkaMessage.Key) > 0 {
+		attributes[AttributeKafkaKey] = string(kafkaMessage.Key)
+	}
+
+	attributes[AttributeKafkaTopic] = kafkaMessage.Topic
+	attributes[AttributeKafkaPartition] = kafkaMessage.Partition
+	attributes[AttributeKafkaOffset] = kafkaMessage.Offset
+
+	return &Message{
+		Attributes: attributes,
+		Body:       string(kafkaMessage.Value),
+	}
+}
+
+func messageToKafkaCommit(msg *Message) (kafka.Message, error) {
+	for _, attribute := range []string{AttributeKafkaTopic, AttributeKafkaPartition, AttributeKafkaOffset} {
+		if _, ok := msg.Attributes[attribute]; !ok {
+			return kafka.Message{}, fmt.Errorf("the message has no attribute %s", attribute)
+		}
+	}
+
+	topic, err := cast.ToStringE(msg.Attributes[AttributeKafkaTopic])
+
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("the attribute %s of the message is not a valid topic: %w", AttributeKafkaTopic, err)
+	}
+
+	partition, err := cast.ToIntE(msg.Attributes[AttributeKafkaPartition])
+
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("the attribute %s of the message is not a valid partition: %w", AttributeKafkaPartition, err)
+	}
+
+	offset, err := cast.ToInt64E(msg.Attributes[AttributeKafkaOffset])
+
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("the attribute %s of the message is not a valid offset: %w", AttributeKafkaOffset, err)
+	}
+
+	return kafka.Message{
+		Topic:     topic,
+		Partition: partition,
+		Offset:    offset,
+	}, nil
+}
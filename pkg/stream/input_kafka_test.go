@@ -0,0 +1,265 @@
+package stream_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/exec"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/stream"
+	"github.com/applike/gosoline/pkg/stream/mocks"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestKafkaInput_Run(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	reader := new(mocks.KafkaReader)
+
+	reader.On("FetchMessage", mock.Anything).Return(kafka.Message{
+		Topic:     "topic",
+		Partition: 2,
+		Offset:    17,
+		Key:       []byte("key"),
+		Value:     []byte(`{"id":1}`),
+		Headers: []kafka.Header{
+			{Key: "encoding", Value: []byte(`"application/json"`)},
+			{Key: "schemaId", Value: []byte(`3`)},
+			{Key: "foreign", Value: []byte(`plain`)},
+		},
+	}, nil).Once()
+	reader.On("FetchMessage", mock.Anything).Return(func(ctx context.Context) kafka.Message {
+		<-ctx.Done()
+		return kafka.Message{}
+	}, context.Canceled).Once()
+	reader.On("Close").Return(nil).Once()
+
+	input := stream.NewKafkaInputWithInterfaces(logger, reader, exec.NewDefaultExecutor(), stream.KafkaInputSettings{
+		Topic:   "topic",
+		GroupId: "group",
+	})
+
+	waitRunDone := make(chan struct{})
+
+	go func() {
+		err := input.Run(context.Background())
+		assert.NoError(t, err)
+
+		close(waitRunDone)
+	}()
+
+	msg := <-input.Data()
+	input.Stop()
+	<-waitRunDone
+
+	assert.Equal(t, &stream.Message{
+		Attributes: map[string]interface{}{
+			"encoding":                     "application/json",
+			"schemaId":                     float64(3),
+			"foreign":                      "plain",
+			stream.AttributeKafkaKey:       "key",
+			stream.AttributeKafkaTopic:     "topic",
+			stream.AttributeKafkaPartition: 2,
+			stream.AttributeKafkaOffset:    int64(17),
+		},
+		Body: `{"id":1}`,
+	}, msg)
+	reader.AssertExpectations(t)
+}
+
+func TestKafkaInput_Run_RetryTemporaryError(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	reader := new(mocks.KafkaReader)
+
+	reader.On("FetchMessage", mock.Anything).Return(kafka.Message{}, kafka.LeaderNotAvailable).Once()
+	reader.On("FetchMessage", mock.Anything).Return(kafka.Message{
+		Topic:     "topic",
+		Partition: 1,
+		Offset:    3,
+		Value:     []byte(`{"id":1}`),
+	}, nil).Once()
+	reader.On("FetchMessage", mock.Anything).Return(func(ctx context.Context) kafka.Message {
+		<-ctx.Done()
+		return kafka.Message{}
+	}, context.Canceled).Once()
+	reader.On("Close").Return(nil).Once()
+
+	executor := exec.NewBackoffExecutor(logger, &exec.ExecutableResource{}, &exec.BackoffSettings{
+		InitialInterval: time.Millisecond,
+		Multiplier:      1,
+		MaxInterval:     time.Millisecond,
+		MaxElapsedTime:  time.Second,
+	}, stream.CheckKafkaTemporaryError)
+
+	input := stream.NewKafkaInputWithInterfaces(logger, reader, executor, stream.KafkaInputSettings{
+		Topic:   "topic",
+		GroupId: "group",
+	})
+
+	waitRunDone := make(chan struct{})
+
+	go func() {
+		err := input.Run(context.Background())
+		assert.NoError(t, err)
+
+		close(waitRunDone)
+	}()
+
+	msg := <-input.Data()
+	input.Stop()
+	<-waitRunDone
+
+	assert.Equal(t, `{"id":1}`, msg.Body)
+	reader.AssertExpectations(t)
+}
+
+func TestKafkaInput_AckBatch(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	reader := new(mocks.KafkaReader)
+
+	for _, kafkaMessage := range []kafka.Message{
+		{Topic: "topic", Partition: 1, Offset: 5},
+		{Topic: "topic", Partition: 1, Offset: 6},
+		{Topic: "topic", Partition: 2, Offset: 9},
+		{Topic: "topic", Partition: 1, Offset: 7},
+	} {
+		reader.On("FetchMessage", mock.Anything).Return(kafkaMessage, nil).Once()
+	}
+
+	reader.On("FetchMessage", mock.Anything).Return(func(ctx context.Context) kafka.Message {
+		<-ctx.Done()
+		return kafka.Message{}
+	}, context.Canceled).Once()
+	reader.On("Close").Return(nil).Once()
+
+	reader.On("CommitMessages", context.Background(), kafka.Message{
+		Topic:     "topic",
+		Partition: 2,
+		Offset:    9,
+	}).Return(nil).Once()
+	reader.On("CommitMessages", context.Background(), kafka.Message{
+		Topic:     "topic",
+		Partition: 1,
+		Offset:    7,
+	}).Return(nil).Once()
+
+	input := stream.NewKafkaInputWithInterfaces(logger, reader, exec.NewDefaultExecutor(), stream.KafkaInputSettings{
+		Topic: "topic",
+	})
+
+	waitRunDone := make(chan struct{})
+
+	go func() {
+		err := input.Run(context.Background())
+		assert.NoError(t, err)
+
+		close(waitRunDone)
+	}()
+
+	msgs := make([]*stream.Message, 0, 4)
+
+	for i := 0; i < 4; i++ {
+		msgs = append(msgs, <-input.Data())
+	}
+
+	err := input.AckBatch([]*stream.Message{
+		msgs[1],
+		msgs[2],
+		{
+			Attributes: map[string]interface{}{},
+		},
+	})
+	assert.EqualError(t, err, "1 error occurred:\n\t* the message has no attribute kafkaTopic\n\n", "the offset 6 can not be committed before 5")
+
+	err = input.AckBatch([]*stream.Message{msgs[3], msgs[0]})
+	assert.NoError(t, err)
+
+	input.Stop()
+	<-waitRunDone
+
+	reader.AssertExpectations(t)
+}
+
+func TestKafkaInput_Run_MaxPending(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	reader := new(mocks.KafkaReader)
+
+	for offset := int64(5); offset < 8; offset++ {
+		reader.On("FetchMessage", mock.Anything).Return(kafka.Message{Topic: "topic", Partition: 1, Offset: offset}, nil).Once()
+	}
+
+	reader.On("Close").Return(nil).Once()
+
+	input := stream.NewKafkaInputWithInterfaces(logger, reader, exec.NewDefaultExecutor(), stream.KafkaInputSettings{
+		Topic:      "topic",
+		MaxPending: 2,
+	})
+
+	waitRunDone := make(chan error)
+
+	go func() {
+		waitRunDone <- input.Run(context.Background())
+	}()
+
+	// the offset 5 is never acknowledged, so the partition can't be committed beyond it
+	<-input.Data()
+	<-input.Data()
+
+	err := <-waitRunDone
+	assert.EqualError(t, err, "the offset 5 of partition 1 of kafka topic topic is not acknowledged while 2 messages of the partition are pending, which blocks its commits")
+
+	input.Stop()
+	reader.AssertExpectations(t)
+}
+
+func TestKafkaInput_AckBatch_Rebalance(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	reader := new(mocks.KafkaReader)
+
+	// after a rebalance the partition is fetched again from the committed offset 5
+	for _, offset := range []int64{5, 6, 5, 6} {
+		reader.On("FetchMessage", mock.Anything).Return(kafka.Message{Topic: "topic", Partition: 1, Offset: offset}, nil).Once()
+	}
+
+	reader.On("FetchMessage", mock.Anything).Return(func(ctx context.Context) kafka.Message {
+		<-ctx.Done()
+		return kafka.Message{}
+	}, context.Canceled).Once()
+	reader.On("Close").Return(nil).Once()
+
+	reader.On("CommitMessages", context.Background(), kafka.Message{
+		Topic:     "topic",
+		Partition: 1,
+		Offset:    6,
+	}).Return(nil).Once()
+
+	input := stream.NewKafkaInputWithInterfaces(logger, reader, exec.NewDefaultExecutor(), stream.KafkaInputSettings{
+		Topic:      "topic",
+		MaxPending: 3,
+	})
+
+	waitRunDone := make(chan struct{})
+
+	go func() {
+		err := input.Run(context.Background())
+		assert.NoError(t, err)
+
+		close(waitRunDone)
+	}()
+
+	msgs := make([]*stream.Message, 0, 4)
+
+	for i := 0; i < 4; i++ {
+		msgs = append(msgs, <-input.Data())
+	}
+
+	// the offsets of the previous assignment were dropped, otherwise the limit would have been reached
+	err := input.AckBatch([]*stream.Message{msgs[2], msgs[3]})
+	assert.NoError(t, err)
+
+	input.Stop()
+	<-waitRunDone
+
+	reader.AssertExpectations(t)
+}
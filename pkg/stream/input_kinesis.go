@@ -8,6 +8,10 @@ import (
 	"github.com/applike/gosoline/pkg/mon"
 )
 
+// AttributeKinesisPartitionKey contains the partition key a message was written with by the kinesis output.
+// It can be used as attribute of the ordered consumer dispatching.
+const AttributeKinesisPartitionKey = "kinesisPartitionKey"
+
 type kinesisInput struct {
 	kinesis.Reader
 	channel chan *Message
@@ -14,6 +14,7 @@ type SnsInputSettings struct {
 	QueueId             string               `cfg:"queue_id"`
 	MaxNumberOfMessages int64                `cfg:"max_number_of_messages" default:"10" validate:"min=1,max=10"`
 	WaitTime            int64                `cfg:"wait_time"`
+	Fifo                sqs.FifoSettings     `cfg:"fifo"`
 	RedrivePolicy       sqs.RedrivePolicy    `cfg:"redrive_policy"`
 	VisibilityTimeout   int                  `cfg:"visibility_timeout"`
 	RunnerCount         int                  `cfg:"runner_count"`
@@ -30,13 +31,15 @@ func (s SnsInputSettings) GetQueueId() string {
 }
 
 func (s SnsInputSettings) IsFifoEnabled() bool {
-	return false
+	return s.Fifo.Enabled
 }
 
 type SnsInputTarget struct {
 	cfg.AppId
 	TopicId    string
 	Attributes map[string]interface{}
+	// ContentBasedDeduplication is used for the fifo topic if it has to be created to subscribe to it
+	ContentBasedDeduplication bool
 }
 
 type snsInput struct {
@@ -53,6 +56,7 @@ func NewSnsInput(config cfg.Config, logger mon.Logger, settings SnsInputSettings
 		WaitTime:            settings.WaitTime,
 		VisibilityTimeout:   settings.VisibilityTimeout,
 		RunnerCount:         settings.RunnerCount,
+		Fifo:                settings.Fifo,
 		RedrivePolicy:       settings.RedrivePolicy,
 		Client:              settings.Client,
 		Backoff:             settings.Backoff,
@@ -66,6 +70,11 @@ func NewSnsInput(config cfg.Config, logger mon.Logger, settings SnsInputSettings
 			topic := sns.NewTopic(config, logger, &sns.Settings{
 				AppId:   target.AppId,
 				TopicId: target.TopicId,
+				// fifo queues can only subscribe to fifo topics and vice versa
+				Fifo: sns.FifoSettings{
+					Enabled:                   settings.Fifo.Enabled,
+					ContentBasedDeduplication: target.ContentBasedDeduplication,
+				},
 				Client:  settings.Client,
 				Backoff: settings.Backoff,
 			})
@@ -9,6 +9,7 @@ import (
 	"github.com/applike/gosoline/pkg/exec"
 	"github.com/applike/gosoline/pkg/mon"
 	"github.com/applike/gosoline/pkg/sqs"
+	awsSqs "github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/hashicorp/go-multierror"
 )
 
@@ -138,6 +139,11 @@ func (i *sqsInput) runLoop(ctx context.Context) error {
 			msg.Attributes[AttributeSqsMessageId] = *sqsMessage.MessageId
 			msg.Attributes[AttributeSqsReceiptHandle] = *sqsMessage.ReceiptHandle
 
+			// the group id of fifo queues is used as default attribute of the ordered consumer dispatching
+			if groupId, ok := sqsMessage.Attributes[awsSqs.MessageSystemAttributeNameMessageGroupId]; ok && groupId != nil {
+				msg.Attributes[sqs.AttributeSqsMessageGroupId] = *groupId
+			}
+
 			i.channel <- msg
 		}
 	}
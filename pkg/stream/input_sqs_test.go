@@ -32,6 +32,9 @@ func TestSqsInput_Run(t *testing.T) {
 
 		return []*sqs.Message{
 			{
+				Attributes: map[string]*string{
+					sqs.MessageSystemAttributeNameMessageGroupId: aws.String("group"),
+				},
 				Body:          aws.String(`{"body": "foobar"}`),
 				MessageId:     aws.String(""),
 				ReceiptHandle: aws.String(""),
@@ -64,6 +67,7 @@ func TestSqsInput_Run(t *testing.T) {
 	<-waitRunDone
 
 	assert.Equal(t, "foobar", msg.Body)
+	assert.Equal(t, "group", msg.Attributes["sqsMessageGroupId"])
 }
 
 func TestSqsInput_Run_Failure(t *testing.T) {
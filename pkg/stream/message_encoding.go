@@ -18,8 +18,11 @@ func AddDefaultEncodeHandler(handler EncodeHandler) {
 }
 
 type MessageEncoderSettings struct {
-	Encoding       string
-	Compression    string
+	Encoding    string
+	Compression string
+	// SchemaSubject is the subject the schemas of the encoded messages are registered for,
+	// the encodings use a subject derived from the model if it is empty
+	SchemaSubject  string
 	EncodeHandlers []EncodeHandler
 }
 
@@ -32,6 +35,7 @@ type MessageEncoder interface {
 type messageEncoder struct {
 	encoding       string
 	compression    string
+	schemaSubject  string
 	encodeHandlers []EncodeHandler
 }
 
@@ -51,6 +55,7 @@ func NewMessageEncoder(config *MessageEncoderSettings) *messageEncoder {
 	return &messageEncoder{
 		encoding:       config.Encoding,
 		compression:    config.Compression,
+		schemaSubject:  config.SchemaSubject,
 		encodeHandlers: config.EncodeHandlers,
 	}
 }
@@ -97,7 +102,18 @@ func (e *messageEncoder) encodeBody(attributes map[string]interface{}, data inte
 		return nil, fmt.Errorf("there is no message body encoder available for encoding '%s'", e.encoding)
 	}
 
-	body, err := encoder.Encode(data)
+	var err error
+	var body []byte
+
+	if attributeEncoder, ok := encoder.(AttributeAwareMessageBodyEncoder); ok {
+		if e.schemaSubject != "" {
+			attributes[AttributeSchemaSubject] = e.schemaSubject
+		}
+
+		body, err = attributeEncoder.EncodeWithAttributes(data, attributes)
+	} else {
+		body, err = encoder.Encode(data)
+	}
 
 	if err != nil {
 		return nil, fmt.Errorf("can not encode message body with encoding '%s': %w", e.encoding, err)
@@ -219,7 +235,13 @@ func (e *messageEncoder) decodeBody(attributes map[string]interface{}, body []by
 		return fmt.Errorf("there is no message body decoder available for encoding '%s'", encoding)
 	}
 
-	err := encoder.Decode(body, out)
+	var err error
+
+	if attributeEncoder, ok := encoder.(AttributeAwareMessageBodyEncoder); ok {
+		err = attributeEncoder.DecodeWithAttributes(body, attributes, out)
+	} else {
+		err = encoder.Decode(body, out)
+	}
 
 	if err != nil {
 		return fmt.Errorf("can not decode message body with encoding '%s': %w", encoding, err)
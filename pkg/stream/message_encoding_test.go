@@ -251,6 +251,59 @@ func (s *MessageEncoderSuite) TestDecode() {
 	}
 }
 
+func (s *MessageEncoderSuite) TestEncodeDecodeCompressions() {
+	data := &encodingTestStruct{
+		Id:        3,
+		Text:      "example",
+		CreatedAt: s.clock.Now(),
+	}
+
+	compressions := []string{
+		stream.CompressionGZip,
+		stream.CompressionZstd,
+		stream.CompressionSnappy,
+		stream.CompressionLz4,
+	}
+
+	for _, compression := range compressions {
+		s.Run(compression, func() {
+			encoder := stream.NewMessageEncoder(&stream.MessageEncoderSettings{
+				Encoding:    stream.EncodingJson,
+				Compression: compression,
+			})
+
+			ctx := context.Background()
+			msg, err := encoder.Encode(ctx, data)
+
+			s.NoError(err)
+			s.Equal(compression, msg.Attributes[stream.AttributeCompression])
+
+			decoded := &encodingTestStruct{}
+			_, attributes, err := encoder.Decode(ctx, msg, decoded)
+
+			s.NoError(err)
+			s.Equal(data, decoded)
+			s.NotContains(attributes, stream.AttributeCompression)
+		})
+	}
+}
+
+func (s *MessageEncoderSuite) TestDecodeLz4Frame() {
+	// written by the lz4 command line tool with block checksums and the content size
+	msg := stream.NewMessage("BCJNGHxAJgAAAAAAAAAeGAAAAK4iZ29zb2xpbmUgCQCgbHo0IGZyYW1lIjJHzsUAAAAATaMw5w==", map[string]interface{}{
+		stream.AttributeEncoding:    stream.EncodingJson,
+		stream.AttributeCompression: stream.CompressionLz4,
+	})
+
+	encoder := stream.NewMessageEncoder(&stream.MessageEncoderSettings{})
+
+	decoded := ""
+	_, _, err := encoder.Decode(context.Background(), msg, &decoded)
+
+	s.NoError(err)
+	s.Equal("gosoline gosoline gosoline lz4 frame", decoded)
+}
+
 func TestMessageEncoderSuite(t *testing.T) {
 	suite.Run(t, new(MessageEncoderSuite))
 }
@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kernel"
+	"github.com/applike/gosoline/pkg/mon"
+	"io"
+	"sync"
+)
+
+// closableOutputs keeps the outputs holding connections which have to be closed after all modules writing to them stopped
+var closableOutputs = struct {
+	sync.Mutex
+	outputs []closableOutput
+}{}
+
+type closableOutput struct {
+	name   string
+	output io.Closer
+}
+
+func registerClosableOutput(name string, output io.Closer) {
+	closableOutputs.Lock()
+	defer closableOutputs.Unlock()
+
+	closableOutputs.outputs = append(closableOutputs.outputs, closableOutput{
+		name:   name,
+		output: output,
+	})
+}
+
+// OutputCloser is a kernel module which closes the outputs holding connections when the application shuts down.
+// As it runs in the essential stage, it is stopped after the consumers and producers.
+type OutputCloser struct {
+	kernel.BackgroundModule
+	kernel.EssentialStage
+	logger mon.Logger
+}
+
+func OutputCloserFactory(_ cfg.Config, _ mon.Logger) (map[string]kernel.ModuleFactory, error) {
+	return map[string]kernel.ModuleFactory{
+		"output-closer": func(ctx context.Context, config cfg.Config, logger mon.Logger) (kernel.Module, error) {
+			return NewOutputCloser(logger), nil
+		},
+	}, nil
+}
+
+func NewOutputCloser(logger mon.Logger) *OutputCloser {
+	return &OutputCloser{
+		logger: logger,
+	}
+}
+
+func (c *OutputCloser) Run(ctx context.Context) error {
+	<-ctx.Done()
+
+	closableOutputs.Lock()
+	defer closableOutputs.Unlock()
+
+	for _, closable := range closableOutputs.outputs {
+		if err := closable.output.Close(); err != nil {
+			c.logger.Error(err, fmt.Sprintf("can not close output %s", closable.name))
+		}
+	}
+
+	closableOutputs.outputs = nil
+
+	return nil
+}
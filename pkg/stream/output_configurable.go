@@ -6,12 +6,14 @@ import (
 	"github.com/applike/gosoline/pkg/cloud"
 	"github.com/applike/gosoline/pkg/exec"
 	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/sns"
 	"github.com/applike/gosoline/pkg/sqs"
 )
 
 const (
 	OutputTypeFile     = "file"
 	OutputTypeInMemory = "inMemory"
+	OutputTypeKafka    = "kafka"
 	OutputTypeKinesis  = "kinesis"
 	OutputTypeMultiple = "multiple"
 	OutputTypeRedis    = "redis"
@@ -30,6 +32,7 @@ func NewConfigurableOutput(config cfg.Config, logger mon.Logger, name string) (O
 	var outputFactories = map[string]OutputFactory{
 		OutputTypeFile:     newFileOutputFromConfig,
 		OutputTypeInMemory: newInMemoryOutputFromConfig,
+		OutputTypeKafka:    newKafkaOutputFromConfig,
 		OutputTypeKinesis:  newKinesisOutputFromConfig,
 		OutputTypeMultiple: NewConfigurableMultiOutput,
 		OutputTypeRedis:    newRedisListOutputFromConfig,
@@ -70,9 +73,18 @@ func newInMemoryOutputFromConfig(_ cfg.Config, _ mon.Logger, name string) (Outpu
 	return ProvideInMemoryOutput(name), nil
 }
 
+func newKafkaOutputFromConfig(config cfg.Config, logger mon.Logger, name string) (Output, error) {
+	key := ConfigurableOutputKey(name)
+	settings := &KafkaOutputSettings{}
+	config.UnmarshalKey(key, settings)
+
+	return NewKafkaOutput(config, logger, settings), nil
+}
+
 type kinesisOutputConfiguration struct {
-	StreamName string               `cfg:"stream_name"`
-	Backoff    exec.BackoffSettings `cfg:"backoff"`
+	StreamName            string               `cfg:"stream_name"`
+	PartitionKeyAttribute string               `cfg:"partition_key_attribute" default:"kinesisPartitionKey"`
+	Backoff               exec.BackoffSettings `cfg:"backoff"`
 }
 
 func newKinesisOutputFromConfig(config cfg.Config, logger mon.Logger, name string) (Output, error) {
@@ -81,8 +93,9 @@ func newKinesisOutputFromConfig(config cfg.Config, logger mon.Logger, name strin
 	config.UnmarshalKey(key, settings, cfg.UnmarshalWithDefaultsFromKey(ConfigKeyStreamBackoff, "backoff"))
 
 	return NewKinesisOutput(config, logger, &KinesisOutputSettings{
-		StreamName: settings.StreamName,
-		Backoff:    settings.Backoff,
+		StreamName:            settings.StreamName,
+		PartitionKeyAttribute: settings.PartitionKeyAttribute,
+		Backoff:               settings.Backoff,
 	}), nil
 }
 
@@ -120,6 +133,7 @@ type SnsOutputConfiguration struct {
 	Family      string               `cfg:"family"`
 	Application string               `cfg:"application"`
 	TopicId     string               `cfg:"topic_id" validate:"required"`
+	Fifo        sns.FifoSettings     `cfg:"fifo"`
 	Client      cloud.ClientSettings `cfg:"client"`
 }
 
@@ -136,6 +150,7 @@ func newSnsOutputFromConfig(config cfg.Config, logger mon.Logger, name string) (
 			Application: configuration.Application,
 		},
 		TopicId: configuration.TopicId,
+		Fifo:    configuration.Fifo,
 		Client:  configuration.Client,
 		Backoff: configuration.Backoff,
 	}), nil
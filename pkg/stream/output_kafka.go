@@ -0,0 +1,155 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/applike/gosoline/pkg/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/spf13/cast"
+	"time"
+)
+
+//go:generate mockery -name KafkaWriter
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+type KafkaOutputSettings struct {
+	Brokers               []string      `cfg:"brokers" validate:"min=1"`
+	Topic                 string        `cfg:"topic" validate:"required"`
+	PartitionKeyAttribute string        `cfg:"partition_key_attribute" default:"kafkaKey"`
+	BatchSize             int           `cfg:"batch_size" default:"100" validate:"min=1"`
+	BatchTimeout          time.Duration `cfg:"batch_timeout" default:"10ms"`
+	RequiredAcks          int           `cfg:"required_acks" default:"-1" validate:"min=-1,max=1"`
+	MaxAttempts           int           `cfg:"max_attempts" default:"10" validate:"min=1"`
+}
+
+type kafkaOutput struct {
+	logger   mon.Logger
+	uuidGen  uuid.Uuid
+	writer   KafkaWriter
+	settings *KafkaOutputSettings
+}
+
+func NewKafkaOutput(_ cfg.Config, logger mon.Logger, settings *KafkaOutputSettings) *kafkaOutput {
+	writer := kafka.NewWriter(kafka.WriterConfig{
+		Brokers:      settings.Brokers,
+		Topic:        settings.Topic,
+		Balancer:     &kafka.Hash{},
+		BatchSize:    settings.BatchSize,
+		BatchTimeout: settings.BatchTimeout,
+		RequiredAcks: settings.RequiredAcks,
+		MaxAttempts:  settings.MaxAttempts,
+	})
+
+	output := NewKafkaOutputWithInterfaces(logger, writer, settings)
+	registerClosableOutput(fmt.Sprintf("kafka-%s", settings.Topic), output)
+
+	return output
+}
+
+func NewKafkaOutputWithInterfaces(logger mon.Logger, writer KafkaWriter, settings *KafkaOutputSettings) *kafkaOutput {
+	return &kafkaOutput{
+		logger:   logger,
+		uuidGen:  uuid.New(),
+		writer:   writer,
+		settings: settings,
+	}
+}
+
+func (o *kafkaOutput) WriteOne(ctx context.Context, msg WritableMessage) error {
+	return o.Write(ctx, []WritableMessage{msg})
+}
+
+func (o *kafkaOutput) Write(ctx context.Context, batch []WritableMessage) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	kafkaMessages := make([]kafka.Message, 0, len(batch))
+
+	for _, msg := range batch {
+		kafkaMessage, err := o.buildKafkaMessage(msg)
+
+		if err != nil {
+			return err
+		}
+
+		kafkaMessages = append(kafkaMessages, kafkaMessage)
+	}
+
+	if err := o.writer.WriteMessages(ctx, kafkaMessages...); err != nil {
+		return fmt.Errorf("can not write %d messages to kafka topic %s: %w", len(kafkaMessages), o.settings.Topic, err)
+	}
+
+	return nil
+}
+
+// Close closes the connections of the writer, it is called by the OutputCloser module on shutdown
+func (o *kafkaOutput) Close() error {
+	if err := o.writer.Close(); err != nil {
+		return fmt.Errorf("can not close the writer of kafka topic %s: %w", o.settings.Topic, err)
+	}
+
+	return nil
+}
+
+// buildKafkaMessage uses the body of stream messages as value and writes the attributes as headers.
+// Messages without the configured partition key attribute are spread over all partitions.
+func (o *kafkaOutput) buildKafkaMessage(msg WritableMessage) (kafka.Message, error) {
+	var err error
+	var value []byte
+
+	if streamMessage, ok := msg.(*Message); ok {
+		value = []byte(streamMessage.Body)
+	} else if value, err = msg.MarshalToBytes(); err != nil {
+		return kafka.Message{}, fmt.Errorf("can not marshal message: %w", err)
+	}
+
+	attributes := getAttributes(msg)
+	headers := make([]kafka.Header, 0, len(attributes))
+
+	for key, attribute := range attributes {
+		if isKafkaInternalAttribute(key) {
+			continue
+		}
+
+		headerValue, err := json.Marshal(attribute)
+
+		if err != nil {
+			return kafka.Message{}, fmt.Errorf("can not marshal attribute %s to a kafka header: %w", key, err)
+		}
+
+		headers = append(headers, kafka.Header{
+			Key:   key,
+			Value: headerValue,
+		})
+	}
+
+	key := o.uuidGen.NewV4()
+
+	if partitionKey, ok := attributes[o.settings.PartitionKeyAttribute]; ok {
+		if key, err = cast.ToStringE(partitionKey); err != nil {
+			return kafka.Message{}, fmt.Errorf("the partition key attribute %s is not a valid key: %w", o.settings.PartitionKeyAttribute, err)
+		}
+	}
+
+	return kafka.Message{
+		Key:     []byte(key),
+		Value:   value,
+		Headers: headers,
+	}, nil
+}
+
+func isKafkaInternalAttribute(key string) bool {
+	switch key {
+	case AttributeKafkaKey, AttributeKafkaTopic, AttributeKafkaPartition, AttributeKafkaOffset:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,80 @@
+package stream_test
+
+import (
+	"context"
+	"fmt"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/stream"
+	"github.com/applike/gosoline/pkg/stream/mocks"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestKafkaOutput_Write(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	writer := new(mocks.KafkaWriter)
+
+	var written []kafka.Message
+
+	writer.On("WriteMessages", context.Background(), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		written = []kafka.Message{args.Get(1).(kafka.Message), args.Get(2).(kafka.Message)}
+	}).Return(nil).Once()
+
+	output := stream.NewKafkaOutputWithInterfaces(logger, writer, &stream.KafkaOutputSettings{
+		Topic:                 "topic",
+		PartitionKeyAttribute: "userId",
+	})
+
+	err := output.Write(context.Background(), []stream.WritableMessage{
+		stream.NewJsonMessage(`{"id":1}`, map[string]interface{}{
+			"userId":                       5,
+			stream.AttributeKafkaPartition: 3,
+		}),
+		stream.NewJsonMessage(`{"id":2}`),
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, written, 2)
+
+	assert.Equal(t, []byte("5"), written[0].Key)
+	assert.Equal(t, []byte(`{"id":1}`), written[0].Value)
+	assert.ElementsMatch(t, []kafka.Header{
+		{Key: "encoding", Value: []byte(`"application/json"`)},
+		{Key: "userId", Value: []byte(`5`)},
+	}, written[0].Headers)
+
+	assert.Len(t, written[1].Key, 36, "messages without partition key should get a random key")
+	assert.Equal(t, []byte(`{"id":2}`), written[1].Value)
+
+	writer.AssertExpectations(t)
+}
+
+func TestKafkaOutput_Write_Error(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	writer := new(mocks.KafkaWriter)
+	writer.On("WriteMessages", context.Background(), mock.Anything).Return(fmt.Errorf("broker not available")).Once()
+
+	output := stream.NewKafkaOutputWithInterfaces(logger, writer, &stream.KafkaOutputSettings{
+		Topic: "topic",
+	})
+
+	err := output.WriteOne(context.Background(), stream.NewJsonMessage(`{}`))
+
+	assert.EqualError(t, err, "can not write 1 messages to kafka topic topic: broker not available")
+	writer.AssertExpectations(t)
+}
+
+func TestKafkaOutput_Close(t *testing.T) {
+	logger := monMocks.NewLoggerMockedAll()
+	writer := new(mocks.KafkaWriter)
+	writer.On("Close").Return(nil).Once()
+
+	output := stream.NewKafkaOutputWithInterfaces(logger, writer, &stream.KafkaOutputSettings{
+		Topic: "topic",
+	})
+
+	assert.NoError(t, output.Close())
+	writer.AssertExpectations(t)
+}
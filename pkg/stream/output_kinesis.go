@@ -16,13 +16,17 @@ import (
 	"github.com/aws/aws-sdk-go/service/kinesis"
 	"github.com/aws/aws-sdk-go/service/kinesis/kinesisiface"
 	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/cast"
 )
 
 const kinesisBatchSizeMax = 500
 
 type KinesisOutputSettings struct {
 	StreamName string
-	Backoff    exec.BackoffSettings
+	// PartitionKeyAttribute names the attribute used as partition key of a record, a random key is used if
+	// a message doesn't have it.
+	PartitionKeyAttribute string
+	Backoff               exec.BackoffSettings
 }
 
 func (k *KinesisOutputSettings) GetResourceName() string {
@@ -88,14 +92,20 @@ func (o *kinesisOutput) Write(ctx context.Context, batch []WritableMessage) erro
 	})
 
 	var err, errs error
-	var chunks Chunks
+	var records []*kinesis.PutRecordsRequestEntry
 
-	if chunks, err = BuildChunks(batch, kinesisBatchSizeMax); err != nil {
+	if records, err = o.buildRecords(batch); err != nil {
 		return fmt.Errorf("could not build batch for messages: %w", err)
 	}
 
-	for _, chunk := range chunks {
-		if err = o.writeBatch(ctx, chunk); err != nil {
+	for start := 0; start < len(records); start += kinesisBatchSizeMax {
+		end := start + kinesisBatchSizeMax
+
+		if end > len(records) {
+			end = len(records)
+		}
+
+		if err = o.writeBatch(ctx, records[start:end]); err != nil {
 			errs = multierror.Append(errs, err)
 		}
 	}
@@ -107,19 +117,46 @@ func (o *kinesisOutput) Write(ctx context.Context, batch []WritableMessage) erro
 	return nil
 }
 
-func (o *kinesisOutput) writeBatch(ctx context.Context, batch [][]byte) error {
+// buildRecords marshals the messages with their partition key stored in the kinesis partition key attribute,
+// as the kinesis input only receives the data of a record.
+func (o *kinesisOutput) buildRecords(batch []WritableMessage) ([]*kinesis.PutRecordsRequestEntry, error) {
 	var err error
 	var records = make([]*kinesis.PutRecordsRequestEntry, 0, len(batch))
 
-	for _, data := range batch {
-		req := &kinesis.PutRecordsRequestEntry{
-			Data:         data,
-			PartitionKey: aws.String(o.uuidGen.NewV4()),
+	for _, msg := range batch {
+		key := o.uuidGen.NewV4()
+		attributes := getAttributes(msg)
+
+		if partitionKey, ok := attributes[o.settings.PartitionKeyAttribute]; ok && o.settings.PartitionKeyAttribute != "" {
+			if key, err = cast.ToStringE(partitionKey); err != nil {
+				return nil, fmt.Errorf("the partition key attribute %s is not a valid key: %w", o.settings.PartitionKeyAttribute, err)
+			}
+		}
+
+		if message, ok := msg.(*Message); ok {
+			message = copyMessage(message)
+			message.Attributes[AttributeKinesisPartitionKey] = key
+			msg = message
 		}
 
-		records = append(records, req)
+		data, err := msg.MarshalToBytes()
+
+		if err != nil {
+			return nil, fmt.Errorf("can not marshal message: %w", err)
+		}
+
+		records = append(records, &kinesis.PutRecordsRequestEntry{
+			Data:         data,
+			PartitionKey: aws.String(key),
+		})
 	}
 
+	return records, nil
+}
+
+func (o *kinesisOutput) writeBatch(ctx context.Context, records []*kinesis.PutRecordsRequestEntry) error {
+	var err error
+
 	_, err = o.batchExec.Execute(ctx, func(ctx context.Context) (interface{}, error) {
 		records, err = o.putRecordsAndCollectFailed(ctx, records)
 		return records, err
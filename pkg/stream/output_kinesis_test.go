@@ -47,3 +47,33 @@ func TestWriter_WriteEvents(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestWriter_WritePartitionKey(t *testing.T) {
+	kinesisClient := new(cloudMocks.KinesisAPI)
+	exec := gosoAws.NewTestableExecutor(&kinesisClient.Mock)
+
+	var records []*kinesis.PutRecordsRequestEntry
+	exec.ExpectExecution("PutRecordsRequest", mock.MatchedBy(func(input *kinesis.PutRecordsInput) bool {
+		records = input.Records
+		return true
+	}), &kinesis.PutRecordsOutput{Records: []*kinesis.PutRecordsResultEntry{}}, nil)
+
+	writer := stream.NewKinesisOutputWithInterfaces(monMocks.NewLoggerMockedAll(), kinesisClient, exec, &stream.KinesisOutputSettings{
+		StreamName:            "streamName",
+		PartitionKeyAttribute: "entityId",
+	})
+
+	msg := stream.NewMessage("1", map[string]interface{}{
+		"entityId": 42,
+	})
+
+	err := writer.WriteOne(context.Background(), msg)
+	assert.NoError(t, err)
+
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "42", *records[0].PartitionKey)
+		assert.JSONEq(t, `{"attributes":{"entityId":42,"kinesisPartitionKey":"42"},"body":"1"}`, string(records[0].Data))
+	}
+
+	assert.NotContains(t, msg.Attributes, stream.AttributeKinesisPartitionKey, "the written message should not be modified")
+}
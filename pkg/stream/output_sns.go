@@ -13,6 +13,7 @@ import (
 type SnsOutputSettings struct {
 	cfg.AppId
 	TopicId string
+	Fifo    sns.FifoSettings
 	Client  cloud.ClientSettings
 	Backoff exec.BackoffSettings
 }
@@ -31,6 +32,7 @@ func NewSnsOutput(config cfg.Config, logger mon.Logger, s SnsOutputSettings) Out
 		Client:  s.Client,
 		Backoff: s.Backoff,
 		TopicId: s.TopicId,
+		Fifo:    s.Fifo,
 	})
 
 	return NewSnsOutputWithInterfaces(logger, topic, s)
@@ -9,10 +9,11 @@ import (
 )
 
 type ProducerSettings struct {
-	Output      string                 `cfg:"output"`
-	Encoding    string                 `cfg:"encoding"`
-	Compression string                 `cfg:"compression"`
-	Daemon      ProducerDaemonSettings `cfg:"daemon"`
+	Output        string                 `cfg:"output"`
+	Encoding      string                 `cfg:"encoding"`
+	Compression   string                 `cfg:"compression"`
+	SchemaSubject string                 `cfg:"schema_subject"`
+	Daemon        ProducerDaemonSettings `cfg:"daemon"`
 }
 
 type Producer interface {
@@ -32,9 +33,22 @@ func NewProducer(config cfg.Config, logger mon.Logger, name string, handlers ...
 	encodeHandlers = append(encodeHandlers, defaultEncodeHandlers...)
 	encodeHandlers = append(encodeHandlers, handlers...)
 
+	compression := settings.Compression
+
+	if err := checkCompression(compression); err != nil {
+		return nil, fmt.Errorf("invalid compression for producer %s: %w", name, err)
+	}
+
+	// aggregates are compressed as a whole by the producer daemon, so there is no need to compress every single message
+	if settings.Daemon.Enabled && settings.Daemon.AggregationSize > 1 && compression != "" && compression != CompressionNone {
+		logger.Infof("the messages of producer %s are compressed with %s as aggregates of %d messages", name, compression, settings.Daemon.AggregationSize)
+		compression = CompressionNone
+	}
+
 	encoder := NewMessageEncoder(&MessageEncoderSettings{
 		Encoding:       settings.Encoding,
-		Compression:    settings.Compression,
+		Compression:    compression,
+		SchemaSubject:  readSchemaSubject(config, settings),
 		EncodeHandlers: encodeHandlers,
 	})
 
@@ -121,6 +135,28 @@ func readProducerSettings(config cfg.Config, name string) *ProducerSettings {
 	return settings
 }
 
+// readSchemaSubject returns the configured schema subject or derives it from the topic of the output
+// like "<topic>-value", falling back to the name of the output if it has no topic.
+func readSchemaSubject(config cfg.Config, settings *ProducerSettings) string {
+	if settings.SchemaSubject != "" {
+		return settings.SchemaSubject
+	}
+
+	topic := settings.Output
+	outputKey := ConfigurableOutputKey(settings.Output)
+
+	for _, setting := range []string{"topic", "topic_id", "stream_name", "queue_id"} {
+		key := fmt.Sprintf("%s.%s", outputKey, setting)
+
+		if config.IsSet(key) {
+			topic = config.GetString(key)
+			break
+		}
+	}
+
+	return fmt.Sprintf("%s-value", topic)
+}
+
 func readAllProducerDaemonSettings(config cfg.Config) map[string]*ProducerSettings {
 	producerSettings := make(map[string]*ProducerSettings)
 	producerMap := config.GetStringMap("stream.producer", map[string]interface{}{})
@@ -79,6 +79,11 @@ func NewProducerDaemon(config cfg.Config, logger mon.Logger, name string) (*Prod
 		return nil, fmt.Errorf("can not create output for producer daemon %s: %w", name, err)
 	}
 
+	marshaller, err := NewAggregateMarshaller(settings.Compression)
+	if err != nil {
+		return nil, fmt.Errorf("can not create aggregate marshaller for producer daemon %s: %w", name, err)
+	}
+
 	defaultMetrics := getProducerDaemonDefaultMetrics(name)
 	metric := mon.NewMetricDaemonWriter(defaultMetrics...)
 
@@ -90,7 +95,7 @@ func NewProducerDaemon(config cfg.Config, logger mon.Logger, name string) (*Prod
 		outCh:         make(chan []WritableMessage, settings.Daemon.BufferSize),
 		output:        output,
 		tickerFactory: clock.NewRealTicker,
-		marshaller:    MarshalJsonMessage,
+		marshaller:    marshaller,
 		settings:      settings.Daemon,
 	}, nil
 }
@@ -372,6 +377,27 @@ func getProducerDaemonDefaultMetrics(name string) mon.MetricData {
 	}
 }
 
+// NewAggregateMarshaller returns a marshaller which encodes the aggregate as json and compresses
+// the resulting body with the given compression
+func NewAggregateMarshaller(compression string) (AggregateMarshaller, error) {
+	if err := checkCompression(compression); err != nil {
+		return nil, err
+	}
+
+	if compression == "" || compression == CompressionNone {
+		return MarshalJsonMessage, nil
+	}
+
+	encoder := &messageEncoder{
+		encoding:    EncodingJson,
+		compression: compression,
+	}
+
+	return func(body interface{}, attributes ...map[string]interface{}) (*Message, error) {
+		return encoder.Encode(context.Background(), body, attributes...)
+	}, nil
+}
+
 func BuildAggregateMessage(marshaller AggregateMarshaller, aggregate []WritableMessage, attributes ...map[string]interface{}) (WritableMessage, error) {
 	attributes = append(attributes, map[string]interface{}{
 		AttributeAggregate: true,
@@ -243,6 +243,47 @@ func (s *ProducerDaemonTestSuite) TestWriteAggregate() {
 	s.output.AssertExpectations(s.T())
 }
 
+func (s *ProducerDaemonTestSuite) TestWriteCompressedAggregate() {
+	marshaller, err := stream.NewAggregateMarshaller(stream.CompressionZstd)
+	s.NoError(err)
+
+	s.SetupDaemon(mon.Info, 2, 2, time.Hour, marshaller)
+
+	messages := []stream.WritableMessage{
+		&stream.Message{Body: "1"},
+		&stream.Message{Body: "2"},
+	}
+
+	var aggregateMessage *stream.Message
+	s.output.On("Write", s.ctx, mock.AnythingOfType("[]stream.WritableMessage")).Run(func(args mock.Arguments) {
+		batch := args.Get(1).([]stream.WritableMessage)
+		aggregateMessage = batch[0].(*stream.Message)
+	}).Return(nil).Once()
+
+	err = s.daemon.Write(context.Background(), messages)
+	s.NoError(err, "there should be no error on write")
+
+	err = s.stop()
+	s.NoError(err, "there should be no error on run")
+	s.output.AssertExpectations(s.T())
+
+	s.Require().NotNil(aggregateMessage)
+	s.Equal(stream.CompressionZstd, aggregateMessage.Attributes[stream.AttributeCompression])
+	s.Equal(true, aggregateMessage.Attributes[stream.AttributeAggregate])
+
+	batch := make([]*stream.Message, 0)
+	encoder := stream.NewMessageEncoder(&stream.MessageEncoderSettings{})
+	_, _, err = encoder.Decode(context.Background(), aggregateMessage, &batch)
+
+	s.NoError(err)
+	s.Equal([]*stream.Message{{Body: "1"}, {Body: "2"}}, batch)
+}
+
+func (s *ProducerDaemonTestSuite) TestUnknownAggregateCompression() {
+	_, err := stream.NewAggregateMarshaller("application/unknown")
+	s.EqualError(err, "there is no compressor for compression 'application/unknown'")
+}
+
 func (s *ProducerDaemonTestSuite) TestAggregateErrorOnWrite() {
 	s.SetupDaemon(mon.Info, 2, 3, time.Hour, func(body interface{}, attributes ...map[string]interface{}) (*stream.Message, error) {
 		return nil, fmt.Errorf("aggregate marshal error")
@@ -0,0 +1,167 @@
+package stream
+
+import (
+	"fmt"
+	"github.com/spf13/cast"
+	"sync"
+)
+
+const (
+	AttributeSchemaId      = "schemaId"
+	AttributeSchemaSubject = "schemaSubject"
+)
+
+//go:generate mockery -name SchemaRegistry
+type SchemaRegistry interface {
+	// Register adds the schema to the subject and returns its id. Registering a known schema again returns the existing id.
+	Register(subject string, schema string) (int, error)
+	// GetSchema returns the schema registered with the given id
+	GetSchema(id int) (string, error)
+	// IsCompatible checks if data written with the schema of the given id can be read with the given schema
+	IsCompatible(id int, schema string) (bool, error)
+}
+
+// WithSchemaRegistry replaces the default protobuf and avro encoders with encoders using the given registry
+func WithSchemaRegistry(registry SchemaRegistry) {
+	AddMessageBodyEncoder(EncodingProtobuf, NewProtobufEncoder(registry))
+	AddMessageBodyEncoder(EncodingAvro, NewAvroEncoder(registry))
+}
+
+type inMemorySchema struct {
+	subject string
+	schema  string
+}
+
+// InMemorySchemaRegistry keeps all schemas in memory. Two schemas are considered compatible
+// if they are equal or have been registered for the same subject.
+type InMemorySchemaRegistry struct {
+	lck     sync.Mutex
+	schemas []inMemorySchema
+}
+
+func NewInMemorySchemaRegistry() *InMemorySchemaRegistry {
+	return &InMemorySchemaRegistry{
+		schemas: make([]inMemorySchema, 0),
+	}
+}
+
+func (r *InMemorySchemaRegistry) Register(subject string, schema string) (int, error) {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	for i, s := range r.schemas {
+		if s.subject == subject && s.schema == schema {
+			return i + 1, nil
+		}
+	}
+
+	r.schemas = append(r.schemas, inMemorySchema{
+		subject: subject,
+		schema:  schema,
+	})
+
+	return len(r.schemas), nil
+}
+
+func (r *InMemorySchemaRegistry) GetSchema(id int) (string, error) {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	if id < 1 || id > len(r.schemas) {
+		return "", fmt.Errorf("there is no schema with id %d", id)
+	}
+
+	return r.schemas[id-1].schema, nil
+}
+
+func (r *InMemorySchemaRegistry) IsCompatible(id int, schema string) (bool, error) {
+	r.lck.Lock()
+	defer r.lck.Unlock()
+
+	if id < 1 || id > len(r.schemas) {
+		return false, fmt.Errorf("there is no schema with id %d", id)
+	}
+
+	writer := r.schemas[id-1]
+
+	if writer.schema == schema {
+		return true, nil
+	}
+
+	for _, s := range r.schemas {
+		if s.subject == writer.subject && s.schema == schema {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// stampSchema registers the schema for the subject configured on the producer or the given default subject
+// and stamps the subject and the schema id on the message
+func stampSchema(registry SchemaRegistry, attributes map[string]interface{}, defaultSubject string, schema string) error {
+	subject := schemaSubject(attributes, defaultSubject)
+	attributes[AttributeSchemaSubject] = subject
+
+	if registry == nil {
+		return nil
+	}
+
+	id, err := registry.Register(subject, schema)
+
+	if err != nil {
+		return fmt.Errorf("can not register schema for subject %s: %w", subject, err)
+	}
+
+	attributes[AttributeSchemaId] = id
+
+	return nil
+}
+
+func schemaSubject(attributes map[string]interface{}, defaultSubject string) string {
+	if subject, ok := attributes[AttributeSchemaSubject].(string); ok && subject != "" {
+		return subject
+	}
+
+	return defaultSubject
+}
+
+// readSchemaId returns the schema id stamped on the message and false if there is none or no registry to resolve it
+func readSchemaId(registry SchemaRegistry, attributes map[string]interface{}) (int, bool, error) {
+	if registry == nil {
+		return 0, false, nil
+	}
+
+	if _, ok := attributes[AttributeSchemaId]; !ok {
+		return 0, false, nil
+	}
+
+	// the id might have been converted to a float during the transport of the message
+	id, err := cast.ToIntE(attributes[AttributeSchemaId])
+
+	if err != nil {
+		return 0, false, fmt.Errorf("the schema id attribute '%v' is not a valid id: %w", attributes[AttributeSchemaId], err)
+	}
+
+	return id, true, nil
+}
+
+func checkSchemaCompatibility(registry SchemaRegistry, attributes map[string]interface{}, schema string) error {
+	id, ok, err := readSchemaId(registry, attributes)
+
+	if err != nil || !ok {
+		return err
+	}
+
+	compatible, err := registry.IsCompatible(id, schema)
+
+	if err != nil {
+		return fmt.Errorf("can not check compatibility with schema %d: %w", id, err)
+	}
+
+	if !compatible {
+		return fmt.Errorf("the schema %d of the message is not compatible with the schema of the model", id)
+	}
+
+	return nil
+}
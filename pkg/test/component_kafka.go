@@ -0,0 +1,90 @@
+package test
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/segmentio/kafka-go"
+)
+
+const componentKafka = "kafka"
+
+type kafkaSettings struct {
+	*mockSettings
+	// the broker advertises its address to the clients, so the port has to be known before the container starts
+	Port           int    `cfg:"port" default:"9092"`
+	AdvertisedHost string `cfg:"advertised_host" default:"127.0.0.1"`
+	// the image bundles zookeeper with the broker, so a single container is enough
+	Version string `cfg:"version" default:"2.6.0"`
+}
+
+type kafkaComponent struct {
+	mockComponentBase
+	settings *kafkaSettings
+	clients  *simpleCache
+}
+
+func (k *kafkaComponent) Boot(config cfg.Config, _ mon.Logger, runner *dockerRunnerLegacy, settings *mockSettings, name string) {
+	k.name = name
+	k.runner = runner
+	k.clients = &simpleCache{}
+	k.settings = &kafkaSettings{
+		mockSettings: settings,
+	}
+	key := fmt.Sprintf("mocks.%s", name)
+	config.UnmarshalKey(key, k.settings)
+}
+
+func (k *kafkaComponent) Start() error {
+	containerName := fmt.Sprintf("gosoline_test_kafka_%s", k.name)
+
+	return k.runner.Run(containerName, &containerConfigLegacy{
+		Repository: "johnnypark/kafka-zookeeper",
+		Tag:        k.settings.Version,
+		Env: []string{
+			fmt.Sprintf("ADVERTISED_HOST=%s", k.settings.AdvertisedHost),
+			fmt.Sprintf("ADVERTISED_PORT=%d", k.settings.Port),
+		},
+		PortBindings: portBindingLegacy{
+			"9092/tcp": fmt.Sprint(k.settings.Port),
+		},
+		PortMappings: portMappingLegacy{
+			"9092/tcp": &k.settings.Port,
+		},
+		HostMapping: hostMappingLegacy{
+			dialPort: &k.settings.Port,
+			setHost:  &k.settings.Host,
+		},
+		HealthCheck: func() error {
+			conn, err := kafka.Dial("tcp", k.address())
+
+			if err != nil {
+				return err
+			}
+
+			defer conn.Close()
+
+			_, err = conn.Brokers()
+
+			return err
+		},
+		PrintLogs:   k.settings.Debug,
+		ExpireAfter: k.settings.ExpireAfter,
+	})
+}
+
+func (k *kafkaComponent) provideKafkaConn() *kafka.Conn {
+	return k.clients.New(k.name, func() interface{} {
+		conn, err := kafka.Dial("tcp", k.address())
+
+		if err != nil {
+			panic(fmt.Errorf("can not connect to kafka broker %s: %w", k.address(), err))
+		}
+
+		return conn
+	}).(*kafka.Conn)
+}
+
+func (k *kafkaComponent) address() string {
+	return fmt.Sprintf("%s:%d", k.settings.Host, k.settings.Port)
+}
@@ -22,8 +22,9 @@ type ddbComponent struct {
 func (c *ddbComponent) AppOptions() []application.Option {
 	return []application.Option{
 		application.WithConfigMap(map[string]interface{}{
-			"aws_dynamoDb_endpoint":   fmt.Sprintf("http://%s:%s", c.binding.host, c.binding.port),
-			"aws_dynamoDb_autoCreate": true,
+			"aws_dynamoDb_endpoint":        fmt.Sprintf("http://%s:%s", c.binding.host, c.binding.port),
+			"aws_dynamoDb_autoCreate":      true,
+			"aws_dynamoDbStreams_endpoint": fmt.Sprintf("http://%s:%s", c.binding.host, c.binding.port),
 		}),
 	}
 }
@@ -0,0 +1,62 @@
+package env
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/applike/gosoline/pkg/application"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+)
+
+type postgresComponent struct {
+	baseComponent
+	client      *sqlx.DB
+	credentials postgresCredentials
+	binding     containerBinding
+}
+
+func (c *postgresComponent) AppOptions() []application.Option {
+	return []application.Option{
+		application.WithConfigMap(map[string]interface{}{
+			"db": map[string]interface{}{
+				c.name: map[string]interface{}{
+					"uri.host":           c.binding.host,
+					"uri.user":           c.credentials.UserName,
+					"uri.password":       c.credentials.UserPassword,
+					"uri.database":       c.credentials.DatabaseName,
+					"uri.port":           c.binding.port,
+					"migrations.enabled": true,
+				},
+			},
+		}),
+	}
+}
+
+func (c *postgresComponent) Client() *sqlx.DB {
+	return c.client
+}
+
+func (c *postgresComponent) Exec(qry string, args ...interface{}) {
+	_, err := c.client.Exec(qry, args...)
+
+	if err != nil {
+		assert.FailNow(c.t, err.Error(), "failed to execute query")
+		return
+	}
+}
+
+func (c *postgresComponent) AssertRowCount(table string, expectedCount int) {
+	qry, args, err := squirrel.Select("COUNT(*)").From(table).ToSql()
+
+	if err != nil {
+		assert.FailNow(c.t, err.Error(), "can not generate qry to count rows in table %s", table)
+	}
+
+	var actualCount int
+	err = c.client.Get(&actualCount, qry, args...)
+
+	if err != nil {
+		assert.FailNow(c.t, err.Error(), "can not count rows in table %s", table)
+	}
+
+	assert.Equal(c.t, expectedCount, actualCount, "row count doesn't match for table %s", table)
+}
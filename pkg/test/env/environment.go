@@ -146,6 +146,10 @@ func (e *Environment) MySql(name string) *mysqlComponent {
 	return e.Component(componentMySql, name).(*mysqlComponent)
 }
 
+func (e *Environment) Postgres(name string) *postgresComponent {
+	return e.Component(componentPostgres, name).(*postgresComponent)
+}
+
 func (e *Environment) Wiremock(name string) *wiremockComponent {
 	return e.Component(componentWiremock, name).(*wiremockComponent)
 }
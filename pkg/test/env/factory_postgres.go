@@ -0,0 +1,152 @@
+package env
+
+import (
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/mon"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"net/url"
+)
+
+func init() {
+	componentFactories[componentPostgres] = new(postgresFactory)
+}
+
+const componentPostgres = "postgres"
+
+type postgresCredentials struct {
+	DatabaseName string `cfg:"database_name" default:"gosoline"`
+	UserName     string `cfg:"user_name" default:"gosoline"`
+	UserPassword string `cfg:"user_password" default:"gosoline"`
+}
+
+type postgresSettings struct {
+	ComponentBaseSettings
+	ComponentContainerSettings
+	Port        int                 `cfg:"port" default:"0"`
+	Version     string              `cfg:"version" default:"12"`
+	Credentials postgresCredentials `cfg:"credentials"`
+}
+
+type postgresFactory struct {
+}
+
+func (f postgresFactory) Detect(config cfg.Config, manager *ComponentsConfigManager) error {
+	if !config.IsSet("db") {
+		return nil
+	}
+
+	if manager.HasType(componentPostgres) {
+		return nil
+	}
+
+	components := config.GetStringMap("db")
+
+	for name := range components {
+		driver := config.Get(fmt.Sprintf("db.%s.driver", name))
+
+		if driver != componentPostgres {
+			continue
+		}
+
+		settings := &postgresSettings{}
+		config.UnmarshalDefaults(settings)
+
+		settings.Type = componentPostgres
+		settings.Name = name
+
+		if err := manager.Add(settings); err != nil {
+			return fmt.Errorf("can not add default postgres component: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (f postgresFactory) GetSettingsSchema() ComponentBaseSettingsAware {
+	return &postgresSettings{}
+}
+
+func (f postgresFactory) ConfigureContainer(settings interface{}) *containerConfig {
+	s := settings.(*postgresSettings)
+
+	env := []string{
+		fmt.Sprintf("POSTGRES_DB=%s", s.Credentials.DatabaseName),
+		fmt.Sprintf("POSTGRES_USER=%s", s.Credentials.UserName),
+		fmt.Sprintf("POSTGRES_PASSWORD=%s", s.Credentials.UserPassword),
+	}
+
+	if len(s.Tmpfs) == 0 {
+		s.Tmpfs = append(s.Tmpfs, TmpfsSettings{
+			Path: "/var/lib/postgresql/data",
+		})
+	}
+
+	return &containerConfig{
+		Repository: "postgres",
+		Tmpfs:      s.Tmpfs,
+		Tag:        s.Version,
+		Env:        env,
+		PortBindings: portBindings{
+			"5432/tcp": s.Port,
+		},
+		ExpireAfter: s.ExpireAfter,
+	}
+}
+
+func (f postgresFactory) HealthCheck(settings interface{}) ComponentHealthCheck {
+	return func(container *container) error {
+		s := settings.(*postgresSettings)
+		binding := container.bindings["5432/tcp"]
+		client, err := f.connection(s, binding)
+
+		if err != nil {
+			return fmt.Errorf("can not create client: %w", err)
+		}
+
+		return client.Ping()
+	}
+}
+
+func (f postgresFactory) Component(_ cfg.Config, _ mon.Logger, container *container, settings interface{}) (Component, error) {
+	s := settings.(*postgresSettings)
+	binding := container.bindings["5432/tcp"]
+	client, err := f.connection(s, binding)
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create client: %w", err)
+	}
+
+	component := &postgresComponent{
+		baseComponent: baseComponent{
+			name: s.Name,
+		},
+		client:      client,
+		credentials: s.Credentials,
+		binding:     binding,
+	}
+
+	return component, nil
+}
+
+func (f postgresFactory) connection(settings *postgresSettings, binding containerBinding) (*sqlx.DB, error) {
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(settings.Credentials.UserName, settings.Credentials.UserPassword),
+		Host:   fmt.Sprintf("%s:%v", binding.host, binding.port),
+		Path:   settings.Credentials.DatabaseName,
+	}
+
+	qry := dsn.Query()
+	qry.Set("sslmode", "disable")
+	dsn.RawQuery = qry.Encode()
+
+	client, err := sqlx.Open("postgres", dsn.String())
+
+	if err != nil {
+		return nil, fmt.Errorf("can not create client: %w", err)
+	}
+
+	return client, nil
+}
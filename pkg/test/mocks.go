@@ -96,6 +96,8 @@ func (m *Mocks) createComponent(component string) (mockComponent, error) {
 		return &dynamoDbComponent{}, nil
 	case "elasticsearch":
 		return &elasticsearchComponent{}, nil
+	case componentKafka:
+		return &kafkaComponent{}, nil
 	case componentKinesis:
 		return &kinesisComponent{}, nil
 	case componentS3:
@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/go-redis/redis"
+	"github.com/segmentio/kafka-go"
 )
 
 func (m *Mocks) ProvideSqsClient(name string) *sqs.SQS {
@@ -42,6 +43,11 @@ func (m *Mocks) ProvideElasticsearchV7Client(name string, clientType string) *es
 	return component.provideElasticsearchV7Client(clientType)
 }
 
+func (m *Mocks) ProvideKafkaConn(name string) *kafka.Conn {
+	component := m.components[name].(*kafkaComponent)
+	return component.provideKafkaConn()
+}
+
 func (m *Mocks) ProvideKinesisClient(name string) *kinesis.Kinesis {
 	component := m.components[name].(*kinesisComponent)
 	return component.provideKinesisClient()
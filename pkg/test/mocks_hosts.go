@@ -25,6 +25,11 @@ func (m *Mocks) ProvideElasticsearchHost(name string) string {
 	return component.settings.Host
 }
 
+func (m *Mocks) ProvideKafkaHost(name string) string {
+	component := m.components[name].(*kafkaComponent)
+	return component.settings.Host
+}
+
 func (m *Mocks) ProvideKinesisHost(name string) string {
 	component := m.components[name].(*kinesisComponent)
 	return component.settings.Host
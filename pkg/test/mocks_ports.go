@@ -25,6 +25,11 @@ func (m *Mocks) ProvideElasticsearchPort(name string) int {
 	return component.settings.Port
 }
 
+func (m *Mocks) ProvideKafkaPort(name string) int {
+	component := m.components[name].(*kafkaComponent)
+	return component.settings.Port
+}
+
 func (m *Mocks) ProvideKinesisPort(name string) int {
 	component := m.components[name].(*kinesisComponent)
 	return component.settings.Port
@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	SpanKindInternal = 1
+	SpanKindServer   = 2
+	SpanKindClient   = 3
+
+	SpanStatusUnset = 0
+	SpanStatusError = 2
+)
+
+//go:generate mockery -name SpanExporter
+type SpanExporter interface {
+	Export(spans []*SpanData) error
+}
+
+// ShutdownableSpanExporter is implemented by exporters which buffer spans and have to flush them before the application exits
+type ShutdownableSpanExporter interface {
+	SpanExporter
+	Shutdown(ctx context.Context) error
+}
+
+// SpanData is a finished span of the otel tracer, the ids are lower case hex strings
+type SpanData struct {
+	TraceId       string
+	SpanId        string
+	ParentSpanId  string
+	Name          string
+	Kind          int
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    map[string]interface{}
+	Events        []*SpanEvent
+	StatusCode    int
+	StatusMessage string
+}
+
+type SpanEvent struct {
+	Name       string
+	Time       time.Time
+	Attributes map[string]interface{}
+}
+
+// InMemoryExporter keeps all exported spans to assert them in tests
+type InMemoryExporter struct {
+	lck   sync.Mutex
+	spans []*SpanData
+}
+
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{
+		spans: make([]*SpanData, 0),
+	}
+}
+
+func (e *InMemoryExporter) Export(spans []*SpanData) error {
+	e.lck.Lock()
+	defer e.lck.Unlock()
+
+	e.spans = append(e.spans, spans...)
+
+	return nil
+}
+
+// GetSpans returns the exported spans in the order they were finished
+func (e *InMemoryExporter) GetSpans() []*SpanData {
+	e.lck.Lock()
+	defer e.lck.Unlock()
+
+	spans := make([]*SpanData, len(e.spans))
+	copy(spans, e.spans)
+
+	return spans
+}
+
+func (e *InMemoryExporter) Reset() {
+	e.lck.Lock()
+	defer e.lck.Unlock()
+
+	e.spans = make([]*SpanData, 0)
+}
@@ -0,0 +1,297 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/encoding/json"
+	"github.com/applike/gosoline/pkg/mon"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceId           string         `json:"traceId"`
+	SpanId            string         `json:"spanId"`
+	ParentSpanId      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// otlpHttpExporter sends the spans json encoded to the traces endpoint of an OTLP/HTTP collector
+type otlpHttpExporter struct {
+	client   *http.Client
+	endpoint string
+	resource otlpResource
+}
+
+func NewOtlpHttpExporter(appId cfg.AppId, settings *OtelSettings) SpanExporter {
+	client := &http.Client{
+		Timeout: settings.Timeout,
+	}
+
+	return NewOtlpHttpExporterWithInterfaces(client, appId, settings.Endpoint)
+}
+
+func NewOtlpHttpExporterWithInterfaces(client *http.Client, appId cfg.AppId, endpoint string) SpanExporter {
+	resource := otlpResource{
+		Attributes: otlpAttributes(map[string]interface{}{
+			"service.name":           appId.Application,
+			"service.namespace":      fmt.Sprintf("%s-%s-%s", appId.Project, appId.Environment, appId.Family),
+			"deployment.environment": appId.Environment,
+		}),
+	}
+
+	return &otlpHttpExporter{
+		client:   client,
+		endpoint: endpoint,
+		resource: resource,
+	}
+}
+
+func (e *otlpHttpExporter) Export(spans []*SpanData) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	traces := otlpTraces{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: e.resource,
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "gosoline"},
+				Spans: make([]otlpSpan, len(spans)),
+			}},
+		}},
+	}
+
+	for i, span := range spans {
+		traces.ResourceSpans[0].ScopeSpans[0].Spans[i] = otlpSpanFromData(span)
+	}
+
+	body, err := json.Marshal(traces)
+
+	if err != nil {
+		return fmt.Errorf("can not marshal %d spans: %w", len(spans), err)
+	}
+
+	resp, err := e.client.Post(e.endpoint, "application/json", bytes.NewReader(body))
+
+	if err != nil {
+		return fmt.Errorf("can not send %d spans to %s: %w", len(spans), e.endpoint, err)
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("the collector at %s responded with status %d to %d spans", e.endpoint, resp.StatusCode, len(spans))
+	}
+
+	return nil
+}
+
+func otlpSpanFromData(data *SpanData) otlpSpan {
+	span := otlpSpan{
+		TraceId:           data.TraceId,
+		SpanId:            data.SpanId,
+		ParentSpanId:      data.ParentSpanId,
+		Name:              data.Name,
+		Kind:              data.Kind,
+		StartTimeUnixNano: strconv.FormatInt(data.StartTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(data.EndTime.UnixNano(), 10),
+		Attributes:        otlpAttributes(data.Attributes),
+		Status: otlpStatus{
+			Code:    data.StatusCode,
+			Message: data.StatusMessage,
+		},
+	}
+
+	for _, event := range data.Events {
+		span.Events = append(span.Events, otlpEvent{
+			TimeUnixNano: strconv.FormatInt(event.Time.UnixNano(), 10),
+			Name:         event.Name,
+			Attributes:   otlpAttributes(event.Attributes),
+		})
+	}
+
+	return span
+}
+
+func otlpAttributes(attributes map[string]interface{}) []otlpKeyValue {
+	keyValues := make([]otlpKeyValue, 0, len(attributes))
+
+	for key, value := range attributes {
+		keyValues = append(keyValues, otlpKeyValue{
+			Key:   key,
+			Value: otlpValue(value),
+		})
+	}
+
+	return keyValues
+}
+
+func otlpValue(value interface{}) otlpAnyValue {
+	switch v := value.(type) {
+	case string:
+		return otlpAnyValue{StringValue: &v}
+	case bool:
+		return otlpAnyValue{BoolValue: &v}
+	case int:
+		s := strconv.Itoa(v)
+		return otlpAnyValue{IntValue: &s}
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		return otlpAnyValue{IntValue: &s}
+	case float64:
+		return otlpAnyValue{DoubleValue: &v}
+	}
+
+	s := fmt.Sprintf("%v", value)
+
+	if encoded, err := json.Marshal(value); err == nil {
+		s = string(encoded)
+	}
+
+	return otlpAnyValue{StringValue: &s}
+}
+
+// batchSpanExporter collects the spans of all finished spans and exports them in batches in the background
+type batchSpanExporter struct {
+	logger    mon.Logger
+	exporter  SpanExporter
+	batchSize int
+	stop      chan struct{}
+	stopOnce  sync.Once
+
+	lck   sync.Mutex
+	spans []*SpanData
+}
+
+func NewBatchSpanExporter(logger mon.Logger, exporter SpanExporter, batchSize int, interval time.Duration) ShutdownableSpanExporter {
+	e := &batchSpanExporter{
+		logger:    logger.WithChannel("tracing"),
+		exporter:  exporter,
+		batchSize: batchSize,
+		stop:      make(chan struct{}),
+		spans:     make([]*SpanData, 0, batchSize),
+	}
+
+	go e.run(interval)
+
+	return e
+}
+
+func (e *batchSpanExporter) Export(spans []*SpanData) error {
+	e.lck.Lock()
+	e.spans = append(e.spans, spans...)
+	full := len(e.spans) >= e.batchSize
+	e.lck.Unlock()
+
+	if full {
+		go e.flush()
+	}
+
+	return nil
+}
+
+// Shutdown stops the periodic export and exports the remaining spans, it returns if the context is done before
+func (e *batchSpanExporter) Shutdown(ctx context.Context) error {
+	e.stopOnce.Do(func() {
+		close(e.stop)
+	})
+
+	done := make(chan struct{})
+
+	go func() {
+		e.flush()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("can not export the remaining spans: %w", ctx.Err())
+	}
+}
+
+func (e *batchSpanExporter) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.stop:
+			return
+		case <-ticker.C:
+			e.flush()
+		}
+	}
+}
+
+func (e *batchSpanExporter) flush() {
+	e.lck.Lock()
+	spans := e.spans
+	e.spans = make([]*SpanData, 0, e.batchSize)
+	e.lck.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+
+	if err := e.exporter.Export(spans); err != nil {
+		e.logger.Warnf("can not export spans: %s", err.Error())
+	}
+}
@@ -0,0 +1,72 @@
+package tracing_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/cfg"
+	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOtlpHttpExporter_Export(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bytes, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		body = string(bytes)
+	}))
+	defer server.Close()
+
+	exporter := tracing.NewOtlpHttpExporterWithInterfaces(server.Client(), cfg.AppId{Application: "app"}, server.URL+"/v1/traces")
+
+	err := exporter.Export([]*tracing.SpanData{{
+		TraceId:    "4bf92f3577b34da6a3ce929d0e0e4736",
+		SpanId:     "00f067aa0ba902b7",
+		Name:       "span",
+		Kind:       tracing.SpanKindServer,
+		StartTime:  time.Unix(1, 0),
+		EndTime:    time.Unix(2, 0),
+		Attributes: map[string]interface{}{"http.status_code": 200},
+		StatusCode: tracing.SpanStatusError,
+	}})
+
+	assert.NoError(t, err)
+	assert.Contains(t, body, `"scopeSpans":[{"scope":{"name":"gosoline"},"spans":[{"traceId":"4bf92f3577b34da6a3ce929d0e0e4736","spanId":"00f067aa0ba902b7","name":"span","kind":2,"startTimeUnixNano":"1000000000","endTimeUnixNano":"2000000000","attributes":[{"key":"http.status_code","value":{"intValue":"200"}}],"status":{"code":2}}]}]`)
+	assert.Contains(t, body, `{"key":"service.name","value":{"stringValue":"app"}}`)
+}
+
+func TestOtlpHttpExporter_ExportStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	exporter := tracing.NewOtlpHttpExporterWithInterfaces(server.Client(), cfg.AppId{}, server.URL)
+	err := exporter.Export([]*tracing.SpanData{{}})
+
+	assert.EqualError(t, err, "the collector at "+server.URL+" responded with status 503 to 1 spans")
+}
+
+func TestBatchSpanExporter_Shutdown(t *testing.T) {
+	inMemory := tracing.NewInMemoryExporter()
+	exporter := tracing.NewBatchSpanExporter(monMocks.NewLoggerMockedAll(), inMemory, 10, time.Hour)
+
+	err := exporter.Export([]*tracing.SpanData{{Name: "span"}})
+	assert.NoError(t, err)
+	assert.Empty(t, inMemory.GetSpans(), "the span should be buffered until the batch is full")
+
+	err = exporter.Shutdown(context.Background())
+	assert.NoError(t, err)
+
+	spans := inMemory.GetSpans()
+	assert.Len(t, spans, 1, "the buffered span should be exported on shutdown")
+	assert.Equal(t, "span", spans[0].Name)
+}
@@ -26,6 +26,12 @@ func (m MessageWithTraceEncoder) Encode(ctx context.Context, _ interface{}, attr
 		return ctx, attributes, nil
 	}
 
+	if IsTraceParentTrace(trace) {
+		attributes[HeaderTraceParent] = TraceToTraceParent(trace)
+
+		return ctx, attributes, nil
+	}
+
 	attributes["traceId"] = TraceToString(trace)
 
 	return ctx, attributes, nil
@@ -35,6 +41,10 @@ func (m MessageWithTraceEncoder) Decode(ctx context.Context, _ interface{}, attr
 	var ok bool
 	var traceId string
 
+	if _, ok = attributes[HeaderTraceParent]; ok {
+		return m.decodeTraceParent(ctx, attributes)
+	}
+
 	if _, ok = attributes["traceId"]; !ok {
 		return ctx, attributes, nil
 	}
@@ -60,3 +70,28 @@ func (m MessageWithTraceEncoder) Decode(ctx context.Context, _ interface{}, attr
 
 	return ctx, attributes, nil
 }
+
+func (m MessageWithTraceEncoder) decodeTraceParent(ctx context.Context, attributes map[string]interface{}) (context.Context, map[string]interface{}, error) {
+	traceParent, ok := attributes[HeaderTraceParent].(string)
+
+	if !ok {
+		err := fmt.Errorf("the traceparent attribute should be of type string to decode it")
+		err = m.strategy.TraceIdInvalid(err)
+
+		return ctx, attributes, err
+	}
+
+	trace, err := TraceParentToTrace(traceParent)
+
+	if err != nil {
+		err := fmt.Errorf("the traceparent attribute is invalid: %w", err)
+		err = m.strategy.TraceIdInvalid(err)
+
+		return ctx, attributes, err
+	}
+
+	ctx = ContextWithTrace(ctx, trace)
+	delete(attributes, HeaderTraceParent)
+
+	return ctx, attributes, nil
+}
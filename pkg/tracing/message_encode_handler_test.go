@@ -70,3 +70,38 @@ func TestMessageWithTraceEncoder_Decode_Warning(t *testing.T) {
 
 	logger.AssertExpectations(t)
 }
+
+func TestMessageWithTraceEncoder_EncodeTraceParent(t *testing.T) {
+	tracer := getOtelTracer(tracing.NewInMemoryExporter())
+	encoder := tracing.NewMessageWithTraceEncoder(tracing.TraceIdErrorReturnStrategy{})
+
+	ctx, span := tracer.StartSpan("test-span")
+	defer span.Finish()
+
+	_, attributes, err := encoder.Encode(ctx, nil, map[string]interface{}{})
+
+	assert.NoError(t, err)
+	assert.NotContains(t, attributes, "traceId")
+	assert.Equal(t, tracing.TraceToTraceParent(span.GetTrace()), attributes["traceparent"])
+}
+
+func TestMessageWithTraceEncoder_DecodeTraceParent(t *testing.T) {
+	ctx := context.Background()
+	attributes := map[string]interface{}{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}
+
+	encoder := tracing.NewMessageWithTraceEncoder(tracing.TraceIdErrorReturnStrategy{})
+	ctx, decodedAttributes, err := encoder.Decode(ctx, nil, attributes)
+
+	trace := tracing.GetTraceFromContext(ctx)
+	expected := &tracing.Trace{
+		TraceId:  "4bf92f3577b34da6a3ce929d0e0e4736",
+		ParentId: "00f067aa0ba902b7",
+		Sampled:  true,
+	}
+
+	assert.NoError(t, err)
+	assert.NotContains(t, decodedAttributes, "traceparent")
+	assert.Equal(t, expected, trace)
+}
@@ -28,6 +28,31 @@ func (_m *Tracer) HttpHandler(h http.Handler) http.Handler {
 	return r0
 }
 
+// StartClientSpan provides a mock function with given fields: ctx, name
+func (_m *Tracer) StartClientSpan(ctx context.Context, name string) (context.Context, tracing.Span) {
+	ret := _m.Called(ctx, name)
+
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func(context.Context, string) context.Context); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	var r1 tracing.Span
+	if rf, ok := ret.Get(1).(func(context.Context, string) tracing.Span); ok {
+		r1 = rf(ctx, name)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(tracing.Span)
+		}
+	}
+
+	return r0, r1
+}
+
 // StartSpan provides a mock function with given fields: name
 func (_m *Tracer) StartSpan(name string) (context.Context, tracing.Span) {
 	ret := _m.Called(name)
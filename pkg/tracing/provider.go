@@ -12,5 +12,6 @@ func AddProvider(name string, provider Provider) {
 }
 
 var providers = map[string]Provider{
+	"otel": NewOtelTracer,
 	"xray": NewAwsTracer,
 }
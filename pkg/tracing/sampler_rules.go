@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"github.com/applike/gosoline/pkg/clock"
+	"math/rand"
+	"net/http"
+	"sync"
+)
+
+// ruleSampler applies the SamplingConfiguration like the localized strategy of X-Ray: the first matching rule samples
+// up to FixedTarget requests per second and the given rate of the remaining ones.
+type ruleSampler struct {
+	lck         sync.Mutex
+	clock       clock.Clock
+	random      func() float64
+	rules       []*sampleReservoir
+	defaultRule *sampleReservoir
+}
+
+type sampleReservoir struct {
+	rule   SampleRule
+	second int64
+	taken  uint64
+}
+
+func newRuleSampler(clock clock.Clock, random func() float64, config *SamplingConfiguration) *ruleSampler {
+	sampler := &ruleSampler{
+		clock:  clock,
+		random: random,
+		rules:  make([]*sampleReservoir, len(config.Rules)),
+		defaultRule: &sampleReservoir{
+			rule: config.Default,
+		},
+	}
+
+	for i, rule := range config.Rules {
+		sampler.rules[i] = &sampleReservoir{
+			rule: rule,
+		}
+	}
+
+	return sampler
+}
+
+// shouldSample decides about a new trace, spans outside of http requests use the default rule. The service name of a
+// rule is matched against the name of the service and the host of the request.
+func (s *ruleSampler) shouldSample(serviceName string, r *http.Request) bool {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	reservoir := s.defaultRule
+
+	if r != nil {
+		for _, candidate := range s.rules {
+			if candidate.matches(serviceName, r) {
+				reservoir = candidate
+				break
+			}
+		}
+	}
+
+	return reservoir.take(s.clock.Now().Unix(), s.random)
+}
+
+func (r *sampleReservoir) matches(serviceName string, req *http.Request) bool {
+	matchesService := wildcardMatch(r.rule.ServiceName, serviceName) || wildcardMatch(r.rule.ServiceName, req.Host)
+
+	return matchesService && wildcardMatch(r.rule.HttpMethod, req.Method) && wildcardMatch(r.rule.UrlPath, req.URL.Path)
+}
+
+func (r *sampleReservoir) take(second int64, random func() float64) bool {
+	if r.second != second {
+		r.second = second
+		r.taken = 0
+	}
+
+	if r.taken < r.rule.FixedTarget {
+		r.taken++
+		return true
+	}
+
+	return random() < r.rule.Rate
+}
+
+// wildcardMatch matches like X-Ray rules, * stands for any number of characters and ? for a single one
+func wildcardMatch(pattern string, value string) bool {
+	if pattern == "" || pattern == "*" {
+		return true
+	}
+
+	p, v := []rune(pattern), []rune(value)
+	star, match := -1, 0
+	i, j := 0, 0
+
+	for j < len(v) {
+		switch {
+		case i < len(p) && (p[i] == '?' || p[i] == v[j]):
+			i++
+			j++
+		case i < len(p) && p[i] == '*':
+			star, match = i, j
+			i++
+		case star != -1:
+			i = star + 1
+			match++
+			j = match
+		default:
+			return false
+		}
+	}
+
+	for i < len(p) && p[i] == '*' {
+		i++
+	}
+
+	return i == len(p)
+}
+
+func defaultRandom() float64 {
+	return rand.Float64()
+}
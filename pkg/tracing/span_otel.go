@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"github.com/applike/gosoline/pkg/clock"
+	"sync"
+)
+
+type otelSpan struct {
+	lck      sync.Mutex
+	clock    clock.Clock
+	exporter SpanExporter
+	sampled  bool
+	finished bool
+	data     *SpanData
+}
+
+func (s *otelSpan) AddAnnotation(key string, value string) {
+	s.setAttribute(key, value)
+}
+
+func (s *otelSpan) AddError(err error) {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	s.data.StatusCode = SpanStatusError
+	s.data.StatusMessage = err.Error()
+	s.data.Events = append(s.data.Events, &SpanEvent{
+		Name: "exception",
+		Time: s.clock.Now(),
+		Attributes: map[string]interface{}{
+			"exception.message": err.Error(),
+		},
+	})
+}
+
+func (s *otelSpan) AddMetadata(key string, value interface{}) {
+	s.setAttribute(key, value)
+}
+
+func (s *otelSpan) Finish() {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	if s.finished {
+		return
+	}
+
+	s.finished = true
+	s.data.EndTime = s.clock.Now()
+
+	if !s.sampled {
+		return
+	}
+
+	// the exporter reports its errors itself, a failed export must not affect the traced code
+	_ = s.exporter.Export([]*SpanData{s.data})
+}
+
+func (s *otelSpan) GetId() string {
+	return s.data.SpanId
+}
+
+func (s *otelSpan) GetTrace() *Trace {
+	return &Trace{
+		TraceId:  s.data.TraceId,
+		Id:       s.data.SpanId,
+		ParentId: s.data.ParentSpanId,
+		Sampled:  s.sampled,
+	}
+}
+
+func (s *otelSpan) setAttribute(key string, value interface{}) {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	s.data.Attributes[key] = value
+}
+
+func (s *otelSpan) setStatusError(message string) {
+	s.lck.Lock()
+	defer s.lck.Unlock()
+
+	s.data.StatusCode = SpanStatusError
+	s.data.StatusMessage = message
+}
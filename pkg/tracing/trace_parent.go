@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HeaderTraceParent is the header and message attribute of the W3C trace context
+const HeaderTraceParent = "traceparent"
+
+var (
+	traceParentTraceIdPattern = regexp.MustCompile("^[0-9a-f]{32}$")
+	traceParentSpanIdPattern  = regexp.MustCompile("^[0-9a-f]{16}$")
+)
+
+// IsTraceParentTrace reports whether the trace uses W3C ids instead of X-Ray ones
+func IsTraceParentTrace(trace *Trace) bool {
+	return trace != nil && traceParentTraceIdPattern.MatchString(trace.TraceId) && traceParentSpanIdPattern.MatchString(trace.Id)
+}
+
+func TraceToTraceParent(trace *Trace) string {
+	flags := "00"
+
+	if trace.Sampled {
+		flags = "01"
+	}
+
+	return fmt.Sprintf("00-%s-%s-%s", trace.TraceId, trace.Id, flags)
+}
+
+// TraceParentToTrace parses a W3C traceparent, the span id of the caller is used as the parent id of the trace
+func TraceParentToTrace(traceParent string) (*Trace, error) {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+
+	if len(parts) < 4 {
+		return nil, fmt.Errorf("the traceparent [%s] should consist of 4 parts", traceParent)
+	}
+
+	if len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) {
+		return nil, fmt.Errorf("the version of the traceparent [%s] is not supported", traceParent)
+	}
+
+	if !traceParentTraceIdPattern.MatchString(parts[1]) || parts[1] == strings.Repeat("0", 32) {
+		return nil, fmt.Errorf("the trace id of the traceparent [%s] is invalid", traceParent)
+	}
+
+	if !traceParentSpanIdPattern.MatchString(parts[2]) || parts[2] == strings.Repeat("0", 16) {
+		return nil, fmt.Errorf("the parent id of the traceparent [%s] is invalid", traceParent)
+	}
+
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+
+	if err != nil || len(parts[3]) != 2 {
+		return nil, fmt.Errorf("the flags of the traceparent [%s] are invalid", traceParent)
+	}
+
+	return &Trace{
+		TraceId:  parts[1],
+		ParentId: parts[2],
+		Sampled:  flags&1 == 1,
+	}, nil
+}
+
+// TraceParentFromContext returns the traceparent of the span in the context, it is empty if there is no W3C span
+func TraceParentFromContext(ctx context.Context) string {
+	span := GetSpanFromContext(ctx)
+
+	if span == nil {
+		return ""
+	}
+
+	trace := span.GetTrace()
+
+	if !IsTraceParentTrace(trace) {
+		return ""
+	}
+
+	return TraceToTraceParent(trace)
+}
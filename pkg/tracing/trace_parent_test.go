@@ -0,0 +1,31 @@
+package tracing_test
+
+import (
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestTraceParentToTrace(t *testing.T) {
+	trace, err := tracing.TraceParentToTrace("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-03")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &tracing.Trace{
+		TraceId:  "4bf92f3577b34da6a3ce929d0e0e4736",
+		ParentId: "00f067aa0ba902b7",
+		Sampled:  true,
+	}, trace)
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-zz",
+	} {
+		_, err := tracing.TraceParentToTrace(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
@@ -15,6 +15,8 @@ type Tracer interface {
 	StartSpan(name string) (context.Context, Span)
 	StartSpanFromContext(ctx context.Context, name string) (context.Context, Span)
 	StartSubSpan(ctx context.Context, name string) (context.Context, Span)
+	// StartClientSpan starts a sub span for a call to another service, its trace has to be propagated with the call
+	StartClientSpan(ctx context.Context, name string) (context.Context, Span)
 }
 
 type TracerSettings struct {
@@ -24,6 +26,7 @@ type TracerSettings struct {
 	AddressValue                string                `cfg:"add_value" default:""`
 	Sampling                    SamplingConfiguration `cfg:"sampling"`
 	StreamingMaxSubsegmentCount int                   `cfg:"streaming_max_subsegment_count" default:"20"`
+	Otel                        OtelSettings          `cfg:"otel"`
 }
 
 var tracerContainer = struct {
@@ -107,6 +107,10 @@ func (t *awsTracer) StartSubSpan(ctx context.Context, name string) (context.Cont
 	return ctxWithSpan, span
 }
 
+func (t *awsTracer) StartClientSpan(ctx context.Context, name string) (context.Context, Span) {
+	return t.StartSubSpan(ctx, name)
+}
+
 func (t *awsTracer) StartSpan(name string) (context.Context, Span) {
 	if !t.enabled {
 		return context.Background(), disabledRootSpan()
@@ -15,6 +15,10 @@ func (t *noopTracer) StartSubSpan(ctx context.Context, name string) (context.Con
 	return ctx, disabledSpan()
 }
 
+func (t *noopTracer) StartClientSpan(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, disabledSpan()
+}
+
 func (t *noopTracer) StartSpan(name string) (context.Context, Span) {
 	return context.Background(), disabledSpan()
 }
@@ -0,0 +1,187 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/mon"
+	"net/http"
+	"time"
+)
+
+type OtelSettings struct {
+	Endpoint      string        `cfg:"endpoint" default:"http://localhost:4318/v1/traces"`
+	BatchSize     int           `cfg:"batch_size" default:"512"`
+	BatchInterval time.Duration `cfg:"batch_interval" default:"5s"`
+	Timeout       time.Duration `cfg:"timeout" default:"10s"`
+}
+
+// otelTracer propagates W3C trace contexts and exports the sampled spans, it keeps the sampling rules of X-Ray
+type otelTracer struct {
+	cfg.AppId
+	clock    clock.Clock
+	sampler  *ruleSampler
+	exporter SpanExporter
+}
+
+func NewOtelTracer(config cfg.Config, logger mon.Logger) Tracer {
+	appId := cfg.AppId{}
+	appId.PadFromConfig(config)
+
+	settings := &TracerSettings{}
+	config.UnmarshalKey("tracing", settings)
+
+	exporter := NewOtlpHttpExporter(appId, &settings.Otel)
+	exporter = NewBatchSpanExporter(logger, exporter, settings.Otel.BatchSize, settings.Otel.BatchInterval)
+
+	return NewOtelTracerWithInterfaces(clock.Provider, appId, &settings.Sampling, exporter)
+}
+
+func NewOtelTracerWithInterfaces(clock clock.Clock, appId cfg.AppId, sampling *SamplingConfiguration, exporter SpanExporter) *otelTracer {
+	return &otelTracer{
+		AppId:    appId,
+		clock:    clock,
+		sampler:  newRuleSampler(clock, defaultRandom, sampling),
+		exporter: exporter,
+	}
+}
+
+func (t *otelTracer) StartSubSpan(ctx context.Context, name string) (context.Context, Span) {
+	parent, ok := GetSpanFromContext(ctx).(*otelSpan)
+
+	if !ok {
+		return ctx, disabledSpan()
+	}
+
+	return t.startSpan(ctx, name, SpanKindInternal, parent.data.TraceId, parent.data.SpanId, parent.sampled)
+}
+
+func (t *otelTracer) StartClientSpan(ctx context.Context, name string) (context.Context, Span) {
+	parent, ok := GetSpanFromContext(ctx).(*otelSpan)
+
+	if !ok {
+		return ctx, disabledSpan()
+	}
+
+	return t.startSpan(ctx, name, SpanKindClient, parent.data.TraceId, parent.data.SpanId, parent.sampled)
+}
+
+// Shutdown flushes the spans buffered by the exporter
+func (t *otelTracer) Shutdown(ctx context.Context) error {
+	if exporter, ok := t.exporter.(ShutdownableSpanExporter); ok {
+		return exporter.Shutdown(ctx)
+	}
+
+	return nil
+}
+
+func (t *otelTracer) StartSpan(name string) (context.Context, Span) {
+	return t.startSpan(context.Background(), name, SpanKindInternal, newTraceId(), "", t.sampler.shouldSample(name, nil))
+}
+
+func (t *otelTracer) StartSpanFromContext(ctx context.Context, name string) (context.Context, Span) {
+	if parentSpan := GetSpanFromContext(ctx); parentSpan != nil {
+		if parentTrace := parentSpan.GetTrace(); IsTraceParentTrace(parentTrace) {
+			return t.startSpan(ctx, name, SpanKindInternal, parentTrace.TraceId, parentTrace.Id, parentTrace.Sampled)
+		}
+	}
+
+	if trace := GetTraceFromContext(ctx); trace != nil && traceParentTraceIdPattern.MatchString(trace.TraceId) {
+		return t.startSpan(ctx, name, SpanKindInternal, trace.TraceId, trace.ParentId, trace.Sampled)
+	}
+
+	return t.startSpan(ctx, name, SpanKindInternal, newTraceId(), "", t.sampler.shouldSample(name, nil))
+}
+
+func (t *otelTracer) HttpHandler(h http.Handler) http.Handler {
+	name := fmt.Sprintf("%v-%v-%v-%v", t.Project, t.Environment, t.Family, t.Application)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ctx context.Context
+		var span *otelSpan
+
+		if trace, err := TraceParentToTrace(r.Header.Get(HeaderTraceParent)); err == nil {
+			ctx, span = t.startSpan(r.Context(), name, SpanKindServer, trace.TraceId, trace.ParentId, trace.Sampled)
+		} else {
+			ctx, span = t.startSpan(r.Context(), name, SpanKindServer, newTraceId(), "", t.sampler.shouldSample(name, r))
+		}
+
+		span.setAttribute("http.method", r.Method)
+		span.setAttribute("http.host", r.Host)
+		span.setAttribute("http.target", r.URL.Path)
+
+		recorder := &statusRecorder{
+			ResponseWriter: w,
+			status:         http.StatusOK,
+		}
+
+		defer func() {
+			span.setAttribute("http.status_code", recorder.status)
+
+			if recorder.status >= http.StatusInternalServerError {
+				span.setStatusError(http.StatusText(recorder.status))
+			}
+
+			span.Finish()
+		}()
+
+		h.ServeHTTP(recorder, r.WithContext(ctx))
+	})
+}
+
+func (t *otelTracer) startSpan(ctx context.Context, name string, kind int, traceId string, parentSpanId string, sampled bool) (context.Context, *otelSpan) {
+	span := &otelSpan{
+		clock:    t.clock,
+		exporter: t.exporter,
+		sampled:  sampled,
+		data: &SpanData{
+			TraceId:      traceId,
+			SpanId:       newSpanId(),
+			ParentSpanId: parentSpanId,
+			Name:         name,
+			Kind:         kind,
+			StartTime:    t.clock.Now(),
+			Attributes:   make(map[string]interface{}),
+			Events:       make([]*SpanEvent, 0),
+		},
+	}
+
+	appFamily := fmt.Sprintf("%v-%v-%v", t.Project, t.Environment, t.Family)
+	appId := fmt.Sprintf("%v-%v-%v-%v", t.Project, t.Environment, t.Family, t.Application)
+	span.AddAnnotation("appFamily", appFamily)
+	span.AddAnnotation("appId", appId)
+
+	return ContextWithSpan(ctx, span), span
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func newTraceId() string {
+	return randomHex(16)
+}
+
+func newSpanId() string {
+	return randomHex(8)
+}
+
+func randomHex(size int) string {
+	id := make([]byte, size)
+
+	// crypto/rand only fails if the system has no source of randomness left
+	if _, err := rand.Read(id); err != nil {
+		panic(fmt.Errorf("can not generate a random id: %w", err))
+	}
+
+	return hex.EncodeToString(id)
+}
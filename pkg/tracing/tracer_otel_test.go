@@ -0,0 +1,136 @@
+package tracing_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/clock"
+	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOtelTracer_StartSubSpan(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracer := getOtelTracer(exporter)
+
+	ctx, trans := tracer.StartSpan("test_trans")
+	_, span := tracer.StartSubSpan(ctx, "test_span")
+
+	span.AddAnnotation("key", "value")
+	span.AddError(fmt.Errorf("failed"))
+	span.Finish()
+	trans.Finish()
+
+	spans := exporter.GetSpans()
+
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "test_span", spans[0].Name)
+	assert.Equal(t, "test_trans", spans[1].Name)
+	assert.Equal(t, spans[1].TraceId, spans[0].TraceId, "the trace ids should match")
+	assert.Equal(t, spans[1].SpanId, spans[0].ParentSpanId, "the span id of the transaction should be the parent id of the span")
+	assert.Empty(t, spans[1].ParentSpanId, "the parent id of the transaction should be empty")
+	assert.Equal(t, "value", spans[0].Attributes["key"])
+	assert.Equal(t, "test_project-test_env-test_family-test_name", spans[0].Attributes["appId"])
+	assert.Equal(t, tracing.SpanStatusError, spans[0].StatusCode)
+	assert.Equal(t, "failed", spans[0].StatusMessage)
+	assert.Equal(t, time.Unix(1600000000, 0), spans[0].EndTime)
+}
+
+func TestOtelTracer_StartClientSpan(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracer := getOtelTracer(exporter)
+
+	ctx, trans := tracer.StartSpan("test_trans")
+	ctx, span := tracer.StartClientSpan(ctx, "GET example.com")
+
+	assert.Equal(t, fmt.Sprintf("00-%s-%s-01", trans.GetTrace().TraceId, span.GetId()), tracing.TraceParentFromContext(ctx), "the client span should be propagated")
+
+	span.Finish()
+	trans.Finish()
+
+	spans := exporter.GetSpans()
+
+	assert.Len(t, spans, 2)
+	assert.Equal(t, tracing.SpanKindClient, spans[0].Kind)
+	assert.Equal(t, spans[1].SpanId, spans[0].ParentSpanId)
+}
+
+func TestOtelTracer_StartSpanFromContextWithTrace(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracer := getOtelTracer(exporter)
+
+	trace, err := tracing.TraceParentToTrace("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	assert.NoError(t, err)
+
+	ctx := tracing.ContextWithTrace(context.Background(), trace)
+	_, transChild := tracer.StartSpanFromContext(ctx, "another_trace")
+	transChild.Finish()
+
+	assert.Equal(t, trace.TraceId, transChild.GetTrace().TraceId, "the trace ids should match")
+	assert.Equal(t, trace.ParentId, transChild.GetTrace().ParentId, "the caller should be the parent")
+	assert.False(t, transChild.GetTrace().Sampled, "the sample decision should match")
+	assert.Empty(t, exporter.GetSpans(), "unsampled spans should not be exported")
+}
+
+func TestOtelTracer_HttpHandler(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracer := getOtelTracer(exporter)
+
+	var traceParent string
+	handler := tracer.HttpHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceParent = tracing.TraceParentFromContext(r.Context())
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/items", nil)
+	request.Header.Set(tracing.HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	spans := exporter.GetSpans()
+
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "test_project-test_env-test_family-test_name", spans[0].Name)
+	assert.Equal(t, tracing.SpanKindServer, spans[0].Kind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].TraceId)
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].ParentSpanId)
+	assert.Equal(t, "/items", spans[0].Attributes["http.target"])
+	assert.Equal(t, http.StatusBadGateway, spans[0].Attributes["http.status_code"])
+	assert.Equal(t, tracing.SpanStatusError, spans[0].StatusCode)
+	assert.Equal(t, fmt.Sprintf("00-4bf92f3577b34da6a3ce929d0e0e4736-%s-01", spans[0].SpanId), traceParent)
+}
+
+func TestOtelTracer_HttpHandlerSamplingRules(t *testing.T) {
+	exporter := tracing.NewInMemoryExporter()
+	tracer := tracing.NewOtelTracerWithInterfaces(clock.NewFakeClockAt(time.Unix(1600000000, 0)), cfg.AppId{}, &tracing.SamplingConfiguration{
+		Default: tracing.SampleRule{FixedTarget: 1},
+		Rules: []tracing.SampleRule{
+			{HttpMethod: "GET", UrlPath: "/health*"},
+		},
+	}, exporter)
+
+	handler := tracer.HttpHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, path := range []string{"/health", "/healthz", "/items", "/items"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	spans := exporter.GetSpans()
+
+	assert.Len(t, spans, 1, "only the first request per second of the default rule should be sampled")
+	assert.Equal(t, "/items", spans[0].Attributes["http.target"])
+}
+
+func getOtelTracer(exporter tracing.SpanExporter) tracing.Tracer {
+	return tracing.NewOtelTracerWithInterfaces(clock.NewFakeClockAt(time.Unix(1600000000, 0)), cfg.AppId{
+		Project:     "test_project",
+		Environment: "test_env",
+		Family:      "test_family",
+		Application: "test_name",
+	}, &tracing.SamplingConfiguration{
+		Default: tracing.SampleRule{FixedTarget: 100},
+	}, exporter)
+}
@@ -0,0 +1,65 @@
+package tracing
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/cfg"
+	"github.com/applike/gosoline/pkg/kernel/common"
+	"github.com/applike/gosoline/pkg/mon"
+	"time"
+)
+
+type shutdownableTracer interface {
+	Shutdown(ctx context.Context) error
+}
+
+// TracerShutdown is a kernel module which exports the spans still buffered by the tracer when the application shuts
+// down. As it runs in the essential stage, it is stopped after the modules creating spans.
+type TracerShutdown struct {
+	logger  mon.Logger
+	tracer  Tracer
+	timeout time.Duration
+}
+
+func NewTracerShutdown(config cfg.Config, logger mon.Logger) (*TracerShutdown, error) {
+	settings := &TracerSettings{}
+	config.UnmarshalKey("tracing", settings)
+
+	tracer := ProviderTracer(config, logger)
+
+	return NewTracerShutdownWithInterfaces(logger, tracer, settings.Otel.Timeout), nil
+}
+
+func NewTracerShutdownWithInterfaces(logger mon.Logger, tracer Tracer, timeout time.Duration) *TracerShutdown {
+	return &TracerShutdown{
+		logger:  logger,
+		tracer:  tracer,
+		timeout: timeout,
+	}
+}
+
+func (s *TracerShutdown) GetType() string {
+	return common.TypeBackground
+}
+
+func (s *TracerShutdown) GetStage() int {
+	return common.StageEssential
+}
+
+func (s *TracerShutdown) Run(ctx context.Context) error {
+	<-ctx.Done()
+
+	tracer, ok := s.tracer.(shutdownableTracer)
+
+	if !ok {
+		return nil
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if err := tracer.Shutdown(shutdownCtx); err != nil {
+		s.logger.Error(err, "can not shutdown the tracer")
+	}
+
+	return nil
+}
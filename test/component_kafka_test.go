@@ -0,0 +1,33 @@
+//+build integration
+
+package test_test
+
+import (
+	pkgTest "github.com/applike/gosoline/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func Test_kafka(t *testing.T) {
+	t.Parallel()
+	setup(t)
+
+	mocks, err := pkgTest.Boot("test_configs/config.kafka.test.yml")
+	defer func() {
+		if mocks != nil {
+			mocks.Shutdown()
+		}
+	}()
+
+	if err != nil {
+		assert.Fail(t, "failed to boot mocks: %s", err.Error())
+
+		return
+	}
+
+	conn := mocks.ProvideKafkaConn("kafka")
+	brokers, err := conn.Brokers()
+
+	assert.NoError(t, err)
+	assert.Len(t, brokers, 1)
+}
@@ -0,0 +1,29 @@
+//+build integration
+
+package test_test
+
+import (
+	"github.com/applike/gosoline/pkg/test"
+	"testing"
+)
+
+type PostgresTestSuite struct {
+	test.Suite
+}
+
+func TestPostgresTestSuite(t *testing.T) {
+	test.RunSuite(t, new(PostgresTestSuite))
+}
+
+func (s *PostgresTestSuite) SetupSuite() []test.SuiteOption {
+	return []test.SuiteOption{
+		test.WithConfigFile("test_configs/config.postgres.test.yml"),
+	}
+}
+
+func (s *PostgresTestSuite) TestPostgres(_ test.AppUnderTest) {
+	client := s.Env().Postgres("default").Client()
+
+	err := client.Ping()
+	s.NoError(err)
+}
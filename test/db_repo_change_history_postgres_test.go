@@ -0,0 +1,150 @@
+//+build integration
+
+package test_test
+
+import (
+	"context"
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/test"
+	"testing"
+)
+
+type DbRepoChangeHistoryPostgresTestSuite struct {
+	test.Suite
+}
+
+func TestDbRepoChangeHistoryPostgresTestSuite(t *testing.T) {
+	test.RunSuite(t, new(DbRepoChangeHistoryPostgresTestSuite))
+}
+
+func (s *DbRepoChangeHistoryPostgresTestSuite) SetupSuite() []test.SuiteOption {
+	return []test.SuiteOption{
+		test.WithConfigFile("test_configs/config.db_repo_change_history_postgres.test.yml"),
+	}
+}
+
+// historyQuery sorts the entries of the history table by their revision
+func historyQuery() *db_repo.QueryBuilder {
+	qb := db_repo.NewQueryBuilder()
+	qb.OrderBy("change_history_revision", "ASC")
+
+	return qb
+}
+
+func (s *DbRepoChangeHistoryPostgresTestSuite) TestChangeHistoryMigration_Migrate_CreateTable(_ test.AppUnderTest) {
+	type TestModel1 struct {
+		db_repo.Model
+		Name *string
+	}
+
+	type TestModel1HistoryEntry struct {
+		db_repo.ChangeHistoryModel
+		TestModel1
+	}
+
+	repo, err := db_repo.New(s.Env().Config(), s.Env().Logger(), db_repo.Settings{})
+
+	if !s.NoError(err) {
+		return
+	}
+
+	err = db_repo.MigrateChangeHistory(s.Env().Config(), s.Env().Logger(), &TestModel1{})
+	s.NoError(err)
+
+	model := &TestModel1{
+		Name: mdl.String("name1"),
+	}
+
+	err = repo.Create(context.Background(), model)
+	s.NoError(err)
+
+	model.Name = mdl.String("name2")
+	err = repo.Update(context.Background(), model)
+	s.NoError(err)
+
+	err = repo.Delete(context.Background(), model)
+	s.NoError(err)
+
+	entries := make([]*TestModel1HistoryEntry, 0)
+	err = repo.Query(context.Background(), historyQuery(), &entries)
+	s.NoError(err)
+
+	if !s.Equal(3, len(entries), "expected 3 change history entries") {
+		return
+	}
+
+	s.Equal(1, entries[0].ChangeHistoryRevision)
+	s.Equal("insert", entries[0].ChangeHistoryAction)
+	s.Equal("name1", *entries[0].Name)
+
+	s.Equal(2, entries[1].ChangeHistoryRevision)
+	s.Equal("update", entries[1].ChangeHistoryAction)
+	s.Equal("name2", *entries[1].Name)
+
+	s.Equal(3, entries[2].ChangeHistoryRevision)
+	s.Equal("delete", entries[2].ChangeHistoryAction)
+	s.Equal("name2", *entries[2].Name)
+}
+
+func (s *DbRepoChangeHistoryPostgresTestSuite) TestChangeHistoryMigration_Migrate_UpdateTable(_ test.AppUnderTest) {
+	type TestModel2 struct {
+		db_repo.Model
+		Name         *string
+		Foo          *string
+		ChangeAuthor *string
+	}
+
+	type TestModel2HistoryEntry struct {
+		db_repo.ChangeHistoryModel
+		TestModel2
+	}
+
+	repo, err := db_repo.New(s.Env().Config(), s.Env().Logger(), db_repo.Settings{})
+
+	if !s.NoError(err) {
+		return
+	}
+
+	err = db_repo.MigrateChangeHistory(s.Env().Config(), s.Env().Logger(), &TestModel2{})
+	s.NoError(err)
+
+	model := &TestModel2{
+		Name:         mdl.String("name1"),
+		Foo:          mdl.String("foo1"),
+		ChangeAuthor: mdl.String("john@example.com"),
+	}
+
+	err = repo.Create(context.Background(), model)
+	s.NoError(err)
+
+	model.Foo = mdl.String("foo2")
+	err = repo.Update(context.Background(), model)
+	s.NoError(err)
+
+	err = repo.Delete(context.Background(), model)
+	s.NoError(err)
+
+	entries := make([]*TestModel2HistoryEntry, 0)
+	err = repo.Query(context.Background(), historyQuery(), &entries)
+	s.NoError(err)
+
+	if !s.Equal(3, len(entries), "expected 3 change history entries") {
+		return
+	}
+
+	s.Equal(1, entries[0].ChangeHistoryRevision)
+	s.Equal("insert", entries[0].ChangeHistoryAction)
+	s.Equal("foo1", *entries[0].Foo)
+	s.Equal("john@example.com", *entries[0].ChangeAuthor)
+
+	s.Equal(2, entries[1].ChangeHistoryRevision)
+	s.Equal("update", entries[1].ChangeHistoryAction)
+	s.Equal("foo2", *entries[1].Foo)
+	s.Equal("john@example.com", *entries[1].ChangeAuthor)
+
+	s.Equal(3, entries[2].ChangeHistoryRevision)
+	s.Equal("delete", entries[2].ChangeHistoryAction)
+	s.Equal("foo2", *entries[2].Foo)
+	s.Nil(entries[2].ChangeAuthor) // change-author is excluded for delete actions as it may be misleading
+}
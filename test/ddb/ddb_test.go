@@ -11,6 +11,8 @@ import (
 	monMocks "github.com/applike/gosoline/pkg/mon/mocks"
 	pkgTest "github.com/applike/gosoline/pkg/test"
 	"github.com/applike/gosoline/pkg/tracing"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"os"
@@ -102,3 +104,50 @@ func (s *DdbTestSuite) TestSetValidBackoffConfig() {
 func TestDdb(t *testing.T) {
 	suite.Run(t, new(DdbTestSuite))
 }
+
+type ReconcileData struct {
+	Id  string `json:"id" ddb:"key=hash"`
+	Ttl int64  `json:"ttl" ddb:"ttl=enabled"`
+}
+
+func (s *DdbTestSuite) TestCreateTable_Reconcile() {
+	logger := monMocks.NewLoggerMockedAll()
+	client := s.mocks.ProvideDynamoDbClient("dynamodb")
+	service := ddb.NewServiceWithInterfaces(logger, client)
+
+	settings := &ddb.Settings{
+		ModelId: mdl.ModelId{
+			Project:     "gosoline",
+			Environment: "test",
+			Family:      "test",
+			Application: "ddb",
+			Name:        "reconcile",
+		},
+		AutoCreate: true,
+		Main: ddb.MainSettings{
+			Model:              &ReconcileData{},
+			ReadCapacityUnits:  1,
+			WriteCapacityUnits: 1,
+		},
+	}
+
+	_, err := service.CreateTable(settings)
+	s.NoError(err)
+
+	settings.Main.BillingMode = ddb.BillingModePayPerRequest
+
+	_, err = service.CreateTable(settings)
+	s.NoError(err)
+
+	table, err := client.DescribeTable(&dynamodb.DescribeTableInput{
+		TableName: aws.String(ddb.TableName(settings)),
+	})
+	s.NoError(err)
+	s.Equal(ddb.BillingModePayPerRequest, *table.Table.BillingModeSummary.BillingMode)
+
+	ttl, err := client.DescribeTimeToLive(&dynamodb.DescribeTimeToLiveInput{
+		TableName: aws.String(ddb.TableName(settings)),
+	})
+	s.NoError(err)
+	s.Equal(dynamodb.TimeToLiveStatusEnabled, *ttl.TimeToLiveDescription.TimeToLiveStatus)
+}
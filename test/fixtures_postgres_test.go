@@ -0,0 +1,158 @@
+//+build integration fixtures
+
+package test_test
+
+import (
+	"github.com/applike/gosoline/pkg/db-repo"
+	"github.com/applike/gosoline/pkg/fixtures"
+	"github.com/applike/gosoline/pkg/mdl"
+	"github.com/applike/gosoline/pkg/test"
+	gosoAssert "github.com/applike/gosoline/pkg/test/assert"
+	"testing"
+)
+
+type FixturesPostgresSuite struct {
+	test.Suite
+}
+
+type PostgresTestModel struct {
+	db_repo.Model
+	Name *string
+}
+
+var PostgresTestModelMetadata = db_repo.Metadata{
+	ModelId: mdl.ModelId{
+		Name: "test_model",
+	},
+	TableName:  "postgres_test_models",
+	PrimaryKey: "model.id",
+	Mappings: db_repo.FieldMappings{
+		"test_model.id":   db_repo.NewFieldMapping("test_model.id"),
+		"test_model.name": db_repo.NewFieldMapping("test_model.name"),
+	},
+}
+
+func TestFixturesPostgresSuite(t *testing.T) {
+	test.RunSuite(t, new(FixturesPostgresSuite))
+}
+
+func (s *FixturesPostgresSuite) SetupSuite() []test.SuiteOption {
+	return []test.SuiteOption{
+		test.WithConfigFile("test_configs/config.postgres.test.yml"),
+	}
+}
+
+func (s *FixturesPostgresSuite) loader() fixtures.FixtureLoader {
+	return fixtures.NewFixtureLoader(s.Env().Config(), s.Env().Logger())
+}
+
+func ormPostgresTestFixtures() []*fixtures.FixtureSet {
+	return []*fixtures.FixtureSet{
+		{
+			Enabled: true,
+			Purge:   false,
+			Writer:  fixtures.PostgresOrmFixtureWriterFactory(&PostgresTestModelMetadata),
+			Fixtures: []interface{}{
+				&PostgresTestModel{
+					Name: mdl.String("testName"),
+				},
+			},
+		},
+	}
+}
+
+func ormPostgresTestFixturesWithPurge() []*fixtures.FixtureSet {
+	return []*fixtures.FixtureSet{
+		{
+			Enabled: true,
+			Purge:   true,
+			Writer:  fixtures.PostgresOrmFixtureWriterFactory(&PostgresTestModelMetadata),
+			Fixtures: []interface{}{
+				&PostgresTestModel{
+					Name: mdl.String("purgedBefore"),
+				},
+			},
+		},
+	}
+}
+
+func plainPostgresTestFixtures() []*fixtures.FixtureSet {
+	return []*fixtures.FixtureSet{
+		{
+			Enabled: true,
+			Purge:   false,
+			Writer: fixtures.PostgresPlainFixtureWriterFactory(&fixtures.PostgresPlainMetaData{
+				TableName:   "postgres_plain_writer_test",
+				Columns:     []string{"id", "name"},
+				PrimaryKeys: []string{"id"},
+			}),
+			Fixtures: []interface{}{
+				fixtures.PostgresPlainFixtureValues{2, "testName2"},
+				fixtures.PostgresPlainFixtureValues{2, "testName3"},
+			},
+		},
+	}
+}
+
+func plainPostgresTestFixturesWithPurge() []*fixtures.FixtureSet {
+	return []*fixtures.FixtureSet{
+		{
+			Enabled: true,
+			Purge:   true,
+			Writer: fixtures.PostgresPlainFixtureWriterFactory(&fixtures.PostgresPlainMetaData{
+				TableName:   "postgres_plain_writer_test",
+				Columns:     []string{"id", "name"},
+				PrimaryKeys: []string{"id"},
+			}),
+			Fixtures: []interface{}{
+				fixtures.PostgresPlainFixtureValues{1, "purgedBefore"},
+			},
+		},
+	}
+}
+
+func (s *FixturesPostgresSuite) TestOrmFixturesPostgres(_ test.AppUnderTest) {
+	err := s.loader().Load(ormPostgresTestFixtures())
+	s.NoError(err)
+
+	db := s.Env().Postgres("default").Client().DB
+
+	gosoAssert.SqlTableHasOneRowOnly(s.T(), db, "postgres_test_models")
+	gosoAssert.SqlColumnHasSpecificValue(s.T(), db, "postgres_test_models", "name", "testName")
+}
+
+func (s *FixturesPostgresSuite) TestPlainFixturesPostgres(_ test.AppUnderTest) {
+	err := s.loader().Load(plainPostgresTestFixtures())
+	s.NoError(err)
+
+	db := s.Env().Postgres("default").Client().DB
+
+	gosoAssert.SqlTableHasOneRowOnly(s.T(), db, "postgres_plain_writer_test")
+	gosoAssert.SqlColumnHasSpecificValue(s.T(), db, "postgres_plain_writer_test", "name", "testName3")
+}
+
+func (s *FixturesPostgresSuite) TestPurgedOrmFixturesPostgres(_ test.AppUnderTest) {
+	err := s.loader().Load(ormPostgresTestFixtures())
+	s.NoError(err)
+
+	db := s.Env().Postgres("default").Client().DB
+	gosoAssert.SqlColumnHasSpecificValue(s.T(), db, "postgres_test_models", "name", "testName")
+
+	err = s.loader().Load(ormPostgresTestFixturesWithPurge())
+	s.NoError(err)
+	gosoAssert.SqlTableHasOneRowOnly(s.T(), db, "postgres_test_models")
+	gosoAssert.SqlColumnHasSpecificValue(s.T(), db, "postgres_test_models", "name", "purgedBefore")
+}
+
+func (s *FixturesPostgresSuite) TestPurgedPlainFixturesPostgres(_ test.AppUnderTest) {
+	err := s.loader().Load(plainPostgresTestFixtures())
+	s.NoError(err)
+
+	db := s.Env().Postgres("default").Client().DB
+	gosoAssert.SqlColumnHasSpecificValue(s.T(), db, "postgres_plain_writer_test", "name", "testName3")
+
+	err = s.loader().Load(plainPostgresTestFixturesWithPurge())
+	s.NoError(err)
+	gosoAssert.SqlTableHasOneRowOnly(s.T(), db, "postgres_plain_writer_test")
+	gosoAssert.SqlColumnHasSpecificValue(s.T(), db, "postgres_plain_writer_test", "name", "purgedBefore")
+}
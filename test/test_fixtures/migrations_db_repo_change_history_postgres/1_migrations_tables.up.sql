@@ -0,0 +1,31 @@
+create table test_model1
+(
+  id serial primary key,
+  name varchar(255) null,
+  updated_at timestamp null,
+  created_at timestamp null
+);
+
+create table test_model2
+(
+  id serial primary key,
+  name varchar(255) null,
+  foo  varchar(8) null,
+  change_author varchar(255),
+  updated_at timestamp null,
+  created_at timestamp null
+);
+
+/* used to test with an existing history table */
+create table test_model2_history_entries
+(
+  change_history_action varchar(8) not null default 'insert',
+  change_history_revision int,
+  change_history_action_at timestamp null default current_timestamp,
+  id int,
+  updated_at timestamp null,
+  created_at timestamp null,
+  change_author varchar(255),
+  name varchar(255),
+  primary key (change_history_revision, id)
+);
@@ -0,0 +1,15 @@
+create table postgres_test_models
+(
+  id serial primary key,
+  name varchar(255) null,
+  updated_at timestamp null,
+  created_at timestamp null
+);
+
+create table postgres_plain_writer_test
+(
+  id serial primary key,
+  name varchar(255) null,
+  updated_at timestamp null,
+  created_at timestamp null
+);